@@ -102,14 +102,24 @@ func getValuesFromConfigMap(kubeconfig string) (string, error) {
 }
 
 func getInjectConfigFromConfigMap(kubeconfig string) (string, error) {
-	client, err := createInterface(kubeconfig)
+	injectConfig, err := getInjectionConfigFromConfigMap(kubeconfig)
 	if err != nil {
 		return "", err
 	}
+	return injectConfig.Template, nil
+}
+
+// getInjectionConfigFromConfigMap returns the whole injection config, for the
+// settings applied beside the template.
+func getInjectionConfigFromConfigMap(kubeconfig string) (*inject.Config, error) {
+	client, err := createInterface(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
 
 	meshConfigMap, err := client.CoreV1().ConfigMaps(istioNamespace).Get(context.TODO(), injectConfigMapName, metav1.GetOptions{})
 	if err != nil {
-		return "", fmt.Errorf("could not find valid configmap %q from namespace  %q: %v - "+
+		return nil, fmt.Errorf("could not find valid configmap %q from namespace  %q: %v - "+
 			"Use --injectConfigFile or re-run kube-inject with `-i <istioSystemNamespace> and ensure istio-sidecar-injector configmap exists",
 			injectConfigMapName, istioNamespace, err)
 	}
@@ -118,16 +128,16 @@ func getInjectConfigFromConfigMap(kubeconfig string) (string, error) {
 	// key
 	injectData, exists := meshConfigMap.Data[injectConfigMapKey]
 	if !exists {
-		return "", fmt.Errorf("missing configuration map key %q in %q",
+		return nil, fmt.Errorf("missing configuration map key %q in %q",
 			injectConfigMapKey, injectConfigMapName)
 	}
 	var injectConfig inject.Config
 	if err := yaml.Unmarshal([]byte(injectData), &injectConfig); err != nil {
-		return "", fmt.Errorf("unable to convert data from configmap %q: %v",
+		return nil, fmt.Errorf("unable to convert data from configmap %q: %v",
 			injectConfigMapName, err)
 	}
 	log.Debugf("using inject template from configmap %q", injectConfigMapName)
-	return injectConfig.Template, nil
+	return &injectConfig, nil
 }
 
 func validateFlags() error {
@@ -154,6 +164,7 @@ var (
 	valuesFile          string
 	injectConfigFile    string
 	injectConfigMapName string
+	kubernetesVersion   string
 )
 
 const (
@@ -192,6 +203,9 @@ kube-inject on deployments to get the most up-to-date changes.
   # Update an existing deployment.
   kubectl get deployment -o yaml | istioctl kube-inject -f - | kubectl apply -f -
 
+  # Render the injected containers for a cluster running Kubernetes 1.18
+  istioctl kube-inject -f deployment.yaml --kubernetesVersion 1.18
+
   # Capture cluster configuration for later use with kube-inject
   kubectl -n istio-system get cm istio-sidecar-injector  -o jsonpath="{.data.config}" > /tmp/inj-template.tmpl
   kubectl -n istio-system get cm istio -o jsonpath="{.data.mesh}" > /tmp/mesh.yaml
@@ -231,6 +245,13 @@ kube-inject on deployments to get the most up-to-date changes.
 				}
 			}
 
+			var kubernetes *inject.KubernetesCapabilities
+			if kubernetesVersion != "" {
+				if kubernetes, err = inject.KubernetesCapabilitiesForVersion(kubernetesVersion); err != nil {
+					return err
+				}
+			}
+
 			var writer io.Writer
 			if outFilename == "" {
 				writer = c.OutOrStdout()
@@ -263,18 +284,17 @@ kube-inject on deployments to get the most up-to-date changes.
 				}
 			}
 
-			var sidecarTemplate string
+			var injectConfig *inject.Config
 			if injectConfigFile != "" {
 				injectionConfig, err := ioutil.ReadFile(injectConfigFile) // nolint: vetshadow
 				if err != nil {
 					return err
 				}
-				var injectConfig inject.Config
-				if err := yaml.Unmarshal(injectionConfig, &injectConfig); err != nil {
+				injectConfig = &inject.Config{}
+				if err := yaml.Unmarshal(injectionConfig, injectConfig); err != nil {
 					return multierror.Append(err, fmt.Errorf("loading --injectConfigFile"))
 				}
-				sidecarTemplate = injectConfig.Template
-			} else if sidecarTemplate, err = getInjectConfigFromConfigMap(kubeconfig); err != nil {
+			} else if injectConfig, err = getInjectionConfigFromConfigMap(kubeconfig); err != nil {
 				return err
 			}
 
@@ -292,7 +312,7 @@ kube-inject on deployments to get the most up-to-date changes.
 			if emitTemplate {
 				cfg := inject.Config{
 					Policy:   inject.InjectionPolicyEnabled,
-					Template: sidecarTemplate,
+					Template: injectConfig.Template,
 				}
 				out, err := yaml.Marshal(&cfg)
 				if err != nil {
@@ -303,7 +323,7 @@ kube-inject on deployments to get the most up-to-date changes.
 			}
 
 			var warnings []string
-			retval := inject.IntoResourceFile(sidecarTemplate, valuesConfig, revision, meshConfig,
+			retval := inject.IntoResourceFileWithConfig(kubernetes, injectConfig, valuesConfig, revision, meshConfig,
 				reader, writer, func(warning string) {
 					warnings = append(warnings, warning)
 				})
@@ -347,6 +367,9 @@ kube-inject on deployments to get the most up-to-date changes.
 
 	injectCmd.PersistentFlags().StringVar(&revision, "revision", "",
 		"Control plane revision")
+	injectCmd.PersistentFlags().StringVar(&kubernetesVersion, "kubernetesVersion", "",
+		"Kubernetes version, such as 1.18, the injected containers are rendered for. "+
+			"Fields the version does not support are replaced or dropped. Defaults to rendering the template as is.")
 
 	return injectCmd
 }
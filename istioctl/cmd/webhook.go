@@ -164,6 +164,15 @@ func Webhook() *cobra.Command {
 	cmd.AddCommand(newEnableCmd())
 	cmd.AddCommand(newDisableCmd())
 	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newImpactCmd())
+	cmd.AddCommand(newBugReportCmd())
+	cmd.AddCommand(newTemplateTestCmd())
+	cmd.AddCommand(newWebhookDevCmd())
+	cmd.AddCommand(newWebhookSoakCmd())
+	cmd.AddCommand(newWebhookInjectCmd())
+	cmd.AddCommand(newWebhookValidateCmd())
+	cmd.AddCommand(newWebhookEncryptValuesCmd())
+	cmd.AddCommand(newWebhookGenConfigCmd())
 
 	return cmd
 }
@@ -0,0 +1,346 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/istio/pkg/kube"
+	"istio.io/pkg/version"
+)
+
+const (
+	bundleManifestName = "manifest.json"
+
+	// injectionMetricsPrefix selects the injector metrics from the Istiod metrics.
+	injectionMetricsPrefix = "sidecar_injection_"
+)
+
+// omittedInjectorConfigKeys are the keys of the injection ConfigMap left out
+// of the support bundle: the Helm values may hold credentials, e.g. registry
+// secrets or proxy environment values.
+var omittedInjectorConfigKeys = map[string]bool{"values": true}
+
+// bundleEntry describes a file of the support bundle, or why it is missing.
+type bundleEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Error       string `json:"error,omitempty"`
+}
+
+type bundleManifest struct {
+	Created         time.Time     `json:"created"`
+	IstioctlVersion string        `json:"istioctlVersion"`
+	IstioNamespace  string        `json:"istioNamespace"`
+	Entries         []bundleEntry `json:"entries"`
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// supportBundle collects the files of an injector support bundle. Files that
+// cannot be collected are recorded in the manifest with the error.
+type supportBundle struct {
+	manifest bundleManifest
+	files    []bundleFile
+}
+
+func newSupportBundle(istioNamespace string) *supportBundle {
+	return &supportBundle{manifest: bundleManifest{
+		Created:         time.Now().UTC(),
+		IstioctlVersion: version.Info.Version,
+		IstioNamespace:  istioNamespace,
+	}}
+}
+
+func (b *supportBundle) add(name, description string, data []byte, err error) {
+	entry := bundleEntry{Name: name, Description: description}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		b.files = append(b.files, bundleFile{name: name, data: data})
+	}
+	b.manifest.Entries = append(b.manifest.Entries, entry)
+}
+
+// write writes the bundle as a gzipped tar archive, starting with the manifest.
+func (b *supportBundle) write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	files := append([]bundleFile{{name: bundleManifestName, data: manifest}}, b.files...)
+	for _, f := range files {
+		header := &tar.Header{
+			Name:    f.name,
+			Mode:    0644,
+			Size:    int64(len(f.data)),
+			ModTime: b.manifest.Created,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+func newBugReportCmd() *cobra.Command {
+	var (
+		output     string
+		configName string
+	)
+	cmd := &cobra.Command{
+		Use:   "bug-report",
+		Short: "Collect a support bundle of the sidecar injector",
+		Long: "This command collects the injection configuration, recent admission decisions, webhook\n" +
+			"configurations with their certificate metadata, injector metrics and Istiod logs into a\n" +
+			"single archive with a manifest, to attach to issues. Pod contents and the injection values\n" +
+			"are not collected, and webhook caBundles are replaced by the metadata of their certificates.",
+		Example: `  # Collect a support bundle of the injector
+  istioctl experimental post-install webhook bug-report
+
+  # Collect a support bundle of the injector of a revision
+  istioctl experimental post-install webhook bug-report --injection-config istio-sidecar-injector-canary`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := kubeClient(kubeconfig, configContext)
+			if err != nil {
+				return fmt.Errorf("failed to create Kubernetes client: %v", err)
+			}
+			bundle := newSupportBundle(istioNamespace)
+			collectInjectorConfig(bundle, client.Kube(), istioNamespace, configName)
+			collectWebhooks(bundle, client.Kube(), istioNamespace)
+			collectIstiod(bundle, client, istioNamespace)
+
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			if err := bundle.write(f); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("failed to write %s: %v", output, err)
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			failed := 0
+			for _, e := range bundle.manifest.Entries {
+				if e.Error != "" {
+					failed++
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s with %d files, %d could not be collected (see %s)\n",
+				output, len(bundle.files), failed, bundleManifestName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "sidecar-injector-bug-report.tar.gz",
+		"The path of the archive to write.")
+	cmd.Flags().StringVar(&configName, "injection-config", "istio-sidecar-injector",
+		"The name of the ConfigMap holding the injection configuration.")
+
+	return cmd
+}
+
+// collectInjectorConfig adds the files of the injection ConfigMap.
+func collectInjectorConfig(b *supportBundle, client kubernetes.Interface, istioNamespace, configName string) {
+	cm, err := client.CoreV1().ConfigMaps(istioNamespace).Get(context.TODO(), configName, metav1.GetOptions{})
+	if err != nil {
+		b.add(path.Join("config", configName), "Injection ConfigMap", nil, err)
+		return
+	}
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		if !omittedInjectorConfigKeys[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.add(path.Join("config", configName, k), fmt.Sprintf("Key %s of the injection ConfigMap", k), []byte(cm.Data[k]), nil)
+	}
+}
+
+// certMetadata describes a certificate of a webhook caBundle.
+type certMetadata struct {
+	Webhook      string    `json:"webhook"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serialNumber"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	DNSNames     []string  `json:"dnsNames,omitempty"`
+}
+
+func caBundleMetadata(webhook string, bundle []byte) ([]certMetadata, error) {
+	var certs []certMetadata
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caBundle of webhook %s: %v", webhook, err)
+		}
+		certs = append(certs, certMetadata{
+			Webhook:      webhook,
+			Subject:      cert.Subject.String(),
+			Issuer:       cert.Issuer.String(),
+			SerialNumber: cert.SerialNumber.String(),
+			NotBefore:    cert.NotBefore,
+			NotAfter:     cert.NotAfter,
+			DNSNames:     cert.DNSNames,
+		})
+	}
+	return certs, nil
+}
+
+// collectWebhooks adds the mutating webhook configurations served from the
+// Istio namespace, with their caBundles replaced by certificate metadata.
+func collectWebhooks(b *supportBundle, client kubernetes.Interface, istioNamespace string) {
+	configs, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		b.add("webhooks", "Injection webhook configurations", nil, err)
+		return
+	}
+	certs := []certMetadata{}
+	var certErr error
+	for _, config := range configs.Items {
+		served := false
+		config := config.DeepCopy()
+		for i := range config.Webhooks {
+			wh := &config.Webhooks[i]
+			if wh.ClientConfig.Service == nil || wh.ClientConfig.Service.Namespace != istioNamespace {
+				continue
+			}
+			served = true
+			md, err := caBundleMetadata(wh.Name, wh.ClientConfig.CABundle)
+			if err != nil {
+				certErr = err
+			}
+			certs = append(certs, md...)
+			wh.ClientConfig.CABundle = nil
+		}
+		if !served {
+			continue
+		}
+		config.ManagedFields = nil
+		data, err := json.MarshalIndent(config, "", "  ")
+		b.add(path.Join("webhooks", config.Name+".json"), "Injection webhook configuration", data, err)
+	}
+	data, err := json.MarshalIndent(certs, "", "  ")
+	if err == nil {
+		err = certErr
+	}
+	b.add("certs.json", "Certificates of the webhook caBundles", data, err)
+}
+
+// filterMetrics returns the lines of the exposition text describing injector metrics.
+func filterMetrics(metrics []byte) []byte {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		name := strings.TrimPrefix(strings.TrimPrefix(line, "# HELP "), "# TYPE ")
+		if strings.HasPrefix(name, injectionMetricsPrefix) || strings.HasPrefix(name, "istio_"+injectionMetricsPrefix) {
+			out.WriteString(line)
+			out.WriteByte('\n')
+		}
+	}
+	return out.Bytes()
+}
+
+// collectIstiod adds the active template, recent decisions, metrics and
+// logs of each Istiod instance.
+func collectIstiod(b *supportBundle, client kube.ExtendedClient, istioNamespace string) {
+	debug := []struct {
+		path, dir, ext, description string
+		filter                      func([]byte) []byte
+	}{
+		{"/debug/inject", "template", ".yaml", "Active injection template", nil},
+		{"/debug/inject_decisions", "decisions", ".json", "Recent admission decisions, without pod contents", nil},
+		{"/metrics", "metrics", ".txt", "Injector metrics snapshot", filterMetrics},
+	}
+	for _, d := range debug {
+		results, err := client.AllDiscoveryDo(context.TODO(), istioNamespace, d.path)
+		if err != nil {
+			b.add(d.dir, d.description, nil, err)
+			continue
+		}
+		names := make([]string, 0, len(results))
+		for name := range results {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			data := results[name]
+			if d.filter != nil {
+				data = d.filter(data)
+			}
+			b.add(path.Join(d.dir, name+d.ext), d.description+" of "+name, data, nil)
+		}
+	}
+
+	pods, err := client.GetIstioPods(context.TODO(), istioNamespace, map[string]string{
+		"labelSelector": "app=istiod",
+		"fieldSelector": "status.phase=Running",
+	})
+	if err == nil && len(pods) == 0 {
+		err = errors.New("unable to find any Istiod instances")
+	}
+	if err != nil {
+		b.add("logs", "Istiod logs", nil, err)
+		return
+	}
+	for _, pod := range pods {
+		logs, err := client.PodLogs(context.TODO(), pod.Name, pod.Namespace, "discovery", false)
+		b.add(path.Join("logs", pod.Name+".log"), "Logs of "+pod.Name, []byte(logs), err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// readBundle returns the files of a written support bundle.
+func readBundle(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+	gzr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = data
+	}
+}
+
+func testCABundle(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{Organization: []string{"cluster.local"}},
+		NotBefore:    time.Unix(0, 0).UTC(),
+		NotAfter:     time.Unix(3600, 0).UTC(),
+		DNSNames:     []string{"istiod.istio-system.svc"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestSupportBundle(t *testing.T) {
+	b := newSupportBundle("istio-system")
+	b.add("config/config", "Injection config", []byte("policy: enabled"), nil)
+	b.add("logs", "Istiod logs", nil, errors.New("no istiod"))
+
+	var out bytes.Buffer
+	if err := b.write(&out); err != nil {
+		t.Fatal(err)
+	}
+	files := readBundle(t, out.Bytes())
+	if len(files) != 2 || string(files["config/config"]) != "policy: enabled" {
+		t.Fatalf("unexpected files %v", files)
+	}
+	var manifest bundleManifest
+	if err := json.Unmarshal(files[bundleManifestName], &manifest); err != nil {
+		t.Fatal(err)
+	}
+	want := []bundleEntry{
+		{Name: "config/config", Description: "Injection config"},
+		{Name: "logs", Description: "Istiod logs", Error: "no istiod"},
+	}
+	if !reflect.DeepEqual(manifest.Entries, want) {
+		t.Fatalf("got manifest entries %+v, want %+v", manifest.Entries, want)
+	}
+}
+
+func TestCollectWebhooks(t *testing.T) {
+	service := func(namespace string) v1beta1.WebhookClientConfig {
+		return v1beta1.WebhookClientConfig{
+			Service:  &v1beta1.ServiceReference{Namespace: namespace, Name: "istiod"},
+			CABundle: testCABundle(t),
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&v1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+			Webhooks:   []v1beta1.MutatingWebhook{{Name: "sidecar-injector.istio.io", ClientConfig: service("istio-system")}},
+		},
+		&v1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Webhooks:   []v1beta1.MutatingWebhook{{Name: "other.example.com", ClientConfig: service("other")}},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector", Namespace: "istio-system"},
+			Data:       map[string]string{"config": "policy: enabled", "values": "{}"},
+		},
+	)
+
+	b := newSupportBundle("istio-system")
+	collectInjectorConfig(b, client, "istio-system", "istio-sidecar-injector")
+	collectWebhooks(b, client, "istio-system")
+	files := map[string][]byte{}
+	for _, f := range b.files {
+		files[f.name] = f.data
+	}
+	for _, name := range []string{"config/istio-sidecar-injector/config", "webhooks/istio-sidecar-injector.json", "certs.json"} {
+		if _, f := files[name]; !f {
+			t.Fatalf("missing %s in %v", name, b.manifest.Entries)
+		}
+	}
+	if _, f := files["config/istio-sidecar-injector/values"]; f {
+		t.Fatalf("unexpected injection values in the bundle")
+	}
+	if _, f := files["webhooks/other.json"]; f {
+		t.Fatalf("unexpected webhook configuration served outside of the Istio namespace")
+	}
+
+	var config v1beta1.MutatingWebhookConfiguration
+	if err := json.Unmarshal(files["webhooks/istio-sidecar-injector.json"], &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Webhooks[0].ClientConfig.CABundle) != 0 {
+		t.Fatalf("caBundle not removed")
+	}
+	var certs []certMetadata
+	if err := json.Unmarshal(files["certs.json"], &certs); err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 || certs[0].Webhook != "sidecar-injector.istio.io" || certs[0].SerialNumber != "42" ||
+		!certs[0].NotAfter.Equal(time.Unix(3600, 0)) {
+		t.Fatalf("unexpected certificate metadata %+v", certs)
+	}
+}
+
+func TestFilterMetrics(t *testing.T) {
+	metrics := `# HELP pilot_xds Number of endpoints connected to this pilot using XDS.
+# TYPE pilot_xds gauge
+pilot_xds 3
+# HELP sidecar_injection_requests_total Total number of sidecar injection requests.
+# TYPE sidecar_injection_requests_total counter
+sidecar_injection_requests_total 7
+`
+	want := `# HELP sidecar_injection_requests_total Total number of sidecar injection requests.
+# TYPE sidecar_injection_requests_total counter
+sidecar_injection_requests_total 7
+`
+	if got := string(filterMetrics([]byte(metrics))); got != want {
+		t.Fatalf("got metrics %q, want %q", got, want)
+	}
+}
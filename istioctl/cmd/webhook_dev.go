@@ -0,0 +1,249 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/api/admissionregistration/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/log"
+)
+
+const (
+	devWebhookConfigName = "istio-sidecar-injector-dev"
+	devWebhookName       = "sidecar-injector-dev.istio.io"
+	devCertTTL           = 24 * time.Hour
+)
+
+type webhookDevOptions struct {
+	injectConfigFile string
+	valuesFile       string
+	meshConfigFile   string
+	listenAddress    string
+	webhookURL       string
+	namespaceLabel   string
+}
+
+func newWebhookDevCmd() *cobra.Command {
+	opts := webhookDevOptions{}
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Run the sidecar injector locally against a development cluster",
+		Long: "This command runs the sidecar injector on this machine, for iterating on injection against a\n" +
+			"development cluster, such as kind, without building and deploying images. It serves injection\n" +
+			"with a self-signed certificate and registers the " + devWebhookConfigName + " webhook config\n" +
+			"calling it at --webhookURL for the namespaces with the --namespaceLabel label. The injection\n" +
+			"configuration files are reloaded when edited. All logging is at debug level. The webhook config\n" +
+			"is deleted on exit.",
+		Example: `  # Inject the pods of the namespaces labelled istio-dev-injection=enabled of a kind cluster,
+  # which reaches the host at 172.17.0.1
+  istioctl experimental post-install webhook dev --injectConfigFile inject-config.yaml \
+    --valuesFile values.json --listen 172.17.0.1:9443 --webhookURL https://172.17.0.1:9443/inject`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.injectConfigFile == "" || opts.valuesFile == "" {
+				return errors.New("--injectConfigFile and --valuesFile are required")
+			}
+			if opts.webhookURL == "" {
+				return errors.New("--webhookURL is required")
+			}
+			return runWebhookDev(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.injectConfigFile, "injectConfigFile", "",
+		"Injection configuration filename, reloaded when edited.")
+	cmd.Flags().StringVar(&opts.valuesFile, "valuesFile", "",
+		"Injection values configuration filename, reloaded when edited.")
+	cmd.Flags().StringVar(&opts.meshConfigFile, "meshConfigFile", "",
+		"Mesh configuration filename. Read from the cluster if not set.")
+	cmd.Flags().StringVar(&opts.listenAddress, "listen", "127.0.0.1:9443",
+		"Address the injector listens on. The cluster must reach it at --webhookURL, so a remote "+
+			"or containerized cluster needs an address other than the default localhost one.")
+	cmd.Flags().StringVar(&opts.webhookURL, "webhookURL", "",
+		"HTTPS URL of the injector as reached from the API server, with the /inject path.")
+	cmd.Flags().StringVar(&opts.namespaceLabel, "namespaceLabel", "istio-dev-injection=enabled",
+		"Label selecting the namespaces injected by the local injector.")
+
+	return cmd
+}
+
+// devWebhookConfig returns the webhook config calling the local injector at
+// webhookURL for the namespaces with the label.
+func devWebhookConfig(webhookURL, namespaceLabel string, caBundle []byte) (*v1beta1.MutatingWebhookConfiguration, error) {
+	if u, err := url.Parse(webhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("--webhookURL %q must be an https URL", webhookURL)
+	}
+	kv := strings.SplitN(namespaceLabel, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return nil, fmt.Errorf("--namespaceLabel %q must be key=value", namespaceLabel)
+	}
+	failurePolicy := v1beta1.Ignore
+	sideEffects := v1beta1.SideEffectClassNone
+	return &v1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: devWebhookConfigName},
+		Webhooks: []v1beta1.MutatingWebhook{{
+			Name:                    devWebhookName,
+			ClientConfig:            v1beta1.WebhookClientConfig{URL: &webhookURL, CABundle: caBundle},
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1beta1", "v1"},
+			NamespaceSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{kv[0]: kv[1]}},
+			Rules: []v1beta1.RuleWithOperations{{
+				Operations: []v1beta1.OperationType{v1beta1.Create},
+				Rule: v1beta1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+				},
+			}},
+		}},
+	}, nil
+}
+
+// devCertificate returns a self-signed certificate for the local addresses
+// and the host of the webhook URL.
+func devCertificate(webhookURL string) (tls.Certificate, []byte, error) {
+	hosts := []string{"localhost", "127.0.0.1"}
+	if u, err := url.Parse(webhookURL); err == nil && u.Hostname() != "" {
+		hosts = append(hosts, u.Hostname())
+	}
+	certPEM, keyPEM, err := util.GenCertKeyFromOptions(util.CertOptions{
+		Host:         strings.Join(hosts, ","),
+		TTL:          devCertTTL,
+		Org:          "istioctl webhook dev",
+		RSAKeySize:   2048,
+		IsCA:         true,
+		IsSelfSigned: true,
+		IsServer:     true,
+	})
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	return cert, certPEM, err
+}
+
+func applyDevWebhookConfig(client kubernetes.Interface, config *v1beta1.MutatingWebhookConfiguration) error {
+	configs := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	current, err := configs.Get(context.TODO(), config.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configs.Create(context.TODO(), config, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	current.Webhooks = config.Webhooks
+	_, err = configs.Update(context.TODO(), current, metav1.UpdateOptions{})
+	return err
+}
+
+func runWebhookDev(cmd *cobra.Command, opts webhookDevOptions) error {
+	for _, s := range log.Scopes() {
+		s.SetOutputLevel(log.DebugLevel)
+	}
+
+	client, err := interfaceFactory(kubeconfig)
+	if err != nil {
+		return err
+	}
+	var meshConfig *meshconfig.MeshConfig
+	if opts.meshConfigFile != "" {
+		meshConfig, err = mesh.ReadMeshConfig(opts.meshConfigFile)
+	} else {
+		meshConfig, err = getMeshConfigFromConfigMap(kubeconfig, "webhook dev")
+	}
+	if err != nil {
+		return err
+	}
+
+	cert, caBundle, err := devCertificate(opts.webhookURL)
+	if err != nil {
+		return fmt.Errorf("failed to generate the serving certificate: %v", err)
+	}
+	config, err := devWebhookConfig(opts.webhookURL, opts.namespaceLabel, caBundle)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	wh, err := inject.NewWebhook(inject.WebhookParameters{
+		ConfigFile:     opts.injectConfigFile,
+		ValuesFile:     opts.valuesFile,
+		Env:            &model.Environment{Watcher: mesh.NewFixedWatcher(meshConfig)},
+		MonitoringPort: -1,
+		Mux:            mux,
+		KubeClient:     client,
+	})
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", opts.listenAddress)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:   mux,
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go wh.Run(stop)
+	go func() {
+		if err := server.ServeTLS(listener, "", ""); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Injection server failed: %v", err)
+		}
+	}()
+	defer server.Close()
+
+	if err := applyDevWebhookConfig(client, config); err != nil {
+		return fmt.Errorf("failed to register the %s webhook config: %v", devWebhookConfigName, err)
+	}
+	defer func() {
+		err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Delete(
+			context.TODO(), devWebhookConfigName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Failed to delete the %s webhook config: %v\n", devWebhookConfigName, err)
+		}
+	}()
+	fmt.Fprintf(cmd.OutOrStdout(), "Injecting the pods of the namespaces labelled %s, served on %s as %s. Press Ctrl-C to stop.\n",
+		opts.namespaceLabel, listener.Addr(), opts.webhookURL)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
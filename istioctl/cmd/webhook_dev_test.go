@@ -0,0 +1,94 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDevWebhookConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		webhookURL     string
+		namespaceLabel string
+		wantErr        bool
+	}{
+		{"valid", "https://172.17.0.1:9443/inject", "istio-dev-injection=enabled", false},
+		{"http url", "http://172.17.0.1:9443/inject", "istio-dev-injection=enabled", true},
+		{"invalid label", "https://172.17.0.1:9443/inject", "istio-dev-injection", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, err := devWebhookConfig(c.webhookURL, c.namespaceLabel, []byte("fake CA"))
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := config.Webhooks[0]
+			if *w.ClientConfig.URL != c.webhookURL || w.NamespaceSelector.MatchLabels["istio-dev-injection"] != "enabled" {
+				t.Fatalf("unexpected webhook %+v", w)
+			}
+		})
+	}
+}
+
+func TestDevCertificate(t *testing.T) {
+	cert, caBundle, err := devCertificate("https://172.17.0.1:9443/inject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caBundle)
+	for _, host := range []string{"localhost", "127.0.0.1", "172.17.0.1"} {
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("certificate not valid for %s: %v", host, err)
+		}
+	}
+}
+
+func TestApplyDevWebhookConfig(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	for _, caBundle := range []string{"CA 1", "CA 2"} {
+		config, err := devWebhookConfig("https://localhost:9443/inject", "istio-dev-injection=enabled", []byte(caBundle))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := applyDevWebhookConfig(client, config); err != nil {
+			t.Fatal(err)
+		}
+		got, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(
+			context.TODO(), devWebhookConfigName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got.Webhooks[0].ClientConfig.CABundle) != caBundle {
+			t.Fatalf("got CA bundle %q, want %q", got.Webhooks[0].ClientConfig.CABundle, caBundle)
+		}
+	}
+}
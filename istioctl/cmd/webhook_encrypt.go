@@ -0,0 +1,64 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"istio.io/istio/pkg/kube/inject"
+)
+
+func newWebhookEncryptValuesCmd() *cobra.Command {
+	var valuesFile, keyFile string
+	cmd := &cobra.Command{
+		Use:   "encrypt-values",
+		Short: "Encrypt an injection values file",
+		Long: "This command encrypts an injection values file with a random data key, itself encrypted with\n" +
+			"the AES-256 key of --keyFile, and prints the encrypted file. The encrypted file can be stored\n" +
+			"in Git and in the injection ConfigMap: istiod decrypts it in memory when INJECT_VALUES_KEY_FILE\n" +
+			"is set to the same key, e.g. mounted from a Secret.",
+		Example: `  # Create a key and encrypt the values with it
+  head -c 32 /dev/urandom | base64 > values.key
+  istioctl experimental post-install webhook encrypt-values --valuesFile values.json --keyFile values.key`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if valuesFile == "" || keyFile == "" {
+				return errors.New("--valuesFile and --keyFile are required")
+			}
+			keys, err := inject.NewFileKeyProvider(keyFile)
+			if err != nil {
+				return err
+			}
+			values, err := ioutil.ReadFile(valuesFile)
+			if err != nil {
+				return err
+			}
+			encrypted, err := inject.EncryptValues(values, keys)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(encrypted))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&valuesFile, "valuesFile", "", "Injection values configuration filename.")
+	cmd.Flags().StringVar(&keyFile, "keyFile", "", "File of the base64 encoded AES-256 key.")
+
+	return cmd
+}
@@ -0,0 +1,124 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/webhooks"
+)
+
+// webhookTemplateConfigMapKey is the key of the injector ConfigMap holding the
+// webhook config template istiod manages.
+const webhookTemplateConfigMapKey = "webhook"
+
+func newWebhookGenConfigCmd() *cobra.Command {
+	var (
+		caCertFile, templateFile, injectorConfigMap  string
+		failurePolicy, reinvocationPolicy, entrySpec string
+		options                                      = webhooks.InjectionWebhookConfigOptions{}
+	)
+	cmd := &cobra.Command{
+		Use:   "gen-config",
+		Short: "Generate the injection webhook configuration",
+		Long: "This command prints the MutatingWebhookConfiguration istiod creates with INJECT_MANAGE_WEBHOOK_CONFIG,\n" +
+			"from the same template: the webhook key of the injector ConfigMap of the revision, or --templateFile.\n" +
+			"The webhooks trust the CA certificate of --caCertFile. Pass the --failurePolicy and --reinvocationPolicy\n" +
+			"flags and the INJECT_WEBHOOK_ENTRY_SPEC file istiod runs with, so the generated configuration is not\n" +
+			"patched once applied.",
+		Example: `  # Generate the webhook configuration of the canary revision from its injector ConfigMap
+  istioctl experimental post-install webhook gen-config --caCertFile ca.pem --revision canary | kubectl apply -f -
+
+  # Generate the webhook configuration from the webhook key of a rendered injector ConfigMap
+  istioctl experimental post-install webhook gen-config --caCertFile ca.pem --templateFile webhook.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if caCertFile == "" {
+				return errors.New("--caCertFile is required")
+			}
+			caBundle, err := ioutil.ReadFile(caCertFile)
+			if err != nil {
+				return fmt.Errorf("unable to read the CA certificate: %v", err)
+			}
+			options.CABundle = caBundle
+			if templateFile != "" {
+				if options.Template, err = ioutil.ReadFile(templateFile); err != nil {
+					return fmt.Errorf("unable to read the webhook config template: %v", err)
+				}
+			} else if options.Template, err = webhookConfigTemplateFromCluster(injectorConfigMap, options.Revision); err != nil {
+				return err
+			}
+			if options.Policies, err = webhooks.ParseWebhookPolicies(failurePolicy, reinvocationPolicy); err != nil {
+				return err
+			}
+			if entrySpec != "" {
+				if options.Policies, err = webhooks.LoadWebhookEntrySpec(entrySpec, options.Policies); err != nil {
+					return err
+				}
+			}
+			config, err := webhooks.GenerateInjectionWebhookConfig(options)
+			if err != nil {
+				return err
+			}
+			out, err := yaml.Marshal(config)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&caCertFile, "caCertFile", "", "File of the PEM encoded CA certificate of the injection webhook.")
+	cmd.Flags().StringVar(&options.Name, "webhookConfigName", "istio-sidecar-injector", "Name of the MutatingWebhookConfiguration.")
+	cmd.Flags().StringVar(&templateFile, "templateFile", "",
+		"File of the webhook config template, the INJECT_WEBHOOK_CONFIG_TEMPLATE of istiod. Read from the cluster when empty.")
+	cmd.Flags().StringVar(&injectorConfigMap, "injectConfigMapName", defaultInjectConfigMapName,
+		"Name of the injector ConfigMap, in the Istio namespace, whose webhook key is the template. Suffixed with the revision.")
+	cmd.Flags().StringVar(&failurePolicy, "failurePolicy", "", "Failure policy of the webhook, Fail or Ignore. Defaults to the template.")
+	cmd.Flags().StringVar(&reinvocationPolicy, "reinvocationPolicy", "", "Reinvocation policy of the webhook, Never or IfNeeded.")
+	cmd.Flags().StringVar(&entrySpec, "entrySpec", "", "Webhook entry spec file overriding the selectors and rules.")
+	cmd.Flags().StringVar(&options.Revision, "revision", "", "Control plane revision, only the pods of the revision are injected when set.")
+
+	return cmd
+}
+
+// webhookConfigTemplateFromCluster returns the webhook config template of the
+// injector ConfigMap of the revision.
+func webhookConfigTemplateFromCluster(configMapName, revision string) ([]byte, error) {
+	if revision != "" {
+		configMapName = configMapName + "-" + revision
+	}
+	client, err := interfaceFactory(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := client.CoreV1().ConfigMaps(istioNamespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the injector ConfigMap %s/%s, use --templateFile: %v", istioNamespace, configMapName, err)
+	}
+	template, f := cm.Data[webhookTemplateConfigMapKey]
+	if !f {
+		return nil, fmt.Errorf("injector ConfigMap %s/%s has no %s key, use --templateFile",
+			istioNamespace, configMapName, webhookTemplateConfigMapKey)
+	}
+	return []byte(template), nil
+}
@@ -0,0 +1,200 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// defaults of the admissionregistration.k8s.io/v1beta1 API
+	defaultWebhookFailurePolicy  = v1beta1.Ignore
+	defaultWebhookTimeoutSeconds = 30
+
+	// maxSafeWebhookTimeoutSeconds is the timeout above which a webhook that
+	// is down noticeably delays pod creation.
+	maxSafeWebhookTimeoutSeconds = 10
+)
+
+// webhookImpact is what happens to pod creation in the namespaces matched by
+// a webhook while the webhook is unavailable.
+type webhookImpact struct {
+	webhook        string
+	failurePolicy  v1beta1.FailurePolicyType
+	timeoutSeconds int32
+	objectSelector bool
+	namespaces     []string
+	// allNamespaces is true if the webhook matches every namespace of the cluster.
+	allNamespaces bool
+	risks         []string
+}
+
+func (w webhookImpact) outcome() string {
+	if w.failurePolicy == v1beta1.Fail {
+		return "pod creation rejected"
+	}
+	return "pods created without sidecar"
+}
+
+func newImpactCmd() *cobra.Command {
+	var configName string
+	cmd := &cobra.Command{
+		Use:   "impact",
+		Short: "Report the impact of an unavailable injection webhook on pod creation",
+		Long: "This command inspects the failure policy, namespace selector and timeout of the injection\n" +
+			"webhooks and reports what would happen to pod creation in each namespace if the injector\n" +
+			"went down, flagging risky combinations.",
+		Example: `  # Report the impact of the default injection webhook being unavailable
+  istioctl experimental post-install webhook impact
+
+  # Report the impact for the webhook of a revision
+  istioctl experimental post-install webhook impact --injection-config istio-sidecar-injector-canary`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createInterface(kubeconfig)
+			if err != nil {
+				return fmt.Errorf("err when creating Kubernetes client interface: %v", err)
+			}
+			impacts, err := webhookImpacts(client, configName)
+			if err != nil {
+				return err
+			}
+			printWebhookImpacts(cmd.OutOrStdout(), impacts)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&configName, "injection-config", "istio-sidecar-injector",
+		"The name of the MutatingWebhookConfiguration to inspect.")
+
+	return cmd
+}
+
+// webhookImpacts returns the impact of each webhook of the configuration
+// being unavailable.
+func webhookImpacts(client kubernetes.Interface, configName string) ([]webhookImpact, error) {
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(
+		context.TODO(), configName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting MutatingWebhookConfiguration %v: %v", configName, err)
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing namespaces: %v", err)
+	}
+
+	impacts := make([]webhookImpact, 0, len(config.Webhooks))
+	for _, wh := range config.Webhooks {
+		impact := webhookImpact{
+			webhook:        wh.Name,
+			failurePolicy:  defaultWebhookFailurePolicy,
+			timeoutSeconds: defaultWebhookTimeoutSeconds,
+			objectSelector: wh.ObjectSelector != nil && (len(wh.ObjectSelector.MatchLabels) > 0 ||
+				len(wh.ObjectSelector.MatchExpressions) > 0),
+		}
+		if wh.FailurePolicy != nil {
+			impact.failurePolicy = *wh.FailurePolicy
+		}
+		if wh.TimeoutSeconds != nil {
+			impact.timeoutSeconds = *wh.TimeoutSeconds
+		}
+
+		selector := labels.Everything()
+		if wh.NamespaceSelector != nil {
+			if selector, err = metav1.LabelSelectorAsSelector(wh.NamespaceSelector); err != nil {
+				return nil, fmt.Errorf("invalid namespace selector of webhook %v: %v", wh.Name, err)
+			}
+		}
+		for _, ns := range namespaces.Items {
+			if selector.Matches(labels.Set(ns.Labels)) {
+				impact.namespaces = append(impact.namespaces, ns.Name)
+			}
+		}
+		sort.Strings(impact.namespaces)
+		impact.allNamespaces = len(namespaces.Items) > 0 && len(impact.namespaces) == len(namespaces.Items)
+
+		var self string
+		if wh.ClientConfig.Service != nil {
+			self = wh.ClientConfig.Service.Namespace
+		}
+		impact.risks = webhookRisks(impact, self)
+		impacts = append(impacts, impact)
+	}
+	return impacts, nil
+}
+
+// webhookRisks returns the risky combinations of the webhook settings.
+// injectorNamespace is the namespace of the webhook service, if known.
+func webhookRisks(impact webhookImpact, injectorNamespace string) []string {
+	var risks []string
+	inScope := func(ns string) bool {
+		for _, n := range impact.namespaces {
+			if n == ns {
+				return true
+			}
+		}
+		return false
+	}
+	if impact.failurePolicy == v1beta1.Fail {
+		if inScope(metav1.NamespaceSystem) {
+			risks = append(risks, fmt.Sprintf("failurePolicy Fail with %s in scope: cluster components cannot be "+
+				"recreated while the injector is down", metav1.NamespaceSystem))
+		}
+		if injectorNamespace != "" && inScope(injectorNamespace) {
+			risks = append(risks, fmt.Sprintf("failurePolicy Fail with the injector namespace %s in scope: "+
+				"the injector cannot be recreated while it is down", injectorNamespace))
+		}
+		if impact.allNamespaces && !impact.objectSelector {
+			risks = append(risks, "failurePolicy Fail matching all namespaces: every pod creation depends on the injector")
+		}
+	}
+	if impact.timeoutSeconds > maxSafeWebhookTimeoutSeconds {
+		risks = append(risks, fmt.Sprintf("timeout of %ds delays each pod creation while the injector is down",
+			impact.timeoutSeconds))
+	}
+	return risks
+}
+
+func printWebhookImpacts(writer io.Writer, impacts []webhookImpact) {
+	w := tabwriter.NewWriter(writer, 0, 8, 1, ' ', 0)
+	fmt.Fprintln(w, "WEBHOOK\tFAILURE POLICY\tTIMEOUT\tNAMESPACES\tWHEN UNAVAILABLE")
+	for _, i := range impacts {
+		outcome := i.outcome()
+		if i.objectSelector {
+			outcome += " (pods matching objectSelector)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%ds\t%d\t%s\n", i.webhook, i.failurePolicy, i.timeoutSeconds, len(i.namespaces), outcome)
+	}
+	_ = w.Flush()
+
+	for _, i := range impacts {
+		if len(i.namespaces) > 0 {
+			fmt.Fprintf(writer, "\n%s affects namespaces: %s\n", i.webhook, strings.Join(i.namespaces, ", "))
+		}
+		for _, r := range i.risks {
+			fmt.Fprintf(writer, "WARNING: %s: %s\n", i.webhook, r)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWebhookImpacts(t *testing.T) {
+	fail, ignore := v1beta1.Fail, v1beta1.Ignore
+	timeout := int32(5)
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	service := v1beta1.WebhookClientConfig{Service: &v1beta1.ServiceReference{Namespace: "istio-system", Name: "istiod"}}
+
+	client := fake.NewSimpleClientset(
+		namespace("kube-system", nil),
+		namespace("istio-system", nil),
+		namespace("default", map[string]string{"istio-injection": "enabled"}),
+		&v1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+			Webhooks: []v1beta1.MutatingWebhook{
+				{
+					Name:           "selected.sidecar-injector.istio.io",
+					ClientConfig:   service,
+					FailurePolicy:  &fail,
+					TimeoutSeconds: &timeout,
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"istio-injection": "enabled"},
+					},
+				},
+				{
+					Name:           "everything.sidecar-injector.istio.io",
+					ClientConfig:   service,
+					FailurePolicy:  &fail,
+					TimeoutSeconds: &timeout,
+				},
+				{
+					Name:          "defaults.sidecar-injector.istio.io",
+					ClientConfig:  service,
+					FailurePolicy: &ignore,
+				},
+			},
+		})
+
+	impacts, err := webhookImpacts(client, "istio-sidecar-injector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(impacts) != 3 {
+		t.Fatalf("got %d impacts, want 3", len(impacts))
+	}
+
+	selected, everything, defaults := impacts[0], impacts[1], impacts[2]
+	if !reflect.DeepEqual(selected.namespaces, []string{"default"}) || len(selected.risks) != 0 {
+		t.Fatalf("unexpected impact of selective webhook: %+v", selected)
+	}
+	if len(everything.namespaces) != 3 || len(everything.risks) != 3 {
+		t.Fatalf("unexpected impact of webhook matching all namespaces: %+v", everything)
+	}
+	if defaults.timeoutSeconds != defaultWebhookTimeoutSeconds || len(defaults.risks) != 1 {
+		t.Fatalf("unexpected impact of webhook with default timeout: %+v", defaults)
+	}
+
+	var out bytes.Buffer
+	printWebhookImpacts(&out, impacts)
+	for _, want := range []string{"pod creation rejected", "pods created without sidecar", "WARNING: everything"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("report does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWebhookImpactsNotFound(t *testing.T) {
+	if _, err := webhookImpacts(fake.NewSimpleClientset(), "missing"); err == nil {
+		t.Fatalf("expected error for missing webhook configuration")
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/istioctl/pkg/util/handlers"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/pkg/log"
+)
+
+type webhookInjectOptions struct {
+	filename         string
+	injectConfigFile string
+	valuesFile       string
+	meshConfigFile   string
+	useCluster       bool
+}
+
+func newWebhookInjectCmd() *cobra.Command {
+	opts := webhookInjectOptions{}
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "Preview the injection of the webhook into pod manifests",
+		Long: "This command sends the pods of a manifest to the sidecar injector webhook handler in process,\n" +
+			"as admission requests for their creation, and prints the pods with the patches returned\n" +
+			"applied. Unlike kube-inject, the pods go through the whole admission path of the webhook:\n" +
+			"injection policy, template rendering and patch generation. Pods the webhook does not inject are\n" +
+			"printed unchanged. With --useCluster, the namespace, owners and limit ranges of the pods are\n" +
+			"looked up in the cluster like the webhook does, otherwise they are ignored.",
+		Example: `  # Preview the injection of a pod
+  istioctl experimental post-install webhook inject -f pod.yaml \
+    --injectConfigFile inject-config.yaml --valuesFile values.json --meshConfigFile mesh.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.filename == "" {
+				return errors.New("filename not specified (see --filename or -f)")
+			}
+			if opts.injectConfigFile == "" || opts.valuesFile == "" {
+				return errors.New("--injectConfigFile and --valuesFile are required")
+			}
+			return runWebhookInject(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.filename, "filename", "f", "",
+		"Pod manifest filename, or - for the standard input.")
+	cmd.Flags().StringVar(&opts.injectConfigFile, "injectConfigFile", "",
+		"Injection configuration filename.")
+	cmd.Flags().StringVar(&opts.valuesFile, "valuesFile", "",
+		"Injection values configuration filename.")
+	cmd.Flags().StringVar(&opts.meshConfigFile, "meshConfigFile", "",
+		"Mesh configuration filename. Read from the cluster if not set.")
+	cmd.Flags().BoolVar(&opts.useCluster, "useCluster", false,
+		"Look up the namespace, owners and limit ranges of the pods in the cluster.")
+
+	return cmd
+}
+
+func runWebhookInject(cmd *cobra.Command, opts webhookInjectOptions) error {
+	// the webhook logs every request, keep the output to the pods
+	for _, s := range log.Scopes() {
+		s.SetOutputLevel(log.WarnLevel)
+	}
+
+	var in io.Reader = os.Stdin
+	if opts.filename != "-" {
+		f, err := os.Open(opts.filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var meshConfig *meshconfig.MeshConfig
+	var err error
+	if opts.meshConfigFile != "" {
+		meshConfig, err = mesh.ReadMeshConfig(opts.meshConfigFile)
+	} else {
+		meshConfig, err = getMeshConfigFromConfigMap(kubeconfig, "webhook inject")
+	}
+	if err != nil {
+		return err
+	}
+	var client kubernetes.Interface
+	if opts.useCluster {
+		if client, err = interfaceFactory(kubeconfig); err != nil {
+			return err
+		}
+	}
+
+	mux := http.NewServeMux()
+	if _, err := inject.NewWebhook(inject.WebhookParameters{
+		ConfigFile:     opts.injectConfigFile,
+		ValuesFile:     opts.valuesFile,
+		Env:            &model.Environment{Watcher: mesh.NewFixedWatcher(meshConfig)},
+		MonitoringPort: -1,
+		Mux:            mux,
+		KubeClient:     client,
+	}); err != nil {
+		return err
+	}
+	return webhookInjectManifest(mux, in, cmd.OutOrStdout())
+}
+
+// webhookInjectManifest injects the pods of the YAML manifest through the
+// webhook handler and writes them out as YAML.
+func webhookInjectManifest(handler http.Handler, in io.Reader, out io.Writer) error {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(in, 4096))
+	first := true
+	for {
+		raw, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		podJSON, err := yaml.YAMLToJSON(raw)
+		if err != nil {
+			return err
+		}
+		var pod corev1.Pod
+		if err := json.Unmarshal(podJSON, &pod); err != nil {
+			return err
+		}
+		if pod.Kind != "Pod" {
+			return fmt.Errorf("%s %s is not a pod: the webhook only injects pods", pod.Kind, pod.Name)
+		}
+		if pod.Namespace == "" {
+			pod.Namespace = handlers.HandleNamespace(namespace, defaultNamespace)
+		}
+		injected, err := webhookInjectPod(handler, &pod, podJSON)
+		if err != nil {
+			return fmt.Errorf("pod %s: %v", pod.Name, err)
+		}
+		if injected, err = yaml.JSONToYAML(injected); err != nil {
+			return err
+		}
+		if !first {
+			fmt.Fprintln(out, "---")
+		}
+		first = false
+		if _, err := out.Write(injected); err != nil {
+			return err
+		}
+	}
+}
+
+// webhookInjectPod sends the admission request of the creation of the pod to
+// the webhook handler, and returns the pod with the patch returned applied.
+func webhookInjectPod(handler http.Handler, pod *corev1.Pod, podJSON []byte) ([]byte, error) {
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "webhook-inject",
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: podJSON},
+		},
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+	req := httptest.NewRequest(http.MethodPost, "/inject", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return nil, fmt.Errorf("webhook returned %d: %s", rec.Code, bytes.TrimSpace(rec.Body.Bytes()))
+	}
+
+	var response admissionv1.AdmissionReview
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("invalid webhook response: %v", err)
+	}
+	if response.Response == nil {
+		return nil, errors.New("webhook returned no response")
+	}
+	if !response.Response.Allowed {
+		msg := "pod rejected"
+		if response.Response.Result != nil && response.Response.Result.Message != "" {
+			msg = response.Response.Result.Message
+		}
+		return nil, errors.New(msg)
+	}
+	if len(response.Response.Patch) == 0 {
+		return podJSON, nil
+	}
+	patch, err := jsonpatch.DecodePatch(response.Response.Patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook patch: %v", err)
+	}
+	return patch.Apply(podJSON)
+}
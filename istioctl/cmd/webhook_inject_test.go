@@ -0,0 +1,121 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeInjectionWebhook injects a proxy container into the pods, skips the
+// ones with injection disabled and rejects the ones named rejected.
+func fakeInjectionWebhook(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review admissionv1.AdmissionReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			t.Errorf("invalid admission review: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var pod corev1.Pod
+		if err := json.Unmarshal(review.Request.Object.Raw, &pod); err != nil {
+			t.Errorf("invalid pod: %v", err)
+		}
+		if review.Request.Operation != admissionv1.Create || review.Request.Name != pod.Name {
+			t.Errorf("unexpected admission request %+v", review.Request)
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		switch {
+		case pod.Name == "rejected":
+			response.Allowed = false
+			response.Result = &metav1.Status{Message: "injection failed"}
+		case pod.Annotations["sidecar.istio.io/inject"] != "false":
+			response.Patch = []byte(`[{"op":"add","path":"/spec/containers/-","value":{"name":"istio-proxy","image":"proxyv2"}}]`)
+		}
+		review.Response = response
+		_ = json.NewEncoder(w).Encode(review)
+	})
+}
+
+func TestWebhookInjectManifest(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: app
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: skipped
+  annotations:
+    sidecar.istio.io/inject: "false"
+spec:
+  containers:
+  - name: app
+    image: app
+`
+	out := &bytes.Buffer{}
+	if err := webhookInjectManifest(fakeInjectionWebhook(t), strings.NewReader(manifest), out); err != nil {
+		t.Fatal(err)
+	}
+	docs := strings.Split(out.String(), "---\n")
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 pods, got:\n%s", out)
+	}
+	if !strings.Contains(docs[0], "name: istio-proxy") {
+		t.Errorf("expected the first pod to be injected:\n%s", docs[0])
+	}
+	if strings.Contains(docs[1], "istio-proxy") || !strings.Contains(docs[1], "name: skipped") {
+		t.Errorf("expected the second pod to be unchanged:\n%s", docs[1])
+	}
+}
+
+func TestWebhookInjectManifestErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name:     "not a pod",
+			manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n",
+			wantErr:  "Deployment app is not a pod",
+		},
+		{
+			name:     "rejected",
+			manifest: "apiVersion: v1\nkind: Pod\nmetadata:\n  name: rejected\nspec:\n  containers:\n  - name: app\n",
+			wantErr:  "pod rejected: injection failed",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := webhookInjectManifest(fakeInjectionWebhook(t), strings.NewReader(c.manifest), &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("got error %v, want %q", err, c.wantErr)
+			}
+		})
+	}
+}
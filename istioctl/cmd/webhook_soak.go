@@ -0,0 +1,514 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/istio/pkg/kube/inject"
+)
+
+const (
+	// soakRunLabel marks the namespaces of a soak run, for their cleanup.
+	soakRunLabel = "istio.io/soak-run"
+	soakAppLabel = "app"
+	// soakDryRunName names the pods of the dry run creations.
+	soakDryRunName = "soak-dry-run"
+
+	// maxSoakErrors bounds the errors kept in the report.
+	maxSoakErrors = 20
+)
+
+type webhookSoakOptions struct {
+	duration          time.Duration
+	interval          time.Duration
+	namespaces        int
+	deployments       int
+	replicas          int
+	image             string
+	namespacePrefix   string
+	injectionLabel    string
+	injectorNamespace string
+	injectorSelector  string
+	timeout           time.Duration
+	reportFile        string
+}
+
+func newWebhookSoakCmd() *cobra.Command {
+	opts := webhookSoakOptions{}
+	cmd := &cobra.Command{
+		Use:   "soak",
+		Short: "Soak test the sidecar injector against a test cluster",
+		Long: "This command continuously creates and deletes batches of namespaces with deployments in a test\n" +
+			"cluster, for qualifying the injector for large clusters. Each batch times a dry run pod creation\n" +
+			"per namespace, checks the pods of the deployments are injected within --timeout, samples the\n" +
+			"resource usage of the injector pods from the metrics API, then deletes its namespaces. At the\n" +
+			"end, or on Ctrl-C, it prints a report of the injection success rate, latency and injector\n" +
+			"resource usage. Do not run it against a production cluster.",
+		Example: `  # Create 10 namespaces of 5 deployments every 30 seconds for 6 hours
+  istioctl experimental post-install webhook soak --duration 6h --interval 30s \
+    --namespaces 10 --deployments 5 --report soak-report.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.namespaces < 1 || opts.deployments < 1 || opts.replicas < 1 {
+				return errors.New("--namespaces, --deployments and --replicas must be positive")
+			}
+			if opts.duration <= 0 || opts.interval <= 0 || opts.timeout <= 0 {
+				return errors.New("--duration, --interval and --timeout must be positive")
+			}
+			if kv := strings.SplitN(opts.injectionLabel, "=", 2); len(kv) != 2 || kv[0] == "" {
+				return fmt.Errorf("--injectionLabel %q must be key=value", opts.injectionLabel)
+			}
+			if opts.injectorNamespace == "" {
+				opts.injectorNamespace = istioNamespace
+			}
+			client, err := interfaceFactory(kubeconfig)
+			if err != nil {
+				return err
+			}
+			return runWebhookSoak(cmd, client, opts)
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.duration, "duration", time.Hour,
+		"How long the soak test runs.")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 30*time.Second,
+		"Interval between the starts of the batches.")
+	cmd.Flags().IntVar(&opts.namespaces, "namespaces", 5,
+		"Number of namespaces created per batch.")
+	cmd.Flags().IntVar(&opts.deployments, "deployments", 2,
+		"Number of deployments created per namespace.")
+	cmd.Flags().IntVar(&opts.replicas, "replicas", 1,
+		"Number of replicas of each deployment.")
+	cmd.Flags().StringVar(&opts.image, "image", "k8s.gcr.io/pause:3.2",
+		"Image of the application container of the deployments.")
+	cmd.Flags().StringVar(&opts.namespacePrefix, "namespacePrefix", "istio-soak-",
+		"Prefix of the names of the namespaces created.")
+	cmd.Flags().StringVar(&opts.injectionLabel, "injectionLabel", "istio-injection=enabled",
+		"Label enabling injection of the namespaces created, e.g. istio.io/rev=canary.")
+	cmd.Flags().StringVar(&opts.injectorNamespace, "injectorNamespace", "",
+		"Namespace of the injector pods. Defaults to --istioNamespace.")
+	cmd.Flags().StringVar(&opts.injectorSelector, "injectorSelector", "app=istiod",
+		"Label selector of the injector pods whose resource usage is sampled.")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 2*time.Minute,
+		"How long the pods of a batch have to be created and injected.")
+	cmd.Flags().StringVar(&opts.reportFile, "report", "",
+		"File the report is written to as JSON, in addition to being printed.")
+
+	return cmd
+}
+
+// soakLatency summarizes the latencies of the dry run pod creations.
+type soakLatency struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// soakUsage is the peak and mean resource usage of the injector pods.
+type soakUsage struct {
+	Samples         int     `json:"samples"`
+	MaxCPUMillis    int64   `json:"maxCpuMillis"`
+	MeanCPUMillis   float64 `json:"meanCpuMillis"`
+	MaxMemoryBytes  int64   `json:"maxMemoryBytes"`
+	MeanMemoryBytes float64 `json:"meanMemoryBytes"`
+}
+
+type soakReport struct {
+	Started     time.Time     `json:"started"`
+	Duration    time.Duration `json:"duration"`
+	Batches     int           `json:"batches"`
+	Namespaces  int           `json:"namespaces"`
+	Pods        int           `json:"pods"`
+	Injected    int           `json:"injected"`
+	SuccessRate float64       `json:"successRate"`
+	// DryRuns are the dry run pod creations timed, and DryRunsInjected the
+	// ones returned with the proxy injected.
+	DryRuns         int         `json:"dryRuns"`
+	DryRunsInjected int         `json:"dryRunsInjected"`
+	Latency         soakLatency `json:"latency"`
+	Injector        soakUsage   `json:"injector"`
+	Errors          []string    `json:"errors,omitempty"`
+}
+
+// webhookSoak runs the batches of a soak test and accumulates the report.
+type webhookSoak struct {
+	client kubernetes.Interface
+	opts   webhookSoakOptions
+	run    string
+	now    func() time.Time
+	// podMetrics returns the metrics.k8s.io PodMetricsList of the injector pods.
+	podMetrics func(ctx context.Context) ([]byte, error)
+
+	// mu guards the results, accumulated by the concurrent batches.
+	mu        sync.Mutex
+	report    soakReport
+	latencies []time.Duration
+	cpuMillis []int64
+	memory    []int64
+}
+
+func runWebhookSoak(cmd *cobra.Command, client kubernetes.Interface, opts webhookSoakOptions) error {
+	// cmdCtx is canceled on Ctrl-C, interrupting the batches in flight
+	cmdCtx, cmdCancel := context.WithCancel(context.Background())
+	defer cmdCancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cmdCancel()
+		case <-cmdCtx.Done():
+		}
+	}()
+	// ctx ends the dispatch of new batches, the last ones run to completion
+	ctx, cancel := context.WithTimeout(cmdCtx, opts.duration)
+	defer cancel()
+
+	s := newWebhookSoak(client, opts, time.Now)
+	fmt.Fprintf(cmd.OutOrStdout(), "Running soak test %s for %v. Press Ctrl-C to stop early.\n", s.run, opts.duration)
+	// the batches are dispatched on the ticker, so a slow batch does not
+	// lower the rate of pod creations
+	var batches sync.WaitGroup
+	dispatch := func(batch int) {
+		batches.Add(1)
+		go func() {
+			defer batches.Done()
+			batchCtx, batchCancel := context.WithTimeout(cmdCtx, opts.timeout+opts.interval)
+			defer batchCancel()
+			s.runBatch(batchCtx, batch)
+			s.mu.Lock()
+			fmt.Fprintf(cmd.OutOrStdout(), "batch %d: %d/%d pods injected, %d errors\n",
+				batch, s.report.Injected, s.report.Pods, len(s.report.Errors))
+			s.mu.Unlock()
+		}()
+	}
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	dispatch(0)
+	for batch := 1; ; batch++ {
+		select {
+		case <-ctx.Done():
+			batches.Wait()
+			s.cleanup(cmd.ErrOrStderr())
+			report := s.summarize()
+			printSoakReport(cmd.OutOrStdout(), report)
+			if opts.reportFile != "" {
+				out, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				return ioutil.WriteFile(opts.reportFile, out, 0o644)
+			}
+			return nil
+		case <-ticker.C:
+			dispatch(batch)
+		}
+	}
+}
+
+func newWebhookSoak(client kubernetes.Interface, opts webhookSoakOptions, now func() time.Time) *webhookSoak {
+	start := now()
+	return &webhookSoak{
+		client: client,
+		opts:   opts,
+		run:    start.UTC().Format("20060102-150405"),
+		now:    now,
+		podMetrics: func(ctx context.Context) ([]byte, error) {
+			return client.CoreV1().RESTClient().Get().
+				AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", opts.injectorNamespace, "pods").
+				Param("labelSelector", opts.injectorSelector).
+				DoRaw(ctx)
+		},
+		report: soakReport{Started: start},
+	}
+}
+
+func (s *webhookSoak) errorf(format string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.report.Errors) < maxSoakErrors {
+		s.report.Errors = append(s.report.Errors, fmt.Sprintf(format, args...))
+	}
+}
+
+// runBatch creates the namespaces and deployments of a batch, measures their
+// injection and deletes them.
+func (s *webhookSoak) runBatch(ctx context.Context, batch int) {
+	s.mu.Lock()
+	s.report.Batches++
+	s.mu.Unlock()
+	kv := strings.SplitN(s.opts.injectionLabel, "=", 2)
+	var namespaces []string
+	defer func() {
+		for _, ns := range namespaces {
+			if err := s.client.CoreV1().Namespaces().Delete(context.TODO(), ns, metav1.DeleteOptions{}); err != nil &&
+				!apierrors.IsNotFound(err) {
+				s.errorf("failed to delete namespace %s: %v", ns, err)
+			}
+		}
+	}()
+
+	for i := 0; i < s.opts.namespaces; i++ {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s%s-%d-%d", s.opts.namespacePrefix, s.run, batch, i),
+			Labels: map[string]string{kv[0]: kv[1], soakRunLabel: s.run},
+		}}
+		if _, err := s.client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil {
+			s.errorf("failed to create namespace %s: %v", ns.Name, err)
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+		s.mu.Lock()
+		s.report.Namespaces++
+		s.mu.Unlock()
+		s.dryRun(ctx, ns.Name)
+		for d := 0; d < s.opts.deployments; d++ {
+			if _, err := s.client.AppsV1().Deployments(ns.Name).Create(ctx, s.deployment(d), metav1.CreateOptions{}); err != nil {
+				s.errorf("failed to create deployment %d in %s: %v", d, ns.Name, err)
+			}
+		}
+	}
+
+	s.waitForInjection(ctx, namespaces)
+	s.sampleInjector(ctx)
+}
+
+func (s *webhookSoak) podTemplate(name string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{soakAppLabel: name}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: s.opts.image}},
+		},
+	}
+}
+
+func (s *webhookSoak) deployment(i int) *appsv1.Deployment {
+	name := fmt.Sprintf("soak-%d", i)
+	replicas := int32(s.opts.replicas)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{soakAppLabel: name}},
+			Template: s.podTemplate(name),
+		},
+	}
+}
+
+// dryRun times a dry run pod creation in the namespace, which calls the
+// webhook like a real one without scheduling a pod.
+func (s *webhookSoak) dryRun(ctx context.Context, namespace string) {
+	template := s.podTemplate(soakDryRunName)
+	pod := &corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}
+	pod.Name = soakDryRunName
+	start := s.now()
+	created, err := s.client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		s.errorf("failed dry run pod creation in %s: %v", namespace, err)
+		return
+	}
+	latency := s.now().Sub(start)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, latency)
+	s.report.DryRuns++
+	if podInjected(created) {
+		s.report.DryRunsInjected++
+	}
+}
+
+func podInjected(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == inject.ProxyContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForInjection counts the pods of the deployments of the namespaces, and
+// the injected ones, once all are created or the batch times out.
+func (s *webhookSoak) waitForInjection(ctx context.Context, namespaces []string) {
+	want := s.opts.deployments * s.opts.replicas
+	var pods, injected int
+	deadline := s.now().Add(s.opts.timeout)
+	for {
+		pods, injected = 0, 0
+		for _, ns := range namespaces {
+			list, err := s.client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+				LabelSelector: soakAppLabel + "!=" + soakDryRunName,
+			})
+			if err != nil {
+				continue
+			}
+			for i := range list.Items {
+				pods++
+				if podInjected(&list.Items[i]) {
+					injected++
+				}
+			}
+		}
+		if pods >= want*len(namespaces) || !s.now().Before(deadline) || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	if ctx.Err() == context.Canceled {
+		// interrupted, the pods not created yet are not failures
+		return
+	}
+	if missing := want*len(namespaces) - pods; missing > 0 {
+		s.errorf("%d pods not created within %v", missing, s.opts.timeout)
+		pods += missing
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Pods += pods
+	s.report.Injected += injected
+}
+
+// podMetricsList is the subset of a metrics.k8s.io/v1beta1 PodMetricsList used.
+type podMetricsList struct {
+	Items []struct {
+		Containers []struct {
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// sampleInjector records the resource usage of the injector pods summed, if
+// the metrics API is available.
+func (s *webhookSoak) sampleInjector(ctx context.Context) {
+	raw, err := s.podMetrics(ctx)
+	if err != nil {
+		s.errorf("failed to sample the injector resource usage: %v", err)
+		return
+	}
+	cpu, memory, err := parsePodMetrics(raw)
+	if err != nil {
+		s.errorf("failed to sample the injector resource usage: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cpuMillis = append(s.cpuMillis, cpu)
+	s.memory = append(s.memory, memory)
+}
+
+// parsePodMetrics returns the CPU in millicores and memory in bytes used by the pods.
+func parsePodMetrics(raw []byte) (int64, int64, error) {
+	var list podMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return 0, 0, err
+	}
+	var cpu, memory int64
+	for _, pod := range list.Items {
+		for _, c := range pod.Containers {
+			if q, err := resource.ParseQuantity(c.Usage["cpu"]); err == nil {
+				cpu += q.MilliValue()
+			}
+			if q, err := resource.ParseQuantity(c.Usage["memory"]); err == nil {
+				memory += q.Value()
+			}
+		}
+	}
+	return cpu, memory, nil
+}
+
+// cleanup deletes the namespaces of the run left behind, e.g. by a batch interrupted.
+func (s *webhookSoak) cleanup(errOut io.Writer) {
+	list, err := s.client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: soakRunLabel + "=" + s.run,
+	})
+	if err != nil {
+		fmt.Fprintf(errOut, "Failed to list the namespaces of soak test %s: %v\n", s.run, err)
+		return
+	}
+	for _, ns := range list.Items {
+		if err := s.client.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{}); err != nil &&
+			!apierrors.IsNotFound(err) {
+			fmt.Fprintf(errOut, "Failed to delete namespace %s: %v\n", ns.Name, err)
+		}
+	}
+}
+
+func (s *webhookSoak) summarize() soakReport {
+	r := s.report
+	r.Duration = s.now().Sub(r.Started)
+	if r.Pods > 0 {
+		r.SuccessRate = float64(r.Injected) / float64(r.Pods)
+	}
+	if len(s.latencies) > 0 {
+		sorted := append([]time.Duration{}, s.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		percentile := func(p float64) time.Duration {
+			return sorted[int(p*float64(len(sorted)-1))]
+		}
+		r.Latency = soakLatency{P50: percentile(.5), P90: percentile(.9), P99: percentile(.99), Max: sorted[len(sorted)-1]}
+	}
+	r.Injector.Samples = len(s.cpuMillis)
+	for i := range s.cpuMillis {
+		if s.cpuMillis[i] > r.Injector.MaxCPUMillis {
+			r.Injector.MaxCPUMillis = s.cpuMillis[i]
+		}
+		if s.memory[i] > r.Injector.MaxMemoryBytes {
+			r.Injector.MaxMemoryBytes = s.memory[i]
+		}
+		r.Injector.MeanCPUMillis += float64(s.cpuMillis[i]) / float64(len(s.cpuMillis))
+		r.Injector.MeanMemoryBytes += float64(s.memory[i]) / float64(len(s.memory))
+	}
+	return r
+}
+
+func printSoakReport(w io.Writer, r soakReport) {
+	fmt.Fprintf(w, "\nSoak test report: %d batches over %v\n", r.Batches, r.Duration.Round(time.Second))
+	fmt.Fprintf(w, "  namespaces created:  %d\n", r.Namespaces)
+	fmt.Fprintf(w, "  pods injected:       %d/%d (%.2f%%)\n", r.Injected, r.Pods, 100*r.SuccessRate)
+	fmt.Fprintf(w, "  dry runs injected:   %d/%d\n", r.DryRunsInjected, r.DryRuns)
+	fmt.Fprintf(w, "  dry run latency:     p50 %v, p90 %v, p99 %v, max %v\n",
+		r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+	if r.Injector.Samples > 0 {
+		fmt.Fprintf(w, "  injector CPU:        max %dm, mean %.0fm\n", r.Injector.MaxCPUMillis, r.Injector.MeanCPUMillis)
+		fmt.Fprintf(w, "  injector memory:     max %dMi, mean %.0fMi\n",
+			r.Injector.MaxMemoryBytes>>20, r.Injector.MeanMemoryBytes/(1<<20))
+	} else {
+		fmt.Fprintf(w, "  injector usage:      unavailable, is the metrics API served?\n")
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(w, "ERROR: %s\n", e)
+	}
+}
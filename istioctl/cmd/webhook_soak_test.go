@@ -0,0 +1,150 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"istio.io/istio/pkg/kube/inject"
+)
+
+func TestWebhookSoakBatch(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	// the dry run creations are injected and not persisted
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod).DeepCopy()
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: inject.ProxyContainerName})
+		return true, pod, nil
+	})
+	// the pods of the deployments are created, only the ones of soak-0 injected
+	client.PrependReactor("create", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		d := action.(k8stesting.CreateAction).GetObject().(*appsv1.Deployment)
+		for i := int32(0); i < *d.Spec.Replicas; i++ {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%d", d.Name, i),
+					Namespace: action.GetNamespace(),
+					Labels:    d.Spec.Template.Labels,
+				},
+				Spec: *d.Spec.Template.Spec.DeepCopy(),
+			}
+			if d.Name == "soak-0" {
+				pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: inject.ProxyContainerName})
+			}
+			if err := client.Tracker().Add(pod); err != nil {
+				return true, nil, err
+			}
+		}
+		return false, nil, nil
+	})
+
+	clock := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	}
+	s := newWebhookSoak(client, webhookSoakOptions{
+		namespaces:      2,
+		deployments:     2,
+		replicas:        2,
+		image:           "pause",
+		namespacePrefix: "soak-",
+		injectionLabel:  "istio-injection=enabled",
+		timeout:         time.Minute,
+	}, now)
+	samples := []string{
+		`{"items":[{"containers":[{"usage":{"cpu":"100m","memory":"100Mi"}}]},{"containers":[{"usage":{"cpu":"50m","memory":"28Mi"}}]}]}`,
+		`{"items":[{"containers":[{"usage":{"cpu":"250m","memory":"64Mi"}}]}]}`,
+	}
+	s.podMetrics = func(context.Context) ([]byte, error) {
+		sample := samples[0]
+		samples = samples[1:]
+		return []byte(sample), nil
+	}
+
+	for batch := 0; batch < 2; batch++ {
+		s.runBatch(context.Background(), batch)
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces.Items) != 0 {
+		t.Fatalf("expected the namespaces of the batches to be deleted, got %d", len(namespaces.Items))
+	}
+
+	r := s.summarize()
+	if len(r.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", r.Errors)
+	}
+	if r.Batches != 2 || r.Namespaces != 4 || r.Pods != 16 || r.Injected != 8 || r.SuccessRate != .5 {
+		t.Fatalf("unexpected counts: %+v", r)
+	}
+	if r.DryRuns != 4 || r.DryRunsInjected != 4 || r.Latency.Max != time.Millisecond {
+		t.Fatalf("unexpected dry runs: %+v", r)
+	}
+	want := soakUsage{Samples: 2, MaxCPUMillis: 250, MeanCPUMillis: 200, MaxMemoryBytes: 128 << 20, MeanMemoryBytes: 96 << 20}
+	if r.Injector != want {
+		t.Fatalf("got injector usage %+v, want %+v", r.Injector, want)
+	}
+
+	out := &bytes.Buffer{}
+	printSoakReport(out, r)
+	for _, line := range []string{"pods injected:       8/16 (50.00%)", "injector CPU:        max 250m, mean 200m"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in the report:\n%s", line, out)
+		}
+	}
+}
+
+func TestWebhookSoakMissingPods(t *testing.T) {
+	clock := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	s := newWebhookSoak(fake.NewSimpleClientset(), webhookSoakOptions{
+		namespaces:      1,
+		deployments:     1,
+		replicas:        3,
+		namespacePrefix: "soak-",
+		injectionLabel:  "istio-injection=enabled",
+		timeout:         time.Second,
+	}, func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	})
+	s.podMetrics = func(context.Context) ([]byte, error) {
+		return nil, fmt.Errorf("the server could not find the requested resource")
+	}
+	s.runBatch(context.Background(), 0)
+
+	r := s.summarize()
+	if r.Pods != 3 || r.Injected != 0 || r.SuccessRate != 0 {
+		t.Fatalf("expected the pods not created to count as failures: %+v", r)
+	}
+	if len(r.Errors) != 2 || !strings.Contains(r.Errors[0], "3 pods not created") ||
+		!strings.Contains(r.Errors[1], "resource usage") {
+		t.Fatalf("unexpected errors: %v", r.Errors)
+	}
+}
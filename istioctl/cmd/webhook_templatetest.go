@@ -0,0 +1,168 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/kube/inject/templatetest"
+)
+
+type templateTestOptions struct {
+	suiteFiles       []string
+	injectConfigFile string
+	valuesFile       string
+	meshConfigFile   string
+}
+
+func newTemplateTestCmd() *cobra.Command {
+	var opts templateTestOptions
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run YAML test suites against the injection template",
+		Long: "This command injects the pods of YAML test suites with the injection template and checks\n" +
+			"the assertions of each test, such as the env of a container or no privileged containers.\n" +
+			"The template, values and mesh config are read from the cluster unless given as files.",
+		Example: `  # Run a test suite against the template of the cluster
+  istioctl experimental post-install webhook test -f tests.yaml
+
+  # Run test suites against local files
+  istioctl experimental post-install webhook test -f proxy.yaml -f security.yaml \
+    --injectConfigFile inject-config.yaml --valuesFile values.json --meshConfigFile mesh.yaml
+
+  # A test suite
+  tests:
+  - name: proxy is configured for the cluster
+    pod:
+      metadata:
+        name: app
+      spec:
+        containers:
+        - name: app
+          image: app
+    assertions:
+    - container: istio-proxy
+      env:
+        ISTIO_META_CLUSTER_ID: Kubernetes
+    - noPrivilegedContainers: true`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.suiteFiles) == 0 {
+				return errors.New("at least one test suite must be given with -f")
+			}
+			config, values, meshConfig, err := opts.injectionConfig()
+			if err != nil {
+				return err
+			}
+			return runTemplateTests(cmd.OutOrStdout(), opts.suiteFiles, config.Template, values, meshConfig)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.suiteFiles, "filename", "f", nil, "Test suite files to run.")
+	cmd.Flags().StringVar(&opts.injectConfigFile, "injectConfigFile", "",
+		"Injection configuration filename. Read from the cluster if not set.")
+	cmd.Flags().StringVar(&opts.valuesFile, "valuesFile", "",
+		"Injection values configuration filename. Read from the cluster if not set.")
+	cmd.Flags().StringVar(&opts.meshConfigFile, "meshConfigFile", "",
+		"Mesh configuration filename. Read from the cluster if not set.")
+
+	return cmd
+}
+
+func (o *templateTestOptions) injectionConfig() (*inject.Config, string, *meshconfig.MeshConfig, error) {
+	var config *inject.Config
+	if o.injectConfigFile != "" {
+		data, err := ioutil.ReadFile(o.injectConfigFile)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		config = &inject.Config{}
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, "", nil, fmt.Errorf("loading --injectConfigFile: %v", err)
+		}
+	} else {
+		var err error
+		if config, err = getInjectionConfigFromConfigMap(kubeconfig); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	var values string
+	if o.valuesFile != "" {
+		data, err := ioutil.ReadFile(o.valuesFile)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		values = string(data)
+	} else {
+		var err error
+		if values, err = getValuesFromConfigMap(kubeconfig); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	var meshConfig *meshconfig.MeshConfig
+	var err error
+	if o.meshConfigFile != "" {
+		meshConfig, err = mesh.ReadMeshConfig(o.meshConfigFile)
+	} else {
+		meshConfig, err = getMeshConfigFromConfigMap(kubeconfig, "webhook test")
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return config, values, meshConfig, nil
+}
+
+// runTemplateTests runs the suites and prints the result of each test,
+// returning an error if any test failed.
+func runTemplateTests(w io.Writer, suiteFiles []string, template, values string, meshConfig *meshconfig.MeshConfig) error {
+	passed, failed := 0, 0
+	for _, file := range suiteFiles {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		suite, err := templatetest.Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		for _, result := range suite.Run(template, values, meshConfig) {
+			if len(result.Failures) == 0 {
+				passed++
+				fmt.Fprintf(w, "PASS %s: %s\n", file, result.Name)
+				continue
+			}
+			failed++
+			fmt.Fprintf(w, "FAIL %s: %s\n", file, result.Name)
+			for _, f := range result.Failures {
+				fmt.Fprintf(w, "    %s\n", f)
+			}
+		}
+	}
+	fmt.Fprintf(w, "%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d template tests failed", failed)
+	}
+	return nil
+}
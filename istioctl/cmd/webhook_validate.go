@@ -0,0 +1,155 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/kube/inject/templatecheck"
+)
+
+type webhookValidateOptions struct {
+	templateTestOptions
+	podFiles     []string
+	serverDryRun bool
+}
+
+func newWebhookValidateCmd() *cobra.Command {
+	var opts webhookValidateOptions
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate an injection configuration against representative pods",
+		Long: "This command injects representative sample pods, and the pods of the given files, as the webhook\n" +
+			"patches them, and checks the injected pods against the rules of the API server on pod specs:\n" +
+			"container and volume names, images, ports, environment variables, volume mounts and resources.\n" +
+			"With --server-dry-run the injected pods are also created in the cluster with a dry run, so they\n" +
+			"pass the complete validation and admission of the API server. It exits with an error listing the\n" +
+			"invalid fields, so CI pipelines can gate changes of the injection ConfigMap on it. The injection\n" +
+			"config, values and mesh config are read from the cluster unless given as files.",
+		Example: `  # Validate a change of the injection configuration
+  istioctl experimental post-install webhook validate \
+    --injectConfigFile inject-config.yaml --valuesFile values.json --meshConfigFile mesh.yaml
+
+  # Also validate the injection of pods of the cluster workloads
+  istioctl experimental post-install webhook validate -f pods.yaml \
+    --injectConfigFile inject-config.yaml --valuesFile values.json --meshConfigFile mesh.yaml
+
+  # Validate the injected pods with the API server of the cluster
+  istioctl experimental post-install webhook validate --server-dry-run \
+    --injectConfigFile inject-config.yaml --valuesFile values.json --meshConfigFile mesh.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, values, meshConfig, err := opts.injectionConfig()
+			if err != nil {
+				return err
+			}
+			pods := templatecheck.SamplePods()
+			for _, file := range opts.podFiles {
+				filePods, err := readValidationPods(file)
+				if err != nil {
+					return err
+				}
+				pods = append(pods, filePods...)
+			}
+			var client kubernetes.Interface
+			if opts.serverDryRun {
+				if client, err = interfaceFactory(kubeconfig); err != nil {
+					return err
+				}
+			}
+			return runWebhookValidate(cmd.OutOrStdout(), client, config, values, meshConfig, pods)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.podFiles, "filename", "f", nil,
+		"Files of pods, separated by ---, validated in addition to the sample pods.")
+	cmd.Flags().StringVar(&opts.injectConfigFile, "injectConfigFile", "",
+		"Injection configuration filename. Read from the cluster if not set.")
+	cmd.Flags().StringVar(&opts.valuesFile, "valuesFile", "",
+		"Injection values configuration filename. Read from the cluster if not set.")
+	cmd.Flags().StringVar(&opts.meshConfigFile, "meshConfigFile", "",
+		"Mesh configuration filename. Read from the cluster if not set.")
+	cmd.Flags().BoolVar(&opts.serverDryRun, "server-dry-run", false,
+		"Also create the injected pods in the cluster with a dry run, validating them with the API server.")
+
+	return cmd
+}
+
+func readValidationPods(file string) ([]corev1.Pod, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReader(f))
+	var pods []corev1.Pod
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return pods, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		var pod corev1.Pod
+		if err := yaml.Unmarshal(doc, &pod); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if pod.Kind != "" && pod.Kind != "Pod" {
+			return nil, fmt.Errorf("%s: %s %s is not a pod", file, pod.Kind, pod.Name)
+		}
+		if pod.Name == "" {
+			pod.Name = fmt.Sprintf("%s[%d]", file, len(pods))
+		}
+		pods = append(pods, pod)
+	}
+}
+
+// runWebhookValidate prints the problems of the injection of the pods,
+// returning an error if there are any. The injected pods are also created
+// with a dry run when client is not nil.
+// nolint: lll
+func runWebhookValidate(w io.Writer, client kubernetes.Interface, config *inject.Config, values string, meshConfig *meshconfig.MeshConfig, pods []corev1.Pod) error {
+	injected, errs := templatecheck.Inject(config, values, meshConfig, pods)
+	for i := range injected {
+		errs = append(errs, templatecheck.ValidatePod(&injected[i])...)
+		if client != nil {
+			errs = append(errs, templatecheck.ValidateServerSide(context.Background(), client, &injected[i])...)
+		}
+	}
+	for _, e := range errs {
+		fmt.Fprintf(w, "ERROR %s\n", e.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("the injection configuration is invalid: %d errors in %d pods", len(errs), len(pods))
+	}
+	fmt.Fprintf(w, "The injection configuration is valid for %d pods\n", len(pods))
+	return nil
+}
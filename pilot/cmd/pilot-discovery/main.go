@@ -82,6 +82,8 @@ var (
 				return fmt.Errorf("failed to start discovery service: %v", err)
 			}
 
+			// SIGHUP reloads the certificates and the configuration files
+			cmd.OnReload(discoveryServer.Reload)
 			cmd.WaitSignal(stop)
 			// Wait until we shut down. In theory this could block forever; in practice we will get
 			// forcibly shut down after 30s in Kubernetes.
@@ -127,6 +129,9 @@ func init() {
 		"File name for Istio mesh networks configuration. If not specified, a default mesh networks will be used.")
 	discoveryCmd.PersistentFlags().StringVarP(&serverArgs.Namespace, "namespace", "n", bootstrap.PodNamespaceVar.Get(),
 		"Select a namespace where the controller resides. If not set, uses ${POD_NAMESPACE} environment variable")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.Revision, "revision", bootstrap.RevisionVar.Get(),
+		"Revision of the control plane, e.g. canary. The sidecar injector only injects the pods and namespaces "+
+			"labeled with this istio.io/rev. If not set, uses ${REVISION} environment variable")
 	discoveryCmd.PersistentFlags().StringSliceVar(&serverArgs.Plugins, "plugins", bootstrap.DefaultPlugins,
 		"comma separated list of networking plugins to enable")
 
@@ -138,6 +143,9 @@ func init() {
 	discoveryCmd.PersistentFlags().IntVar(&serverArgs.MCPOptions.InitialConnWindowSize, "mcpInitialConnWindowSize", defaultMCPInitialConnWindowSize,
 		"Initial connection window size for MCP's gRPC connection")
 
+	discoveryCmd.PersistentFlags().DurationVar(&serverArgs.ShutdownDuration, "shutdownDuration", 10*time.Second,
+		"Duration the discovery server and the sidecar injector drain in-flight requests for on shutdown")
+
 	// RegistryOptions Controller options
 	discoveryCmd.PersistentFlags().StringVar(&serverArgs.RegistryOptions.FileDir, "configDir", "",
 		"Directory to watch for updates to config yaml files. If specified, the files will be used as the source of config, rather than a CRD client.")
@@ -166,6 +174,33 @@ func init() {
 		"HTTP address to use for pilot's self-monitoring information")
 	discoveryCmd.PersistentFlags().BoolVar(&serverArgs.ServerOptions.EnableProfiling, "profile", true,
 		"Enable profiling via web interface host:port/debug/pprof")
+	discoveryCmd.PersistentFlags().IntVar(&serverArgs.InjectionOptions.InsecurePort, "insecurePort", 0,
+		"If positive, also serve sidecar injection without TLS on this localhost port. For local testing only.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.InjectionOptions.TemplateOverrideDirectory, "templateOverrideDir", "",
+		"Directory whose injection config and values files, if present, take precedence over the injection ConfigMap. "+
+			"For emergency fixes when the ConfigMap cannot be updated.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.InjectionOptions.UDSPath, "uds", "",
+		"If set, also serve sidecar injection without TLS on a unix socket at this path, for integration tests "+
+			"and local tooling.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.InjectionOptions.AuditLogFile, "auditLogFile", "",
+		"If set, append a JSON line per sidecar injection decision to this file: namespace, pod generateName, "+
+			"owner kind, decision, skip reason, template hash and patch size.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.InjectionOptions.FailurePolicy, "failurePolicy", "",
+		"If set, the failurePolicy, Fail or Ignore, patched onto the sidecar injection webhook with its caBundle.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.InjectionOptions.ReinvocationPolicy, "reinvocationPolicy", "",
+		"If set, the reinvocationPolicy, Never or IfNeeded, patched onto the sidecar injection webhook with its caBundle.")
+	discoveryCmd.PersistentFlags().StringSliceVar(&serverArgs.InjectionOptions.InjectNamespaces, "injectNamespaces", nil,
+		"If set, comma separated globs of the only namespaces whose pods are injected, checked by the injector "+
+			"in addition to the namespaceSelector of the webhook.")
+	discoveryCmd.PersistentFlags().StringSliceVar(&serverArgs.InjectionOptions.SkipNamespaces, "skipNamespaces", nil,
+		"Comma separated globs of the namespaces whose pods are never injected, checked by the injector "+
+			"in addition to the namespaceSelector of the webhook. Takes precedence over --injectNamespaces.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.InjectionOptions.ConfigMapName, "injectConfigMapName", "",
+		"If set, the sidecar injection ConfigMap of --namespace is watched with the Kubernetes API, so its updates "+
+			"apply within seconds instead of once the kubelet updates the mounted files, still used as a fallback.")
+	discoveryCmd.PersistentFlags().BoolVar(&serverArgs.InjectionOptions.WatchMeshConfigMap, "injectWatchMeshConfigMap", false,
+		"If enabled, the injector watches the mesh ConfigMap of the revision in --namespace with the Kubernetes API, "+
+			"so its updates apply within seconds instead of once the kubelet updates the mounted mesh config file.")
 
 	// Use TLS certificates if provided.
 	discoveryCmd.PersistentFlags().StringVar(&serverArgs.ServerOptions.TLSOptions.CaCertFile, "caCertFile", "",
@@ -174,6 +209,22 @@ func init() {
 		"File containing the x509 Server Certificate")
 	discoveryCmd.PersistentFlags().StringVar(&serverArgs.ServerOptions.TLSOptions.KeyFile, "tlsKeyFile", "",
 		"File containing the x509 private key matching --tlsCertFile")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.ServerOptions.TLSOptions.CertSecretName, "certSecretName", "",
+		"If set, the Secret, name or namespace/name in the Istiod namespace by default, holding the x509 Server Certificate "+
+			"and private key, as tls.crt and tls.key or cert-chain.pem and key.pem, with an optional CA bundle as ca.crt "+
+			"or root-cert.pem. Used instead of --tlsCertFile and --tlsKeyFile and reloaded whenever the Secret is updated.")
+	discoveryCmd.PersistentFlags().BoolVar(&serverArgs.ServerOptions.TLSOptions.SelfSignedCerts, "selfSignedCerts", false,
+		"If set, generate a CA and a serving certificate signed by it into the --certSecretName Secret, "+
+			"istiod-self-signed-certs by default, and rotate them before they expire. The CA is patched as the caBundle "+
+			"of the webhook configs. For clusters without Citadel or a cert provider.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.ServerOptions.TLSOptions.MinVersion, "tlsMinVersion", "",
+		"Minimum TLS version of the webhook HTTPS server: 1.0, 1.1, 1.2 or 1.3. Defaults to the Go default.")
+	discoveryCmd.PersistentFlags().StringSliceVar(&serverArgs.ServerOptions.TLSOptions.CipherSuites, "tlsCipherSuites", nil,
+		"Comma separated cipher suites allowed by the webhook HTTPS server for TLS 1.2 and older, "+
+			"e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Defaults to the Go defaults.")
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.ServerOptions.TLSOptions.ClientCAFile, "clientCAFile", "",
+		"File containing the x509 CA certificates verifying the client certificates required to call the injection webhook, "+
+			"e.g. the one of the kube-apiserver admission client certificate.")
 
 	discoveryCmd.PersistentFlags().Float32Var(&serverArgs.RegistryOptions.KubeOptions.KubernetesAPIQPS, "kubernetesApiQPS", 20.0,
 		"Maximum QPS when communicating with the kubernetes API")
@@ -181,6 +232,12 @@ func init() {
 	discoveryCmd.PersistentFlags().IntVar(&serverArgs.RegistryOptions.KubeOptions.KubernetesAPIBurst, "kubernetesApiBurst", 40,
 		"Maximum burst for throttle when communicating with the kubernetes API")
 
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.RegistryOptions.KubeOptions.KubernetesAPIProxy, "kubernetesApiProxy", "",
+		"Proxy used when communicating with the kubernetes API. Defaults to HTTPS_PROXY of the environment")
+
+	discoveryCmd.PersistentFlags().StringVar(&serverArgs.RegistryOptions.KubeOptions.KubernetesAPINoProxy, "kubernetesApiNoProxy", "",
+		"Comma separated hosts, domains and CIDRs reached without --kubernetesApiProxy")
+
 	// Attach the Istio logging options to the command.
 	loggingOptions.AttachCobraFlags(rootCmd)
 
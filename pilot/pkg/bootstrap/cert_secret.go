@@ -0,0 +1,204 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/pkg/log"
+)
+
+var (
+	// The certificates of the --certSecretName Secret are saved for the webhook
+	// config patching and statusz, which read them from files. The private
+	// key is only kept in memory.
+	secretCertFile = "./" + filepath.Join(dnsCertDir, "secret-cert-chain.pem")
+	secretCAFile   = "./" + filepath.Join(dnsCertDir, "secret-root-cert.pem")
+)
+
+// hasCertSecret returns true if the serving certificate is loaded from a Secret.
+func hasCertSecret(tlsOptions TLSOptions) bool {
+	return tlsOptions.CertSecretName != "" || tlsOptions.SelfSignedCerts
+}
+
+// certSecretName returns the Secret holding the serving certificate.
+func certSecretName(tlsOptions TLSOptions) string {
+	if tlsOptions.CertSecretName == "" && tlsOptions.SelfSignedCerts {
+		return defaultSelfSignedCertsSecretName
+	}
+	return tlsOptions.CertSecretName
+}
+
+// parseCertSecretName returns the namespace and name of the Secret named
+// name or namespace/name, in namespace by default.
+func parseCertSecretName(secretName, namespace string) (string, string, error) {
+	parts := strings.Split(secretName, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return namespace, parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("invalid certificate secret %q: must be name or namespace/name", secretName)
+}
+
+// certSecretData returns the PEM encoded certificate chain, key and CA bundle
+// of a kubernetes.io/tls Secret, with tls.crt, tls.key and ca.crt, or of an
+// Istio Secret, with cert-chain.pem, key.pem and root-cert.pem. The CA bundle
+// is optional.
+func certSecretData(secret *corev1.Secret) (certChain, key, caBundle []byte, err error) {
+	if certChain = secret.Data[corev1.TLSCertKey]; len(certChain) > 0 {
+		return certChain, secret.Data[corev1.TLSPrivateKeyKey], secret.Data[corev1.ServiceAccountRootCAKey], nil
+	}
+	if certChain = secret.Data[constants.CertChainFilename]; len(certChain) > 0 {
+		return certChain, secret.Data[constants.KeyFilename], secret.Data[constants.RootCertFilename], nil
+	}
+	return nil, nil, nil, fmt.Errorf("secret %s/%s has neither %s nor %s",
+		secret.Namespace, secret.Name, corev1.TLSCertKey, constants.CertChainFilename)
+}
+
+// initCertificateSecret loads the serving certificate from the --certSecretName
+// Secret and swaps it on every update of the Secret, so certificates rotated
+// into the Secret are served without restarting Istiod.
+func (s *Server) initCertificateSecret(args *PilotArgs) error {
+	if s.kubeClient == nil {
+		return fmt.Errorf("--certSecretName requires a Kubernetes client")
+	}
+	namespace, name, err := parseCertSecretName(certSecretName(args.ServerOptions.TLSOptions), args.Namespace)
+	if err != nil {
+		return err
+	}
+	// the serving certificate has to be loaded before the servers start
+	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get certificate secret %s/%s: %v", namespace, name, err)
+	}
+	if err := s.loadCertificateSecret(secret); err != nil {
+		return err
+	}
+	s.caBundlePath = secretCAFile
+
+	watchlist := cache.NewListWatchFromClient(
+		s.kubeClient.CoreV1().RESTClient(),
+		"secrets",
+		namespace,
+		fields.OneTermEqualSelector("metadata.name", name))
+	update := func(obj interface{}) {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		if err := s.loadCertificateSecret(secret); err != nil {
+			log.Errorf("error in reloading certs from secret %s/%s, %v", namespace, name, err)
+		}
+	}
+	_, informer := cache.NewInformer(watchlist, &corev1.Secret{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc:    update,
+		UpdateFunc: func(_, obj interface{}) { update(obj) },
+		DeleteFunc: func(interface{}) {
+			log.Warnf("certificate secret %s/%s was deleted, serving the last certificate loaded", namespace, name)
+		},
+	})
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go informer.Run(stop)
+		return nil
+	})
+	log.Infof("loading Istiod certificates from secret %s/%s", namespace, name)
+	return nil
+}
+
+// loadCertificateSecret swaps the serving certificate for the one of the
+// Secret, if it changed, and saves its certificates.
+func (s *Server) loadCertificateSecret(secret *corev1.Secret) error {
+	certChain, key, caBundle, err := certSecretData(secret)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(certChain, key)
+	if err != nil {
+		return fmt.Errorf("invalid certificate in secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+
+	s.certMu.Lock()
+	unchanged := s.istiodCert != nil && certificateEqual(s.istiodCert, &cert)
+	if !unchanged {
+		s.istiodCert = &cert
+	}
+	s.certMu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	var certs []*x509.Certificate
+	for _, der := range cert.Certificate {
+		if c, err := x509.ParseCertificate(der); err == nil {
+			certs = append(certs, c)
+		}
+	}
+	checkCertClockSkew("serving", certs, time.Now(), certClockSkewTolerance)
+	if len(caBundle) == 0 {
+		// without a CA bundle the leaf is trusted as is, e.g. a self-signed certificate
+		caBundle = certChain
+	}
+	if err := saveSecretCertificates(certChain, caBundle); err != nil {
+		log.Errorf("failed to save certificates of secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	for _, h := range s.certReloadHandlers {
+		h(&cert)
+	}
+	if len(certs) > 0 {
+		log.Infof("Istiod certificates are reloaded from secret %s/%s: Subject: %q, SN: %x, NotAfter: %q",
+			secret.Namespace, secret.Name, certs[0].Subject, certs[0].SerialNumber, certs[0].NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// certificateEqual returns true if a and b hold the same certificate chain.
+func certificateEqual(a, b *tls.Certificate) bool {
+	if len(a.Certificate) != len(b.Certificate) {
+		return false
+	}
+	for i := range a.Certificate {
+		if !bytes.Equal(a.Certificate[i], b.Certificate[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func saveSecretCertificates(certChain, caBundle []byte) error {
+	if err := os.MkdirAll(filepath.Dir(secretCertFile), 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(secretCertFile, certChain, 0600); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(secretCAFile, caBundle, 0600)
+}
@@ -0,0 +1,132 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/test/env"
+)
+
+func TestParseCertSecretName(t *testing.T) {
+	cases := []struct {
+		secretName    string
+		wantNamespace string
+		wantName      string
+		wantErr       bool
+	}{
+		{"istiod-tls", "istio-system", "istiod-tls", false},
+		{"certs/istiod-tls", "certs", "istiod-tls", false},
+		{"", "", "", true},
+		{"certs/", "", "", true},
+		{"a/b/c", "", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.secretName, func(t *testing.T) {
+			namespace, name, err := parseCertSecretName(c.secretName, "istio-system")
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if namespace != c.wantNamespace || name != c.wantName {
+				t.Fatalf("got %s/%s, want %s/%s", namespace, name, c.wantNamespace, c.wantName)
+			}
+		})
+	}
+}
+
+func certSecret(t *testing.T, dir string, tlsKeys bool) *corev1.Secret {
+	t.Helper()
+	read := func(file string) []byte {
+		b, err := ioutil.ReadFile(path.Join(env.IstioSrc, "tests/testdata/certs", dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "istiod-tls"}}
+	if tlsKeys {
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       read("cert-chain.pem"),
+			corev1.TLSPrivateKeyKey: read("key.pem"),
+		}
+	} else {
+		secret.Data = map[string][]byte{
+			"cert-chain.pem": read("cert-chain.pem"),
+			"key.pem":        read("key.pem"),
+			"root-cert.pem":  read("root-cert.pem"),
+		}
+	}
+	return secret
+}
+
+func TestLoadCertificateSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "istiod_secret_certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(certFile, caFile string) { secretCertFile, secretCAFile = certFile, caFile }(secretCertFile, secretCAFile)
+	secretCertFile, secretCAFile = path.Join(dir, "cert-chain.pem"), path.Join(dir, "root-cert.pem")
+
+	s := &Server{}
+	reloaded := 0
+	s.certReloadHandlers = append(s.certReloadHandlers, func(*tls.Certificate) { reloaded++ })
+
+	pilot := certSecret(t, "pilot", false)
+	if err := s.loadCertificateSecret(pilot); err != nil {
+		t.Fatal(err)
+	}
+	if ca, _ := ioutil.ReadFile(secretCAFile); !bytes.Equal(ca, pilot.Data["root-cert.pem"]) {
+		t.Fatalf("got CA bundle %q, want the root-cert.pem of the secret", ca)
+	}
+	first, _ := s.getIstiodCertificate(nil)
+
+	// an update of the secret not changing the certificate is ignored
+	if err := s.loadCertificateSecret(certSecret(t, "pilot", false)); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded != 1 {
+		t.Fatalf("got %d reloads, want 1", reloaded)
+	}
+
+	// the rotated certificate is swapped in, trusted as is without a CA bundle
+	dns := certSecret(t, "dns", true)
+	if err := s.loadCertificateSecret(dns); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := s.getIstiodCertificate(nil); reloaded != 2 || certificateEqual(first, second) {
+		t.Fatalf("certificate not swapped after %d reloads", reloaded)
+	}
+	if ca, _ := ioutil.ReadFile(secretCAFile); !bytes.Equal(ca, dns.Data[corev1.TLSCertKey]) {
+		t.Fatalf("got CA bundle %q, want the certificate chain of the secret", ca)
+	}
+
+	// an invalid certificate keeps the one loaded
+	dns.Data[corev1.TLSPrivateKeyKey] = pilot.Data["key.pem"]
+	if err := s.loadCertificateSecret(dns); err == nil {
+		t.Fatalf("expected error for a mismatched private key")
+	}
+	if reloaded != 2 {
+		t.Fatalf("got %d reloads, want 2", reloaded)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"time"
+
+	"istio.io/pkg/env"
+	"istio.io/pkg/log"
+	"istio.io/pkg/monitoring"
+)
+
+var (
+	certClockSkewTolerance = env.RegisterDurationVar("PILOT_CERT_CLOCK_SKEW_TOLERANCE", 5*time.Minute,
+		"How far the local clock may be behind the NotBefore of the serving certificate and CA bundle of Istiod "+
+			"before a clock skew warning is logged and recorded by pilot_cert_clock_skew_seconds.").Get()
+
+	certTag = monitoring.MustCreateLabel("cert")
+
+	certClockSkewGauge = monitoring.NewGauge(
+		"pilot_cert_clock_skew_seconds",
+		"How far the local clock is behind the NotBefore of the loaded certificate, beyond "+
+			"PILOT_CERT_CLOCK_SKEW_TOLERANCE. Zero if the clock does not appear skewed.",
+		monitoring.WithLabels(certTag),
+	)
+)
+
+func init() {
+	monitoring.MustRegister(certClockSkewGauge)
+}
+
+// certClockSkew returns how far now is before the NotBefore of the
+// certificates, zero if they are all already valid. A certificate not yet
+// valid is usually the sign of a local clock behind the one of its issuer.
+func certClockSkew(certs []*x509.Certificate, now time.Time) time.Duration {
+	var skew time.Duration
+	for _, c := range certs {
+		if d := c.NotBefore.Sub(now); d > skew {
+			skew = d
+		}
+	}
+	return skew
+}
+
+// checkCertClockSkew logs the validity of the certificates relative to the
+// local clock, and records the clock skew beyond the tolerance. Certificates
+// are never rejected: if the local clock is wrong, they are valid for peers.
+func checkCertClockSkew(name string, certs []*x509.Certificate, now time.Time, tolerance time.Duration) {
+	for _, c := range certs {
+		log.Debugf("%s certificate %q valid from %v to %v, local time %v", name, c.Subject,
+			c.NotBefore.Format(time.RFC3339), c.NotAfter.Format(time.RFC3339), now.Format(time.RFC3339))
+		if now.After(c.NotAfter) {
+			log.Warnf("%s certificate %q expired %v ago, at %v; the local clock may be ahead if it was just issued",
+				name, c.Subject, now.Sub(c.NotAfter).Round(time.Second), c.NotAfter.Format(time.RFC3339))
+		}
+	}
+	skew := certClockSkew(certs, now)
+	switch {
+	case skew > tolerance:
+		log.Warnf("%s certificate is not valid for another %v, beyond the %v clock skew tolerance: "+
+			"the local clock appears to be behind, peers checking the certificate against it may fail TLS",
+			name, skew.Round(time.Second), tolerance)
+		certClockSkewGauge.With(certTag.Value(name)).Record(skew.Seconds())
+		return
+	case skew > 0:
+		log.Infof("%s certificate is not valid for another %v, within the %v clock skew tolerance",
+			name, skew.Round(time.Second), tolerance)
+	}
+	certClockSkewGauge.With(certTag.Value(name)).Record(0)
+}
+
+// checkCertFileClockSkew runs checkCertClockSkew on the PEM certificates of the file.
+func checkCertFileClockSkew(name, path string) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debugf("Not checking the clock skew of %s certificate: %v", name, err)
+		return
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if c, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, c)
+		}
+	}
+	checkCertClockSkew(name, certs, time.Now(), certClockSkewTolerance)
+}
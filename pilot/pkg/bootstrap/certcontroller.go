@@ -26,6 +26,7 @@ import (
 
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/serviceregistry/kube/controller"
+	"istio.io/istio/pkg/file"
 	"istio.io/istio/security/pkg/k8s/chiron"
 	"istio.io/pkg/log"
 )
@@ -100,22 +101,9 @@ func (s *Server) initCertController(args *PilotArgs) error {
 	return nil
 }
 
-// initDNSCerts will create the certificates to be used by Istiod GRPC server and webhooks.
-// If the certificate creation fails - for example no support in K8S - returns an error.
-// Will use the mesh.yaml DiscoveryAddress to find the default expected address of the control plane,
-// with an environment variable allowing override.
-//
-// Controlled by features.IstiodService env variable, which defines the name of the service to use in the DNS
-// cert, or empty for disabling this feature.
-//
-// TODO: If the discovery address in mesh.yaml is set to port 15012 (XDS-with-DNS-certs) and the name
-// matches the k8s namespace, failure to start DNS server is a fatal error.
-func (s *Server) initDNSCerts(hostname, customHost, namespace string) error {
-	// Name in the Istiod cert - support the old service names as well.
-	// validate hostname contains namespace
-	parts := strings.Split(hostname, ".")
-	hostnamePrefix := parts[0]
-
+// istiodDNSNames returns the names in the Istiod cert: the hostname, the custom hostname if
+// there is any, and the old service names as well.
+func istiodDNSNames(hostname, customHost, namespace string) []string {
 	// append custom hostname if there is any
 	names := []string{hostname}
 	if customHost != "" && customHost != hostname {
@@ -132,15 +120,32 @@ func (s *Server) initDNSCerts(hostname, customHost, namespace string) error {
 		}
 		names = append(names, name)
 	}
+	return names
+}
+
+// initDNSCerts will create the certificates to be used by Istiod GRPC server and webhooks.
+// If the certificate creation fails - for example no support in K8S - returns an error.
+// Will use the mesh.yaml DiscoveryAddress to find the default expected address of the control plane,
+// with an environment variable allowing override.
+//
+// Controlled by features.IstiodService env variable, which defines the name of the service to use in the DNS
+// cert, or empty for disabling this feature.
+//
+// TODO: If the discovery address in mesh.yaml is set to port 15012 (XDS-with-DNS-certs) and the name
+// matches the k8s namespace, failure to start DNS server is a fatal error.
+func (s *Server) initDNSCerts(hostname, customHost, namespace string) error {
+	// Name in the Istiod cert - support the old service names as well.
+	// validate hostname contains namespace
+	parts := strings.Split(hostname, ".")
+	hostnamePrefix := parts[0]
+
+	names := istiodDNSNames(hostname, customHost, namespace)
 
 	var certChain, keyPEM []byte
 	var err error
 	if features.PilotCertProvider.Get() == KubernetesCAProvider {
-		log.Infof("Generating K8S-signed cert for %v", names)
-		certChain, keyPEM, _, err = chiron.GenKeyCertK8sCA(s.kubeClient.CertificatesV1beta1().CertificateSigningRequests(),
-			strings.Join(names, ","), hostnamePrefix+".csr.secret", namespace, defaultCACertPath)
-
 		s.caBundlePath = defaultCACertPath
+		return s.initK8sSignedCerts(names, hostnamePrefix+".csr.secret", namespace)
 	} else if features.PilotCertProvider.Get() == IstiodCAProvider {
 		log.Infof("Generating istiod-signed cert for %v", names)
 		certChain, keyPEM, err = s.CA.GenKeyCert(names, SelfSignedCACertTTL.Get())
@@ -198,19 +203,22 @@ func (s *Server) initDNSCerts(hostname, customHost, namespace string) error {
 		return err
 	}
 
-	// Save the certificates to ./var/run/secrets/istio-dns - this is needed since most of the code we currently
-	// use to start grpc and webhooks is based on files. This is a memory-mounted dir.
-	if err := os.MkdirAll(dnsCertDir, 0700); err != nil {
+	if err := saveDNSCerts(certChain, keyPEM); err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(dnsKeyFile, keyPEM, 0600)
-	if err != nil {
+	log.Infoa("DNS certificates created in ", dnsCertDir)
+	return nil
+}
+
+// saveDNSCerts saves the certificates to ./var/run/secrets/istio-dns - this is needed since most of the code we currently
+// use to start grpc and webhooks is based on files. This is a memory-mounted dir.
+// The files are replaced atomically, so a reload never reads them half written.
+func saveDNSCerts(certChain, keyPEM []byte) error {
+	if err := os.MkdirAll(dnsCertDir, 0700); err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(dnsCertFile, certChain, 0600)
-	if err != nil {
+	if err := file.AtomicWrite(dnsKeyFile, keyPEM, 0600); err != nil {
 		return err
 	}
-	log.Infoa("DNS certificates created in ", dnsCertDir)
-	return nil
+	return file.AtomicWrite(dnsCertFile, certChain, 0600)
 }
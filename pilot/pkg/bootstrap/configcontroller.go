@@ -97,7 +97,7 @@ func (s *Server) initConfigController(args *PilotArgs) error {
 			log.Warnf("Disabled ingress status syncer due to %v", err)
 		} else {
 			s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
-				le := leaderelection.NewLeaderElection(args.Namespace, args.PodName, leaderelection.IngressController, s.kubeClient.Kube())
+				le := s.newLeaderElection(args, leaderelection.IngressController)
 				le.AddRunFunction(func(leaderStop <-chan struct{}) {
 					log.Infof("Starting ingress controller")
 					ingressSyncer.Run(leaderStop)
@@ -0,0 +1,158 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"istio.io/istio/security/pkg/k8s/chiron"
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/env"
+	"istio.io/pkg/log"
+)
+
+// csrCertsRetryInterval is how long Istiod waits to submit another CSR after
+// one failed before it was approved.
+const csrCertsRetryInterval = 10 * time.Second
+
+var (
+	csrCertsSelfApprove = env.RegisterBoolVar("PILOT_CSR_CERTS_SELF_APPROVE", true,
+		"If enabled, Istiod approves the CertificateSigningRequests of its DNS certificates itself when PILOT_CERT_PROVIDER "+
+			"is kubernetes, which requires the RBAC permission to approve them. Otherwise it waits for them to be approved, "+
+			"e.g. by an operator, serving without a certificate until the first one is.").Get()
+
+	csrCertsApprovalTimeout = env.RegisterDurationVar("PILOT_CSR_CERTS_APPROVAL_TIMEOUT", 5*time.Minute,
+		"How long Istiod waits for a CertificateSigningRequest of its DNS certificates to be approved and signed, "+
+			"when PILOT_CSR_CERTS_SELF_APPROVE is disabled, before it submits another one.").Get()
+
+	csrCertsCheckInterval = env.RegisterDurationVar("PILOT_CSR_CERTS_CHECK_INTERVAL", time.Hour,
+		"How often the DNS certificates signed by the cluster CA are checked for renewal.").Get()
+)
+
+// initK8sSignedCerts obtains the DNS certificates from the cluster CA through
+// a CertificateSigningRequest, and renews them once less than a third of their
+// lifetime is left or they are no longer signed by the cluster CA. When the
+// CSRs are not self approved, startup does not wait for the approval: the
+// first CSR is retried in the background until signed, and the certificates
+// loaded then.
+func (s *Server) initK8sSignedCerts(names []string, secretName, namespace string) error {
+	csrs := s.kubeClient.CertificatesV1beta1().CertificateSigningRequests()
+	dnsName := strings.Join(names, ",")
+	issue := func() error {
+		var certChain, keyPEM []byte
+		var err error
+		if csrCertsSelfApprove {
+			certChain, keyPEM, _, err = chiron.GenKeyCertK8sCA(csrs, dnsName, secretName, namespace, defaultCACertPath)
+		} else {
+			certChain, keyPEM, _, err = chiron.GenKeyCertK8sCAWithApproval(csrs, dnsName, secretName, namespace, defaultCACertPath,
+				false, csrCertsApprovalTimeout)
+		}
+		if err != nil {
+			return err
+		}
+		if err := saveDNSCerts(certChain, keyPEM); err != nil {
+			return err
+		}
+		// the certificate watches are not set up if the files were missing at startup
+		s.reloadCertKeyPair(TLSOptions{})
+		return nil
+	}
+
+	log.Infof("Generating K8S-signed cert for %v", names)
+	if csrCertsSelfApprove {
+		if err := issue(); err != nil {
+			return err
+		}
+		log.Infoa("DNS certificates created in ", dnsCertDir)
+	} else {
+		log.Warnf("Serving without DNS certificates until the CSR for %v is approved", names)
+	}
+
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go func() {
+			if !csrCertsSelfApprove {
+				for {
+					// GenKeyCertK8sCAWithApproval waits for the approval itself
+					err := issue()
+					if err == nil {
+						log.Infoa("DNS certificates created in ", dnsCertDir)
+						break
+					}
+					log.Errorf("failed to obtain the K8S-signed cert for %v, retrying in %v: %v", names, csrCertsRetryInterval, err)
+					select {
+					case <-stop:
+						return
+					case <-time.After(csrCertsRetryInterval):
+					}
+				}
+			}
+			ticker := time.NewTicker(csrCertsCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+				}
+				renew, reason := csrCertNeedsRenewal(dnsCertFile, defaultCACertPath, time.Now())
+				if !renew {
+					continue
+				}
+				log.Infof("Renewing K8S-signed cert for %v: %s", names, reason)
+				if err := issue(); err != nil {
+					log.Errorf("error in renewing certs, %v", err)
+				}
+			}
+		}()
+		return nil
+	})
+	return nil
+}
+
+// csrCertNeedsRenewal returns whether the serving certificate in certFile has
+// to be renewed, and why: it is invalid, close to expiry or no longer verifies
+// against the CA in caFile, e.g. after a rotation of the cluster CA.
+func csrCertNeedsRenewal(certFile, caFile string, now time.Time) (bool, string) {
+	certChain, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return true, err.Error()
+	}
+	cert, err := util.ParsePemEncodedCertificate(certChain)
+	if err != nil {
+		return true, err.Error()
+	}
+	if needsRotation(cert, now) {
+		return true, fmt.Sprintf("it expires at %v", cert.NotAfter.Format(time.RFC3339))
+	}
+	caCert, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		// keep the certificate until the CA is readable again
+		log.Warnf("failed to read the cluster CA %s: %v", caFile, err)
+		return false, ""
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caCert) {
+		log.Warnf("invalid cluster CA %s", caFile)
+		return false, ""
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now}); err != nil {
+		return true, fmt.Sprintf("it no longer verifies against the cluster CA: %v", err)
+	}
+	return false, ""
+}
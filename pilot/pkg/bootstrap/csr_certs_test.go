@@ -0,0 +1,96 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"istio.io/istio/security/pkg/pki/util"
+)
+
+func TestCSRCertNeedsRenewal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "istiod_csr_certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	genCA := func() ([]byte, []byte) {
+		t.Helper()
+		certPEM, keyPEM, err := util.GenCertKeyFromOptions(util.CertOptions{
+			NotBefore: now, TTL: 365 * 24 * time.Hour, Org: "cluster", IsCA: true, IsSelfSigned: true, RSAKeySize: 2048,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certPEM, keyPEM
+	}
+	caCert, caKey := genCA()
+	signer, err := util.ParsePemEncodedCertificate(caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signerKey, err := util.ParsePemEncodedKey(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, _, err := util.GenCertKeyFromOptions(util.CertOptions{
+		Host: "istiod.istio-system.svc", NotBefore: now, TTL: 30 * 24 * time.Hour,
+		SignerCert: signer, SignerPriv: signerKey, IsServer: true, RSAKeySize: 2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, _ := genCA()
+
+	write := func(name string, b []byte) string {
+		t.Helper()
+		p := path.Join(dir, name)
+		if err := ioutil.WriteFile(p, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	certFile := write("cert-chain.pem", append(append([]byte{}, certPEM...), caCert...))
+	caFile := write("ca.crt", caCert)
+	rotatedCAFile := write("rotated-ca.crt", otherCA)
+
+	cases := []struct {
+		name     string
+		certFile string
+		caFile   string
+		now      time.Time
+		want     bool
+	}{
+		{"valid", certFile, caFile, now.Add(time.Hour), false},
+		{"close to expiry", certFile, caFile, now.Add(21 * 24 * time.Hour), true},
+		{"cluster CA rotated", certFile, rotatedCAFile, now.Add(time.Hour), true},
+		{"missing certificate", path.Join(dir, "missing.pem"), caFile, now, true},
+		{"missing cluster CA", certFile, path.Join(dir, "missing.pem"), now, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, reason := csrCertNeedsRenewal(c.certFile, c.caFile, c.now)
+			if got != c.want {
+				t.Fatalf("got renewal %v (%s), want %v", got, reason, c.want)
+			}
+		})
+	}
+}
@@ -16,14 +16,30 @@ package bootstrap
 
 import (
 	"encoding/json"
+	"fmt"
+	"time"
 
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/util/gogoprotomarshal"
 	"istio.io/pkg/filewatcher"
 	"istio.io/pkg/log"
+	"istio.io/pkg/monitoring"
 	"istio.io/pkg/version"
 )
 
+// meshConfigHealthInterval is how often the health of the mesh config source is recorded.
+const meshConfigHealthInterval = 10 * time.Second
+
+var meshConfigDegradedGauge = monitoring.NewGauge(
+	"pilot_mesh_config_degraded",
+	"Whether the mesh config file has been unreadable for longer than PILOT_MESH_CONFIG_STALENESS_THRESHOLD.",
+)
+
+func init() {
+	monitoring.MustRegister(meshConfigDegradedGauge)
+}
+
 // initMeshConfiguration creates the mesh in the pilotConfig from the input arguments.
 func (s *Server) initMeshConfiguration(args *PilotArgs, fileWatcher filewatcher.FileWatcher) {
 	log.Infoa("initializing mesh configuration ", args.MeshConfigFile)
@@ -41,6 +57,10 @@ func (s *Server) initMeshConfiguration(args *PilotArgs, fileWatcher filewatcher.
 	if args.MeshConfigFile != "" {
 		s.environment.Watcher, err = mesh.NewWatcher(fileWatcher, args.MeshConfigFile)
 		if err == nil {
+			if w, ok := s.environment.Watcher.(mesh.ReloadableWatcher); ok {
+				s.reloadHandlers = append(s.reloadHandlers, w.Reload)
+			}
+			s.initMeshConfigHealth(features.MeshConfigStalenessThreshold)
 			return
 		}
 		log.Warnf("Watching mesh config file %s failed: %v", args.MeshConfigFile, err)
@@ -51,6 +71,54 @@ func (s *Server) initMeshConfiguration(args *PilotArgs, fileWatcher filewatcher.
 	s.environment.Watcher = mesh.NewFixedWatcher(&meshConfig)
 }
 
+// initMeshConfigHealth reports Istiod as degraded, in readiness and metrics, while the
+// mesh config source has been failing for longer than the threshold.
+func (s *Server) initMeshConfigHealth(threshold time.Duration) {
+	w, ok := s.environment.Watcher.(mesh.SourceWatcher)
+	if !ok || threshold <= 0 {
+		return
+	}
+	check := func() error {
+		err := meshConfigDegraded(w, threshold, time.Now())
+		if err != nil {
+			meshConfigDegradedGauge.Record(1)
+		} else {
+			meshConfigDegradedGauge.Record(0)
+		}
+		return err
+	}
+	s.addReadinessProbe("mesh config", func() (bool, error) {
+		err := check()
+		return err == nil, err
+	})
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go func() {
+			t := time.NewTicker(meshConfigHealthInterval)
+			defer t.Stop()
+			for {
+				select {
+				case <-t.C:
+					_ = check()
+				case <-stop:
+					return
+				}
+			}
+		}()
+		return nil
+	})
+}
+
+// meshConfigDegraded returns an error if reading the mesh config source has
+// been failing for at least the threshold.
+func meshConfigDegraded(w mesh.SourceWatcher, threshold time.Duration, now time.Time) error {
+	since := w.FailingSince()
+	if since.IsZero() || now.Sub(since) < threshold {
+		return nil
+	}
+	return fmt.Errorf("mesh config unreadable since %v, serving the last valid configuration",
+		since.Format(time.RFC3339))
+}
+
 // initMeshNetworks loads the mesh networks configuration from the file provided
 // in the args and add a watcher for changes in this file.
 func (s *Server) initMeshNetworks(args *PilotArgs, fileWatcher filewatcher.FileWatcher) {
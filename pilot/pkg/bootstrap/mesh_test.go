@@ -0,0 +1,52 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+type failingWatcher struct {
+	mesh.Watcher
+	since time.Time
+}
+
+func (w failingWatcher) FailingSince() time.Time {
+	return w.since
+}
+
+func TestMeshConfigDegraded(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name    string
+		since   time.Time
+		wantErr bool
+	}{
+		{"healthy", time.Time{}, false},
+		{"failing within threshold", now.Add(-time.Second), false},
+		{"failing beyond threshold", now.Add(-time.Minute), true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := meshConfigDegraded(failingWatcher{since: c.since}, 30*time.Second, now)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -98,6 +98,36 @@ type DiscoveryServerOptions struct {
 type InjectionOptions struct {
 	// Directory of injection related config files.
 	InjectionDirectory string
+
+	// InsecurePort, if positive, serves injection without TLS on localhost, for local testing.
+	InsecurePort int
+
+	// TemplateOverrideDirectory holds injection config files taking precedence over InjectionDirectory.
+	TemplateOverrideDirectory string
+
+	// UDSPath, if set, serves injection without TLS on a unix socket, for test harnesses.
+	UDSPath string
+
+	// AuditLogFile, if set, records every injection decision as a JSON line.
+	AuditLogFile string
+
+	// FailurePolicy and ReinvocationPolicy, if set, are patched onto the
+	// injection webhook with its caBundle.
+	FailurePolicy      string
+	ReinvocationPolicy string
+
+	// InjectNamespaces, if set, and SkipNamespaces are the globs of the
+	// namespaces injected and never injected, enforced by the injector.
+	InjectNamespaces []string
+	SkipNamespaces   []string
+
+	// ConfigMapName, if set, is the injection ConfigMap of the istiod
+	// namespace watched with the Kubernetes API, in addition to the files.
+	ConfigMapName string
+
+	// WatchMeshConfigMap, if set, watches the mesh ConfigMap of the revision
+	// in the istiod namespace with the Kubernetes API, in addition to the file.
+	WatchMeshConfigMap bool
 }
 
 type MCPOptions struct {
@@ -111,6 +141,22 @@ type TLSOptions struct {
 	CaCertFile string
 	CertFile   string
 	KeyFile    string
+
+	// CertSecretName, if set, is the Secret, name or namespace/name, holding
+	// the serving certificate, used instead of the files and reloaded on
+	// every update of the Secret.
+	CertSecretName string
+	// SelfSignedCerts, if set, generates a CA and a serving certificate into
+	// the CertSecretName Secret, istiod-self-signed-certs by default, and
+	// rotates them before they expire.
+	SelfSignedCerts bool
+
+	// MinVersion and CipherSuites restrict the TLS of the webhook HTTPS server.
+	MinVersion   string
+	CipherSuites []string
+	// ClientCAFile, if set, holds the CAs verifying the client certificates
+	// required to call the injection webhook.
+	ClientCAFile string
 }
 
 var PodNamespaceVar = env.RegisterStringVar("POD_NAMESPACE", constants.IstioSystemNamespace, "")
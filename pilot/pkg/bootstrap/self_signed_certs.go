@@ -0,0 +1,323 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/webhooks"
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/env"
+	"istio.io/pkg/log"
+)
+
+const (
+	// defaultSelfSignedCertsSecretName is the Secret holding the certificates of
+	// --selfSignedCerts, unless --certSecretName is set.
+	defaultSelfSignedCertsSecretName = "istiod-self-signed-certs"
+
+	// The signing CA is kept in the Secret next to the serving certificate, so
+	// all replicas and restarts share it.
+	selfSignedCACertKey = "ca-cert.pem"
+	selfSignedCAKeyKey  = "ca-key.pem"
+
+	selfSignedCertsOrg = "Istio"
+)
+
+var (
+	selfSignedCertTTL = env.RegisterDurationVar("PILOT_SELF_SIGNED_CERT_TTL", 90*24*time.Hour,
+		"The TTL of the serving certificate generated by --selfSignedCerts. "+
+			"It is rotated once less than a third of its TTL is left.")
+
+	selfSignedCertsCATTL = env.RegisterDurationVar("PILOT_SELF_SIGNED_CERTS_CA_TTL", 10*365*24*time.Hour,
+		"The TTL of the CA generated by --selfSignedCerts. "+
+			"It is rotated once less than a third of its TTL is left, and trusted until it expires.")
+
+	selfSignedCertsCheckInterval = env.RegisterDurationVar("PILOT_SELF_SIGNED_CERTS_CHECK_INTERVAL", time.Hour,
+		"How often the certificates generated by --selfSignedCerts are checked for rotation.")
+)
+
+// selfSignedCerts generates a CA and a serving certificate signed by it into a
+// Secret, and rotates them before they expire. The Secret is the source of
+// truth: replicas racing to create or rotate it are resolved by the API
+// server, the losers loading the certificates of the winner.
+type selfSignedCerts struct {
+	secrets corev1client.SecretInterface
+	name    string
+	hosts   []string
+	certTTL time.Duration
+	caTTL   time.Duration
+	now     func() time.Time
+	// trusted returns whether the caBundle patched onto the webhook configs
+	// trusts the CA, so the serving certificate can be signed by it. Nil
+	// trusts any CA.
+	trusted func(ca *x509.Certificate) bool
+}
+
+// reconcile creates the Secret, or rotates its certificates if needed.
+func (c *selfSignedCerts) reconcile() error {
+	secret, err := c.secrets.Get(context.TODO(), c.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		data, _, err := c.rotate(nil)
+		if err != nil {
+			return err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: c.name},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		if _, err := c.secrets.Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to create self-signed certificates secret %s: %v", c.name, err)
+		}
+		log.Infof("Created self-signed certificates secret %s for %v", c.name, c.hosts)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	data, rotated, err := c.rotate(secret.Data)
+	if err != nil {
+		return err
+	}
+	if !rotated {
+		return nil
+	}
+	secret.Data = data
+	// the resource version of the Secret read keeps replicas from overwriting each other
+	if _, err := c.secrets.Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to rotate self-signed certificates secret %s: %v", c.name, err)
+	}
+	log.Infof("Rotated self-signed certificates secret %s", c.name)
+	return nil
+}
+
+// rotate returns the data of the Secret with the CA and the serving certificate
+// issued anew if missing, invalid or close to expiry, and whether they were.
+// A CA rotated is trusted in ca.crt next to its successor until it expires, so
+// the caBundle keeps verifying serving certificates still signed by it. A valid
+// serving certificate is only switched to a new CA on a later reconcile, once
+// the caBundle trusts it, so the webhooks never serve a certificate the API
+// server cannot verify yet.
+func (c *selfSignedCerts) rotate(data map[string][]byte) (map[string][]byte, bool, error) {
+	now := c.now()
+	caCertPEM, caKeyPEM := data[selfSignedCACertKey], data[selfSignedCAKeyKey]
+	caCert, err := util.ParsePemEncodedCertificate(caCertPEM)
+	caRotated := err != nil || needsRotation(caCert, now)
+	if !caRotated {
+		_, err = util.ParsePemEncodedKey(caKeyPEM)
+		caRotated = err != nil
+	}
+	if caRotated {
+		caCertPEM, caKeyPEM, err = util.GenCertKeyFromOptions(util.CertOptions{
+			NotBefore:    now,
+			TTL:          c.caTTL,
+			Org:          selfSignedCertsOrg,
+			IsCA:         true,
+			IsSelfSigned: true,
+			RSAKeySize:   2048,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to generate self-signed CA: %v", err)
+		}
+		if caCert, err = util.ParsePemEncodedCertificate(caCertPEM); err != nil {
+			return nil, false, err
+		}
+	}
+
+	certPEM, keyPEM := data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey]
+	cert, err := util.ParsePemEncodedCertificate(certPEM)
+	signedByCA := err == nil && cert.CheckSignatureFrom(caCert) == nil
+	reissue := err != nil || needsRotation(cert, now) || !signedByCA || !sameHosts(cert.DNSNames, c.hosts)
+	if reissue && err == nil && now.Before(cert.NotAfter) && !signedByCA && (caRotated || !c.caTrusted(caCert)) {
+		// publish the new CA first, the serving certificate is still valid
+		log.Infof("Keeping the self-signed serving certificate until the caBundle trusts the new CA")
+		reissue = false
+	}
+	if !caRotated && !reissue {
+		return data, false, nil
+	}
+	if reissue {
+		caKey, err := util.ParsePemEncodedKey(caKeyPEM)
+		if err != nil {
+			return nil, false, err
+		}
+		certPEM, keyPEM, err = util.GenCertKeyFromOptions(util.CertOptions{
+			Host:       strings.Join(c.hosts, ","),
+			NotBefore:  now,
+			TTL:        c.certTTL,
+			SignerCert: caCert,
+			SignerPriv: caKey,
+			Org:        selfSignedCertsOrg,
+			IsServer:   true,
+			RSAKeySize: 2048,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to generate self-signed serving certificate: %v", err)
+		}
+	}
+
+	// trust the current CA first, then the previous ones still valid
+	bundle := append([]byte{}, caCertPEM...)
+	for _, root := range splitPEMCerts(data[corev1.ServiceAccountRootCAKey]) {
+		if r, err := util.ParsePemEncodedCertificate(root); err == nil && now.Before(r.NotAfter) &&
+			!bytes.Equal(r.Raw, caCert.Raw) {
+			bundle = append(bundle, root...)
+		}
+	}
+	return map[string][]byte{
+		corev1.TLSCertKey:              certPEM,
+		corev1.TLSPrivateKeyKey:        keyPEM,
+		corev1.ServiceAccountRootCAKey: bundle,
+		selfSignedCACertKey:            caCertPEM,
+		selfSignedCAKeyKey:             caKeyPEM,
+	}, true, nil
+}
+
+func (c *selfSignedCerts) caTrusted(ca *x509.Certificate) bool {
+	return c.trusted == nil || c.trusted(ca)
+}
+
+// webhookTrustsCA returns whether the caBundle of the injection webhook config
+// trusts the CA. It does when there is no webhook config to patch.
+func webhookTrustsCA(client kubernetes.Interface, configName string, ca *x509.Certificate) bool {
+	if configName == "" {
+		return true
+	}
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+		Get(context.TODO(), configName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true
+	} else if err != nil {
+		log.Warnf("Failed to read the caBundle of the webhook config %s: %v", configName, err)
+		return false
+	}
+	for _, w := range config.Webhooks {
+		if webhooks.IsWebhookEntry(w.Name, webhookName) && !bundleTrusts(w.ClientConfig.CABundle, ca) {
+			return false
+		}
+	}
+	return true
+}
+
+// bundleTrusts returns whether the PEM encoded bundle holds the CA.
+func bundleTrusts(bundle []byte, ca *x509.Certificate) bool {
+	for _, root := range splitPEMCerts(bundle) {
+		if r, err := util.ParsePemEncodedCertificate(root); err == nil && bytes.Equal(r.Raw, ca.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsRotation returns true once less than a third of the lifetime of the certificate is left.
+func needsRotation(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotAfter.Add(-lifetime / 3))
+}
+
+func sameHosts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPEMCerts returns the PEM encoded certificates of a bundle.
+func splitPEMCerts(bundle []byte) [][]byte {
+	const end = "-----END CERTIFICATE-----"
+	var certs [][]byte
+	for {
+		i := bytes.Index(bundle, []byte(end))
+		if i < 0 {
+			return certs
+		}
+		i += len(end)
+		cert := bytes.TrimSpace(bundle[:i])
+		certs = append(certs, append(append([]byte{}, cert...), '\n'))
+		bundle = bundle[i:]
+	}
+}
+
+// initSelfSignedCerts generates the serving certificate into a Secret, then
+// loads it from the Secret like --certSecretName. The CA of the Secret becomes
+// the caBundle patched onto the webhook configs.
+func (s *Server) initSelfSignedCerts(args *PilotArgs, host string) error {
+	if s.kubeClient == nil {
+		return fmt.Errorf("--selfSignedCerts requires a Kubernetes client")
+	}
+	namespace, name, err := parseCertSecretName(certSecretName(args.ServerOptions.TLSOptions), args.Namespace)
+	if err != nil {
+		return err
+	}
+	c := &selfSignedCerts{
+		secrets: s.kubeClient.CoreV1().Secrets(namespace),
+		name:    name,
+		hosts:   istiodDNSNames(host, features.IstiodServiceCustomHost.Get(), args.Namespace),
+		certTTL: selfSignedCertTTL.Get(),
+		caTTL:   selfSignedCertsCATTL.Get(),
+		now:     time.Now,
+		trusted: func(ca *x509.Certificate) bool {
+			return webhookTrustsCA(s.kubeClient, features.InjectionWebhookConfigName.Get(), ca)
+		},
+	}
+	if err := c.reconcile(); err != nil {
+		return err
+	}
+	if err := s.initCertificateSecret(args); err != nil {
+		return err
+	}
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go func() {
+			ticker := time.NewTicker(selfSignedCertsCheckInterval.Get())
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					if err := c.reconcile(); err != nil {
+						log.Errorf("error in rotating self-signed certs, %v", err)
+					}
+				}
+			}
+		}()
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/security/pkg/pki/util"
+)
+
+func TestSelfSignedCerts(t *testing.T) {
+	now := time.Now()
+	c := &selfSignedCerts{
+		secrets: fake.NewSimpleClientset().CoreV1().Secrets("istio-system"),
+		name:    defaultSelfSignedCertsSecretName,
+		hosts:   []string{"istiod.istio-system.svc", "istiod-remote.istio-system.svc"},
+		certTTL: 24 * time.Hour,
+		caTTL:   30 * 24 * time.Hour,
+		now:     func() time.Time { return now },
+	}
+	secret := func() *corev1.Secret {
+		t.Helper()
+		if err := c.reconcile(); err != nil {
+			t.Fatal(err)
+		}
+		s, err := c.secrets.Get(context.TODO(), c.name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the serving certificate verifies against the CA bundle
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(s.Data[corev1.ServiceAccountRootCAKey]) {
+			t.Fatalf("invalid CA bundle %q", s.Data[corev1.ServiceAccountRootCAKey])
+		}
+		cert, err := util.ParsePemEncodedCertificate(s.Data[corev1.TLSCertKey])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: c.hosts[0], Roots: roots, CurrentTime: now}); err != nil {
+			t.Fatalf("serving certificate does not verify: %v", err)
+		}
+		return s
+	}
+
+	created := secret()
+	if created.Type != corev1.SecretTypeTLS {
+		t.Fatalf("got secret type %v", created.Type)
+	}
+
+	// nothing to rotate yet
+	now = now.Add(12 * time.Hour)
+	if s := secret(); !bytes.Equal(s.Data[corev1.TLSCertKey], created.Data[corev1.TLSCertKey]) {
+		t.Fatalf("serving certificate rotated before two thirds of its TTL")
+	}
+
+	// the serving certificate is rotated, signed by the same CA
+	now = now.Add(6 * time.Hour)
+	rotated := secret()
+	if bytes.Equal(rotated.Data[corev1.TLSCertKey], created.Data[corev1.TLSCertKey]) {
+		t.Fatalf("serving certificate not rotated")
+	}
+	if !bytes.Equal(rotated.Data[selfSignedCACertKey], created.Data[selfSignedCACertKey]) {
+		t.Fatalf("CA rotated before two thirds of its TTL")
+	}
+
+	// the CA is rotated, the previous one still trusted until it expires
+	now = now.Add(21 * 24 * time.Hour)
+	caRotated := secret()
+	if bytes.Equal(caRotated.Data[selfSignedCACertKey], created.Data[selfSignedCACertKey]) {
+		t.Fatalf("CA not rotated")
+	}
+	if got := len(splitPEMCerts(caRotated.Data[corev1.ServiceAccountRootCAKey])); got != 2 {
+		t.Fatalf("got %d CAs in the bundle, want 2", got)
+	}
+
+	// the hosts changed
+	c.hosts = []string{"istiod.istio-system.svc"}
+	if s := secret(); bytes.Equal(s.Data[corev1.TLSCertKey], caRotated.Data[corev1.TLSCertKey]) {
+		t.Fatalf("serving certificate not reissued for new hosts")
+	}
+}
+
+func TestSelfSignedCertsCARotation(t *testing.T) {
+	now := time.Now()
+	trusted := false
+	c := &selfSignedCerts{
+		secrets: fake.NewSimpleClientset().CoreV1().Secrets("istio-system"),
+		name:    defaultSelfSignedCertsSecretName,
+		hosts:   []string{"istiod.istio-system.svc"},
+		certTTL: 24 * time.Hour,
+		caTTL:   3 * 24 * time.Hour,
+		now:     func() time.Time { return now },
+		trusted: func(*x509.Certificate) bool { return trusted },
+	}
+	secret := func() *corev1.Secret {
+		t.Helper()
+		if err := c.reconcile(); err != nil {
+			t.Fatal(err)
+		}
+		s, err := c.secrets.Get(context.TODO(), c.name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	signedBy := func(s *corev1.Secret) *x509.Certificate {
+		t.Helper()
+		cert, err := util.ParsePemEncodedCertificate(s.Data[corev1.TLSCertKey])
+		if err != nil {
+			t.Fatal(err)
+		}
+		ca, err := util.ParsePemEncodedCertificate(s.Data[selfSignedCACertKey])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert.CheckSignatureFrom(ca) != nil {
+			return nil
+		}
+		return ca
+	}
+
+	secret()
+	// the serving certificate is rotated just before the CA
+	now = now.Add(47 * time.Hour)
+	before := secret()
+
+	// the new CA is published, the serving certificate is kept
+	now = now.Add(2 * time.Hour)
+	published := secret()
+	if bytes.Equal(published.Data[selfSignedCACertKey], before.Data[selfSignedCACertKey]) {
+		t.Fatalf("CA not rotated")
+	}
+	if !bytes.Equal(published.Data[corev1.TLSCertKey], before.Data[corev1.TLSCertKey]) || signedBy(published) != nil {
+		t.Fatalf("serving certificate switched to the new CA before the caBundle trusts it")
+	}
+	if got := len(splitPEMCerts(published.Data[corev1.ServiceAccountRootCAKey])); got != 2 {
+		t.Fatalf("got %d CAs in the bundle, want 2", got)
+	}
+	if s := secret(); !bytes.Equal(s.Data[corev1.TLSCertKey], before.Data[corev1.TLSCertKey]) {
+		t.Fatalf("serving certificate switched to the new CA before the caBundle trusts it")
+	}
+
+	// switched once the caBundle is patched
+	trusted = true
+	if s := secret(); signedBy(s) == nil {
+		t.Fatalf("serving certificate not switched to the new CA")
+	}
+}
+
+func TestBundleTrusts(t *testing.T) {
+	caPEM, _, err := util.GenCertKeyFromOptions(util.CertOptions{TTL: time.Hour, Org: "Istio", IsCA: true, IsSelfSigned: true,
+		RSAKeySize: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPEM, _, err := util.GenCertKeyFromOptions(util.CertOptions{TTL: time.Hour, Org: "Istio", IsCA: true, IsSelfSigned: true,
+		RSAKeySize: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := util.ParsePemEncodedCertificate(caPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bundleTrusts(append(append([]byte{}, otherPEM...), caPEM...), ca) {
+		t.Fatal("CA of the bundle not trusted")
+	}
+	if bundleTrusts(otherPEM, ca) {
+		t.Fatal("CA missing from the bundle trusted")
+	}
+}
+
+func TestSplitPEMCerts(t *testing.T) {
+	a := "-----BEGIN CERTIFICATE-----\na\n-----END CERTIFICATE-----\n"
+	b := "-----BEGIN CERTIFICATE-----\nb\n-----END CERTIFICATE-----\n"
+	bundle := []byte(a + "\n" + b)
+	got := splitPEMCerts(bundle)
+	if len(got) != 2 || string(got[0]) != a || string(got[1]) != b {
+		t.Fatalf("got %q", got)
+	}
+	if string(bundle) != a+"\n"+b {
+		t.Fatalf("bundle modified: %q", bundle)
+	}
+}
@@ -160,6 +160,12 @@ type Server struct {
 	istiodCert   *tls.Certificate
 	jwtPath      string
 
+	// certReloadHandlers are called with the istiod certificate once it is reloaded.
+	certReloadHandlers []func(cert *tls.Certificate)
+
+	// reloadHandlers are called by Reload.
+	reloadHandlers []func()
+
 	// startFuncs keeps track of functions that need to be executed when Istiod starts.
 	startFuncs []startFunc
 	// requiredTerminations keeps track of components that should block server exit
@@ -169,6 +175,10 @@ type Server struct {
 	statusReporter       *status.Reporter
 	readinessProbes      map[string]readinessProbe
 
+	// leaderElections are the elections of the leader-elected components, reported by /statusz.
+	leaderMu        sync.Mutex
+	leaderElections []*leaderelection.LeaderElection
+
 	// duration used for graceful shutdown.
 	shutdownDuration time.Duration
 
@@ -188,13 +198,14 @@ func NewServer(args *PilotArgs) (*Server, error) {
 	e.ServiceDiscovery = ac
 
 	s := &Server{
-		clusterID:       getClusterID(args),
-		environment:     e,
-		XDSServer:       xds.NewDiscoveryServer(e, args.Plugins),
-		fileWatcher:     filewatcher.NewWatcher(),
-		httpMux:         http.NewServeMux(),
-		monitoringMux:   http.NewServeMux(),
-		readinessProbes: make(map[string]readinessProbe),
+		clusterID:        getClusterID(args),
+		environment:      e,
+		XDSServer:        xds.NewDiscoveryServer(e, args.Plugins),
+		fileWatcher:      filewatcher.NewWatcher(),
+		httpMux:          http.NewServeMux(),
+		monitoringMux:    http.NewServeMux(),
+		readinessProbes:  make(map[string]readinessProbe),
+		shutdownDuration: args.ShutdownDuration,
 	}
 
 	if args.ShutdownDuration == 0 {
@@ -263,7 +274,9 @@ func NewServer(args *PilotArgs) (*Server, error) {
 	}
 
 	// common https server for webhooks (e.g. injection, validation)
-	s.initSecureWebhookServer(args)
+	if err := s.initSecureWebhookServer(args); err != nil {
+		return nil, fmt.Errorf("error initializing secure webhook server: %v", err)
+	}
 
 	wh, err := s.initSidecarInjector(args)
 	if err != nil {
@@ -474,6 +487,10 @@ func (s *Server) initKubeClient(args *PilotArgs) error {
 		if err != nil {
 			return fmt.Errorf("failed creating kube config: %v", err)
 		}
+		if err := kubelib.SetProxy(s.kubeRestConfig, args.RegistryOptions.KubeOptions.KubernetesAPIProxy,
+			args.RegistryOptions.KubeOptions.KubernetesAPINoProxy); err != nil {
+			return fmt.Errorf("failed creating kube config: %v", err)
+		}
 
 		s.kubeClient, err = kubelib.NewClient(kubelib.NewClientConfigForRestConfig(s.kubeRestConfig))
 		if err != nil {
@@ -539,6 +556,8 @@ func (s *Server) initIstiodAdminServer(args *PilotArgs, wh *inject.Webhook) erro
 	// Readiness Handler.
 	s.httpMux.HandleFunc("/ready", s.istiodReadyHandler)
 
+	s.monitoringMux.HandleFunc(statuszPath, s.statuszHandler(args, wh))
+
 	s.HTTPListener = listener
 	return nil
 }
@@ -725,6 +744,15 @@ func (s *Server) addReadinessProbe(name string, fn readinessProbe) {
 	s.readinessProbes[name] = fn
 }
 
+// newLeaderElection creates the leader election of a component of the server.
+func (s *Server) newLeaderElection(args *PilotArgs, electionID string) *leaderelection.LeaderElection {
+	le := leaderelection.NewLeaderElection(args.Namespace, args.PodName, electionID, s.kubeClient)
+	s.leaderMu.Lock()
+	s.leaderElections = append(s.leaderElections, le)
+	s.leaderMu.Unlock()
+	return le
+}
+
 // addRequireStartFunc adds a function that should terminate before the serve shuts down
 // This is useful to do cleanup activities
 // This is does not guarantee they will terminate gracefully - best effort only
@@ -832,6 +860,12 @@ func (s *Server) initRegistryEventHandlers() error {
 
 // initIstiodCerts creates Istiod certificates and also sets up watches to them.
 func (s *Server) initIstiodCerts(args *PilotArgs, host string) error {
+	if args.ServerOptions.TLSOptions.SelfSignedCerts {
+		return s.initSelfSignedCerts(args, host)
+	}
+	if hasCertSecret(args.ServerOptions.TLSOptions) {
+		return s.initCertificateSecret(args)
+	}
 	if err := s.maybeInitDNSCerts(args, host); err != nil {
 		return fmt.Errorf("error initializing DNS certs: %v", err)
 	}
@@ -874,6 +908,9 @@ func (s *Server) initCertificateWatches(tlsOptions TLSOptions) error {
 			return fmt.Errorf("could not watch %v: %v", file, err)
 		}
 	}
+	s.reloadHandlers = append(s.reloadHandlers, func() {
+		s.reloadCertKeyPair(tlsOptions)
+	})
 	s.addStartFunc(func(stop <-chan struct{}) error {
 		go func() {
 			var keyCertTimerC <-chan time.Time
@@ -881,33 +918,7 @@ func (s *Server) initCertificateWatches(tlsOptions TLSOptions) error {
 				select {
 				case <-keyCertTimerC:
 					keyCertTimerC = nil
-					// Reload the certificates from the paths.
-					cert, err := s.getCertKeyPair(tlsOptions)
-					if err != nil {
-						log.Errorf("error in reloading certs, %v", err)
-						// TODO: Add metrics?
-						break
-					}
-					s.certMu.Lock()
-					s.istiodCert = &cert
-					s.certMu.Unlock()
-
-					var cnum int
-					log.Info("Istiod certificates are reloaded")
-					for _, c := range cert.Certificate {
-						if x509Cert, err := x509.ParseCertificates(c); err != nil {
-							log.Infof("x509 cert [%v] - ParseCertificates() error: %v\n", cnum, err)
-							cnum++
-						} else {
-							for _, c := range x509Cert {
-								log.Infof("x509 cert [%v] - Issuer: %q, Subject: %q, SN: %x, NotBefore: %q, NotAfter: %q\n",
-									cnum, c.Issuer, c.Subject, c.SerialNumber,
-									c.NotBefore.Format(time.RFC3339), c.NotAfter.Format(time.RFC3339))
-								cnum++
-							}
-						}
-					}
-
+					s.reloadCertKeyPair(tlsOptions)
 				case <-s.fileWatcher.Events(certFile):
 					if keyCertTimerC == nil {
 						keyCertTimerC = time.After(watchDebounceDelay)
@@ -930,6 +941,48 @@ func (s *Server) initCertificateWatches(tlsOptions TLSOptions) error {
 	return nil
 }
 
+// reloadCertKeyPair loads the istiod certificate from its paths again, and
+// calls the certReloadHandlers.
+func (s *Server) reloadCertKeyPair(tlsOptions TLSOptions) {
+	cert, err := s.getCertKeyPair(tlsOptions)
+	if err != nil {
+		log.Errorf("error in reloading certs, %v", err)
+		// TODO: Add metrics?
+		return
+	}
+	s.certMu.Lock()
+	s.istiodCert = &cert
+	s.certMu.Unlock()
+	for _, h := range s.certReloadHandlers {
+		h(&cert)
+	}
+
+	var cnum int
+	log.Info("Istiod certificates are reloaded")
+	for _, c := range cert.Certificate {
+		if x509Cert, err := x509.ParseCertificates(c); err != nil {
+			log.Infof("x509 cert [%v] - ParseCertificates() error: %v\n", cnum, err)
+			cnum++
+		} else {
+			for _, c := range x509Cert {
+				log.Infof("x509 cert [%v] - Issuer: %q, Subject: %q, SN: %x, NotBefore: %q, NotAfter: %q\n",
+					cnum, c.Issuer, c.Subject, c.SerialNumber,
+					c.NotBefore.Format(time.RFC3339), c.NotAfter.Format(time.RFC3339))
+				cnum++
+			}
+		}
+	}
+}
+
+// Reload reads again the serving certificate files, the mesh config file and
+// the injection configuration files, and patches the injection webhook
+// caBundle again, e.g. on SIGHUP.
+func (s *Server) Reload() {
+	for _, h := range s.reloadHandlers {
+		h()
+	}
+}
+
 // getCertKeyPair returns cert and key loaded in tls.Certificate.
 func (s *Server) getCertKeyPair(tlsOptions TLSOptions) (tls.Certificate, error) {
 	key, cert := s.getCertKeyPaths(tlsOptions)
@@ -937,6 +990,13 @@ func (s *Server) getCertKeyPair(tlsOptions TLSOptions) (tls.Certificate, error)
 	if err != nil {
 		return tls.Certificate{}, err
 	}
+	var certs []*x509.Certificate
+	for _, der := range keyPair.Certificate {
+		if c, err := x509.ParseCertificate(der); err == nil {
+			certs = append(certs, c)
+		}
+	}
+	checkCertClockSkew("serving", certs, time.Now(), certClockSkewTolerance)
 	return keyPair, nil
 }
 
@@ -1024,7 +1084,7 @@ func (s *Server) initNamespaceController(args *PilotArgs) {
 		// create namespace controller
 		nsController := kubecontroller.NewNamespaceController(s.fetchCARoot, s.kubeClient)
 		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
-			le := leaderelection.NewLeaderElection(args.Namespace, args.PodName, leaderelection.NamespaceController, s.kubeClient.Kube())
+			le := s.newLeaderElection(args, leaderelection.NamespaceController)
 			le.AddRunFunction(func(leaderStop <-chan struct{}) {
 				nsController.Run(leaderStop)
 			})
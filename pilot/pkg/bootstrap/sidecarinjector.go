@@ -15,11 +15,19 @@
 package bootstrap
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/leaderelection"
+	"istio.io/istio/pilot/pkg/serviceregistry/kube/controller"
+	"istio.io/istio/pkg/cmd"
 	"istio.io/istio/pkg/kube/inject"
 	"istio.io/istio/pkg/webhooks"
 	"istio.io/pkg/env"
@@ -29,10 +37,156 @@ import (
 const (
 	// Name of the webhook config in the config - no need to change it.
 	webhookName = "sidecar-injector.istio.io"
+
+	// webhookPatchInitialBackoff and webhookPatchMaxBackoff bound the delay
+	// between the attempts of the leader to patch the webhook config.
+	webhookPatchInitialBackoff = time.Second
+	webhookPatchMaxBackoff     = 5 * time.Minute
 )
 
 var (
 	injectionEnabled = env.RegisterBoolVar("INJECT_ENABLED", true, "Enable mutating webhook handler.")
+
+	injectionMetricsBackend = env.RegisterStringVar("INJECT_METRICS_BACKEND", inject.PrometheusMetricsBackend,
+		"Backend used to export injector metrics: prometheus or statsd. Prometheus metrics are served by the istiod monitoring port.")
+	injectionStatsdAddress = env.RegisterStringVar("INJECT_STATSD_ADDRESS", "",
+		"Address (host:port) of the statsd server injector metrics are pushed to when INJECT_METRICS_BACKEND is statsd.")
+
+	injectionAdmissionWorkers = env.RegisterIntVar("INJECT_ADMISSION_WORKERS", 0,
+		"Number of workers handling injection requests, shared fairly between namespaces. Zero disables the worker pool.")
+
+	injectionMaxQueuedAdmissions = env.RegisterIntVar("INJECT_MAX_QUEUED_ADMISSIONS", 0,
+		"Number of injection requests waiting for one of INJECT_ADMISSION_WORKERS. Requests beyond it are rejected "+
+			"with an error. Zero disables the limit.")
+	injectionAdmissionQueueTimeout = env.RegisterDurationVar("INJECT_ADMISSION_QUEUE_TIMEOUT", 0,
+		"How long an injection request waits for one of INJECT_ADMISSION_WORKERS before it is rejected. Zero waits until one is free.")
+
+	injectionWatchdogInterval = env.RegisterDurationVar("INJECT_WATCHDOG_INTERVAL", 0,
+		"How often the injector watchdog samples heap, goroutine and file watch usage. Zero disables the watchdog. "+
+			"The usage is that of the whole istiod process, including discovery.")
+	injectionWatchdogMaxHeapGrowthBytes = env.RegisterIntVar("INJECT_WATCHDOG_MAX_HEAP_GROWTH_BYTES", 0,
+		"Growth in bytes of the istiod heap, from its smallest size sampled, above which the injector watchdog trips. "+
+			"Zero disables the check.")
+	injectionWatchdogMaxGoroutines = env.RegisterIntVar("INJECT_WATCHDOG_MAX_GOROUTINES", 0,
+		"Goroutine count of istiod above which the injector watchdog trips. Zero disables the check.")
+	injectionWatchdogMaxWatches = env.RegisterIntVar("INJECT_WATCHDOG_MAX_WATCHES", 0,
+		"Number of inotify file watches held by istiod above which the injector watchdog trips. Zero disables the check.")
+	injectionWatchdogExit = env.RegisterBoolVar("INJECT_WATCHDOG_EXIT", false,
+		"If enabled, istiod shuts down gracefully when the injector watchdog trips so that Kubernetes restarts the pod. "+
+			"As the thresholds cover the whole process, this also stops discovery.")
+
+	injectionAllowedClientCNs = env.RegisterStringVar("INJECT_ALLOWED_CLIENT_CNS", "",
+		"Comma separated list of client certificate common names allowed to request injection. "+
+			"Requires client certificates to be verified by the HTTPS server.")
+	injectionAllowedClientOrganizations = env.RegisterStringVar("INJECT_ALLOWED_CLIENT_ORGANIZATIONS", "",
+		"Comma separated list of client certificate organizations allowed to request injection. "+
+			"Requires client certificates to be verified by the HTTPS server.")
+
+	injectionTokenAudiences = env.RegisterStringVar("INJECT_TOKEN_AUDIENCES", "",
+		"Comma separated audiences the bearer tokens of injection requests must be bound to, verified with the "+
+			"TokenReview API. For injectors reached through a URL webhook clientConfig, with the API servers "+
+			"configured to send tokens. Empty disables token authentication.")
+	injectionTokenAllowedUsers = env.RegisterStringVar("INJECT_TOKEN_ALLOWED_USERS", "",
+		"Comma separated users the tokens of INJECT_TOKEN_AUDIENCES may authenticate. Empty allows any user.")
+
+	injectionCanarySamples = env.RegisterIntVar("INJECT_CANARY_SAMPLES", 0,
+		"Number of recently injected pods a reloaded injection configuration is tested against before activation. "+
+			"Zero disables the check.")
+	injectionCanaryMaxFailureRatio = env.RegisterFloatVar("INJECT_CANARY_MAX_FAILURE_RATIO", 0,
+		"Ratio of the recently injected pods allowed to fail injection with a reloaded configuration before it is held.")
+
+	injectionCABundleRotation = env.RegisterBoolVar("INJECT_CA_BUNDLE_ROTATION", false,
+		"If enabled, the injection webhook caBundle follows the mesh root in the istio-ca-root-cert ConfigMap, or in the "+
+			"--certSecretName Secret, keeping the previous root trusted until the serving certificate is reissued by the new root.")
+
+	injectionManageWebhookConfig = env.RegisterBoolVar("INJECT_MANAGE_WEBHOOK_CONFIG", false,
+		"If enabled, the injection webhook config named by INJECTION_WEBHOOK_CONFIG_NAME is created from "+
+			"INJECT_WEBHOOK_CONFIG_TEMPLATE and restored when edited or deleted, instead of only patching its caBundle.")
+	injectionWebhookConfigTemplate = env.RegisterStringVar("INJECT_WEBHOOK_CONFIG_TEMPLATE", "",
+		"MutatingWebhookConfiguration managed by INJECT_MANAGE_WEBHOOK_CONFIG. Defaults to the webhook file of the "+
+			"injection directory.")
+	injectionWebhookEntrySpec = env.RegisterStringVar("INJECT_WEBHOOK_ENTRY_SPEC", "",
+		"File with a MutatingWebhook entry whose namespaceSelector, objectSelector, rules, failurePolicy and "+
+			"reinvocationPolicy are restored on the injection webhook entry together with its caBundle, when a stale "+
+			"apply of the webhook config clobbers them. --failurePolicy and --reinvocationPolicy take precedence.")
+
+	injectionEnrollmentStatus = env.RegisterBoolVar("INJECT_ENROLLMENT_STATUS", false,
+		"If enabled, Deployments and StatefulSets are annotated with a summary of the injection state of their pods.")
+
+	injectionDiscoveryGate = env.RegisterBoolVar("INJECT_DISCOVERY_READINESS_GATE", false,
+		"If enabled, Istiod is not ready while the discovery address of the mesh config cannot be reached, so pods are "+
+			"not injected with proxies unable to connect. Only for an injector whose proxies use a remote control plane.")
+	injectionDiscoveryGateTimeout = env.RegisterDurationVar("INJECT_DISCOVERY_READINESS_TIMEOUT", time.Second,
+		"Timeout of the connection attempts of INJECT_DISCOVERY_READINESS_GATE.")
+
+	injectionNotificationURL = env.RegisterStringVar("INJECT_NOTIFICATION_URL", "",
+		"URL the injector lifecycle events are posted to: configuration reloads, template changes, certificate "+
+			"rotations and repeated admission failures. Empty disables the notifications.")
+	injectionNotificationFormat = env.RegisterStringVar("INJECT_NOTIFICATION_FORMAT", inject.NotificationFormatJSON,
+		"Format of the notifications posted to INJECT_NOTIFICATION_URL: json or cloudevents.")
+	injectionNotificationFailureThreshold = env.RegisterIntVar("INJECT_NOTIFICATION_FAILURE_THRESHOLD", 5,
+		"Number of consecutive failed injections notified to INJECT_NOTIFICATION_URL.")
+
+	injectionTimeoutTuning = env.RegisterBoolVar("INJECT_WEBHOOK_TIMEOUT_TUNING", false,
+		"If enabled, the timeoutSeconds of the injection webhook follows the p99 admission latency plus "+
+			"INJECT_WEBHOOK_TIMEOUT_HEADROOM, within INJECT_WEBHOOK_TIMEOUT_MIN and INJECT_WEBHOOK_TIMEOUT_MAX. "+
+			"Requires INJECTION_WEBHOOK_CONFIG_NAME.")
+	injectionTimeoutMin = env.RegisterDurationVar("INJECT_WEBHOOK_TIMEOUT_MIN", 2*time.Second,
+		"Lowest timeout set by INJECT_WEBHOOK_TIMEOUT_TUNING.")
+	injectionTimeoutMax = env.RegisterDurationVar("INJECT_WEBHOOK_TIMEOUT_MAX", 10*time.Second,
+		"Highest timeout set by INJECT_WEBHOOK_TIMEOUT_TUNING.")
+	injectionTimeoutHeadroom = env.RegisterDurationVar("INJECT_WEBHOOK_TIMEOUT_HEADROOM", time.Second,
+		"Added to the p99 admission latency by INJECT_WEBHOOK_TIMEOUT_TUNING.")
+
+	injectionNamespaceCache = env.RegisterBoolVar("INJECT_NAMESPACE_CACHE", false,
+		"If enabled, the namespaces are kept in an informer backed cache, so injection requests make no API call "+
+			"to get the namespace of the pod. The cache can be relisted with a POST to "+
+			"/debug/inject_namespaces/refresh on the istiod debug port.")
+
+	injectionClustersFile = env.RegisterStringVar("INJECT_CLUSTERS_FILE", "",
+		"File listing the clusters whose API servers call the injector through a URL webhook clientConfig, "+
+			"with the users of their tokens or the common names of their certificates and their injection "+
+			"and mesh configuration files. Empty serves the injection of the local cluster only.")
+
+	injectionValuesKeyFile = env.RegisterStringVar("INJECT_VALUES_KEY_FILE", "",
+		"File of the base64 encoded AES-256 key decrypting the injection values files encrypted with "+
+			"istioctl experimental post-install webhook encrypt-values, e.g. mounted from a Secret. "+
+			"Empty loads the values files as plaintext.")
+
+	injectionDriftInterval = env.RegisterDurationVar("INJECT_DRIFT_VERIFICATION_INTERVAL", 0,
+		"If positive, how often a sample of the running injected pods is injected again with the active "+
+			"configuration and compared with what they run, reporting the drift in metrics and on "+
+			"/debug/inject_drift of the istiod debug port. Zero disables the verification.")
+	injectionDriftSampleSize = env.RegisterIntVar("INJECT_DRIFT_VERIFICATION_SAMPLE_SIZE", 100,
+		"Number of injected pods checked by each drift verification of INJECT_DRIFT_VERIFICATION_INTERVAL. "+
+			"The metrics and the report cover a whole pass over the pods.")
+
+	injectionCostSampleRate = env.RegisterFloatVar("INJECT_COST_SAMPLE_RATE", 0,
+		"Fraction of the injection admissions labeled with the namespace and template version of the pod in "+
+			"the CPU profiles of istiod, attributing the injection CPU time to them. Zero labels none.")
+
+	injectionStartupGate = env.RegisterBoolVar("INJECT_STARTUP_GATE", false,
+		"If enabled, the injection requests received before the injection configuration and the serving "+
+			"certificate pass the /readyz checks are answered with an admission error rather than injected.")
+	injectionStartupSoftFail = env.RegisterBoolVar("INJECT_STARTUP_SOFT_FAIL", false,
+		"If enabled, the pods gated by INJECT_STARTUP_GATE are admitted without injection instead of rejected "+
+			"with a 503 status.")
+
+	injectionFailureEvents = env.RegisterBoolVar("INJECT_FAILURE_EVENTS", false,
+		"If enabled, the failures to render the injection template or create the patch of a pod are recorded "+
+			"as InjectionFailed events on its namespace and ReplicaSet.")
+
+	injectionManagedFields = env.RegisterBoolVar("INJECT_MANAGED_FIELDS", false,
+		"If enabled, the fields injected into pods tracking managedFields are attributed to the "+
+			inject.FieldManager+" field manager, so later server-side applies of the pod manifests do not conflict with them.")
+
+	injectionWebhookJanitor = env.RegisterStringVar("INJECT_WEBHOOK_JANITOR", "",
+		"If set, injection webhook configs of other revisions whose services have no ready endpoint are reported "+
+			"(report) or deleted (remove, requires the delete permission on mutatingwebhookconfigurations). "+
+			"Empty disables the check.")
+	injectionWebhookJanitorGracePeriod = env.RegisterDurationVar("INJECT_WEBHOOK_JANITOR_GRACE_PERIOD", 10*time.Minute,
+		"How long the webhook config of a revision has to stay without a running injector before "+
+			"INJECT_WEBHOOK_JANITOR reports or deletes it.")
 )
 
 func (s *Server) initSidecarInjector(args *PilotArgs) (*inject.Webhook, error) {
@@ -52,30 +206,272 @@ func (s *Server) initSidecarInjector(args *PilotArgs) (*inject.Webhook, error) {
 	log.Info("initializing sidecar injector")
 
 	parameters := inject.WebhookParameters{
-		ConfigFile: filepath.Join(injectPath, "config"),
-		ValuesFile: filepath.Join(injectPath, "values"),
-		Env:        s.environment,
+		ConfigFile:          filepath.Join(injectPath, "config"),
+		ValuesFile:          filepath.Join(injectPath, "values"),
+		TemplateOverrideDir: args.InjectionOptions.TemplateOverrideDirectory,
+		Env:                 s.environment,
 		// Disable monitoring. The injection metrics will be picked up by Pilots metrics exporter already
-		MonitoringPort: -1,
-		Mux:            s.httpsMux,
-		Revision:       args.Revision,
+		MonitoringPort:   -1,
+		Mux:              s.httpsMux,
+		Revision:         args.Revision,
+		InsecurePort:     args.InjectionOptions.InsecurePort,
+		UDSPath:          args.InjectionOptions.UDSPath,
+		KubeClient:       s.kubeClient,
+		MetricsBackend:   injectionMetricsBackend.Get(),
+		StatsdAddress:    injectionStatsdAddress.Get(),
+		AdmissionWorkers: injectionAdmissionWorkers.Get(),
+		LoadShedding: inject.LoadSheddingOptions{
+			MaxQueued:    injectionMaxQueuedAdmissions.Get(),
+			QueueTimeout: injectionAdmissionQueueTimeout.Get(),
+		},
+		Watchdog: inject.WatchdogOptions{
+			Interval:           injectionWatchdogInterval.Get(),
+			MaxHeapGrowthBytes: uint64(injectionWatchdogMaxHeapGrowthBytes.Get()),
+			MaxGoroutines:      injectionWatchdogMaxGoroutines.Get(),
+			MaxWatches:         injectionWatchdogMaxWatches.Get(),
+		},
+		ClientAuth: inject.ClientAuthOptions{
+			AllowedCommonNames:   splitList(injectionAllowedClientCNs.Get()),
+			AllowedOrganizations: splitList(injectionAllowedClientOrganizations.Get()),
+			// the HTTPS server verifies the client certificates against the client CAs
+			RequireClientCertificate: args.ServerOptions.TLSOptions.ClientCAFile != "",
+		},
+		TokenAuth: inject.TokenAuthOptions{
+			Audiences:    splitList(injectionTokenAudiences.Get()),
+			AllowedUsers: splitList(injectionTokenAllowedUsers.Get()),
+		},
+		Canary: inject.CanaryOptions{
+			Samples:         injectionCanarySamples.Get(),
+			MaxFailureRatio: injectionCanaryMaxFailureRatio.Get(),
+		},
+		DiscoveryGate: inject.DiscoveryGateOptions{
+			Enabled: injectionDiscoveryGate.Get(),
+			Timeout: injectionDiscoveryGateTimeout.Get(),
+		},
+		ShutdownGracePeriod: s.shutdownDuration,
+		AuditLogFile:        args.InjectionOptions.AuditLogFile,
+		NamespaceCache:      injectionNamespaceCache.Get(),
+		FanInConfigFile:     injectionClustersFile.Get(),
+		DriftVerifier: inject.DriftVerifierOptions{
+			Interval:   injectionDriftInterval.Get(),
+			SampleSize: injectionDriftSampleSize.Get(),
+		},
+		CostSampleRate: injectionCostSampleRate.Get(),
+		StartupGate: inject.StartupGateOptions{
+			Enabled:  injectionStartupGate.Get(),
+			SoftFail: injectionStartupSoftFail.Get(),
+		},
+		FailureEvents: injectionFailureEvents.Get(),
+		ConfigMap: inject.ConfigMapWatchOptions{
+			Name:      args.InjectionOptions.ConfigMapName,
+			Namespace: args.Namespace,
+		},
+		NamespaceFilter: inject.NamespaceFilterOptions{
+			Inject: args.InjectionOptions.InjectNamespaces,
+			Skip:   args.InjectionOptions.SkipNamespaces,
+		},
+		Notifications: inject.NotificationOptions{
+			URL:              injectionNotificationURL.Get(),
+			Format:           injectionNotificationFormat.Get(),
+			FailureThreshold: injectionNotificationFailureThreshold.Get(),
+		},
+	}
+	if s.kubeClient != nil {
+		parameters.Informers = s.kubeClient.KubeInformer()
+	}
+	if args.InjectionOptions.WatchMeshConfigMap {
+		parameters.MeshConfigMap = inject.ConfigMapWatchOptions{
+			Name:      meshConfigMapName(args.Revision),
+			Namespace: args.Namespace,
+		}
+	}
+	if injectionManagedFields.Get() {
+		parameters.FieldManager = inject.FieldManager
+	}
+	if injectionWatchdogExit.Get() {
+		// shut down as on SIGTERM, so that istiod drains its connections
+		parameters.Watchdog.Shutdown = cmd.Terminate
+	}
+	if keyFile := injectionValuesKeyFile.Get(); keyFile != "" {
+		keys, err := inject.NewFileKeyProvider(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		parameters.ValuesKeys = keys
+	}
+
+	if s.httpsServer != nil {
+		parameters.ServingCertificate = func() (*tls.Certificate, error) {
+			return s.getIstiodCertificate(nil)
+		}
 	}
 
 	wh, err := inject.NewWebhook(parameters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create injection webhook: %v", err)
 	}
+	s.reloadHandlers = append(s.reloadHandlers, wh.Reload)
+	s.certReloadHandlers = append(s.certReloadHandlers, func(cert *tls.Certificate) {
+		data := map[string]string{}
+		if len(cert.Certificate) > 0 {
+			if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil {
+				data["serialNumber"] = fmt.Sprintf("%x", leaf.SerialNumber)
+				data["notAfter"] = leaf.NotAfter.Format(time.RFC3339)
+			}
+		}
+		wh.Notify(inject.EventCertRotated, data)
+	})
+	policies, err := webhooks.ParseWebhookPolicies(args.InjectionOptions.FailurePolicy, args.InjectionOptions.ReinvocationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if spec := injectionWebhookEntrySpec.Get(); spec != "" {
+		if policies, err = webhooks.LoadWebhookEntrySpec(spec, policies); err != nil {
+			return nil, err
+		}
+	}
 	// Patch cert if a webhook config name is provided.
 	// This requires RBAC permissions - a low-priv Istiod should not attempt to patch but rely on
 	// operator or CI/CD
+	if injectionManageWebhookConfig.Get() {
+		if features.InjectionWebhookConfigName.Get() == "" {
+			return nil, fmt.Errorf("INJECT_MANAGE_WEBHOOK_CONFIG requires INJECTION_WEBHOOK_CONFIG_NAME")
+		}
+		// the caBundle and timeout would be reverted to the template
+		if injectionCABundleRotation.Get() || injectionTimeoutTuning.Get() {
+			return nil, fmt.Errorf("INJECT_MANAGE_WEBHOOK_CONFIG cannot be combined with INJECT_CA_BUNDLE_ROTATION " +
+				"or INJECT_WEBHOOK_TIMEOUT_TUNING")
+		}
+		if policies.IsSet() {
+			return nil, fmt.Errorf("INJECT_MANAGE_WEBHOOK_CONFIG cannot be combined with --failurePolicy, " +
+				"--reinvocationPolicy or INJECT_WEBHOOK_ENTRY_SPEC, set them in the webhook config template")
+		}
+	}
 	if features.InjectionWebhookConfigName.Get() != "" {
-		s.addStartFunc(func(stop <-chan struct{}) error {
-			// No leader election - different istiod revisions will patch their own cert.
-			caBundlePath := s.caBundlePath
-			if hasCustomTLSCerts(args.ServerOptions.TLSOptions) {
-				caBundlePath = args.ServerOptions.TLSOptions.CaCertFile
+		caBundleReloads := make(chan struct{}, 1)
+		s.reloadHandlers = append(s.reloadHandlers, func() {
+			select {
+			case caBundleReloads <- struct{}{}:
+			default:
+			}
+		})
+		if injectionCABundleRotation.Get() {
+			// a rotation in progress completes once the serving certificate is reissued
+			s.certReloadHandlers = append(s.certReloadHandlers, func(*tls.Certificate) {
+				select {
+				case caBundleReloads <- struct{}{}:
+				default:
+				}
+			})
+		}
+		caBundlePath := s.caBundlePath
+		if hasCustomTLSCerts(args.ServerOptions.TLSOptions) {
+			caBundlePath = args.ServerOptions.TLSOptions.CaCertFile
+		}
+		rotateCABundle := injectionCABundleRotation.Get() && !hasCustomTLSCerts(args.ServerOptions.TLSOptions)
+		var certPatcher *webhooks.CertPatcher
+		if !injectionManageWebhookConfig.Get() && !rotateCABundle {
+			// the manager and its cache run for the lifetime of istiod, the
+			// leaderships only start and stop the patches
+			mgr, err := webhooks.NewManager(s.kubeClient)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create the webhook controller manager: %v", err)
 			}
-			webhooks.PatchCertLoop(features.InjectionWebhookConfigName.Get(), webhookName, caBundlePath, s.kubeClient, stop)
+			if certPatcher, err = webhooks.NewCertPatcher(mgr, features.InjectionWebhookConfigName.Get(), webhookName,
+				caBundlePath, policies, s.kubeClient.Kube(), caBundleReloads); err != nil {
+				return nil, err
+			}
+			s.addStartFunc(func(stop <-chan struct{}) error {
+				go func() {
+					if err := mgr.Start(stop); err != nil {
+						log.Errorf("Webhook controller manager exited: %v", err)
+					}
+				}()
+				return nil
+			})
+		}
+		patchWebhook := func(stop <-chan struct{}) error {
+			checkCertFileClockSkew("caBundle", caBundlePath)
+			if injectionManageWebhookConfig.Get() {
+				template := injectionWebhookConfigTemplate.Get()
+				if template == "" {
+					template = filepath.Join(injectPath, "webhook")
+				}
+				return webhooks.ManageWebhookConfig(features.InjectionWebhookConfigName.Get(), template, caBundlePath,
+					args.Revision, s.kubeClient, caBundleReloads, stop)
+			}
+			if rotateCABundle {
+				o := webhooks.CABundleOptions{
+					WebhookConfigName: features.InjectionWebhookConfigName.Get(),
+					WebhookName:       webhookName,
+					Namespace:         args.Namespace,
+					ConfigMapName:     controller.CACertNamespaceConfigMap,
+					Reload:            caBundleReloads,
+					Policies:          policies,
+				}
+				chainFile := dnsCertFile
+				if hasCertSecret(args.ServerOptions.TLSOptions) {
+					namespace, name, err := parseCertSecretName(certSecretName(args.ServerOptions.TLSOptions), args.Namespace)
+					if err != nil {
+						return err
+					}
+					o.Namespace, o.SecretName, chainFile = namespace, name, secretCertFile
+				}
+				o.ServingCertChain = func() ([]byte, error) {
+					return ioutil.ReadFile(chainFile)
+				}
+				go webhooks.NewCABundleController(o, s.kubeClient).Run(stop)
+				return nil
+			}
+			return certPatcher.Start(stop)
+		}
+		// Replicas of a revision elect the one patching the webhook config, while all of them
+		// serve admission requests. Different revisions patch their own webhook config.
+		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
+			le := s.newLeaderElection(args, leaderelection.WebhookPatcher+"-"+features.InjectionWebhookConfigName.Get())
+			le.AddRunFunction(func(leaderStop <-chan struct{}) {
+				retryWebhookPatch(leaderStop, patchWebhook)
+			})
+			le.Run(stop)
+			return nil
+		})
+	}
+	if injectionTimeoutTuning.Get() && features.InjectionWebhookConfigName.Get() != "" {
+		o := webhooks.TimeoutTunerOptions{
+			WebhookConfigName: features.InjectionWebhookConfigName.Get(),
+			WebhookName:       webhookName,
+			MinTimeout:        injectionTimeoutMin.Get(),
+			MaxTimeout:        injectionTimeoutMax.Get(),
+			Headroom:          injectionTimeoutHeadroom.Get(),
+			MinSamples:        20,
+			Latency:           wh.AdmissionLatencyP99,
+		}
+		if err := o.Validate(); err != nil {
+			return nil, err
+		}
+		// Replicas observe different latencies, only the leader tunes the timeout.
+		tuner := webhooks.NewTimeoutTuner(o, s.kubeClient)
+		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
+			le := s.newLeaderElection(args, leaderelection.WebhookTimeoutTuner)
+			le.AddRunFunction(tuner.Run)
+			le.Run(stop)
+			return nil
+		})
+	}
+	if injectionWebhookJanitor.Get() != "" {
+		o := webhooks.JanitorOptions{
+			Policy:      injectionWebhookJanitor.Get(),
+			Revision:    args.Revision,
+			GracePeriod: injectionWebhookJanitorGracePeriod.Get(),
+		}
+		if err := o.Validate(); err != nil {
+			return nil, err
+		}
+		janitor := webhooks.NewJanitor(o, s.kubeClient)
+		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
+			le := s.newLeaderElection(args, leaderelection.WebhookJanitor)
+			le.AddRunFunction(janitor.Run)
+			le.Run(stop)
 			return nil
 		})
 	}
@@ -83,5 +479,62 @@ func (s *Server) initSidecarInjector(args *PilotArgs) (*inject.Webhook, error) {
 		go wh.Run(stop)
 		return nil
 	})
+	// the injector is ready with istiod, on /ready of the httpAddr, and its
+	// metrics are served on the monitoringAddr
+	s.addReadinessProbe("sidecar injector", wh.Ready)
+	if injectionDiscoveryGate.Get() {
+		s.addReadinessProbe("injection discovery gate", wh.DiscoveryReady)
+	}
+	if injectionEnrollmentStatus.Get() && s.kubeClient != nil {
+		enrollment := inject.NewEnrollmentController(s.kubeClient, s.kubeClient.KubeInformer().Core().V1().Pods(),
+			s.kubeClient.KubeInformer().Core().V1().Namespaces())
+		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
+			le := s.newLeaderElection(args, leaderelection.EnrollmentController)
+			le.AddRunFunction(enrollment.Run)
+			le.Run(stop)
+			return nil
+		})
+	}
 	return wh, nil
 }
+
+// retryWebhookPatch runs patch until it succeeds, backing off between the
+// attempts, so the leader does not hold the election without patching.
+func retryWebhookPatch(stop <-chan struct{}, patch func(stop <-chan struct{}) error) {
+	backoff := webhookPatchInitialBackoff
+	for {
+		err := patch(stop)
+		if err == nil {
+			return
+		}
+		log.Errorf("Failed to patch the injection webhook config, retrying in %v: %v", backoff, err)
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > webhookPatchMaxBackoff {
+			backoff = webhookPatchMaxBackoff
+		}
+	}
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// meshConfigMapName is the name of the ConfigMap the mesh config file of the
+// revision is mounted from.
+func meshConfigMapName(revision string) string {
+	if revision == "" {
+		return "istio"
+	}
+	return "istio-" + revision
+}
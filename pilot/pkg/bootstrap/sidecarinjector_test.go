@@ -0,0 +1,53 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryWebhookPatch(t *testing.T) {
+	t.Run("retries until the patch succeeds", func(t *testing.T) {
+		attempts := 0
+		retryWebhookPatch(make(chan struct{}), func(<-chan struct{}) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("webhook config not found")
+			}
+			return nil
+		})
+		if attempts != 2 {
+			t.Fatalf("got %d attempts, want 2", attempts)
+		}
+	})
+	t.Run("gives up when leadership is lost", func(t *testing.T) {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			retryWebhookPatch(stop, func(<-chan struct{}) error {
+				return errors.New("webhook config not found")
+			})
+			close(done)
+		}()
+		close(stop)
+		select {
+		case <-done:
+		case <-time.After(webhookPatchInitialBackoff * 10):
+			t.Fatal("still patching after leadership was lost")
+		}
+	})
+}
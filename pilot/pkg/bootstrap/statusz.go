@@ -0,0 +1,177 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pkg/kube/inject"
+	"istio.io/istio/pkg/util/gogoprotomarshal"
+	"istio.io/istio/pkg/webhooks"
+	"istio.io/pkg/version"
+)
+
+const (
+	statuszPath = "/statusz"
+
+	// statuszWebhookTimeout bounds the lookup of the webhook config by /statusz.
+	statuszWebhookTimeout = time.Second
+)
+
+// istiodStatus is the status reported by /statusz.
+type istiodStatus struct {
+	Version      version.BuildInfo      `json:"version"`
+	MeshConfig   string                 `json:"meshConfig,omitempty"`
+	Injector     *inject.ConfigVersions `json:"injector,omitempty"`
+	Certificates []certificateStatus    `json:"certificates"`
+	Webhook      *webhookStatus         `json:"webhook,omitempty"`
+	Leader       map[string]bool        `json:"leader"`
+}
+
+// certificateStatus describes the first certificate of a PEM file.
+type certificateStatus struct {
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Subject     string    `json:"subject,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	NotAfter    time.Time `json:"notAfter,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// webhookStatus reports whether the injection webhook trusts the CA bundle of Istiod.
+type webhookStatus struct {
+	Name           string `json:"name"`
+	CABundleSynced bool   `json:"caBundleSynced"`
+	TimeoutSeconds int32  `json:"timeoutSeconds,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// readCertificateStatus returns the status of the first certificate of the file.
+func readCertificateStatus(name, path string) certificateStatus {
+	status := certificateStatus{Name: name, Path: path}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		status.Error = "no PEM certificate found"
+		return status
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	status.Subject = cert.Subject.String()
+	status.Fingerprint = hex.EncodeToString(fingerprint[:])
+	status.NotAfter = cert.NotAfter
+	return status
+}
+
+// readWebhookStatus compares the CA bundle of the injection webhook to the one of the file.
+func readWebhookStatus(ctx context.Context, client kubernetes.Interface, configName, caBundlePath string) *webhookStatus {
+	status := &webhookStatus{Name: configName}
+	caBundle, err := ioutil.ReadFile(caBundlePath)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(ctx, configName, metav1.GetOptions{})
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	for _, w := range config.Webhooks {
+		if !webhooks.IsWebhookEntry(w.Name, webhookName) {
+			continue
+		}
+		status.CABundleSynced = bytes.Equal(w.ClientConfig.CABundle, caBundle)
+		if w.TimeoutSeconds != nil {
+			status.TimeoutSeconds = *w.TimeoutSeconds
+		}
+		return status
+	}
+	status.Error = fmt.Sprintf("webhook %s not found", webhookName)
+	return status
+}
+
+// statuszHandler serves the build version, configuration hashes, certificates,
+// injection webhook state and leader election state of Istiod as JSON, for
+// operators and fleet tooling.
+func (s *Server) statuszHandler(args *PilotArgs, wh *inject.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		// the CA bundle path is only known once the certificates are initialized
+		servingCertPath, caBundlePath := dnsCertFile, s.caBundlePath
+		if hasCustomTLSCerts(args.ServerOptions.TLSOptions) {
+			servingCertPath, caBundlePath = args.ServerOptions.TLSOptions.CertFile, args.ServerOptions.TLSOptions.CaCertFile
+		} else if hasCertSecret(args.ServerOptions.TLSOptions) {
+			servingCertPath = secretCertFile
+		}
+		status := istiodStatus{
+			Version: version.Info,
+			Certificates: []certificateStatus{
+				readCertificateStatus("serving", servingCertPath),
+				readCertificateStatus("caBundle", caBundlePath),
+			},
+			Leader: map[string]bool{},
+		}
+		if s.environment != nil && s.environment.Watcher != nil {
+			if mc, err := gogoprotomarshal.ToJSON(s.environment.Mesh()); err == nil {
+				hash := sha256.Sum256([]byte(mc))
+				status.MeshConfig = hex.EncodeToString(hash[:])
+			}
+		}
+		if wh != nil {
+			v := wh.ConfigVersions()
+			status.Injector = &v
+		}
+		if name := features.InjectionWebhookConfigName.Get(); name != "" && s.kubeClient != nil {
+			ctx, cancel := context.WithTimeout(req.Context(), statuszWebhookTimeout)
+			status.Webhook = readWebhookStatus(ctx, s.kubeClient.Kube(), name, caBundlePath)
+			cancel()
+		}
+		s.leaderMu.Lock()
+		for _, le := range s.leaderElections {
+			status.Leader[le.ElectionID()] = le.IsLeader()
+		}
+		s.leaderMu.Unlock()
+
+		out, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "unable to marshal status: %v", err)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	}
+}
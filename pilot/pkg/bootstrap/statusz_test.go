@@ -0,0 +1,80 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/pkg/test/env"
+)
+
+func TestReadCertificateStatus(t *testing.T) {
+	status := readCertificateStatus("serving", path.Join(env.IstioSrc, "tests/testdata/certs/pilot/cert-chain.pem"))
+	if status.Error != "" || len(status.Fingerprint) != 64 || status.NotAfter.IsZero() {
+		t.Fatalf("unexpected certificate status %+v", status)
+	}
+	for _, file := range []string{"missing.pem", path.Join(env.IstioSrc, "tests/testdata/certs/pilot/key.pem")} {
+		if status := readCertificateStatus("serving", file); status.Error == "" {
+			t.Fatalf("expected error for %s", file)
+		}
+	}
+}
+
+func TestReadWebhookStatus(t *testing.T) {
+	caBundlePath := path.Join(env.IstioSrc, "tests/testdata/certs/pilot/root-cert.pem")
+	caBundle, err := ioutil.ReadFile(caBundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeout := int32(10)
+	client := fake.NewSimpleClientset(
+		&v1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "synced"},
+			Webhooks: []v1beta1.MutatingWebhook{{
+				Name:           webhookName,
+				ClientConfig:   v1beta1.WebhookClientConfig{CABundle: caBundle},
+				TimeoutSeconds: &timeout,
+			}},
+		},
+		&v1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "stale"},
+			Webhooks:   []v1beta1.MutatingWebhook{{Name: webhookName}},
+		},
+	)
+	cases := []struct {
+		config     string
+		wantSynced bool
+		wantErr    bool
+	}{
+		{"synced", true, false},
+		{"stale", false, false},
+		{"missing", false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.config, func(t *testing.T) {
+			status := readWebhookStatus(context.Background(), client, c.config, caBundlePath)
+			if status.CABundleSynced != c.wantSynced || (status.Error != "") != c.wantErr {
+				t.Fatalf("unexpected webhook status %+v", status)
+			}
+		})
+	}
+}
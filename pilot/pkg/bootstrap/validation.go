@@ -84,7 +84,7 @@ func (s *Server) initConfigValidation(args *PilotArgs) error {
 			return err
 		}
 		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
-			le := leaderelection.NewLeaderElection(args.Namespace, args.PodName, leaderelection.ValidationController, s.kubeClient)
+			le := s.newLeaderElection(args, leaderelection.ValidationController)
 			le.AddRunFunction(func(leaderStop <-chan struct{}) {
 				log.Infof("Starting validation controller")
 				whController.Start(leaderStop)
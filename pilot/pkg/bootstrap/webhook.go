@@ -16,10 +16,14 @@ package bootstrap
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"time"
 
+	"istio.io/istio/pkg/kube/inject"
 	"istio.io/pkg/log"
 )
 
@@ -30,23 +34,45 @@ const (
 // initSSecureWebhookServer handles initialization for the HTTPS webhook server.
 // If https address is off the injection handlers will be registered on the main http endpoint, with
 // TLS handled by a proxy/gateway in front of Istiod.
-func (s *Server) initSecureWebhookServer(args *PilotArgs) {
+func (s *Server) initSecureWebhookServer(args *PilotArgs) error {
 	// create the https server for hosting the k8s injectionWebhook handlers.
 	if s.kubeClient == nil || args.ServerOptions.HTTPSAddr == "" {
 		s.httpsMux = s.httpMux
 		log.Infoa("HTTPS port is disabled, multiplexing webhooks on the httpAddr ", args.ServerOptions.HTTPAddr)
-		return
+		return nil
 	}
 
 	log.Info("initializing secure webhook server for istiod webhooks")
 	// create the https server for hosting the k8s injectionWebhook handlers.
+	tlsConfig := &tls.Config{
+		GetCertificate: s.getIstiodCertificate,
+	}
+	tlsOptions := inject.TLSOptions{
+		MinVersion:   args.ServerOptions.TLSOptions.MinVersion,
+		CipherSuites: args.ServerOptions.TLSOptions.CipherSuites,
+	}
+	if err := tlsOptions.Apply(tlsConfig); err != nil {
+		return err
+	}
+	if caFile := args.ServerOptions.TLSOptions.ClientCAFile; caFile != "" {
+		caCerts, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file %s: %v", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCerts) {
+			return fmt.Errorf("no certificates found in client CA file %s", caFile)
+		}
+		// the readiness probe does not present a certificate, the injection
+		// handler rejects the requests without one
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		tlsConfig.ClientCAs = pool
+	}
 	s.httpsMux = http.NewServeMux()
 	s.httpsServer = &http.Server{
-		Addr:    args.ServerOptions.HTTPSAddr,
-		Handler: s.httpsMux,
-		TLSConfig: &tls.Config{
-			GetCertificate: s.getIstiodCertificate,
-		},
+		Addr:      args.ServerOptions.HTTPSAddr,
+		Handler:   s.httpsMux,
+		TLSConfig: tlsConfig,
 	}
 
 	// setup our readiness handler and the corresponding client we'll use later to check it with.
@@ -62,6 +88,7 @@ func (s *Server) initSecureWebhookServer(args *PilotArgs) {
 		},
 	}
 	s.addReadinessProbe("Secure Webhook Server", s.webhookReadyHandler)
+	return nil
 }
 
 func (s *Server) webhookReadyHandler() (bool, error) {
@@ -350,4 +350,8 @@ var (
 	AllowMetadataCertsInMutualTLS = env.RegisterBoolVar("PILOT_ALLOW_METADATA_CERTS_DR_MUTUAL_TLS", false,
 		"If true, Pilot will allow certs specified in Metadata to override DR certs in MUTUAL TLS mode. "+
 			"This is only enabled for migration and will be removed soon.").Get()
+
+	MeshConfigStalenessThreshold = env.RegisterDurationVar("PILOT_MESH_CONFIG_STALENESS_THRESHOLD", 0,
+		"If positive, Istiod reports itself as degraded, in readiness and the pilot_mesh_config_degraded metric, "+
+			"once the mesh config file has been unreadable for this long. The last valid mesh config keeps being served.").Get()
 )
@@ -31,6 +31,11 @@ import (
 const (
 	NamespaceController  = "istio-namespace-controller-election"
 	ValidationController = "istio-validation-controller-election"
+	EnrollmentController = "istio-enrollment-controller-election"
+	WebhookTimeoutTuner  = "istio-webhook-timeout-tuner-election"
+	WebhookJanitor       = "istio-webhook-janitor-election"
+	// WebhookPatcher is suffixed with the webhook config name, each revision patching its own.
+	WebhookPatcher = "istio-webhook-patcher-election"
 	// This holds the legacy name to not conflict with older control plane deployments which are just
 	// doing the ingress syncing.
 	IngressController = "istio-leader"
@@ -49,6 +54,8 @@ type LeaderElection struct {
 	// This is mostly just for testing
 	cycle      *atomic.Int32
 	electionID string
+	// leading is whether the election is currently won
+	leading *atomic.Bool
 }
 
 // Run will start leader election, calling all runFns when we become the leader.
@@ -83,11 +90,13 @@ func (l *LeaderElection) Run(stop <-chan struct{}) {
 func (l *LeaderElection) create() (*leaderelection.LeaderElector, error) {
 	callbacks := leaderelection.LeaderCallbacks{
 		OnStartedLeading: func(ctx context.Context) {
+			l.leading.Store(true)
 			for _, f := range l.runFns {
 				go f(ctx.Done())
 			}
 		},
 		OnStoppedLeading: func() {
+			l.leading.Store(false)
 			log.Infof("leader election lock lost: %v", l.electionID)
 		},
 	}
@@ -118,6 +127,16 @@ func (l *LeaderElection) AddRunFunction(f func(stop <-chan struct{})) *LeaderEle
 	return l
 }
 
+// IsLeader returns whether the election is currently won.
+func (l *LeaderElection) IsLeader() bool {
+	return l.leading.Load()
+}
+
+// ElectionID returns the name of the lock of the election.
+func (l *LeaderElection) ElectionID() string {
+	return l.electionID
+}
+
 func NewLeaderElection(namespace, name, electionID string, client kubernetes.Interface) *LeaderElection {
 	if name == "" {
 		name = "unknown"
@@ -128,7 +147,8 @@ func NewLeaderElection(namespace, name, electionID string, client kubernetes.Int
 		electionID: electionID,
 		client:     client,
 		// Default to a 30s ttl. Overridable for tests
-		ttl:   time.Second * 30,
-		cycle: atomic.NewInt32(0),
+		ttl:     time.Second * 30,
+		cycle:   atomic.NewInt32(0),
+		leading: atomic.NewBool(false),
 	}
 }
@@ -66,9 +66,12 @@ func createElection(t *testing.T, name string, expectLeader bool, client kuberne
 func TestLeaderElection(t *testing.T) {
 	client := fake.NewSimpleClientset()
 	// First pod becomes the leader
-	_, stop := createElection(t, "pod1", true, client)
+	l1, stop := createElection(t, "pod1", true, client)
 	// A new pod is not the leader
-	_, stop2 := createElection(t, "pod2", false, client)
+	l2, stop2 := createElection(t, "pod2", false, client)
+	if !l1.IsLeader() || l2.IsLeader() {
+		t.Fatalf("got leaders %v and %v, want only the first", l1.IsLeader(), l2.IsLeader())
+	}
 	// The first pod exists, now the new pod becomes the leader
 	close(stop2)
 	close(stop)
@@ -129,6 +129,13 @@ type Options struct {
 
 	// Maximum burst for throttle when communicating with the kubernetes API
 	KubernetesAPIBurst int
+
+	// KubernetesAPIProxy is the proxy used for the kubernetes API, overriding
+	// HTTPS_PROXY of the environment.
+	KubernetesAPIProxy string
+
+	// KubernetesAPINoProxy are the hosts reached without KubernetesAPIProxy.
+	KubernetesAPINoProxy string
 }
 
 // EndpointMode decides what source to use to get endpoint information
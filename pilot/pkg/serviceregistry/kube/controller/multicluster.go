@@ -134,7 +134,7 @@ func (m *Multicluster) AddMemberCluster(clients kubelib.Client, clusterID string
 	if m.fetchCaRoot != nil {
 		nc := NewNamespaceController(m.fetchCaRoot, clients)
 		go nc.Run(stopCh)
-		go webhooks.PatchCertLoop(features.InjectionWebhookConfigName.Get(), webhookName, m.caBundlePath, clients.Kube(), stopCh)
+		m.patchRemoteWebhookCert(clients, clusterID, stopCh)
 		valicationWebhookController := webhooks.CreateValidationWebhookController(clients, webhookConfigName,
 			m.secretNamespace, m.caBundlePath, true)
 		if valicationWebhookController != nil {
@@ -146,6 +146,31 @@ func (m *Multicluster) AddMemberCluster(clients kubelib.Client, clusterID string
 	return nil
 }
 
+// patchRemoteWebhookCert patches the CA bundle into the injection webhook
+// config of the remote cluster, with the webhook controller manager of the
+// cluster.
+func (m *Multicluster) patchRemoteWebhookCert(clients kubelib.Client, clusterID string, stopCh <-chan struct{}) {
+	mgr, err := webhooks.NewManager(clients)
+	if err != nil {
+		log.Errorf("Skipping the webhook patch of cluster %s, failed to create the controller manager: %v", clusterID, err)
+		return
+	}
+	patcher, err := webhooks.NewCertPatcher(mgr, features.InjectionWebhookConfigName.Get(), webhookName, m.caBundlePath,
+		webhooks.WebhookPolicies{}, clients.Kube(), nil)
+	if err != nil {
+		log.Errorf("Skipping the webhook patch of cluster %s: %v", clusterID, err)
+		return
+	}
+	go func() {
+		if err := mgr.Start(stopCh); err != nil {
+			log.Errorf("Webhook controller manager of cluster %s exited: %v", clusterID, err)
+		}
+	}()
+	if err := patcher.Start(stopCh); err != nil {
+		log.Errorf("Skipping the webhook patch of cluster %s: %v", clusterID, err)
+	}
+}
+
 func (m *Multicluster) UpdateMemberCluster(clients kubelib.Client, clusterID string) error {
 	if err := m.DeleteMemberCluster(clusterID); err != nil {
 		return err
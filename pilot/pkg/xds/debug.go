@@ -175,6 +175,13 @@ func (s *DiscoveryServer) AddDebugHandlers(mux *http.ServeMux, enableProfiling b
 	s.addDebugHandler(mux, "/debug/push_status", "Last PushContext Details", s.PushStatusHandler)
 
 	s.addDebugHandler(mux, "/debug/inject", "Active inject template", s.InjectTemplateHandler(webhook))
+	s.addDebugHandler(mux, "/debug/inject?config=true", "Active inject template, values and mesh config", s.InjectTemplateHandler(webhook))
+	s.addDebugHandler(mux, "/debug/inject_namespaces/refresh", "Relists the namespaces of the injection namespace cache, "+
+		"with a POST", s.InjectNamespacesRefreshHandler(webhook))
+	s.addDebugHandler(mux, "/debug/inject_drift", "Last drift report of the injected pods", s.InjectDriftHandler(webhook))
+	s.addDebugHandler(mux, "/debug/inject_decisions", "Recent sidecar injection decisions", s.InjectDecisionsHandler(webhook))
+	s.addDebugHandler(mux, "/debug/decisions", "Recent sidecar injection decisions of a workload, "+
+		"by namespace and owner", s.WorkloadDecisionsHandler(webhook))
 }
 
 func (s *DiscoveryServer) addDebugHandler(mux *http.ServeMux, path string, help string,
@@ -595,6 +602,8 @@ func (s *DiscoveryServer) configDump(conn *Connection) (*adminapi.ConfigDump, er
 
 // InjectTemplateHandler dumps the injection template
 // Replaces dumping the template at startup.
+// With the config query parameter, dumps the template, its SHA, the values and
+// the mesh config in effect as JSON.
 func (s *DiscoveryServer) InjectTemplateHandler(webhook *inject.Webhook) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, req *http.Request) {
 		// TODO: we should split the inject template into smaller modules (separate one for dump core, etc),
@@ -605,7 +614,90 @@ func (s *DiscoveryServer) InjectTemplateHandler(webhook *inject.Webhook) func(ht
 			return
 		}
 
-		_, _ = w.Write([]byte(webhook.Config.Template))
+		if req.URL.Query().Get("config") == "" {
+			_, _ = w.Write([]byte(webhook.Config.Template))
+			return
+		}
+		dump, err := webhook.ConfigDump()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "unable to dump the injection configuration: %v", err)
+			return
+		}
+		out, err := json.MarshalIndent(dump, "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "unable to marshal the injection configuration: %v", err)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	}
+}
+
+// InjectNamespacesRefreshHandler relists the namespaces of the injection
+// namespace cache.
+func (s *DiscoveryServer) InjectNamespacesRefreshHandler(webhook *inject.Webhook) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if webhook == nil {
+			w.WriteHeader(404)
+			return
+		}
+		webhook.ServeNamespaceRefresh(w, req)
+	}
+}
+
+// InjectDriftHandler dumps the last drift report of the injected pods.
+func (s *DiscoveryServer) InjectDriftHandler(webhook *inject.Webhook) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if webhook == nil {
+			w.WriteHeader(404)
+			return
+		}
+		webhook.ServeDriftReport(w, req)
+	}
+}
+
+// InjectDecisionsHandler dumps the recent admission decisions of the injector
+func (s *DiscoveryServer) InjectDecisionsHandler(webhook *inject.Webhook) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if webhook == nil {
+			w.WriteHeader(404)
+			return
+		}
+		out, err := json.MarshalIndent(webhook.RecentDecisions(), "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "unable to marshal injection decisions: %v", err)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	}
+}
+
+// WorkloadDecisionsHandler dumps the recent admission decisions of the pods of
+// the namespace query parameter, owned by the owner query parameter if set.
+func (s *DiscoveryServer) WorkloadDecisionsHandler(webhook *inject.Webhook) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if webhook == nil {
+			w.WriteHeader(404)
+			return
+		}
+		namespace := req.URL.Query().Get("namespace")
+		if namespace == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("You must provide a namespace in the query string"))
+			return
+		}
+		out, err := json.MarshalIndent(webhook.WorkloadDecisions(namespace, req.URL.Query().Get("owner")), "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "unable to marshal injection decisions: %v", err)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write(out)
 	}
 }
 
@@ -490,10 +490,12 @@ func getNodeMetaData(envs []string, plat platform.Environment, nodeIPs []string,
 	// Add all instance labels with lower precedence than pod labels
 	extractInstanceLabels(plat, meta)
 
-	// Add all pod labels found from filesystem
+	// Add all pod labels found from filesystem, unless the injector filtered them
 	// These are typically volume mounted by the downward API
 	lbls, err := readPodLabels()
-	if err == nil {
+	if labelsFiltered(envs) {
+		log.Debugf("ignoring pod labels, filtered at injection")
+	} else if err == nil {
 		if meta.Labels == nil {
 			meta.Labels = map[string]string{}
 		}
@@ -507,6 +509,17 @@ func getNodeMetaData(envs []string, plat platform.Environment, nodeIPs []string,
 	return meta, untypedMeta, nil
 }
 
+// labelsFiltered returns whether the injector filtered the labels of the pod
+// in ISTIO_METAJSON_LABELS.
+func labelsFiltered(envs []string) bool {
+	for _, varStr := range envs {
+		if name, val := parseEnvVar(varStr); name == constants.MetadataLabelsFilteredEnv {
+			return val == "true"
+		}
+	}
+	return false
+}
+
 // Extracts instance labels for the platform into model.NodeMetadata.Labels
 // only if not running on Kubernetes
 func extractInstanceLabels(plat platform.Environment, meta *model.BootstrapNodeMetadata) {
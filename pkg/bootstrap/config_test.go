@@ -71,3 +71,20 @@ other: setting`,
 		})
 	}
 }
+
+func TestLabelsFiltered(t *testing.T) {
+	cases := []struct {
+		envs []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{`ISTIO_METAJSON_LABELS={"app":"foo"}`}, false},
+		{[]string{`ISTIO_METAJSON_LABELS={"app":"foo"}`, "ISTIO_METADATA_LABELS_FILTERED=true"}, true},
+		{[]string{"ISTIO_METADATA_LABELS_FILTERED=false"}, false},
+	}
+	for _, tt := range cases {
+		if got := labelsFiltered(tt.envs); got != tt.want {
+			t.Errorf("labelsFiltered(%v) = %v, want %v", tt.envs, got, tt.want)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"flag"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -26,20 +27,74 @@ import (
 	"istio.io/pkg/log"
 )
 
+var (
+	reloadMu        sync.Mutex
+	reloadCallbacks []func()
+
+	terminate     = make(chan struct{})
+	terminateOnce sync.Once
+)
+
+// OnReload registers a callback called on SIGHUP by WaitSignal and
+// WaitSignalFunc. Without callbacks registered before they are called,
+// SIGHUP keeps its default behavior.
+func OnReload(f func()) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadCallbacks = append(reloadCallbacks, f)
+}
+
+// reloads returns the registered reload callbacks.
+func reloads() []func() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return append([]func(){}, reloadCallbacks...)
+}
+
+// Terminate makes WaitSignal and WaitSignalFunc return as on SIGTERM, so that
+// the process shuts down gracefully. It may be called more than once.
+func Terminate() {
+	terminateOnce.Do(func() {
+		close(terminate)
+	})
+}
+
+// waitTermination awaits for SIGINT, SIGTERM or Terminate, calling the reload
+// callbacks on each SIGHUP meanwhile.
+func waitTermination() {
+	callbacks := reloads()
+	sigs := make(chan os.Signal, 1)
+	if len(callbacks) > 0 {
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	} else {
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	}
+	for {
+		select {
+		case s := <-sigs:
+			if s != syscall.SIGHUP {
+				return
+			}
+			log.Infof("Received SIGHUP, reloading")
+			for _, f := range callbacks {
+				f()
+			}
+		case <-terminate:
+			return
+		}
+	}
+}
+
 // WaitSignal awaits for SIGINT or SIGTERM and closes the channel
 func WaitSignal(stop chan struct{}) {
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-	<-sigs
+	waitTermination()
 	close(stop)
 	_ = log.Sync()
 }
 
 // WaitSignalFunc awaits for SIGINT or SIGTERM and calls the cancel function
 func WaitSignalFunc(cancel func()) {
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-	<-sigs
+	waitTermination()
 	cancel()
 	_ = log.Sync()
 }
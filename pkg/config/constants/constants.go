@@ -90,6 +90,11 @@ const (
 	// This is typically set by the downward API
 	PodInfoAnnotationsPath = "./etc/istio/pod/annotations"
 
+	// MetadataLabelsFilteredEnv is set on proxies whose labels in
+	// ISTIO_METAJSON_LABELS were filtered at injection, so the labels of the
+	// pod read from PodInfoLabelsPath are ignored.
+	MetadataLabelsFilteredEnv = "ISTIO_METADATA_LABELS_FILTERED"
+
 	// DefaultSdsUdsPath is the path used for SDS communication between istio-agent and proxy during
 	// mtls.
 	DefaultSdsUdsPath = "unix:./etc/istio/proxy/SDS"
@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/davecgh/go-spew/spew"
@@ -40,12 +41,37 @@ type Watcher interface {
 	AddMeshHandler(func())
 }
 
-var _ Watcher = &watcher{}
+// SourceWatcher is a Watcher reading the mesh config from a source which may
+// become unreadable, in which case the last valid mesh config is kept.
+type SourceWatcher interface {
+	Watcher
+
+	// FailingSince returns when reading the mesh config source started
+	// failing, or the zero time if the last read succeeded.
+	FailingSince() time.Time
+}
+
+// ReloadableWatcher is a Watcher whose mesh config source can be read again
+// on demand, e.g. on SIGHUP.
+type ReloadableWatcher interface {
+	Watcher
+
+	// Reload reads the mesh config source again, as if it had changed.
+	Reload()
+}
+
+var (
+	_ SourceWatcher     = &watcher{}
+	_ ReloadableWatcher = &watcher{}
+)
 
 type watcher struct {
-	mutex    sync.Mutex
-	handlers []func()
-	mesh     *meshconfig.MeshConfig
+	mutex        sync.Mutex
+	handlers     []func()
+	mesh         *meshconfig.MeshConfig
+	failingSince time.Time
+	// reload reads the mesh config file, nil for fixed watchers.
+	reload func()
 }
 
 // NewFixedWatcher creates a new Watcher that always returns the given mesh config. It will never
@@ -69,17 +95,23 @@ func NewWatcher(fileWatcher filewatcher.FileWatcher, filename string) (Watcher,
 	}
 
 	// Watch the config file for changes and reload if it got modified
-	addFileWatcher(fileWatcher, filename, func() {
+	w.reload = func() {
 		// Reload the config file
-		meshConfig, err = ReadMeshConfig(filename)
+		meshConfig, err := ReadMeshConfig(filename)
 		if err != nil {
-			log.Warnf("failed to read mesh configuration, using default: %v", err)
+			log.Warnf("failed to read mesh configuration, keeping the previous configuration: %v", err)
+			w.mutex.Lock()
+			if w.failingSince.IsZero() {
+				w.failingSince = time.Now()
+			}
+			w.mutex.Unlock()
 			return
 		}
 
 		var handlers []func()
 
 		w.mutex.Lock()
+		w.failingSince = time.Time{}
 		if !reflect.DeepEqual(meshConfig, w.mesh) {
 			log.Infof("mesh configuration updated to: %s", spew.Sdump(meshConfig))
 			if !reflect.DeepEqual(meshConfig.ConfigSources, w.mesh.ConfigSources) {
@@ -97,15 +129,30 @@ func NewWatcher(fileWatcher filewatcher.FileWatcher, filename string) (Watcher,
 		for _, h := range handlers {
 			h()
 		}
-	})
+	}
+	addFileWatcher(fileWatcher, filename, w.reload)
 	return w, nil
 }
 
+// Reload reads the mesh config file again. Fixed watchers have nothing to read.
+func (w *watcher) Reload() {
+	if w.reload != nil {
+		w.reload()
+	}
+}
+
 // Mesh returns the latest mesh config.
 func (w *watcher) Mesh() *meshconfig.MeshConfig {
 	return (*meshconfig.MeshConfig)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&w.mesh))))
 }
 
+// FailingSince returns when reading the mesh config file started failing.
+func (w *watcher) FailingSince() time.Time {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.failingSince
+}
+
 // AddMeshHandler registers a callback handler for changes to the mesh config.
 func (w *watcher) AddMeshHandler(h func()) {
 	w.mutex.Lock()
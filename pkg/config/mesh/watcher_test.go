@@ -71,6 +71,53 @@ func TestWatcherShouldNotifyHandlers(t *testing.T) {
 	}
 }
 
+func TestWatcherShouldTrackReadFailures(t *testing.T) {
+	g := NewWithT(t)
+
+	path := newTempFile(t)
+	defer removeSilent(path)
+
+	m := mesh.DefaultMeshConfig()
+	writeMessage(t, path, &m)
+
+	w := newWatcher(t, path).(mesh.SourceWatcher)
+	g.Expect(w.FailingSince().IsZero()).To(BeTrue())
+
+	// An invalid file keeps the previous mesh config.
+	writeFile(t, path, "defaultConfig: [")
+	g.Eventually(func() bool { return w.FailingSince().IsZero() }, 5*time.Second).Should(BeFalse())
+	g.Expect(w.Mesh()).To(Equal(&m))
+
+	// A valid file clears the failure.
+	m.IngressClass = "foo"
+	writeMessage(t, path, &m)
+	g.Eventually(func() bool { return w.FailingSince().IsZero() }, 5*time.Second).Should(BeTrue())
+	g.Expect(w.Mesh()).To(Equal(&m))
+}
+
+func TestWatcherReload(t *testing.T) {
+	g := NewWithT(t)
+
+	path := newTempFile(t)
+	defer removeSilent(path)
+
+	m := mesh.DefaultMeshConfig()
+	writeMessage(t, path, &m)
+
+	w := newWatcher(t, path).(mesh.ReloadableWatcher)
+	m.IngressClass = "foo"
+	writeMessage(t, path, &m)
+
+	// the file is read synchronously, without waiting for its change event
+	w.Reload()
+	g.Expect(w.Mesh()).To(Equal(&m))
+
+	// fixed watchers have nothing to reload
+	fixed := mesh.NewFixedWatcher(&m).(mesh.ReloadableWatcher)
+	fixed.Reload()
+	g.Expect(fixed.Mesh()).To(Equal(&m))
+}
+
 func newWatcher(t testing.TB, filename string) mesh.Watcher {
 	t.Helper()
 	w, err := mesh.NewWatcher(filewatcher.NewWatcher(), filename)
@@ -0,0 +1,77 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/api/annotation"
+)
+
+const (
+	// DataplaneModeLabel selects how traffic of a pod, or of all pods in a
+	// namespace, is captured. Pods in ambient mode are redirected at the node
+	// level and must not get a sidecar.
+	DataplaneModeLabel = "istio.io/dataplane-mode"
+
+	// DataplaneModeAmbient is the DataplaneModeLabel value enabling ambient mode.
+	DataplaneModeAmbient = "ambient"
+
+	// DataplaneModeSidecar is the DataplaneModeLabel value a pod can use to
+	// opt out of the ambient mode of its namespace.
+	DataplaneModeSidecar = "sidecar"
+
+	// SkipReasonAnnotation records why the injector did not inject a sidecar.
+	SkipReasonAnnotation = "sidecar.istio.io/skipReason"
+
+	skipReasonAmbient = "ambient"
+)
+
+// ambientEnabled reports whether the pod is captured by node-level ambient
+// redirection. A pod label takes precedence over the namespace label.
+func ambientEnabled(podLabels, namespaceLabels map[string]string) bool {
+	if mode, f := podLabels[DataplaneModeLabel]; f {
+		return mode == DataplaneModeAmbient
+	}
+	return namespaceLabels[DataplaneModeLabel] == DataplaneModeAmbient
+}
+
+// createAmbientPatch returns a patch which records the skip decision and
+// removes any sidecar previously injected into the pod, so that a pod is
+// never captured by both the sidecar and node-level redirection.
+func createAmbientPatch(ctx context.Context, pod *corev1.Pod, statuses *statusStore) ([]byte, error) {
+	var patch []rfc6902PatchOperation
+	annotations := map[string]string{SkipReasonAnnotation: skipReasonAmbient}
+
+	if _, injected := pod.Annotations[annotation.SidecarStatus.Name]; injected {
+		prevStatus, err := statuses.resolve(ctx, pod)
+		if err != nil {
+			return nil, err
+		}
+		patch = append(patch, removeContainers(pod.Spec.InitContainers, prevStatus.InitContainers, "/spec/initContainers")...)
+		patch = append(patch, removeContainers(pod.Spec.Containers, prevStatus.Containers, "/spec/containers")...)
+		patch = append(patch, removeVolumes(pod.Spec.Volumes, prevStatus.Volumes, "/spec/volumes")...)
+		patch = append(patch, removeImagePullSecrets(pod.Spec.ImagePullSecrets, prevStatus.ImagePullSecrets, "/spec/imagePullSecrets")...)
+		patch = append(patch, rfc6902PatchOperation{
+			Op:   "remove",
+			Path: "/metadata/annotations/" + escapeJSONPointerValue(annotation.SidecarStatus.Name),
+		})
+	}
+	patch = append(patch, updateAnnotation(pod.Annotations, annotations)...)
+	return json.Marshal(patch)
+}
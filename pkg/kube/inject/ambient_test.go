@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+)
+
+func TestAmbientEnabled(t *testing.T) {
+	ambient := map[string]string{DataplaneModeLabel: DataplaneModeAmbient}
+	sidecar := map[string]string{DataplaneModeLabel: DataplaneModeSidecar}
+	cases := []struct {
+		name      string
+		pod       map[string]string
+		namespace map[string]string
+		want      bool
+	}{
+		{"unlabeled", nil, nil, false},
+		{"namespace ambient", nil, ambient, true},
+		{"pod ambient", ambient, nil, true},
+		{"pod opt out", sidecar, ambient, false},
+		{"pod opt in", ambient, sidecar, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ambientEnabled(c.pod, c.namespace); got != c.want {
+				t.Fatalf("ambientEnabled() got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCreateAmbientPatch(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				annotation.SidecarStatus.Name: `{"initContainers":["istio-init"],"containers":["istio-proxy"],"volumes":["istio-envoy"]}`,
+			},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "istio-init"}},
+			Containers:     []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}},
+			Volumes:        []corev1.Volume{{Name: "istio-envoy"}},
+		},
+	}
+	patchBytes, err := createAmbientPatch(context.TODO(), pod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patch []rfc6902PatchOperation
+	if err := json.Unmarshal(patchBytes, &patch); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/spec/initContainers/0",
+		"/spec/containers/1",
+		"/spec/volumes/0",
+		"/metadata/annotations/sidecar.istio.io~1status",
+		"/metadata/annotations/sidecar.istio.io~1skipReason",
+	}
+	if len(patch) != len(want) {
+		t.Fatalf("got %d patch operations, want %d: %s", len(patch), len(want), string(patchBytes))
+	}
+	for i, p := range patch {
+		if p.Path != want[i] {
+			t.Errorf("operation %d: got path %q, want %q", i, p.Path, want[i])
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"istio.io/api/annotation"
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/pkg/log"
+)
+
+const (
+	// annotationCatalogPath serves the description of the annotations honored by the injector.
+	annotationCatalogPath = "/annotations"
+)
+
+// Formats of annotation values, as reported in the annotation catalog.
+const (
+	formatString           = "string"
+	formatImage            = "image"
+	formatQuantity         = "quantity"
+	formatBool             = "bool"
+	formatUInt32           = "uint32"
+	formatPort             = "port"
+	formatPortList         = "port-list"
+	formatCIDRList         = "cidr-list"
+	formatInterceptionMode = "interception-mode"
+	formatProxyConfig      = "proxy-config"
+	formatJSON             = "json"
+	formatYAML             = "yaml"
+)
+
+// annotationSpec describes an annotation honored by the injector. It is the
+// single source of truth used both for validation and for the catalog.
+//
+// The defaults are not written down: they are those of the injection
+// template, or defaultValue for the annotations read by the injector itself.
+type annotationSpec struct {
+	name         string
+	format       string
+	validate     annotationValidationFunc
+	defaultValue annotationDefaultFunc
+}
+
+// annotationDefaultFunc returns the default of an annotation read by the
+// injector, for the values and mesh config.
+type annotationDefaultFunc func(values map[string]interface{}, mesh *meshconfig.MeshConfig) string
+
+// injectAnnotations lists every annotation honored by the injector.
+var injectAnnotations = []annotationSpec{
+	{annotation.SidecarInject.Name, formatBool, alwaysValidFunc, nil},
+	{annotation.SidecarStatus.Name, formatJSON, alwaysValidFunc, nil},
+	{annotation.SidecarRewriteAppHTTPProbers.Name, formatBool, alwaysValidFunc, rewriteAppHTTPProbersDefault},
+	{annotation.SidecarControlPlaneAuthPolicy.Name, formatString, alwaysValidFunc, nil},
+	{annotation.SidecarDiscoveryAddress.Name, formatString, alwaysValidFunc, nil},
+	{annotation.SidecarProxyImage.Name, formatImage, validateProxyImage, nil},
+	{annotation.SidecarProxyCPU.Name, formatQuantity, validateQuantity, nil},
+	{annotation.SidecarProxyMemory.Name, formatQuantity, validateQuantity, nil},
+	{ProxyCPULimitAnnotation, formatQuantity, validateQuantity, nil},
+	{ProxyMemoryLimitAnnotation, formatQuantity, validateQuantity, nil},
+	{annotation.SidecarInterceptionMode.Name, formatInterceptionMode, validateInterceptionMode, nil},
+	{annotation.SidecarBootstrapOverride.Name, formatString, alwaysValidFunc, nil},
+	{annotation.SidecarStatsInclusionPrefixes.Name, formatString, alwaysValidFunc, nil},
+	{annotation.SidecarStatsInclusionSuffixes.Name, formatString, alwaysValidFunc, nil},
+	{annotation.SidecarStatsInclusionRegexps.Name, formatString, alwaysValidFunc, nil},
+	{annotation.SidecarUserVolume.Name, formatJSON, alwaysValidFunc, nil},
+	{annotation.SidecarUserVolumeMount.Name, formatJSON, alwaysValidFunc, nil},
+	{annotation.SidecarEnableCoreDump.Name, formatBool, validateBool, nil},
+	{annotation.SidecarStatusPort.Name, formatPort, validateStatusPort, statusPortDefault},
+	{annotation.SidecarStatusReadinessInitialDelaySeconds.Name, formatUInt32, validateUInt32, nil},
+	{annotation.SidecarStatusReadinessPeriodSeconds.Name, formatUInt32, validateUInt32, nil},
+	{annotation.SidecarStatusReadinessFailureThreshold.Name, formatUInt32, validateUInt32, nil},
+	{annotation.SidecarTrafficIncludeOutboundIPRanges.Name, formatCIDRList, ValidateIncludeIPRanges, nil},
+	{annotation.SidecarTrafficExcludeOutboundIPRanges.Name, formatCIDRList, ValidateExcludeIPRanges, nil},
+	{annotation.SidecarTrafficIncludeInboundPorts.Name, formatPortList, ValidateIncludeInboundPorts, nil},
+	{annotation.SidecarTrafficExcludeInboundPorts.Name, formatPortList, ValidateExcludeInboundPorts, nil},
+	{annotation.SidecarTrafficExcludeOutboundPorts.Name, formatPortList, ValidateExcludeOutboundPorts, nil},
+	{annotation.SidecarTrafficKubevirtInterfaces.Name, formatString, alwaysValidFunc, nil},
+	{annotation.PrometheusMergeMetrics.Name, formatBool, validateBool, prometheusMergeDefault},
+	{annotation.ProxyConfig.Name, formatProxyConfig, validateProxyConfig, nil},
+	{SkipReasonAnnotation, formatString, alwaysValidFunc, nil},
+	{ValuesAnnotation, formatYAML, validateValuesOverlay, nil},
+	{StatsInclusionAnnotation, formatString, validateStatsInclusionPreset, nil},
+	{"k8s.v1.cni.cncf.io/networks", formatString, alwaysValidFunc, nil},
+}
+
+// rewriteAppHTTPProbersDefault is the rewriteAppHTTPProbe of the template, set from the values.
+func rewriteAppHTTPProbersDefault(values map[string]interface{}, _ *meshconfig.MeshConfig) string {
+	rewrite, _, _ := unstructured.NestedBool(values, "sidecarInjectorWebhook", "rewriteAppHTTPProbe")
+	return strconv.FormatBool(rewrite)
+}
+
+func statusPortDefault(_ map[string]interface{}, mesh *meshconfig.MeshConfig) string {
+	return strconv.Itoa(int(mesh.GetDefaultConfig().GetStatusPort()))
+}
+
+func prometheusMergeDefault(_ map[string]interface{}, mesh *meshconfig.MeshConfig) string {
+	return strconv.FormatBool(enablePrometheusMerge(mesh, nil))
+}
+
+// templateAnnotationDefaults returns the defaults the template reads the
+// annotations with, `annotation .ObjectMeta name default`, evaluated for the
+// values and mesh config. Defaults depending on the pod are left out, and the
+// first default of an annotation read more than once is kept.
+func templateAnnotationDefaults(tmpl string, values map[string]interface{}, mesh *meshconfig.MeshConfig) map[string]string {
+	data := SidecarTemplateData{
+		TypeMeta:       &metav1.TypeMeta{},
+		DeploymentMeta: &metav1.ObjectMeta{},
+		ObjectMeta:     &metav1.ObjectMeta{},
+		Spec:           &corev1.PodSpec{},
+		ProxyConfig:    mesh.GetDefaultConfig(),
+		MeshConfig:     mesh,
+		Values:         values,
+	}
+	funcs := templateFuncMap(data)
+	t, err := template.New("inject").Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return nil
+	}
+	defaults := map[string]string{}
+	var visit func(parse.Node)
+	visit = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				visit(c)
+			}
+		case *parse.ActionNode:
+			visit(n.Pipe)
+		case *parse.IfNode:
+			visit(n.Pipe)
+			visit(n.List)
+			visit(n.ElseList)
+		case *parse.RangeNode:
+			visit(n.Pipe)
+			visit(n.List)
+			visit(n.ElseList)
+		case *parse.WithNode:
+			visit(n.Pipe)
+			visit(n.List)
+			visit(n.ElseList)
+		case *parse.TemplateNode:
+			visit(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				visit(c)
+			}
+		case *parse.CommandNode:
+			if name, expr, ok := annotationCall(n); ok {
+				if _, f := defaults[name]; !f {
+					if v, ok := evalTemplateDefault(expr, funcs, data); ok {
+						defaults[name] = v
+					}
+				}
+			}
+			for _, a := range n.Args {
+				visit(a)
+			}
+		}
+	}
+	for _, tt := range t.Templates() {
+		if tt.Tree != nil {
+			visit(tt.Tree.Root)
+		}
+	}
+	return defaults
+}
+
+// annotationCall returns the annotation name and the default expression of
+// an `annotation .ObjectMeta name default` command.
+func annotationCall(n *parse.CommandNode) (string, string, bool) {
+	if len(n.Args) != 4 {
+		return "", "", false
+	}
+	if id, ok := n.Args[0].(*parse.IdentifierNode); !ok || id.Ident != "annotation" {
+		return "", "", false
+	}
+	name, ok := n.Args[2].(*parse.StringNode)
+	if !ok {
+		return "", "", false
+	}
+	return name.Text, n.Args[3].String(), true
+}
+
+// evalTemplateDefault renders the default expression, unless it depends on the pod.
+func evalTemplateDefault(expr string, funcs template.FuncMap, data SidecarTemplateData) (string, bool) {
+	for _, pod := range []string{".Spec", ".ObjectMeta", ".DeploymentMeta", ".TypeMeta"} {
+		if strings.Contains(expr, pod) {
+			return "", false
+		}
+	}
+	t, err := template.New("default").Funcs(funcs).Parse("{{ " + expr + " }}")
+	if err != nil {
+		return "", false
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", false
+	}
+	return out.String(), true
+}
+
+func buildAnnotationValidation(specs []annotationSpec) map[string]annotationValidationFunc {
+	out := make(map[string]annotationValidationFunc, len(specs))
+	for _, s := range specs {
+		out[s.name] = s.validate
+	}
+	return out
+}
+
+// annotationSchema is the OpenAPI schema of a single annotation.
+type annotationSchema struct {
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
+// annotationCatalog returns an OpenAPI document describing the annotations
+// honored by the injector as the properties of a PodAnnotations object, with
+// the defaults of the template, values and mesh config.
+func annotationCatalog(tmpl string, values map[string]interface{}, mesh *meshconfig.MeshConfig) map[string]interface{} {
+	descriptions := map[string]*annotation.Instance{}
+	for _, a := range annotation.AllResourceAnnotations() {
+		descriptions[a.Name] = a
+	}
+	defaults := templateAnnotationDefaults(tmpl, values, mesh)
+
+	properties := map[string]annotationSchema{}
+	for _, s := range injectAnnotations {
+		schema := annotationSchema{
+			Type:    "string",
+			Format:  s.format,
+			Default: defaults[s.name],
+		}
+		if s.defaultValue != nil {
+			schema.Default = s.defaultValue(values, mesh)
+		}
+		if a, f := descriptions[s.name]; f {
+			schema.Description = a.Description
+			schema.Deprecated = a.Deprecated
+		}
+		properties[s.name] = schema
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]string{
+			"title":   "Istio sidecar injection annotations",
+			"version": "v1",
+		},
+		"paths": map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"PodAnnotations": map[string]interface{}{
+					"type":       "object",
+					"properties": properties,
+				},
+			},
+		},
+	}
+}
+
+// serveAnnotationCatalog serves the annotation catalog for the current
+// injection configuration.
+func (wh *Webhook) serveAnnotationCatalog(w http.ResponseWriter, _ *http.Request) {
+	wh.mu.RLock()
+	tmpl, valuesConfig, mesh := "", wh.valuesConfig, wh.meshConfig
+	if wh.Config != nil {
+		tmpl = wh.Config.Template
+	}
+	wh.mu.RUnlock()
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(valuesConfig), &values); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b, err := json.MarshalIndent(annotationCatalog(tmpl, values, mesh), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		log.Errorf("Failed to write annotation catalog: %v", err)
+	}
+}
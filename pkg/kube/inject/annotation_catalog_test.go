@@ -0,0 +1,77 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestAnnotationCatalog(t *testing.T) {
+	m := mesh.DefaultMeshConfig()
+	wh := &Webhook{
+		Config: &Config{Template: "{{ annotation .ObjectMeta `sidecar.istio.io/interceptionMode` .ProxyConfig.InterceptionMode }}\n" +
+			"{{ if eq (annotation .ObjectMeta `sidecar.istio.io/proxyImage` .Values.global.proxy.image) `` }}{{ end }}\n" +
+			"{{ annotation .ObjectMeta `traffic.sidecar.istio.io/includeInboundPorts` (includeInboundPorts .Spec.Containers) }}\n"},
+		valuesConfig: "global:\n  proxy:\n    image: proxyv2\nsidecarInjectorWebhook:\n  rewriteAppHTTPProbe: true\n",
+		meshConfig:   &m,
+	}
+	w := httptest.NewRecorder()
+	wh.serveAnnotationCatalog(w, httptest.NewRequest("GET", annotationCatalogPath, nil))
+	if w.Code != 200 {
+		t.Fatalf("got status %d", w.Code)
+	}
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]annotationSchema `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	properties := doc.Components.Schemas["PodAnnotations"].Properties
+	if len(properties) != len(AnnotationValidation) {
+		t.Fatalf("catalog has %d annotations, validation has %d", len(properties), len(AnnotationValidation))
+	}
+	for name := range AnnotationValidation {
+		if _, f := properties[name]; !f {
+			t.Errorf("annotation %s missing from the catalog", name)
+		}
+	}
+	port := properties[annotation.SidecarStatusPort.Name]
+	if port.Format != formatPort || port.Default != "15020" || port.Description == "" {
+		t.Errorf("unexpected schema for %s: %+v", annotation.SidecarStatusPort.Name, port)
+	}
+
+	// the defaults are those of the template, values and mesh config
+	for name, want := range map[string]string{
+		annotation.SidecarInterceptionMode.Name:           "REDIRECT",
+		annotation.SidecarProxyImage.Name:                 "proxyv2",
+		annotation.SidecarRewriteAppHTTPProbers.Name:      "true",
+		annotation.PrometheusMergeMetrics.Name:            "true",
+		annotation.SidecarTrafficIncludeInboundPorts.Name: "",
+	} {
+		if got := properties[name].Default; got != want {
+			t.Errorf("got default %q for %s, want %q", got, name, want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package annotations provides typed access to the annotations honored by the
+// sidecar injector. Both the webhook and kube-inject parse annotation values
+// through this package, so that a new annotation is a one-place change.
+package annotations
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"istio.io/api/annotation"
+)
+
+// ParseBool parses a boolean annotation value.
+func ParseBool(value string) (bool, error) {
+	return strconv.ParseBool(value)
+}
+
+// ParseUInt32 parses a non-negative integer annotation value.
+func ParseUInt32(value string) (uint32, error) {
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(v), nil
+}
+
+// ParsePort parses a single port.
+func ParsePort(value string) (int, error) {
+	port, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("failed parsing port '%s': %v", value, err)
+	}
+	return int(port), nil
+}
+
+// SplitList splits a comma separated annotation value.
+func SplitList(value string) []string {
+	return strings.Split(value, ",")
+}
+
+// ParsePortList parses a comma separated list of ports. An empty value is an empty list.
+func ParsePortList(value string) ([]int, error) {
+	value = strings.TrimSpace(value)
+	ports := make([]int, 0)
+	if len(value) > 0 {
+		for _, portStr := range SplitList(value) {
+			port, err := ParsePort(portStr)
+			if err != nil {
+				return nil, err
+			}
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// ParseCIDRList parses a comma separated list of CIDRs. An empty value is an empty list.
+func ParseCIDRList(value string) ([]*net.IPNet, error) {
+	cidrs := make([]*net.IPNet, 0)
+	if len(value) > 0 {
+		for _, cidr := range SplitList(value) {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing cidr '%s': %v", cidr, err)
+			}
+			cidrs = append(cidrs, ipNet)
+		}
+	}
+	return cidrs, nil
+}
+
+// GetBool returns the boolean value of the annotation, or def if the
+// annotation is not set. An invalid value returns def and an error.
+func GetBool(annotations map[string]string, name string, def bool) (bool, error) {
+	v, f := annotations[name]
+	if !f {
+		return def, nil
+	}
+	b, err := ParseBool(v)
+	if err != nil {
+		return def, fmt.Errorf("invalid value '%s' for annotation '%s': %v", v, name, err)
+	}
+	return b, nil
+}
+
+// GetUInt32 returns the integer value of the annotation, or def if the
+// annotation is not set. An invalid value returns def and an error.
+func GetUInt32(annotations map[string]string, name string, def uint32) (uint32, error) {
+	v, f := annotations[name]
+	if !f {
+		return def, nil
+	}
+	i, err := ParseUInt32(v)
+	if err != nil {
+		return def, fmt.Errorf("invalid value '%s' for annotation '%s': %v", v, name, err)
+	}
+	return i, nil
+}
+
+// GetPort returns the port value of the annotation, or def if the annotation
+// is not set. An invalid value returns def and an error.
+func GetPort(annotations map[string]string, name string, def int) (int, error) {
+	v, f := annotations[name]
+	if !f {
+		return def, nil
+	}
+	p, err := ParsePort(v)
+	if err != nil {
+		return def, fmt.Errorf("invalid value '%s' for annotation '%s': %v", v, name, err)
+	}
+	return p, nil
+}
+
+// GetPortList returns the ports listed by the annotation, or nil if the
+// annotation is not set.
+func GetPortList(annotations map[string]string, name string) ([]int, error) {
+	v, f := annotations[name]
+	if !f {
+		return nil, nil
+	}
+	ports, err := ParsePortList(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value '%s' for annotation '%s': %v", v, name, err)
+	}
+	return ports, nil
+}
+
+// GetCIDRList returns the CIDRs listed by the annotation, or nil if the
+// annotation is not set.
+func GetCIDRList(annotations map[string]string, name string) ([]*net.IPNet, error) {
+	v, f := annotations[name]
+	if !f {
+		return nil, nil
+	}
+	cidrs, err := ParseCIDRList(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value '%s' for annotation '%s': %v", v, name, err)
+	}
+	return cidrs, nil
+}
+
+var deprecated = func() map[string]bool {
+	out := map[string]bool{}
+	for _, a := range annotation.AllResourceAnnotations() {
+		if a.Deprecated {
+			out[a.Name] = true
+		}
+	}
+	return out
+}()
+
+// Deprecated returns the names of the deprecated annotations set, sorted.
+func Deprecated(annotations map[string]string) []string {
+	var names []string
+	for name := range annotations {
+		if deprecated[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
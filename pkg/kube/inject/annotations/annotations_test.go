@@ -0,0 +1,92 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetBool(t *testing.T) {
+	annos := map[string]string{"good": "true", "bad": "maybe"}
+	if v, err := GetBool(annos, "good", false); err != nil || !v {
+		t.Errorf("good: got %v, %v", v, err)
+	}
+	if v, err := GetBool(annos, "unset", true); err != nil || !v {
+		t.Errorf("unset: got %v, %v", v, err)
+	}
+	if v, err := GetBool(annos, "bad", true); err == nil || !v {
+		t.Errorf("bad: got %v, %v", v, err)
+	}
+}
+
+func TestGetPort(t *testing.T) {
+	annos := map[string]string{"good": "15021", "bad": "70000"}
+	if v, err := GetPort(annos, "good", 15020); err != nil || v != 15021 {
+		t.Errorf("good: got %v, %v", v, err)
+	}
+	if v, err := GetPort(annos, "unset", 15020); err != nil || v != 15020 {
+		t.Errorf("unset: got %v, %v", v, err)
+	}
+	if _, err := GetPort(annos, "bad", 15020); err == nil {
+		t.Errorf("bad: expected error")
+	}
+}
+
+func TestGetPortList(t *testing.T) {
+	cases := []struct {
+		value   string
+		want    []int
+		wantErr bool
+	}{
+		{"", []int{}, false},
+		{"80", []int{80}, false},
+		{"80, 8080", []int{80, 8080}, false},
+		{"80,http", nil, true},
+	}
+	for _, c := range cases {
+		got, err := GetPortList(map[string]string{"ports": c.value}, "ports")
+		if (err != nil) != c.wantErr {
+			t.Errorf("%q: got err %v, wantErr %v", c.value, err, c.wantErr)
+		}
+		if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: got %v, want %v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestGetCIDRList(t *testing.T) {
+	got, err := GetCIDRList(map[string]string{"cidrs": "10.0.0.0/8,192.168.0.0/16"}, "cidrs")
+	if err != nil || len(got) != 2 || got[1].String() != "192.168.0.0/16" {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if _, err := GetCIDRList(map[string]string{"cidrs": "10.0.0.0"}, "cidrs"); err == nil {
+		t.Errorf("expected error for address without prefix length")
+	}
+	if got, err := GetCIDRList(nil, "cidrs"); err != nil || got != nil {
+		t.Errorf("unset: got %v, %v", got, err)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	deprecated["test.istio.io/old"] = true
+	deprecated["test.istio.io/older"] = true
+	defer delete(deprecated, "test.istio.io/old")
+	defer delete(deprecated, "test.istio.io/older")
+	annos := map[string]string{"test.istio.io/older": "", "test.istio.io/new": "", "test.istio.io/old": ""}
+	if got, want := Deprecated(annos), []string{"test.istio.io/old", "test.istio.io/older"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
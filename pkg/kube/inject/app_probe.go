@@ -19,23 +19,21 @@ package inject
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"istio.io/api/annotation"
 	"istio.io/istio/pilot/cmd/pilot-agent/status"
+	"istio.io/istio/pkg/kube/inject/annotations"
 	"istio.io/pkg/log"
 )
 
 // ShouldRewriteAppHTTPProbers returns if we should rewrite apps' probers config.
-func ShouldRewriteAppHTTPProbers(annotations map[string]string, spec *SidecarInjectionSpec) bool {
-	if annotations != nil {
-		if value, ok := annotations[annotation.SidecarRewriteAppHTTPProbers.Name]; ok {
-			if isSetInAnnotation, err := strconv.ParseBool(value); err == nil {
-				return isSetInAnnotation
-			}
+func ShouldRewriteAppHTTPProbers(annos map[string]string, spec *SidecarInjectionSpec) bool {
+	if _, f := annos[annotation.SidecarRewriteAppHTTPProbers.Name]; f {
+		if isSetInAnnotation, err := annotations.GetBool(annos, annotation.SidecarRewriteAppHTTPProbers.Name, false); err == nil {
+			return isSetInAnnotation
 		}
 	}
 	if spec == nil {
@@ -124,8 +122,8 @@ func DumpAppProbers(podspec *corev1.PodSpec) string {
 }
 
 // createProbeRewritePatch generates the patch for webhook.
-func createProbeRewritePatch(annotations map[string]string, podSpec *corev1.PodSpec, spec *SidecarInjectionSpec, defaultPort int32) []rfc6902PatchOperation {
-	if !ShouldRewriteAppHTTPProbers(annotations, spec) {
+func createProbeRewritePatch(annos map[string]string, podSpec *corev1.PodSpec, spec *SidecarInjectionSpec, defaultPort int32) []rfc6902PatchOperation {
+	if !ShouldRewriteAppHTTPProbers(annos, spec) {
 		return []rfc6902PatchOperation{}
 	}
 	podPatches := []rfc6902PatchOperation{}
@@ -133,13 +131,9 @@ func createProbeRewritePatch(annotations map[string]string, podSpec *corev1.PodS
 	if sidecar == nil {
 		return nil
 	}
-	statusPort := int(defaultPort)
-	if v, f := annotations[annotation.SidecarStatusPort.Name]; f {
-		p, err := strconv.Atoi(v)
-		if err != nil {
-			log.Errorf("Invalid annotation %v=%v: %v", annotation.SidecarStatusPort, p, err)
-		}
-		statusPort = p
+	statusPort, err := annotations.GetPort(annos, annotation.SidecarStatusPort.Name, int(defaultPort))
+	if err != nil {
+		log.Errorf("%v", err)
 	}
 	for i, c := range podSpec.Containers {
 		// Skip sidecar container.
@@ -0,0 +1,206 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// AppProxyAnnotation on a namespace overrides, as JSON, the fields of the
+	// AppProxy configuration set for its pods, or disables it with {"disabled": true}.
+	AppProxyAnnotation = "sidecar.istio.io/appProxy"
+
+	httpProxyEnv  = "HTTP_PROXY"
+	httpsProxyEnv = "HTTPS_PROXY"
+	noProxyEnv    = "NO_PROXY"
+)
+
+// AppProxyConfig injects the corporate proxy settings into the application
+// containers, so they use the proxy for external traffic only.
+type AppProxyConfig struct {
+	// Proxies is the proxy URL used by the workloads of each zone, with the
+	// "*" key for the others. It is rendered into HTTP_PROXY and HTTPS_PROXY.
+	Proxies map[string]string `json:"proxies,omitempty"`
+
+	// MeshCIDRs are the CIDRs of the pods and services of the mesh, added
+	// to NO_PROXY so in-mesh traffic stays on the sidecar.
+	MeshCIDRs []string `json:"meshCIDRs,omitempty"`
+
+	// NoProxy are additional NO_PROXY entries. They are templates of
+	// appProxyTemplateData, e.g. ".{{ .Namespace }}.svc.{{ .ClusterDomain }}".
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// Disabled turns the settings off, for the namespaces opting out with
+	// AppProxyAnnotation.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// appProxyTemplateData is the data the NoProxy entries are rendered with.
+type appProxyTemplateData struct {
+	Namespace     string
+	ClusterDomain string
+}
+
+func validateAppProxy(c *AppProxyConfig) error {
+	if c == nil {
+		return nil
+	}
+	for zone, proxy := range c.Proxies {
+		if zone == "" {
+			return fmt.Errorf("invalid app proxy zone %q", zone)
+		}
+		if u, err := url.Parse(proxy); err != nil || u.Host == "" {
+			return fmt.Errorf("invalid app proxy %q for zone %s", proxy, zone)
+		}
+	}
+	for _, cidr := range c.MeshCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid app proxy mesh CIDR %q: %v", cidr, err)
+		}
+	}
+	for _, entry := range c.NoProxy {
+		if _, err := template.New("noProxy").Option("missingkey=error").Parse(entry); err != nil {
+			return fmt.Errorf("invalid app proxy NO_PROXY entry %q: %v", entry, err)
+		}
+	}
+	return nil
+}
+
+// namespaceAppProxy returns the AppProxy configuration with the fields set
+// by the namespace annotation overridden, or nil if disabled.
+func namespaceAppProxy(c *AppProxyConfig, annotation string) *AppProxyConfig {
+	if annotation == "" {
+		if c == nil || c.Disabled {
+			return nil
+		}
+		return c
+	}
+	var ns AppProxyConfig
+	if err := json.Unmarshal([]byte(annotation), &ns); err != nil || validateAppProxy(&ns) != nil {
+		log.Warnf("Ignoring invalid %s annotation %q", AppProxyAnnotation, annotation)
+		return namespaceAppProxy(c, "")
+	}
+	if ns.Disabled {
+		return nil
+	}
+	merged := AppProxyConfig{}
+	if c != nil {
+		merged = *c
+	}
+	if len(ns.Proxies) > 0 {
+		merged.Proxies = ns.Proxies
+	}
+	if len(ns.MeshCIDRs) > 0 {
+		merged.MeshCIDRs = ns.MeshCIDRs
+	}
+	if len(ns.NoProxy) > 0 {
+		merged.NoProxy = ns.NoProxy
+	}
+	merged.Disabled = false
+	if len(merged.Proxies) == 0 {
+		return nil
+	}
+	return &merged
+}
+
+// appProxyEnvs returns the proxy environment of the application containers
+// of a pod in the zone, or nil if no proxy applies.
+func appProxyEnvs(c *AppProxyConfig, zone string, data appProxyTemplateData) ([]corev1.EnvVar, error) {
+	if c == nil {
+		return nil, nil
+	}
+	proxy, f := c.Proxies[zone]
+	if zone == "" || !f {
+		proxy = c.Proxies[defaultEgressZone]
+	}
+	if proxy == "" {
+		return nil, nil
+	}
+
+	noProxy := []string{"localhost", "127.0.0.1", "::1", ".svc"}
+	if data.ClusterDomain != "" {
+		noProxy = append(noProxy, "."+data.ClusterDomain)
+	}
+	noProxy = append(noProxy, c.MeshCIDRs...)
+	for _, entry := range c.NoProxy {
+		t, err := template.New("noProxy").Option("missingkey=error").Parse(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid app proxy NO_PROXY entry %q: %v", entry, err)
+		}
+		var out bytes.Buffer
+		if err := t.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("invalid app proxy NO_PROXY entry %q: %v", entry, err)
+		}
+		if s := strings.TrimSpace(out.String()); s != "" {
+			noProxy = append(noProxy, s)
+		}
+	}
+	return []corev1.EnvVar{
+		{Name: httpProxyEnv, Value: proxy},
+		{Name: httpsProxyEnv, Value: proxy},
+		{Name: noProxyEnv, Value: strings.Join(noProxy, ",")},
+	}, nil
+}
+
+// addAppEnv adds the environment variables to the application containers,
+// keeping the ones they already define. It skips the containers previously
+// injected, removed by the patch.
+func addAppEnv(target []corev1.Container, added []corev1.EnvVar, skipped []string, basePath string) (patch []rfc6902PatchOperation) {
+	if len(added) == 0 {
+		return nil
+	}
+	skip := map[string]bool{ProxyContainerName: true}
+	for _, name := range skipped {
+		skip[name] = true
+	}
+	for i, c := range target {
+		if skip[c.Name] {
+			continue
+		}
+		defined := map[string]bool{}
+		for _, e := range c.Env {
+			defined[e.Name] = true
+		}
+		var missing []corev1.EnvVar
+		for _, e := range added {
+			if !defined[e.Name] {
+				missing = append(missing, e)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		path := fmt.Sprintf("%s/%d/env", basePath, i)
+		if len(c.Env) == 0 {
+			patch = append(patch, rfc6902PatchOperation{Op: "add", Path: path, Value: missing})
+			continue
+		}
+		for _, e := range missing {
+			patch = append(patch, rfc6902PatchOperation{Op: "add", Path: path + "/-", Value: e})
+		}
+	}
+	return patch
+}
@@ -0,0 +1,180 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestAppProxyEnvs(t *testing.T) {
+	config := &AppProxyConfig{
+		Proxies:   map[string]string{"*": "http://proxy:3128", "us-east-1a": "http://proxy-a:3128"},
+		MeshCIDRs: []string{"10.0.0.0/8"},
+		NoProxy:   []string{".{{ .Namespace }}.svc.{{ .ClusterDomain }}", "internal.example.com"},
+	}
+	cases := []struct {
+		name       string
+		config     *AppProxyConfig
+		annotation string
+		zone       string
+		want       []corev1.EnvVar
+	}{
+		{
+			name:   "default zone",
+			config: config,
+			want: []corev1.EnvVar{
+				{Name: httpProxyEnv, Value: "http://proxy:3128"},
+				{Name: httpsProxyEnv, Value: "http://proxy:3128"},
+				{Name: noProxyEnv, Value: "localhost,127.0.0.1,::1,.svc,.cluster.local,10.0.0.0/8,.app.svc.cluster.local,internal.example.com"},
+			},
+		},
+		{
+			name:   "zone proxy",
+			config: &AppProxyConfig{Proxies: config.Proxies},
+			zone:   "us-east-1a",
+			want: []corev1.EnvVar{
+				{Name: httpProxyEnv, Value: "http://proxy-a:3128"},
+				{Name: httpsProxyEnv, Value: "http://proxy-a:3128"},
+				{Name: noProxyEnv, Value: "localhost,127.0.0.1,::1,.svc,.cluster.local"},
+			},
+		},
+		{
+			name:       "namespace override",
+			config:     config,
+			annotation: `{"proxies":{"*":"http://team-proxy:8080"},"noProxy":["team.example.com"]}`,
+			want: []corev1.EnvVar{
+				{Name: httpProxyEnv, Value: "http://team-proxy:8080"},
+				{Name: httpsProxyEnv, Value: "http://team-proxy:8080"},
+				{Name: noProxyEnv, Value: "localhost,127.0.0.1,::1,.svc,.cluster.local,10.0.0.0/8,team.example.com"},
+			},
+		},
+		{
+			name:       "namespace without mesh config",
+			annotation: `{"proxies":{"*":"http://team-proxy:8080"}}`,
+			want: []corev1.EnvVar{
+				{Name: httpProxyEnv, Value: "http://team-proxy:8080"},
+				{Name: httpsProxyEnv, Value: "http://team-proxy:8080"},
+				{Name: noProxyEnv, Value: "localhost,127.0.0.1,::1,.svc,.cluster.local"},
+			},
+		},
+		{
+			name:       "namespace opt out",
+			config:     config,
+			annotation: `{"disabled":true}`,
+		},
+		{
+			name:       "invalid annotation ignored",
+			config:     &AppProxyConfig{Proxies: map[string]string{"*": "http://proxy:3128"}},
+			annotation: `{"proxies":{"*":"not a url"}}`,
+			want: []corev1.EnvVar{
+				{Name: httpProxyEnv, Value: "http://proxy:3128"},
+				{Name: httpsProxyEnv, Value: "http://proxy:3128"},
+				{Name: noProxyEnv, Value: "localhost,127.0.0.1,::1,.svc,.cluster.local"},
+			},
+		},
+		{
+			name:   "no proxy for the zone",
+			config: &AppProxyConfig{Proxies: map[string]string{"us-east-1a": "http://proxy-a:3128"}},
+			zone:   "us-west-2a",
+		},
+		{
+			name: "not configured",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := appProxyEnvs(namespaceAppProxy(c.config, c.annotation), c.zone,
+				appProxyTemplateData{Namespace: "app", ClusterDomain: "cluster.local"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateAppProxy(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  *AppProxyConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", &AppProxyConfig{Proxies: map[string]string{"*": "http://proxy:3128"}, MeshCIDRs: []string{"10.0.0.0/8"}}, false},
+		{"invalid proxy", &AppProxyConfig{Proxies: map[string]string{"*": "proxy"}}, true},
+		{"invalid CIDR", &AppProxyConfig{MeshCIDRs: []string{"10.0.0.0"}}, true},
+		{"invalid template", &AppProxyConfig{NoProxy: []string{"{{ .Namespace"}}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := validateAppProxy(c.config); (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddAppEnv(t *testing.T) {
+	envs := []corev1.EnvVar{{Name: httpProxyEnv, Value: "http://proxy:3128"}, {Name: noProxyEnv, Value: "localhost"}}
+	containers := []corev1.Container{
+		{Name: "app"},
+		{Name: "custom", Env: []corev1.EnvVar{{Name: httpProxyEnv, Value: "http://own:3128"}}},
+		{Name: ProxyContainerName},
+		{Name: "injected-before"},
+	}
+	got := addAppEnv(containers, envs, []string{"injected-before"}, "/spec/containers")
+	want := []rfc6902PatchOperation{
+		{Op: "add", Path: "/spec/containers/0/env", Value: envs},
+		{Op: "add", Path: "/spec/containers/1/env/-", Value: envs[1]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestIntoResourceFileAppProxy(t *testing.T) {
+	c := &Config{
+		Template: minimalSidecarTemplate.Template,
+		AppProxy: &AppProxyConfig{Proxies: map[string]string{"*": "http://proxy:3128"}},
+	}
+	m := mesh.DefaultMeshConfig()
+	in := `apiVersion: v1
+kind: Pod
+metadata:
+  name: hello
+  namespace: app
+spec:
+  containers:
+  - name: hello
+    image: fake.docker.io/google-samples/hello-go-gke:1.0
+`
+	var out bytes.Buffer
+	if err := IntoResourceFileWithConfig(nil, c, "{}", "", &m, strings.NewReader(in), &out, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+	// kube-inject sets the proxy environment of the application containers as the webhook does
+	if !strings.Contains(out.String(), "HTTP_PROXY") {
+		t.Fatalf("the application proxy environment is not injected:\n%s", out.String())
+	}
+}
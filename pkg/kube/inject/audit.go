@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"istio.io/pkg/log"
+)
+
+// AuditRecord is the line of the audit log recording an admission decision.
+// Like Decision, only the identity of the workload is recorded.
+type AuditRecord struct {
+	Time         time.Time `json:"time"`
+	Namespace    string    `json:"namespace"`
+	GenerateName string    `json:"generateName,omitempty"`
+	OwnerKind    string    `json:"ownerKind,omitempty"`
+	Decision     string    `json:"decision"`
+	// Reason is the skip reason or the error of failed injections.
+	Reason string `json:"reason,omitempty"`
+	// Template is the version hash of the injection template in use.
+	Template  string `json:"template,omitempty"`
+	PatchSize int    `json:"patchSize"`
+}
+
+// auditLog appends one JSON line per admission decision to a file.
+type auditLog struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func openAuditLog(path string) (*auditLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the injection audit log: %v", err)
+	}
+	return &auditLog{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (a *auditLog) record(r AuditRecord) {
+	if a == nil {
+		return
+	}
+	r.Reason = decisionReason(r.Reason)
+	r.Time = time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.enc == nil {
+		// closed, requests may still be served by the shared HTTPS server
+		return
+	}
+	if err := a.enc.Encode(r); err != nil {
+		log.Errorf("Failed to write the injection audit log: %v", err)
+	}
+}
+
+func (a *auditLog) close() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enc = nil
+	if err := a.f.Close(); err != nil {
+		log.Warnf("Failed to close the injection audit log: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	if a, err := openAuditLog(""); a != nil || err != nil {
+		t.Fatalf("expected no audit log without a file, got %v, %v", a, err)
+	}
+	a, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.record(AuditRecord{Namespace: "app", GenerateName: "productpage-v1-", OwnerKind: "Deployment",
+		Decision: DecisionInjected, Template: "v1", PatchSize: 1024})
+	a.record(AuditRecord{Namespace: "app", GenerateName: "job-", OwnerKind: "Job",
+		Decision: DecisionSkipped, Reason: skipReasonPolicy, Template: "v1"})
+	a.record(AuditRecord{Namespace: "app", Decision: DecisionFailed, Reason: "template error\n" + strings.Repeat("x", 1000)})
+	a.close()
+	// records once closed are dropped
+	a.record(AuditRecord{Namespace: "app", Decision: DecisionInjected})
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d audit records, want 3: %s", len(lines), data)
+	}
+	var records []AuditRecord
+	for _, l := range lines {
+		var r AuditRecord
+		if err := json.Unmarshal([]byte(l), &r); err != nil {
+			t.Fatalf("invalid audit record %q: %v", l, err)
+		}
+		records = append(records, r)
+	}
+	if r := records[0]; r.GenerateName != "productpage-v1-" || r.OwnerKind != "Deployment" || r.PatchSize != 1024 || r.Time.IsZero() {
+		t.Errorf("unexpected injected record %+v", r)
+	}
+	if r := records[1]; r.Decision != DecisionSkipped || r.Reason != skipReasonPolicy {
+		t.Errorf("unexpected skipped record %+v", r)
+	}
+	if r := records[2]; r.Reason != "template error" {
+		t.Errorf("expected the reason truncated to its first line, got %q", r.Reason)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"sync"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+)
+
+// CanaryOptions configures the validation of reloaded injection
+// configurations against the pods recently injected.
+type CanaryOptions struct {
+	// Samples is the number of recently injected pods kept. Zero disables the canary.
+	Samples int
+
+	// MaxFailureRatio is the ratio of the samples allowed to fail injection
+	// with a new configuration. Above it the configuration is not activated.
+	MaxFailureRatio float64
+}
+
+// canary keeps a ring of the parameters of recently successful injections,
+// with the pods redacted.
+type canary struct {
+	mu      sync.Mutex
+	options CanaryOptions
+	samples []InjectionParameters
+	next    int
+}
+
+func newCanary(o CanaryOptions) *canary {
+	if o.Samples <= 0 {
+		return nil
+	}
+	return &canary{options: o}
+}
+
+// record adds the parameters of a successful injection to the ring.
+func (c *canary) record(params InjectionParameters) {
+	if c == nil {
+		return
+	}
+	params.pod = redactPod(params.pod)
+	params.ctx = nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.samples) < c.options.Samples {
+		c.samples = append(c.samples, params)
+		return
+	}
+	c.samples[c.next] = params
+	c.next = (c.next + 1) % c.options.Samples
+}
+
+// check injects the recorded pods with the configuration and returns an
+// error if too many of them fail.
+func (c *canary) check(config *Config, valuesConfig, version string, mc *meshconfig.MeshConfig) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	samples := append([]InjectionParameters{}, c.samples...)
+	c.mu.Unlock()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	failures := 0
+	var lastErr error
+	for _, s := range samples {
+		params := s.withConfig(config, valuesConfig, version, mc)
+		params.pod = s.pod.DeepCopy()
+		if _, _, err := InjectionData(params, params.typeMeta, params.deployMeta); err != nil {
+			failures++
+			lastErr = err
+		}
+	}
+	if ratio := float64(failures) / float64(len(samples)); ratio > c.options.MaxFailureRatio {
+		return fmt.Errorf("injection of %d of the %d recently injected pods fails with the new configuration: %v",
+			failures, len(samples), lastErr)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestCanary(t *testing.T) {
+	m := mesh.DefaultMeshConfig()
+	brokenTemplate := &Config{Policy: InjectionPolicyEnabled, Template: "containers: ["}
+
+	cases := []struct {
+		name            string
+		maxFailureRatio float64
+		config          *Config
+		wantErr         bool
+	}{
+		{"valid configuration", 0, minimalSidecarTemplate, false},
+		{"broken configuration", 0, brokenTemplate, true},
+		{"broken configuration within ratio", 1, brokenTemplate, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			canary := newCanary(CanaryOptions{Samples: 2, MaxFailureRatio: c.maxFailureRatio})
+			for i := 0; i < 3; i++ {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"}}
+				canary.record(InjectionParameters{
+					pod:        pod,
+					deployMeta: &pod.ObjectMeta,
+					typeMeta:   &metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				})
+			}
+			if len(canary.samples) != 2 || canary.samples[0].pod.Name != "pod-2" {
+				t.Fatalf("unexpected samples %v", canary.samples)
+			}
+			err := canary.check(c.config, "global: {}", "v1", &m)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanaryDisabled(t *testing.T) {
+	canary := newCanary(CanaryOptions{})
+	canary.record(InjectionParameters{pod: &corev1.Pod{}})
+	if err := canary.check(&Config{Template: "containers: ["}, "", "", nil); err != nil {
+		t.Fatalf("disabled canary returned %v", err)
+	}
+}
+
+func TestCanaryHoldsMeshConfig(t *testing.T) {
+	good := mesh.DefaultMeshConfig()
+	wh := &Webhook{
+		Config: &Config{Policy: InjectionPolicyEnabled, Template: `
+containers:
+- name: istio-proxy
+  args: ["--concurrency", "{{ .MeshConfig.DefaultConfig.Concurrency.Value }}"]
+`},
+		valuesConfig: "global: {}",
+		meshConfig:   &good,
+		canary:       newCanary(CanaryOptions{Samples: 1}),
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}}
+	wh.canary.record(InjectionParameters{pod: pod, deployMeta: &pod.ObjectMeta, typeMeta: &metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}})
+
+	broken := mesh.DefaultMeshConfig()
+	broken.DefaultConfig.Concurrency = nil
+	wh.updateMeshConfig(&broken)
+	if wh.meshConfig != &good {
+		t.Fatalf("mesh config breaking injection activated")
+	}
+
+	updated := mesh.DefaultMeshConfig()
+	updated.DefaultConfig.Concurrency.Value = 4
+	wh.updateMeshConfig(&updated)
+	if wh.meshConfig != &updated {
+		t.Fatalf("valid mesh config not activated")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"istio.io/pkg/log"
+)
+
+// ClientAuthOptions restricts the callers of the injection endpoint to
+// clients presenting a verified certificate with an allowed subject. It
+// requires the HTTPS server to request and verify client certificates.
+// Leaving both lists empty, without RequireClientCertificate, allows any caller.
+type ClientAuthOptions struct {
+	// AllowedCommonNames are the subject common names allowed to call the webhook.
+	AllowedCommonNames []string
+
+	// AllowedOrganizations are the subject organizations allowed to call the webhook.
+	AllowedOrganizations []string
+
+	// RequireClientCertificate allows any client presenting a verified
+	// certificate when both lists are empty.
+	RequireClientCertificate bool
+}
+
+// Validate returns an error if subjects are allowed without the client
+// certificates being verified, as no caller could then be allowed.
+func (o ClientAuthOptions) Validate() error {
+	if (len(o.AllowedCommonNames) > 0 || len(o.AllowedOrganizations) > 0) && !o.RequireClientCertificate {
+		return errors.New("the allowed client certificate subjects require the HTTPS server to verify client certificates")
+	}
+	return nil
+}
+
+func (o ClientAuthOptions) enabled() bool {
+	return o.RequireClientCertificate || len(o.AllowedCommonNames) > 0 || len(o.AllowedOrganizations) > 0
+}
+
+// authorize returns an error if the client of the request is not allowed.
+func (o ClientAuthOptions) authorize(r *http.Request) error {
+	if !o.enabled() {
+		return nil
+	}
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return errors.New("no verified client certificate")
+	}
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	if len(o.AllowedCommonNames) == 0 && len(o.AllowedOrganizations) == 0 {
+		return nil
+	}
+	for _, cn := range o.AllowedCommonNames {
+		if subject.CommonName == cn {
+			return nil
+		}
+	}
+	for _, org := range subject.Organization {
+		for _, allowed := range o.AllowedOrganizations {
+			if org == allowed {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("client %q is not allowed", subject.String())
+}
+
+// authorizeClient wraps the handler, rejecting clients not allowed by the options.
+func (o ClientAuthOptions) authorizeClient(next http.HandlerFunc) http.HandlerFunc {
+	if !o.enabled() {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := o.authorize(r); err != nil {
+			totalUnauthorizedInjections.Increment()
+			log.Warnf("Rejecting injection request from %s: %v", r.RemoteAddr, err)
+			http.Error(w, "client not allowed", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorizeClient(t *testing.T) {
+	withClient := func(subject *pkix.Name) *http.Request {
+		r := httptest.NewRequest("POST", "https://istiod/inject", nil)
+		if subject == nil {
+			r.TLS = nil
+			return r
+		}
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: *subject}}}}
+		return r
+	}
+	allowlist := ClientAuthOptions{
+		AllowedCommonNames:   []string{"kube-apiserver"},
+		AllowedOrganizations: []string{"system:masters"},
+	}
+
+	cases := []struct {
+		name    string
+		options ClientAuthOptions
+		request *http.Request
+		want    int
+	}{
+		{"disabled", ClientAuthOptions{}, withClient(nil), http.StatusOK},
+		{"no client certificate", allowlist, withClient(nil), http.StatusForbidden},
+		{"allowed common name", allowlist, withClient(&pkix.Name{CommonName: "kube-apiserver"}), http.StatusOK},
+		{"allowed organization", allowlist, withClient(&pkix.Name{CommonName: "other", Organization: []string{"system:masters"}}),
+			http.StatusOK},
+		{"not allowed", allowlist, withClient(&pkix.Name{CommonName: "other", Organization: []string{"other"}}), http.StatusForbidden},
+		{"required certificate", ClientAuthOptions{RequireClientCertificate: true}, withClient(&pkix.Name{CommonName: "other"}),
+			http.StatusOK},
+		{"required certificate missing", ClientAuthOptions{RequireClientCertificate: true}, withClient(nil), http.StatusForbidden},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c.options.authorizeClient(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})(w, c.request)
+			if w.Code != c.want {
+				t.Fatalf("got status %v, want %v", w.Code, c.want)
+			}
+		})
+	}
+}
+
+func TestClientAuthOptionsValidate(t *testing.T) {
+	if err := (ClientAuthOptions{AllowedCommonNames: []string{"kube-apiserver"}}).Validate(); err == nil {
+		t.Fatal("expected an allowlist without verified client certificates to be refused")
+	}
+	if err := (ClientAuthOptions{AllowedCommonNames: []string{"kube-apiserver"}, RequireClientCertificate: true}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthorizeClientWithoutTLS(t *testing.T) {
+	wh := &Webhook{}
+	wh.injectHandler = ClientAuthOptions{RequireClientCertificate: true}.authorizeClient(wh.serveInject)
+	server, listener, err := wh.serveInsecure("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	res, err := http.Post("http://"+listener.Addr().String()+"/inject", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusForbidden {
+		t.Fatalf("got status %v on the insecure port, want %v", res.StatusCode, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ManagedCompatibilityProfile avoids mutations disallowed on managed
+	// platforms such as GKE Autopilot, which reject privileged containers and
+	// network administration capabilities.
+	ManagedCompatibilityProfile = "managed"
+)
+
+// managedDisallowedCapabilities are the capabilities managed platforms refuse to grant.
+var managedDisallowedCapabilities = map[corev1.Capability]bool{
+	"NET_ADMIN": true,
+	"NET_RAW":   true,
+	"SYS_ADMIN": true,
+}
+
+// managedInitCapabilities are the capabilities managed platforms grant to the
+// istio-init container of the chart, as it cannot redirect the traffic of the
+// pod without them.
+var managedInitCapabilities = map[corev1.Capability]bool{
+	"NET_ADMIN": true,
+	"NET_RAW":   true,
+}
+
+func validateCompatibilityProfile(profile string) error {
+	switch profile {
+	case "", ManagedCompatibilityProfile:
+		return nil
+	default:
+		return fmt.Errorf("unknown compatibility profile %q", profile)
+	}
+}
+
+// checkCompatibilityProfile reports the injected containers which break the
+// constraints of the profile as an error. The containers are not modified: a
+// proxy made privileged for debugging must be reverted by whoever requested
+// it, rather than silently run without its privileges.
+func checkCompatibilityProfile(profile string, sic *SidecarInjectionSpec) error {
+	if profile != ManagedCompatibilityProfile {
+		return nil
+	}
+	var err error
+	for _, c := range append(append([]corev1.Container{}, sic.InitContainers...), sic.Containers...) {
+		if e := managedContainerViolations(c); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	if err != nil {
+		return multierror.Prefix(err, fmt.Sprintf("injection not supported by the %s compatibility profile "+
+			"(enable istio-cni to capture traffic without privileged init containers):", ManagedCompatibilityProfile))
+	}
+	return nil
+}
+
+func managedContainerViolations(c corev1.Container) error {
+	sc := c.SecurityContext
+	if sc == nil {
+		return nil
+	}
+	if sc.Privileged != nil && *sc.Privileged {
+		return fmt.Errorf("container %q requires privileged mode", c.Name)
+	}
+	if sc.Capabilities != nil {
+		for _, capability := range sc.Capabilities.Add {
+			if c.Name == InitContainerName && managedInitCapabilities[capability] {
+				continue
+			}
+			if managedDisallowedCapabilities[capability] {
+				return fmt.Errorf("container %q requires capability %s", c.Name, capability)
+			}
+		}
+	}
+	return nil
+}
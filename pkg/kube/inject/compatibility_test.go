@@ -0,0 +1,98 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCheckCompatibilityProfile(t *testing.T) {
+	privileged := func() *corev1.SecurityContext {
+		p := true
+		return &corev1.SecurityContext{Privileged: &p}
+	}
+	netAdmin := &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}}}
+
+	cases := []struct {
+		name    string
+		profile string
+		sic     SidecarInjectionSpec
+		wantErr bool
+	}{
+		{
+			name:    "no profile",
+			sic:     SidecarInjectionSpec{InitContainers: []corev1.Container{{Name: "istio-init", SecurityContext: netAdmin}}},
+			wantErr: false,
+		},
+		{
+			name:    "privileged init",
+			profile: ManagedCompatibilityProfile,
+			sic:     SidecarInjectionSpec{InitContainers: []corev1.Container{{Name: "istio-init", SecurityContext: privileged()}}},
+			wantErr: true,
+		},
+		{
+			name:    "net admin istio-init",
+			profile: ManagedCompatibilityProfile,
+			sic:     SidecarInjectionSpec{InitContainers: []corev1.Container{{Name: InitContainerName, SecurityContext: netAdmin}}},
+			wantErr: false,
+		},
+		{
+			name:    "net admin custom init",
+			profile: ManagedCompatibilityProfile,
+			sic:     SidecarInjectionSpec{InitContainers: []corev1.Container{{Name: "setup-network", SecurityContext: netAdmin}}},
+			wantErr: true,
+		},
+		{
+			name:    "sys admin istio-init",
+			profile: ManagedCompatibilityProfile,
+			sic: SidecarInjectionSpec{InitContainers: []corev1.Container{{Name: InitContainerName, SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}},
+			}}}},
+			wantErr: true,
+		},
+		{
+			name:    "cni validation",
+			profile: ManagedCompatibilityProfile,
+			sic: SidecarInjectionSpec{
+				InitContainers: []corev1.Container{{Name: ValidationContainerName}},
+				Containers:     []corev1.Container{{Name: ProxyContainerName}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "privileged proxy",
+			profile: ManagedCompatibilityProfile,
+			sic: SidecarInjectionSpec{
+				InitContainers: []corev1.Container{{Name: ValidationContainerName}},
+				Containers:     []corev1.Container{{Name: ProxyContainerName, SecurityContext: privileged()}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkCompatibilityProfile(c.profile, &c.sic)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got err %v, wantErr %v", err, c.wantErr)
+			}
+		})
+	}
+
+	if err := validateCompatibilityProfile("unknown"); err == nil {
+		t.Fatalf("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"istio.io/istio/pkg/util/gogoprotomarshal"
+)
+
+// ConfigDump is the injection configuration in effect.
+type ConfigDump struct {
+	Template    string          `json:"template"`
+	TemplateSHA string          `json:"templateSHA"`
+	Values      json.RawMessage `json:"values,omitempty"`
+	// ValuesEncrypted is set when the values may be encrypted, so are not dumped.
+	ValuesEncrypted bool            `json:"valuesEncrypted,omitempty"`
+	MeshConfig      json.RawMessage `json:"meshConfig,omitempty"`
+}
+
+// ConfigDump returns the active injection configuration.
+func (wh *Webhook) ConfigDump() (ConfigDump, error) {
+	wh.mu.RLock()
+	defer wh.mu.RUnlock()
+	d := ConfigDump{
+		Template:    wh.Config.Template,
+		TemplateSHA: wh.sidecarTemplateVersion,
+	}
+	if wh.valuesKeys != nil {
+		// the values may hold secrets, only decrypted in memory
+		d.ValuesEncrypted = true
+	} else if json.Valid([]byte(wh.valuesConfig)) {
+		d.Values = json.RawMessage(wh.valuesConfig)
+	} else if wh.valuesConfig != "" {
+		values, err := json.Marshal(wh.valuesConfig)
+		if err != nil {
+			return ConfigDump{}, err
+		}
+		d.Values = values
+	}
+	if wh.meshConfig != nil {
+		mc, err := gogoprotomarshal.ToJSON(wh.meshConfig)
+		if err != nil {
+			return ConfigDump{}, err
+		}
+		d.MeshConfig = json.RawMessage(mc)
+	}
+	return d, nil
+}
+
+// ServeNamespaceRefresh relists the namespaces of the namespace cache on a
+// POST, 404 when the namespaces are not cached.
+func (wh *Webhook) ServeNamespaceRefresh(w http.ResponseWriter, r *http.Request) {
+	if wh.namespaces == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	wh.namespaces.serveRefresh(w, r)
+}
+
+// ServeDriftReport serves the last drift report, 404 when the drift is not
+// verified.
+func (wh *Webhook) ServeDriftReport(w http.ResponseWriter, r *http.Request) {
+	if wh.drift == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	wh.drift.serveReport(w, r)
+}
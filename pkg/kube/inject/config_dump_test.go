@@ -0,0 +1,66 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestConfigDump(t *testing.T) {
+	m := mesh.DefaultMeshConfig()
+	wh := &Webhook{
+		Config:                 &Config{Template: "containers: []"},
+		sidecarTemplateVersion: "fake-version",
+		valuesConfig:           `{"global":{"hub":"docker.io/istio"}}`,
+		meshConfig:             &m,
+	}
+	d, err := wh.ConfigDump()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Template    string                 `json:"template"`
+		TemplateSHA string                 `json:"templateSHA"`
+		Values      map[string]interface{} `json:"values"`
+		MeshConfig  map[string]interface{} `json:"meshConfig"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid config dump %s: %v", body, err)
+	}
+	if got.Template != "containers: []" || got.TemplateSHA != "fake-version" || got.Values["global"] == nil || got.MeshConfig["defaultConfig"] == nil {
+		t.Fatalf("unexpected config dump %s", body)
+	}
+
+	// the namespaces are not cached and the drift is not verified
+	w := httptest.NewRecorder()
+	wh.ServeNamespaceRefresh(w, httptest.NewRequest("POST", "/debug/inject_namespaces/refresh", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d for the namespace refresh", w.Code)
+	}
+	w = httptest.NewRecorder()
+	wh.ServeDriftReport(w, httptest.NewRequest("GET", "/debug/inject_drift", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d for the drift report", w.Code)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/config/mesh"
+)
+
+var configInfoDesc = prometheus.NewDesc(
+	"sidecar_injection_config_info",
+	"Versions of the active injection configuration, by the hashes of the template and values, the resource "+
+		"version of the mesh config and the proxy image tag of the values.",
+	[]string{"template", "values", "mesh_config", "proxy_tag"},
+	nil,
+)
+
+// configInfos collects the config info of the webhooks of the process.
+var configInfos = &configInfoCollector{recorders: map[*configInfoRecorder]struct{}{}}
+
+// configInfo is the set of labels of the config info metric.
+type configInfo struct {
+	versions ConfigVersions
+	proxyTag string
+}
+
+// configInfoRecorder records the versions of the active configuration, so
+// dashboards find the injectors still serving a stale configuration after a
+// rollout.
+type configInfoRecorder struct {
+	mu   sync.Mutex
+	last *configInfo
+}
+
+// record reads the active configuration under r.mu, so concurrent reloads are
+// recorded in the order they are applied.
+func (r *configInfoRecorder) record(read func() configInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info := read()
+	if r.last != nil && *r.last == info {
+		return
+	}
+	if r.last == nil {
+		configInfos.add(r)
+	}
+	r.last = &info
+}
+
+func (r *configInfoRecorder) active() *configInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// configInfoCollector reports only the active configuration of each webhook:
+// a superseded one disappears from the next scrape.
+type configInfoCollector struct {
+	mu        sync.Mutex
+	recorders map[*configInfoRecorder]struct{}
+}
+
+func (c *configInfoCollector) add(r *configInfoRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorders[r] = struct{}{}
+}
+
+func (c *configInfoCollector) remove(r *configInfoRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.recorders, r)
+}
+
+func (c *configInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- configInfoDesc
+}
+
+func (c *configInfoCollector) Collect(ch chan<- prometheus.Metric) {
+	// the recorders are read without c.mu, record holds r.mu when adding one
+	c.mu.Lock()
+	recorders := make([]*configInfoRecorder, 0, len(c.recorders))
+	for r := range c.recorders {
+		recorders = append(recorders, r)
+	}
+	c.mu.Unlock()
+	// webhooks with the same configuration are reported once
+	seen := map[configInfo]struct{}{}
+	for _, r := range recorders {
+		info := r.active()
+		if info == nil {
+			continue
+		}
+		if _, ok := seen[*info]; ok {
+			continue
+		}
+		seen[*info] = struct{}{}
+		ch <- prometheus.MustNewConstMetric(configInfoDesc, prometheus.GaugeValue, 1,
+			info.versions.Template, info.versions.Values, info.versions.MeshConfig, info.proxyTag)
+	}
+}
+
+// meshConfigVersion returns the resource version of the mesh ConfigMap when it
+// holds the active mesh config, empty when it is not watched or the mounted
+// file is not updated yet.
+func meshConfigVersion(cm *corev1.ConfigMap, active *meshconfig.MeshConfig) string {
+	if cm == nil || active == nil {
+		return ""
+	}
+	mc, err := mesh.ApplyMeshConfigDefaults(cm.Data["mesh"])
+	if err != nil || !proto.Equal(mc, active) {
+		return ""
+	}
+	return cm.ResourceVersion
+}
+
+// recordConfigInfo records the versions of the active configuration.
+func (wh *Webhook) recordConfigInfo() {
+	wh.configInfo.record(func() configInfo {
+		wh.mu.RLock()
+		defer wh.mu.RUnlock()
+		// the template may set the image regardless of the values, leave the tag empty then
+		tag, _ := proxyImageTag(wh.valuesConfig)
+		return configInfo{versions: wh.configVersions(), proxyTag: tag}
+	})
+}
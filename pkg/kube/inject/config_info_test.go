@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestRecordConfigInfo(t *testing.T) {
+	wh, cleanup := createWebhook(t, minimalSidecarTemplate)
+	defer cleanup()
+	wh.recordConfigInfo()
+	first := wh.configInfo.last
+	// the mesh ConfigMap is not watched
+	if first == nil || first.versions.Template != wh.sidecarTemplateVersion || first.versions.MeshConfig != "" {
+		t.Fatalf("unexpected config info %+v", first)
+	}
+
+	wh.mu.Lock()
+	wh.valuesConfig = `{"global":{"proxy":{"image":"docker.io/istio/proxyv2:1.8.1"}}}`
+	wh.mu.Unlock()
+	wh.recordConfigInfo()
+	second := wh.configInfo.last
+	if second == first || second.proxyTag != "1.8.1" || second.versions.Values == first.versions.Values {
+		t.Fatalf("config info not updated: %+v", second)
+	}
+
+	// an unchanged configuration is not recorded again
+	wh.recordConfigInfo()
+	if wh.configInfo.last != second {
+		t.Fatal("unchanged config info recorded again")
+	}
+}
+
+func TestConfigInfoCollector(t *testing.T) {
+	wh, cleanup := createWebhook(t, minimalSidecarTemplate)
+	defer cleanup()
+	defer configInfos.remove(&wh.configInfo)
+	collector := &configInfoCollector{recorders: map[*configInfoRecorder]struct{}{&wh.configInfo: {}}}
+
+	wh.recordConfigInfo()
+	wh.mu.Lock()
+	wh.valuesConfig = `{"global":{"proxy":{"image":"docker.io/istio/proxyv2:1.8.1"}}}`
+	wh.mu.Unlock()
+	wh.recordConfigInfo()
+	// the superseded configuration is not reported
+	if n := testutil.CollectAndCount(collector); n != 1 {
+		t.Fatalf("got %d config info series, want 1", n)
+	}
+}
+
+func TestMeshConfigVersion(t *testing.T) {
+	active := mesh.DefaultMeshConfig()
+	active.IngressClass = "foo"
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system", ResourceVersion: "42"},
+		Data:       map[string]string{"mesh": "ingressClass: foo"},
+	}
+	if got := meshConfigVersion(cm, &active); got != "42" {
+		t.Fatalf("got version %q, want 42", got)
+	}
+	// the mounted file is not updated yet
+	cm.Data["mesh"] = "ingressClass: bar"
+	if got := meshConfigVersion(cm, &active); got != "" {
+		t.Fatalf("got version %q for a stale mesh config", got)
+	}
+	if got := meshConfigVersion(nil, &active); got != "" {
+		t.Fatalf("got version %q without the ConfigMap", got)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// configMapConfigKey and configMapValuesKey are the keys of the injection
+	// ConfigMap mounted as the config and values files.
+	configMapConfigKey = "config"
+	configMapValuesKey = "values"
+
+	// configMapSyncTimeout is how long the ConfigMap is waited for before
+	// relying on the mounted files only.
+	configMapSyncTimeout = 30 * time.Second
+)
+
+// ConfigMapWatchOptions watches the injection ConfigMap with the Kubernetes
+// API, so its updates are applied within seconds rather than once the
+// kubelet has updated the mounted files, which can take over a minute. The
+// files are only relied on until the ConfigMap is synced, as the lagging
+// files would otherwise revert its updates. Requires KubeClient.
+type ConfigMapWatchOptions struct {
+	// Name of the ConfigMap. Empty disables the watch.
+	Name string
+
+	// Namespace of the ConfigMap.
+	Namespace string
+}
+
+func (o ConfigMapWatchOptions) enabled() bool {
+	return o.Name != ""
+}
+
+// configMapWatcher applies the updates of a ConfigMap, the injection or the
+// mesh one.
+type configMapWatcher struct {
+	options ConfigMapWatchOptions
+	// kind names the ConfigMap in logs.
+	kind     string
+	informer cache.SharedIndexInformer
+}
+
+func newConfigMapWatcher(client kubernetes.Interface, o ConfigMapWatchOptions, kind string,
+	apply func(*corev1.ConfigMap)) *configMapWatcher {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithNamespace(o.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", o.Name).String()
+		}))
+	w := &configMapWatcher{
+		options:  o,
+		kind:     kind,
+		informer: factory.Core().V1().ConfigMaps().Informer(),
+	}
+	handle := func(obj interface{}) {
+		if cm, ok := obj.(*corev1.ConfigMap); ok {
+			apply(cm)
+		}
+	}
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    handle,
+		UpdateFunc: func(_, obj interface{}) { handle(obj) },
+	})
+	return w
+}
+
+// synced returns whether the ConfigMap is watched, so the mounted files are
+// not relied on.
+func (w *configMapWatcher) synced() bool {
+	return w != nil && w.informer.HasSynced()
+}
+
+// latest returns the last version of the ConfigMap watched, nil if it does not
+// exist or is not watched.
+func (w *configMapWatcher) latest() *corev1.ConfigMap {
+	if w == nil {
+		return nil
+	}
+	obj, exists, err := w.informer.GetStore().GetByKey(w.options.Namespace + "/" + w.options.Name)
+	if err != nil || !exists {
+		return nil
+	}
+	cm, _ := obj.(*corev1.ConfigMap)
+	return cm
+}
+
+// run watches the ConfigMap until the stop channel is closed.
+func (w *configMapWatcher) run(stop <-chan struct{}) {
+	go w.informer.Run(stop)
+	synced := make(chan struct{})
+	go func() {
+		if cache.WaitForCacheSync(stop, w.informer.HasSynced) {
+			close(synced)
+		}
+	}()
+	select {
+	case <-synced:
+		log.Infof("Watching the %s ConfigMap %s/%s", w.kind, w.options.Namespace, w.options.Name)
+	case <-time.After(configMapSyncTimeout):
+		log.Warnf("Could not watch the %s ConfigMap %s/%s, relying on the mounted files until it is reachable",
+			w.kind, w.options.Namespace, w.options.Name)
+	case <-stop:
+	}
+}
+
+// applyConfigMap activates the injection configuration of the ConfigMap,
+// unless the files are overridden by the template override directory. During
+// a freeze window the ConfigMap is applied again once the window is over.
+func (wh *Webhook) applyConfigMap(cm *corev1.ConfigMap) {
+	wh.mu.RLock()
+	until := frozenUntil(wh.Config.FreezeWindows, time.Now())
+	wh.mu.RUnlock()
+	if !until.IsZero() {
+		configFreezeHolds.Increment()
+		log.Warnf("Not applying the update of the injection ConfigMap %s/%s until the end of the freeze window at %v",
+			cm.Namespace, cm.Name, until)
+		// the reload applies the latest ConfigMap, so later updates are not lost
+		time.AfterFunc(time.Until(until), wh.Reload)
+		return
+	}
+	config, values := cm.Data[configMapConfigKey], cm.Data[configMapValuesKey]
+	if config == "" || values == "" {
+		log.Warnf("Ignoring the injection ConfigMap %s/%s without %s and %s", cm.Namespace, cm.Name,
+			configMapConfigKey, configMapValuesKey)
+		return
+	}
+	configFile, valuesFile := templateOverrideFiles(wh.templateOverrideDir, wh.configFile, wh.valuesFile)
+	if configFile != wh.configFile || valuesFile != wh.valuesFile {
+		log.Warnf("Ignoring the update of the injection ConfigMap %s/%s, the template override directory takes precedence",
+			cm.Namespace, cm.Name)
+		return
+	}
+	sidecarConfig, valuesConfig, err := parseConfig([]byte(config), []byte(values),
+		"ConfigMap "+cm.Namespace+"/"+cm.Name, wh.valuesKeys)
+	if err != nil {
+		configReloads.With(resultTag.Value(reloadFailure)).Increment()
+		log.Errorf("update error of the injection ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+		return
+	}
+	wh.activateConfig(sidecarConfig, valuesConfig)
+}
@@ -0,0 +1,117 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyConfigMap(t *testing.T) {
+	wh, cleanup := createWebhook(t, minimalSidecarTemplate)
+	defer cleanup()
+	initial := wh.sidecarTemplateVersion
+	values, err := ioutil.ReadFile(wh.valuesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := *minimalSidecarTemplate
+	updated.Template += "- name: istio-certs\n"
+	configBytes, err := yaml.Marshal(&updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMap := func(config string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector", Namespace: "istio-system"},
+			Data:       map[string]string{configMapConfigKey: config, configMapValuesKey: string(values)},
+		}
+	}
+
+	wh.applyConfigMap(configMap(string(configBytes)))
+	if wh.sidecarTemplateVersion == initial || wh.Config.Template != updated.Template {
+		t.Fatalf("template of the ConfigMap not swapped in: version %s", wh.sidecarTemplateVersion)
+	}
+
+	// invalid or incomplete ConfigMaps keep the current configuration
+	version := wh.sidecarTemplateVersion
+	for _, cm := range []*corev1.ConfigMap{configMap("portCollisionPolicy: unknown"), configMap("")} {
+		wh.applyConfigMap(cm)
+		if wh.sidecarTemplateVersion != version {
+			t.Fatalf("invalid ConfigMap %v swapped in", cm.Data)
+		}
+	}
+
+	// the mounted files are not reloaded over a synced ConfigMap
+	client := fake.NewSimpleClientset(configMap(string(configBytes)))
+	watcher := newConfigMapWatcher(client, ConfigMapWatchOptions{Name: "istio-sidecar-injector", Namespace: "istio-system"},
+		"injection", func(*corev1.ConfigMap) {})
+	stop := make(chan struct{})
+	defer close(stop)
+	watcher.run(stop)
+	if !watcher.synced() {
+		t.Fatal("ConfigMap not synced")
+	}
+	wh.configMaps = watcher
+	wh.reloadConfig()
+	if wh.sidecarTemplateVersion != version {
+		t.Fatalf("mounted files reloaded over the ConfigMap: version %s, want %s", wh.sidecarTemplateVersion, version)
+	}
+
+	// frozen configurations are kept
+	wh.mu.Lock()
+	wh.Config.FreezeWindows = []FreezeWindow{{Schedule: "* * * * *", Duration: "1h"}}
+	wh.mu.Unlock()
+	updated.Template += "- name: istio-envoy\n"
+	if configBytes, err = yaml.Marshal(&updated); err != nil {
+		t.Fatal(err)
+	}
+	wh.applyConfigMap(configMap(string(configBytes)))
+	if wh.sidecarTemplateVersion != version {
+		t.Fatalf("ConfigMap swapped in during a freeze window")
+	}
+}
+
+func TestConfigMapWatcher(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector", Namespace: "istio-system"},
+		Data:       map[string]string{configMapConfigKey: "policy: enabled"},
+	}
+	client := fake.NewSimpleClientset(cm)
+	applied := make(chan *corev1.ConfigMap, 1)
+	w := newConfigMapWatcher(client, ConfigMapWatchOptions{Name: cm.Name, Namespace: cm.Namespace}, "injection",
+		func(cm *corev1.ConfigMap) {
+			applied <- cm
+		})
+	stop := make(chan struct{})
+	defer close(stop)
+	go w.run(stop)
+
+	select {
+	case got := <-applied:
+		if got.Data[configMapConfigKey] != "policy: enabled" {
+			t.Fatalf("got ConfigMap %v", got.Data)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ConfigMap not applied")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"math/rand"
+	"runtime/pprof"
+
+	"istio.io/istio/pkg/kube"
+)
+
+// costSampler labels a fraction of the admissions with the namespace of the
+// pod and the template version injected, so the CPU profiles of the injector
+// attribute its consumption to tenants and costly pod specs can be found, e.g.
+// with go tool pprof -tagfocus namespace=foo. Unlike measuring each admission,
+// the labels neither stop the world nor pin the admission to a thread.
+type costSampler struct {
+	rate float64
+}
+
+// newCostSampler returns nil, labeling nothing, unless the rate is positive.
+func newCostSampler(rate float64) *costSampler {
+	if rate <= 0 {
+		return nil
+	}
+	return &costSampler{rate: rate}
+}
+
+func (s *costSampler) sampled() bool {
+	return s != nil && (s.rate >= 1 || rand.Float64() < s.rate)
+}
+
+// costLabels returns the profiler labels of an admission.
+func costLabels(namespace, template string) pprof.LabelSet {
+	return pprof.Labels("namespace", namespace, "template", template)
+}
+
+// injectWithCost injects, labeling the sampled admissions in the CPU profiles.
+func (wh *Webhook) injectWithCost(ctx context.Context, ar *kube.AdmissionReview, path string) *kube.AdmissionResponse {
+	if !wh.costs.sampled() || ar == nil || ar.Request == nil {
+		return wh.inject(ctx, ar, path)
+	}
+	wh.mu.RLock()
+	template := wh.sidecarTemplateVersion
+	wh.mu.RUnlock()
+	var resp *kube.AdmissionResponse
+	pprof.Do(ctx, costLabels(ar.Request.Namespace, template), func(ctx context.Context) {
+		resp = wh.inject(ctx, ar, path)
+	})
+	return resp
+}
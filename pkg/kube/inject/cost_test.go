@@ -0,0 +1,51 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestCostSampler(t *testing.T) {
+	cases := []struct {
+		name string
+		rate float64
+		want bool
+	}{
+		{"disabled", 0, false},
+		{"negative", -1, false},
+		{"all", 1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := newCostSampler(c.rate).sampled(); got != c.want {
+				t.Fatalf("sampled() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCostLabels(t *testing.T) {
+	pprof.Do(context.Background(), costLabels("foo", "abc"), func(ctx context.Context) {
+		if ns, _ := pprof.Label(ctx, "namespace"); ns != "foo" {
+			t.Errorf("got namespace label %q, want foo", ns)
+		}
+		if template, _ := pprof.Label(ctx, "template"); template != "abc" {
+			t.Errorf("got template label %q, want abc", template)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"istio.io/pkg/log"
+)
+
+// admissionContext returns the context of an admission request, ending
+// before the API server gives up on the webhook so there is time left to
+// respond. The API server passes its timeout as the timeout query parameter.
+func admissionContext(r *http.Request) (context.Context, context.CancelFunc) {
+	var timeout time.Duration
+	if r.URL != nil {
+		timeout, _ = time.ParseDuration(r.URL.Query().Get("timeout"))
+	}
+	if timeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), timeout-timeout/10)
+}
+
+// lookupAllowed returns false once the deadline of the request has passed,
+// so optional lookups are skipped rather than failing the admission.
+func lookupAllowed(ctx context.Context, lookup string) bool {
+	if ctx.Err() == nil {
+		return true
+	}
+	log.Warnf("Skipping the %s lookup, the admission deadline has passed", lookup)
+	skippedLookups.With(lookupTag.Value(lookup)).Increment()
+	return false
+}
@@ -0,0 +1,69 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdmissionContext(t *testing.T) {
+	cases := []struct {
+		name   string
+		url    string
+		within time.Duration
+	}{
+		{"api server timeout", "/inject?timeout=10s", 9 * time.Second},
+		{"no timeout", "/inject", 0},
+		{"invalid timeout", "/inject?timeout=soon", 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := admissionContext(httptest.NewRequest("POST", c.url, nil))
+			defer cancel()
+			deadline, ok := ctx.Deadline()
+			if c.within == 0 {
+				if ok {
+					t.Fatalf("unexpected deadline %v", deadline)
+				}
+				return
+			}
+			if !ok || time.Until(deadline) > c.within {
+				t.Fatalf("got deadline in %v, want within %v", time.Until(deadline), c.within)
+			}
+		})
+	}
+}
+
+func TestLookupsSkippedAfterDeadline(t *testing.T) {
+	wh := &Webhook{kubeClient: fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Labels: map[string]string{"istio-injection": "enabled"}},
+	})}
+	if ns := wh.getNamespace(context.Background(), "foo"); ns == nil {
+		t.Fatalf("namespace not found before the deadline")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ns := wh.getNamespace(ctx, "foo"); ns != nil {
+		t.Fatalf("namespace looked up after the deadline")
+	}
+}
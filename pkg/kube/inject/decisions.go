@@ -0,0 +1,116 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxDecisions is the number of recent admission decisions kept.
+	maxDecisions = 100
+
+	// maxDecisionReasonLength bounds the reasons recorded for failures,
+	// which may quote the injection template or values.
+	maxDecisionReasonLength = 256
+)
+
+// Outcomes of admission decisions.
+const (
+	DecisionInjected = "injected"
+	DecisionSkipped  = "skipped"
+	DecisionFailed   = "failed"
+)
+
+// Decision is the outcome of an admission request. Only the identity of the
+// workload is recorded, never the contents of the pod.
+type Decision struct {
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Workload  string    `json:"workload"`
+	// Owner is the name of the workload owning the pod, or of the pod itself.
+	Owner string `json:"owner,omitempty"`
+	// Template is the version hash of the injection template in use.
+	Template string `json:"template,omitempty"`
+	Outcome  string `json:"outcome"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// decisionLog keeps the most recent admission decisions.
+type decisionLog struct {
+	mu        sync.Mutex
+	decisions []Decision
+	next      int
+}
+
+func (l *decisionLog) record(d Decision) {
+	if l == nil {
+		return
+	}
+	d.Reason = decisionReason(d.Reason)
+	d.Time = time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.decisions) < maxDecisions {
+		l.decisions = append(l.decisions, d)
+		return
+	}
+	l.decisions[l.next] = d
+	l.next = (l.next + 1) % maxDecisions
+}
+
+// decisionReason returns the first line of the reason, truncated.
+func decisionReason(reason string) string {
+	if i := strings.IndexByte(reason, '\n'); i >= 0 {
+		reason = reason[:i]
+	}
+	if len(reason) > maxDecisionReasonLength {
+		reason = reason[:maxDecisionReasonLength] + "..."
+	}
+	return reason
+}
+
+// recent returns the recorded decisions, oldest first.
+func (l *decisionLog) recent() []Decision {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Decision, 0, len(l.decisions))
+	out = append(out, l.decisions[l.next:]...)
+	return append(out, l.decisions[:l.next]...)
+}
+
+// RecentDecisions returns the most recent admission decisions of the webhook, oldest first.
+func (wh *Webhook) RecentDecisions() []Decision {
+	return wh.decisions.recent()
+}
+
+// WorkloadDecisions returns the most recent admission decisions of the pods of
+// the namespace, oldest first. If owner is set, only the decisions of the pods
+// owned by that workload are returned.
+func (wh *Webhook) WorkloadDecisions(namespace, owner string) []Decision {
+	out := []Decision{}
+	for _, d := range wh.decisions.recent() {
+		if d.Namespace == namespace && (owner == "" || d.Owner == owner) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecisionLog(t *testing.T) {
+	var l decisionLog
+	for i := 0; i < maxDecisions+10; i++ {
+		l.record(Decision{Namespace: "foo", Workload: "app-" + strconv.Itoa(i), Outcome: DecisionInjected})
+	}
+	recent := l.recent()
+	if len(recent) != maxDecisions {
+		t.Fatalf("got %d decisions, want %d", len(recent), maxDecisions)
+	}
+	if recent[0].Workload != "app-10" || recent[maxDecisions-1].Workload != "app-109" {
+		t.Fatalf("got decisions from %s to %s, want app-10 to app-109", recent[0].Workload, recent[maxDecisions-1].Workload)
+	}
+
+	l.record(Decision{Namespace: "foo", Workload: "app", Outcome: DecisionFailed,
+		Reason: strings.Repeat("x", 2*maxDecisionReasonLength) + "\nsecret: value"})
+	last := l.recent()[maxDecisions-1]
+	if len(last.Reason) != maxDecisionReasonLength+3 || strings.Contains(last.Reason, "secret") {
+		t.Fatalf("reason not redacted: %q", last.Reason)
+	}
+}
+
+func TestWorkloadDecisions(t *testing.T) {
+	wh := &Webhook{decisions: &decisionLog{}}
+	wh.decisions.record(Decision{Namespace: "foo", Workload: "deployment/app", Owner: "app", Template: "v1", Outcome: DecisionInjected})
+	wh.decisions.record(Decision{Namespace: "foo", Workload: "deployment/other", Owner: "other", Outcome: DecisionInjected})
+	wh.decisions.record(Decision{Namespace: "bar", Workload: "deployment/app", Owner: "app", Outcome: DecisionInjected})
+	wh.decisions.record(Decision{Namespace: "foo", Workload: "deployment/app", Owner: "app", Template: "v2",
+		Outcome: DecisionSkipped, Reason: skipReasonPolicy})
+
+	got := wh.WorkloadDecisions("foo", "app")
+	if len(got) != 2 || got[0].Template != "v1" || got[1].Reason != skipReasonPolicy {
+		t.Fatalf("unexpected decisions %+v", got)
+	}
+	if got := wh.WorkloadDecisions("foo", ""); len(got) != 3 {
+		t.Fatalf("got %d decisions of the namespace, want 3", len(got))
+	}
+	if got := wh.WorkloadDecisions("baz", "app"); got == nil || len(got) != 0 {
+		t.Fatalf("unexpected decisions %+v", got)
+	}
+}
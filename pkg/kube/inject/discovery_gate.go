@@ -0,0 +1,73 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// defaultDiscoveryGateTimeout bounds the connection attempts of the discovery gate.
+const defaultDiscoveryGateTimeout = time.Second
+
+// DiscoveryGateOptions gates the readiness of the injector, and so the
+// traffic sent to the webhook, on the discovery address of the mesh config
+// being reachable from the injector. Pods injected while their proxies
+// cannot reach the control plane are stuck not ready.
+//
+// The gate is meant for injectors whose proxies connect to a remote control
+// plane. When the discovery address is served by the injector's own Service,
+// the injector can never become ready as the Service has no ready endpoints.
+type DiscoveryGateOptions struct {
+	// Enabled turns the gate on.
+	Enabled bool
+
+	// Timeout bounds each connection attempt. Defaults to one second.
+	Timeout time.Duration
+}
+
+// checkDiscoveryAddress returns an error if no connection to the address can
+// be opened within the timeout.
+func checkDiscoveryAddress(address string, timeout time.Duration) error {
+	if address == "" {
+		return errors.New("no discovery address is configured")
+	}
+	if timeout <= 0 {
+		timeout = defaultDiscoveryGateTimeout
+	}
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return fmt.Errorf("discovery address %s is not reachable: %v", address, err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// DiscoveryReady reports whether the discovery address proxies are injected
+// with is reachable. It is always ready when the discovery gate is disabled.
+func (wh *Webhook) DiscoveryReady() (bool, error) {
+	if !wh.discoveryGate.Enabled {
+		return true, nil
+	}
+	wh.mu.RLock()
+	address := wh.meshConfig.GetDefaultConfig().GetDiscoveryAddress()
+	wh.mu.RUnlock()
+	if err := checkDiscoveryAddress(address, wh.discoveryGate.Timeout); err != nil {
+		return false, err
+	}
+	return true, nil
+}
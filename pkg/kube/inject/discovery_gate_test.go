@@ -0,0 +1,61 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+)
+
+func TestDiscoveryReady(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddress := closed.Addr().String()
+	closed.Close()
+
+	cases := []struct {
+		name      string
+		gate      DiscoveryGateOptions
+		address   string
+		wantReady bool
+	}{
+		{"disabled", DiscoveryGateOptions{}, closedAddress, true},
+		{"reachable", DiscoveryGateOptions{Enabled: true}, listener.Addr().String(), true},
+		{"unreachable", DiscoveryGateOptions{Enabled: true, Timeout: 100 * time.Millisecond}, closedAddress, false},
+		{"no address", DiscoveryGateOptions{Enabled: true}, "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			wh := &Webhook{
+				discoveryGate: c.gate,
+				meshConfig:    &meshconfig.MeshConfig{DefaultConfig: &meshconfig.ProxyConfig{DiscoveryAddress: c.address}},
+			}
+			ready, err := wh.DiscoveryReady()
+			if ready != c.wantReady {
+				t.Fatalf("got ready %v (%v), want %v", ready, err, c.wantReady)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/pkg/log"
+)
+
+// dnsCaptureEnv enables the capture of the DNS traffic of the pod by the agent.
+const dnsCaptureEnv = "ISTIO_META_DNS_CAPTURE"
+
+func dnsCaptureEnabled(sidecar *corev1.Container) bool {
+	if sidecar == nil {
+		return false
+	}
+	for _, e := range sidecar.Env {
+		if e.Name == dnsCaptureEnv {
+			return e.Value != ""
+		}
+	}
+	return false
+}
+
+// validateDNSCapture returns an error if the DNS settings of the pod cannot
+// work with the DNS traffic captured by the agent. Only IPv4 traffic is
+// captured, and the agent forwards the queries it cannot answer to the
+// nameservers of the pod. Host network pods are never injected, so the
+// ClusterFirstWithHostNet policy behaves as ClusterFirst here.
+func validateDNSCapture(spec *corev1.PodSpec, sic *SidecarInjectionSpec) error {
+	if !dnsCaptureEnabled(FindSidecar(sic.Containers)) {
+		return nil
+	}
+	// the DNS config of the template replaces the one of the pod
+	dnsConfig := spec.DNSConfig
+	if sic.DNSConfig != nil {
+		dnsConfig = sic.DNSConfig
+	}
+	var nameservers []string
+	if dnsConfig != nil {
+		nameservers = dnsConfig.Nameservers
+	}
+	if spec.DNSPolicy == corev1.DNSNone && len(nameservers) == 0 {
+		return errors.New("DNS capture: dnsPolicy None requires nameservers for the agent to forward queries to")
+	}
+
+	ipv4, ipv6 := 0, 0
+	for _, ns := range nameservers {
+		ip := net.ParseIP(ns)
+		if ip == nil {
+			continue
+		}
+		if ip.IsLoopback() {
+			return fmt.Errorf("DNS capture: nameserver %s is a loopback address, "+
+				"queries forwarded by the agent would be captured again", ns)
+		}
+		if ip.To4() != nil {
+			ipv4++
+		} else {
+			ipv6++
+		}
+	}
+	if spec.DNSPolicy == corev1.DNSNone && ipv4 == 0 {
+		return errors.New("DNS capture: dnsPolicy None with only IPv6 nameservers, " +
+			"whose queries are not captured")
+	}
+	if ipv6 > 0 {
+		log.Warnf("DNS capture: queries to the IPv6 nameservers %v of the pod are not captured", nameservers)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateDNSCapture(t *testing.T) {
+	capturing := []corev1.Container{{
+		Name: ProxyContainerName,
+		Env:  []corev1.EnvVar{{Name: dnsCaptureEnv, Value: "true"}},
+	}}
+	nameservers := func(ns ...string) *corev1.PodDNSConfig {
+		return &corev1.PodDNSConfig{Nameservers: ns}
+	}
+	cases := []struct {
+		name       string
+		spec       corev1.PodSpec
+		containers []corev1.Container
+		templateNS *corev1.PodDNSConfig
+		wantErr    bool
+	}{
+		{
+			name:       "capture disabled",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: nameservers("127.0.0.1")},
+			containers: []corev1.Container{{Name: ProxyContainerName}},
+		},
+		{
+			name:       "cluster first",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst},
+			containers: capturing,
+		},
+		{
+			name:       "cluster first with host net",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirstWithHostNet},
+			containers: capturing,
+		},
+		{
+			name:       "custom nameservers",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: nameservers("10.0.0.10", "fd00::10")},
+			containers: capturing,
+		},
+		{
+			name:       "none without nameservers",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSNone},
+			containers: capturing,
+			wantErr:    true,
+		},
+		{
+			name:       "loopback nameserver",
+			spec:       corev1.PodSpec{DNSConfig: nameservers("127.0.0.53")},
+			containers: capturing,
+			wantErr:    true,
+		},
+		{
+			name:       "only ipv6 nameservers",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: nameservers("fd00::10")},
+			containers: capturing,
+			wantErr:    true,
+		},
+		{
+			name:       "template nameservers replace the pod ones",
+			spec:       corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: nameservers("127.0.0.1")},
+			containers: capturing,
+			templateNS: nameservers("10.0.0.10"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateDNSCapture(&c.spec, &SidecarInjectionSpec{Containers: c.containers, DNSConfig: c.templateNS})
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/api/annotation"
+	"istio.io/pkg/log"
+)
+
+// Drift classes of the injected containers and volumes of a pod.
+const (
+	DriftImage            = "image"
+	DriftResources        = "resources"
+	DriftMissingContainer = "missingContainer"
+	DriftMissingVolume    = "missingVolume"
+)
+
+var driftClasses = []string{DriftImage, DriftResources, DriftMissingContainer, DriftMissingVolume}
+
+// DriftVerifierOptions configures the background verification of the
+// running injected pods: their original specs are injected again with the
+// active configuration and the result compared with what they run, so pods
+// injected with an earlier configuration, or modified since, are found.
+// Requires Informers.
+type DriftVerifierOptions struct {
+	// Interval between two verifications. Zero disables the verifier.
+	Interval time.Duration
+
+	// SampleSize is the number of injected pods checked per verification,
+	// the next ones are checked by the next verification. Defaults to 100.
+	SampleSize int
+}
+
+const defaultDriftSampleSize = 100
+
+// PodDrift is the drift of a pod from the active configuration.
+type PodDrift struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Classes   []string `json:"classes"`
+	Details   []string `json:"details"`
+}
+
+// DriftReport is the result of a verification pass over all the injected
+// pods. Time is when the pass started.
+type DriftReport struct {
+	Time    time.Time  `json:"time"`
+	Checked int        `json:"checked"`
+	Drifted []PodDrift `json:"drifted,omitempty"`
+}
+
+type driftVerifier struct {
+	wh      *Webhook
+	options DriftVerifierOptions
+	pods    corelisters.PodLister
+	synced  cache.InformerSynced
+
+	mu sync.Mutex
+	// report is the last complete pass.
+	report DriftReport
+
+	// pass is the pass in progress, with its drifted pods by class, and next
+	// the key of the pod the next verification resumes after. Only accessed
+	// by verify.
+	pass   *DriftReport
+	counts map[string]int
+	next   string
+}
+
+func newDriftVerifier(wh *Webhook, o DriftVerifierOptions, pods coreinformers.PodInformer) *driftVerifier {
+	if o.Interval <= 0 {
+		return nil
+	}
+	if o.SampleSize <= 0 {
+		o.SampleSize = defaultDriftSampleSize
+	}
+	return &driftVerifier{wh: wh, options: o, pods: pods.Lister(), synced: pods.Informer().HasSynced}
+}
+
+func (v *driftVerifier) run(stop <-chan struct{}) {
+	t := time.NewTicker(v.options.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			ctx, cancel := context.WithTimeout(context.Background(), v.options.Interval)
+			if err := v.verify(ctx); err != nil {
+				log.Warnf("Failed to verify the drift of injected pods: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// verify checks the next sample of injected pods, in the order of their keys.
+// Once the pass reaches the last pod, its report and metrics are recorded and
+// the next verification starts another pass.
+func (v *driftVerifier) verify(ctx context.Context) error {
+	if !v.synced() {
+		return fmt.Errorf("pods not synced")
+	}
+	pods, err := v.pods.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return podKey(pods[i]) < podKey(pods[j])
+	})
+	var sample []*corev1.Pod
+	for _, pod := range pods {
+		if _, f := pod.Annotations[annotation.SidecarStatus.Name]; !f || pod.DeletionTimestamp != nil || podKey(pod) <= v.next {
+			continue
+		}
+		sample = append(sample, pod)
+		if len(sample) == v.options.SampleSize {
+			break
+		}
+	}
+
+	if v.pass == nil {
+		v.pass, v.counts = &DriftReport{Time: time.Now()}, map[string]int{}
+	}
+	for _, pod := range sample {
+		v.pass.Checked++
+		drift, err := v.wh.podDrift(ctx, pod)
+		if err != nil {
+			log.Debugf("Not verifying the drift of %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		if drift == nil {
+			continue
+		}
+		v.pass.Drifted = append(v.pass.Drifted, *drift)
+		for _, c := range drift.Classes {
+			v.counts[c]++
+		}
+	}
+	if len(sample) == v.options.SampleSize {
+		v.next = podKey(sample[len(sample)-1])
+		return nil
+	}
+
+	for _, c := range driftClasses {
+		driftedPods.With(driftTag.Value(c)).Record(float64(v.counts[c]))
+	}
+	driftVerifiedPods.Record(float64(v.pass.Checked))
+	v.mu.Lock()
+	v.report = *v.pass
+	v.mu.Unlock()
+	v.pass, v.counts, v.next = nil, nil, ""
+	return nil
+}
+
+func podKey(pod *corev1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// podDrift injects the original spec of the pod with the active configuration
+// and compares the injected containers and volumes with the ones of the pod,
+// with the resources defaulted as the API server defaults them. It returns nil
+// if they match.
+func (wh *Webhook) podDrift(ctx context.Context, pod *corev1.Pod) (*PodDrift, error) {
+	status, err := wh.statuses.resolve(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	original := uninjectedPod(pod, status)
+	deploy, typeMeta := wh.getDeployMeta(ctx, original)
+	nsAnnotations := wh.getNamespace(ctx, pod.Namespace).nsAnnotations()
+	wh.mu.RLock()
+	params := InjectionParameters{
+		pod:               original,
+		deployMeta:        deploy,
+		typeMeta:          typeMeta,
+		revision:          wh.revision,
+		namespaceValues:   nsAnnotations[ValuesAnnotation],
+		workloadValues:    wh.getWorkloadValues(ctx, original, deploy, typeMeta),
+		namespaceAppProxy: nsAnnotations[AppProxyAnnotation],
+		kubernetes:        wh.kubernetes,
+	}.withConfig(wh.Config, wh.valuesConfig, wh.sidecarTemplateVersion, wh.meshConfig)
+	limitRangeAware := wh.Config.LimitRangeAware
+	wh.mu.RUnlock()
+	var limitRanges []corev1.LimitRangeItem
+	if wh.limits != nil {
+		limitRanges = wh.limits.get(ctx, pod.Namespace)
+	}
+	if limitRangeAware {
+		params.limitRanges = limitRanges
+	}
+	spec, _, err := InjectionData(params, params.typeMeta, params.deployMeta)
+	if err != nil {
+		return nil, err
+	}
+	// the resources of the pod are defaulted once injected
+	defaultResources(spec.InitContainers, limitRanges)
+	defaultResources(spec.Containers, limitRanges)
+
+	drift := &PodDrift{Namespace: pod.Namespace, Name: pod.Name}
+	classes := map[string]bool{}
+	add := func(class, format string, args ...interface{}) {
+		classes[class] = true
+		drift.Details = append(drift.Details, fmt.Sprintf(format, args...))
+	}
+	compare := func(expected, actual []corev1.Container) {
+		for _, e := range expected {
+			a := findContainer(actual, e.Name)
+			if a == nil {
+				add(DriftMissingContainer, "container %s is missing", e.Name)
+				continue
+			}
+			if a.Image != e.Image {
+				add(DriftImage, "container %s runs %s instead of %s", e.Name, a.Image, e.Image)
+			}
+			if !equality.Semantic.DeepEqual(a.Resources, e.Resources) {
+				add(DriftResources, "container %s has resources %v instead of %v", e.Name, a.Resources, e.Resources)
+			}
+		}
+	}
+	initContainers, containers := spec.podContainers()
+	compare(initContainers, pod.Spec.InitContainers)
+	compare(containers, pod.Spec.Containers)
+	for _, e := range spec.Volumes {
+		found := false
+		for _, a := range pod.Spec.Volumes {
+			found = found || a.Name == e.Name
+		}
+		if !found {
+			add(DriftMissingVolume, "volume %s is missing", e.Name)
+		}
+	}
+	if len(classes) == 0 {
+		return nil, nil
+	}
+	for c := range classes {
+		drift.Classes = append(drift.Classes, c)
+	}
+	sort.Strings(drift.Classes)
+	return drift, nil
+}
+
+// uninjectedPod returns the pod without the containers, volumes and image pull
+// secrets recorded as injected by its status.
+func uninjectedPod(pod *corev1.Pod, status *SidecarInjectionStatus) *corev1.Pod {
+	out := pod.DeepCopy()
+	injected := map[string]bool{}
+	for _, names := range [][]string{status.InitContainers, status.Containers, status.Volumes, status.ImagePullSecrets} {
+		for _, n := range names {
+			injected[n] = true
+		}
+	}
+	keepContainers := func(cs []corev1.Container) []corev1.Container {
+		var kept []corev1.Container
+		for _, c := range cs {
+			if !injected[c.Name] {
+				kept = append(kept, c)
+			}
+		}
+		return kept
+	}
+	out.Spec.InitContainers = keepContainers(out.Spec.InitContainers)
+	out.Spec.Containers = keepContainers(out.Spec.Containers)
+	var volumes []corev1.Volume
+	for _, v := range out.Spec.Volumes {
+		if !injected[v.Name] {
+			volumes = append(volumes, v)
+		}
+	}
+	out.Spec.Volumes = volumes
+	var secrets []corev1.LocalObjectReference
+	for _, s := range out.Spec.ImagePullSecrets {
+		if !injected[s.Name] {
+			secrets = append(secrets, s)
+		}
+	}
+	out.Spec.ImagePullSecrets = secrets
+	delete(out.Annotations, annotation.SidecarStatus.Name)
+	return out
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+func (v *driftVerifier) serveReport(w http.ResponseWriter, _ *http.Request) {
+	v.mu.Lock()
+	out, err := json.MarshalIndent(v.report, "", "  ")
+	v.mu.Unlock()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to write the drift report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(out)
+}
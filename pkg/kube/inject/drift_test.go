@@ -0,0 +1,170 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/api/annotation"
+)
+
+func TestPodDrift(t *testing.T) {
+	wh, cleanup := createWebhook(t, &Config{
+		Policy: InjectionPolicyEnabled,
+		Template: `
+containers:
+- name: istio-proxy
+  image: proxy:1.8
+  resources:
+    requests:
+      cpu: 100m
+volumes:
+- name: istio-envoy
+  emptyDir: {}
+`,
+	})
+	defer cleanup()
+
+	status, err := json.Marshal(SidecarInjectionStatus{Containers: []string{"istio-proxy"}, Volumes: []string{"istio-envoy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	injected := func(mutate func(*corev1.Pod)) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default",
+				Annotations: map[string]string{annotation.SidecarStatus.Name: string(status)}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app"},
+					{Name: "istio-proxy", Image: "proxy:1.8", Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+					}},
+				},
+				Volumes: []corev1.Volume{{Name: "istio-envoy", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+
+	cases := []struct {
+		name string
+		pod  *corev1.Pod
+		want []string
+	}{
+		{"in sync", injected(nil), nil},
+		{"image", injected(func(p *corev1.Pod) { p.Spec.Containers[1].Image = "proxy:1.7" }), []string{DriftImage}},
+		{"resources", injected(func(p *corev1.Pod) { p.Spec.Containers[1].Resources = corev1.ResourceRequirements{} }),
+			[]string{DriftResources}},
+		{"missing volume", injected(func(p *corev1.Pod) { p.Spec.Volumes = nil }), []string{DriftMissingVolume}},
+		{"missing container", injected(func(p *corev1.Pod) { p.Spec.Containers = p.Spec.Containers[:1] }),
+			[]string{DriftMissingContainer}},
+	}
+	// the limits defaulted by a LimitRange of the namespace are not a drift
+	wh.limits = newLimitRangeCache(fake.NewSimpleClientset(&corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "defaulted"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:    corev1.LimitTypeContainer,
+			Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}}},
+	}))
+	cases = append(cases, []struct {
+		name string
+		pod  *corev1.Pod
+		want []string
+	}{
+		{"limit range defaults", injected(func(p *corev1.Pod) {
+			p.Namespace = "defaulted"
+			p.Spec.Containers[1].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+		}), nil},
+		{"limit range not defaulted", injected(func(p *corev1.Pod) { p.Namespace = "defaulted" }), []string{DriftResources}},
+	}...)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			drift, err := wh.podDrift(context.Background(), c.pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			if drift != nil {
+				got = drift.Classes
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got drift %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDriftVerifierPass(t *testing.T) {
+	wh, cleanup := createWebhook(t, &Config{
+		Policy: InjectionPolicyEnabled,
+		Template: `
+containers:
+- name: istio-proxy
+  image: proxy:1.8
+`,
+	})
+	defer cleanup()
+
+	status, err := json.Marshal(SidecarInjectionStatus{Containers: []string{"istio-proxy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := func(name, image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default",
+				Annotations: map[string]string{annotation.SidecarStatus.Name: string(status)}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}, {Name: "istio-proxy", Image: image}}},
+		}
+	}
+	uninjected := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}}
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(
+		pod("a", "proxy:1.7"), uninjected, pod("c", "proxy:1.8"), pod("d", "proxy:1.7")), 0)
+	v := newDriftVerifier(wh, DriftVerifierOptions{Interval: time.Minute, SampleSize: 2}, factory.Core().V1().Pods())
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	cache.WaitForCacheSync(stop, v.synced)
+
+	if err := v.verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if v.report.Checked != 0 {
+		t.Fatalf("expected no report before the end of the pass, got %+v", v.report)
+	}
+	if err := v.verify(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if v.report.Checked != 3 || len(v.report.Drifted) != 2 {
+		t.Fatalf("expected a report of the 3 injected pods with 2 drifted, got %+v", v.report)
+	}
+	if v.pass != nil || v.next != "" {
+		t.Fatalf("expected the next verification to start another pass")
+	}
+}
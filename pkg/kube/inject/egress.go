@@ -0,0 +1,107 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EgressGatewaysEnv is the proxy metadata listing the egress gateway of
+	// each zone, as comma separated zone=gateway pairs.
+	EgressGatewaysEnv = "ISTIO_META_EGRESS_GATEWAYS"
+
+	// EgressGatewayEnv is the proxy metadata holding the egress gateway
+	// preferred for the zone the pod is pinned to, or the default gateway.
+	EgressGatewayEnv = "ISTIO_META_EGRESS_GATEWAY"
+
+	// defaultEgressZone is the EgressGateways key of the gateway used by pods
+	// in other zones, or not pinned to a zone.
+	defaultEgressZone = "*"
+)
+
+func validateEgressGateways(gateways map[string]string) error {
+	for zone, gateway := range gateways {
+		if zone == "" || strings.ContainsAny(zone, ",=") {
+			return fmt.Errorf("invalid egress gateway zone %q", zone)
+		}
+		if gateway == "" || strings.ContainsAny(gateway, ",=") {
+			return fmt.Errorf("invalid egress gateway %q for zone %s", gateway, zone)
+		}
+	}
+	return nil
+}
+
+// podZone returns the zone the pod is pinned to by its node selector or
+// required node affinity, or an empty string. Pods are not scheduled yet
+// when injected, so the zone of pods free to run anywhere is unknown.
+func podZone(spec *corev1.PodSpec) string {
+	for _, label := range []string{corev1.LabelZoneFailureDomainStable, corev1.LabelZoneFailureDomain} {
+		if zone := spec.NodeSelector[label]; zone != "" {
+			return zone
+		}
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil ||
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	// terms are OR'ed, so all of them have to pin the same zone
+	zone := ""
+	for _, term := range spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		termZone := ""
+		for _, e := range term.MatchExpressions {
+			if (e.Key == corev1.LabelZoneFailureDomainStable || e.Key == corev1.LabelZoneFailureDomain) &&
+				e.Operator == corev1.NodeSelectorOpIn && len(e.Values) == 1 {
+				termZone = e.Values[0]
+			}
+		}
+		if termZone == "" || (zone != "" && zone != termZone) {
+			return ""
+		}
+		zone = termZone
+	}
+	return zone
+}
+
+// applyEgressGateways renders the egress gateways into the metadata of the
+// proxy, so routing can match the gateway of the zone of the workload.
+func applyEgressGateways(sidecar *corev1.Container, gateways map[string]string, zone string) {
+	if sidecar == nil || len(gateways) == 0 {
+		return
+	}
+	zones := make([]string, 0, len(gateways))
+	for z := range gateways {
+		zones = append(zones, z)
+	}
+	sort.Strings(zones)
+	pairs := make([]string, 0, len(zones))
+	for _, z := range zones {
+		pairs = append(pairs, z+"="+gateways[z])
+	}
+	envs := map[string]string{EgressGatewaysEnv: strings.Join(pairs, ",")}
+
+	preferred, f := gateways[zone]
+	if zone == "" || !f {
+		preferred = gateways[defaultEgressZone]
+	}
+	if preferred != "" {
+		envs[EgressGatewayEnv] = preferred
+	}
+	updateClusterEnvs(sidecar, envs)
+}
@@ -0,0 +1,133 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodZone(t *testing.T) {
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+	zoneTerm := func(values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: corev1.LabelZoneFailureDomainStable, Operator: corev1.NodeSelectorOpIn, Values: values},
+		}}
+	}
+	cases := []struct {
+		name string
+		spec corev1.PodSpec
+		want string
+	}{
+		{"unpinned", corev1.PodSpec{}, ""},
+		{"node selector", corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelZoneFailureDomainStable: "us-east-1a"}}, "us-east-1a"},
+		{"beta node selector", corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelZoneFailureDomain: "us-east-1b"}}, "us-east-1b"},
+		{"affinity", corev1.PodSpec{Affinity: affinity(zoneTerm("us-east-1a"))}, "us-east-1a"},
+		{"affinity to several zones", corev1.PodSpec{Affinity: affinity(zoneTerm("us-east-1a", "us-east-1b"))}, ""},
+		{"terms pinning different zones", corev1.PodSpec{Affinity: affinity(zoneTerm("us-east-1a"), zoneTerm("us-east-1b"))}, ""},
+		{"terms pinning the same zone", corev1.PodSpec{Affinity: affinity(zoneTerm("us-east-1a"), zoneTerm("us-east-1a"))}, "us-east-1a"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := podZone(&c.spec); got != c.want {
+				t.Fatalf("got zone %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestApplyEgressGateways(t *testing.T) {
+	gateways := map[string]string{
+		"us-east-1a":      "egress-a.istio-system.svc.cluster.local",
+		"us-east-1b":      "egress-b.istio-system.svc.cluster.local",
+		defaultEgressZone: "egress.istio-system.svc.cluster.local",
+	}
+	cases := []struct {
+		name          string
+		gateways      map[string]string
+		zone          string
+		wantGateways  string
+		wantPreferred string
+	}{
+		{
+			name:          "zone gateway",
+			gateways:      gateways,
+			zone:          "us-east-1b",
+			wantGateways:  "*=egress.istio-system.svc.cluster.local,us-east-1a=egress-a.istio-system.svc.cluster.local,us-east-1b=egress-b.istio-system.svc.cluster.local",
+			wantPreferred: "egress-b.istio-system.svc.cluster.local",
+		},
+		{
+			name:          "unknown zone",
+			gateways:      gateways,
+			zone:          "",
+			wantGateways:  "*=egress.istio-system.svc.cluster.local,us-east-1a=egress-a.istio-system.svc.cluster.local,us-east-1b=egress-b.istio-system.svc.cluster.local",
+			wantPreferred: "egress.istio-system.svc.cluster.local",
+		},
+		{
+			name:         "no default gateway",
+			gateways:     map[string]string{"us-east-1a": "egress-a"},
+			zone:         "us-west-2a",
+			wantGateways: "us-east-1a=egress-a",
+		},
+		{
+			name: "no gateways",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sidecar := &corev1.Container{Name: ProxyContainerName}
+			applyEgressGateways(sidecar, c.gateways, c.zone)
+			envs := map[string]string{}
+			for _, e := range sidecar.Env {
+				envs[e.Name] = e.Value
+			}
+			if envs[EgressGatewaysEnv] != c.wantGateways {
+				t.Fatalf("got gateways %q, want %q", envs[EgressGatewaysEnv], c.wantGateways)
+			}
+			if envs[EgressGatewayEnv] != c.wantPreferred {
+				t.Fatalf("got preferred gateway %q, want %q", envs[EgressGatewayEnv], c.wantPreferred)
+			}
+			if !sort.SliceIsSorted(sidecar.Env, func(i, j int) bool { return sidecar.Env[i].Name < sidecar.Env[j].Name }) {
+				t.Fatalf("got envs %v, want them in the order of their names", sidecar.Env)
+			}
+		})
+	}
+}
+
+func TestValidateEgressGateways(t *testing.T) {
+	cases := []struct {
+		name     string
+		gateways map[string]string
+		wantErr  bool
+	}{
+		{"valid", map[string]string{"us-east-1a": "egress-a", "*": "egress"}, false},
+		{"empty gateway", map[string]string{"us-east-1a": ""}, true},
+		{"separator in zone", map[string]string{"us-east-1a,b": "egress"}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateEgressGateways(c.gateways)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
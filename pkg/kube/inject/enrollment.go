@@ -0,0 +1,345 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+	"istio.io/pkg/log"
+)
+
+const (
+	// EnrollmentAnnotation summarizes on a Deployment or StatefulSet the
+	// injection state of its pods, as a JSON encoded Enrollment.
+	EnrollmentAnnotation = "sidecar.istio.io/enrollment"
+
+	// EnrollmentInjected is the state of a workload whose pods are all injected.
+	EnrollmentInjected = "Injected"
+	// EnrollmentPartial is the state of a workload with only some pods injected.
+	EnrollmentPartial = "Partial"
+	// EnrollmentSkipped is the state of a workload whose pods are not injected.
+	EnrollmentSkipped = "Skipped"
+
+	skipReasonUnknown = "unknown"
+
+	// injectionLabel enables the injection of the pods of a namespace by the
+	// default revision.
+	injectionLabel = "istio-injection"
+
+	workloadIndex   = "workload"
+	enrollmentRetry = 5
+)
+
+// Enrollment is the injection state of the pods of a workload.
+type Enrollment struct {
+	State    string `json:"state"`
+	Pods     int    `json:"pods"`
+	Injected int    `json:"injected"`
+
+	// Skipped counts the pods not injected by the reason recorded in their
+	// SkipReasonAnnotation.
+	Skipped map[string]int `json:"skipped,omitempty"`
+}
+
+// EnrollmentController maintains the EnrollmentAnnotation of the
+// Deployments and StatefulSets whose pods are watched, in the namespaces with
+// injection enabled, so a single field tells whether a workload is enrolled
+// in the mesh.
+type EnrollmentController struct {
+	client     kubernetes.Interface
+	pods       cache.SharedIndexInformer
+	namespaces corelisters.NamespaceLister
+	nsSynced   cache.InformerSynced
+	queue      workqueue.RateLimitingInterface
+
+	// written is the annotation last written, or read, per workload. Only
+	// accessed from the worker.
+	written map[string]string
+}
+
+// NewEnrollmentController creates a controller for the workloads of the pods
+// of the informer. The informers are shared, and started by their factory;
+// the pod informer must not be started yet as the controller indexes it.
+func NewEnrollmentController(client kubernetes.Interface, pods coreinformers.PodInformer,
+	namespaces coreinformers.NamespaceInformer) *EnrollmentController {
+	c := &EnrollmentController{
+		client:     client,
+		pods:       pods.Informer(),
+		namespaces: namespaces.Lister(),
+		nsSynced:   namespaces.Informer().HasSynced,
+		queue:      workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		written:    map[string]string{},
+	}
+	if err := c.pods.AddIndexers(cache.Indexers{workloadIndex: func(obj interface{}) ([]string, error) {
+		if key := workloadKey(obj.(*corev1.Pod)); key != "" {
+			return []string{key}, nil
+		}
+		return nil, nil
+	}}); err != nil {
+		log.Errorf("Failed to index pods by workload: %v", err)
+	}
+	c.pods.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+		DeleteFunc: c.enqueue,
+	})
+	// the workloads of a namespace whose injection is enabled are enrolled
+	namespaces.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueNamespace,
+		UpdateFunc: func(old, cur interface{}) {
+			if injectionEnabled(old.(*corev1.Namespace)) != injectionEnabled(cur.(*corev1.Namespace)) {
+				c.enqueueNamespace(cur)
+			}
+		},
+	})
+	return c
+}
+
+// Run processes the workloads until the stop channel is closed.
+func (c *EnrollmentController) Run(stop <-chan struct{}) {
+	defer c.queue.ShutDown()
+	if !cache.WaitForCacheSync(stop, c.pods.HasSynced, c.nsSynced) {
+		log.Errorf("Failed to sync pods of the enrollment controller")
+		return
+	}
+	// the informer may have synced before the leadership was acquired
+	for _, obj := range c.pods.GetStore().List() {
+		c.enqueue(obj)
+	}
+	go func() {
+		for c.processNext() {
+		}
+	}()
+	<-stop
+}
+
+func (c *EnrollmentController) enqueue(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if pod, ok := obj.(*corev1.Pod); ok {
+		if key := workloadKey(pod); key != "" {
+			c.queue.Add(key)
+		}
+	}
+}
+
+func (c *EnrollmentController) enqueueNamespace(obj interface{}) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok || !injectionEnabled(ns) {
+		return
+	}
+	pods, err := c.pods.GetIndexer().ByIndex(cache.NamespaceIndex, ns.Name)
+	if err != nil {
+		log.Warnf("Failed to list the pods of namespace %s for their enrollment: %v", ns.Name, err)
+		return
+	}
+	for _, pod := range pods {
+		c.enqueue(pod)
+	}
+}
+
+func (c *EnrollmentController) processNext() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.reconcile(key.(string)); err != nil {
+		if c.queue.NumRequeues(key) < enrollmentRetry {
+			c.queue.AddRateLimited(key)
+			return true
+		}
+		log.Errorf("Failed to update the enrollment of %s: %v", key, err)
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *EnrollmentController) reconcile(key string) error {
+	kind, namespace, name := splitWorkloadKey(key)
+	ns, err := c.namespaces.Get(namespace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !injectionEnabled(ns) {
+		return nil
+	}
+	objs, err := c.pods.GetIndexer().ByIndex(workloadIndex, key)
+	if err != nil {
+		return err
+	}
+	var pods []*corev1.Pod
+	for _, obj := range objs {
+		pods = append(pods, obj.(*corev1.Pod))
+	}
+	enrollment := enrollmentOf(pods)
+	if enrollment.Pods == 0 {
+		// keep the last state of workloads scaled down or deleted
+		delete(c.written, key)
+		return nil
+	}
+	value, err := json.Marshal(enrollment)
+	if err != nil {
+		return err
+	}
+	if _, f := c.written[key]; !f {
+		// e.g. after a failover, the annotation was written by the previous leader
+		live, err := c.liveEnrollment(kind, namespace, name)
+		if apierrors.IsNotFound(err) {
+			log.Debugf("Skipping enrollment of %s: %v", key, err)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		c.written[key] = live
+	}
+	if c.written[key] == string(value) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{EnrollmentAnnotation: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case "Deployment":
+		_, err = c.client.AppsV1().Deployments(namespace).Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = c.client.AppsV1().StatefulSets(namespace).Patch(context.TODO(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		// e.g. a ReplicaSet not owned by a Deployment
+		log.Debugf("Skipping enrollment of %s: %v", key, err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	c.written[key] = string(value)
+	return nil
+}
+
+// liveEnrollment returns the EnrollmentAnnotation of the workload.
+func (c *EnrollmentController) liveEnrollment(kind, namespace, name string) (string, error) {
+	var meta *metav1.ObjectMeta
+	switch kind {
+	case "Deployment":
+		d, err := c.client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		meta = &d.ObjectMeta
+	case "StatefulSet":
+		s, err := c.client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		meta = &s.ObjectMeta
+	default:
+		return "", fmt.Errorf("unknown workload kind %s", kind)
+	}
+	return meta.Annotations[EnrollmentAnnotation], nil
+}
+
+// injectionEnabled returns true if the pods of the namespace are selected for
+// injection, by the default revision or another one.
+func injectionEnabled(ns *corev1.Namespace) bool {
+	if _, f := ns.Labels[label.IstioRev]; f {
+		return true
+	}
+	return ns.Labels[injectionLabel] == "enabled"
+}
+
+// enrollmentOf summarizes the injection state of the pods. Terminated and
+// terminating pods are not counted.
+func enrollmentOf(pods []*corev1.Pod) Enrollment {
+	e := Enrollment{}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		e.Pods++
+		if _, f := pod.Annotations[annotation.SidecarStatus.Name]; f {
+			e.Injected++
+			continue
+		}
+		reason := pod.Annotations[SkipReasonAnnotation]
+		if reason == "" {
+			reason = skipReasonUnknown
+		}
+		if e.Skipped == nil {
+			e.Skipped = map[string]int{}
+		}
+		e.Skipped[reason]++
+	}
+	switch {
+	case e.Injected == e.Pods:
+		e.State = EnrollmentInjected
+	case e.Injected == 0:
+		e.State = EnrollmentSkipped
+	default:
+		e.State = EnrollmentPartial
+	}
+	return e
+}
+
+// workloadKey returns the Deployment or StatefulSet owning the pod as
+// kind/namespace/name, or an empty string for other pods. The Deployment of
+// a ReplicaSet is derived from the pod-template-hash suffix the Deployment
+// controller names its ReplicaSets with.
+func workloadKey(pod *corev1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return ""
+	}
+	switch ref.Kind {
+	case "StatefulSet":
+		return fmt.Sprintf("StatefulSet/%s/%s", pod.Namespace, ref.Name)
+	case "ReplicaSet":
+		hash := pod.Labels["pod-template-hash"]
+		if hash == "" || !strings.HasSuffix(ref.Name, "-"+hash) {
+			return ""
+		}
+		return fmt.Sprintf("Deployment/%s/%s", pod.Namespace, strings.TrimSuffix(ref.Name, "-"+hash))
+	}
+	return ""
+}
+
+func splitWorkloadKey(key string) (kind, namespace, name string) {
+	parts := strings.SplitN(key, "/", 3)
+	return parts[0], parts[1], parts[2]
+}
@@ -0,0 +1,214 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/api/annotation"
+	"istio.io/api/label"
+)
+
+func enrollmentPod(name, kind, owner, hash string, annotations map[string]string) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   "foo",
+		Labels:      map[string]string{"pod-template-hash": hash},
+		Annotations: annotations,
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: kind, Name: owner, Controller: &controller},
+		},
+	}}
+}
+
+func TestWorkloadKey(t *testing.T) {
+	cases := []struct {
+		name string
+		pod  *corev1.Pod
+		want string
+	}{
+		{"deployment", enrollmentPod("p", "ReplicaSet", "frontend-5d4f8", "5d4f8", nil), "Deployment/foo/frontend"},
+		{"standalone replicaset", enrollmentPod("p", "ReplicaSet", "frontend", "", nil), ""},
+		{"statefulset", enrollmentPod("p", "StatefulSet", "db", "", nil), "StatefulSet/foo/db"},
+		{"job", enrollmentPod("p", "Job", "migrate", "", nil), ""},
+		{"no controller", &corev1.Pod{}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := workloadKey(c.pod); got != c.want {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestEnrollmentOf(t *testing.T) {
+	injected := map[string]string{annotation.SidecarStatus.Name: "{}"}
+	ambient := map[string]string{SkipReasonAnnotation: skipReasonAmbient}
+	terminated := enrollmentPod("done", "StatefulSet", "db", "", nil)
+	terminated.Status.Phase = corev1.PodSucceeded
+
+	cases := []struct {
+		name string
+		pods []*corev1.Pod
+		want Enrollment
+	}{
+		{
+			name: "all injected",
+			pods: []*corev1.Pod{
+				enrollmentPod("a", "StatefulSet", "db", "", injected),
+				enrollmentPod("b", "StatefulSet", "db", "", injected),
+				terminated,
+			},
+			want: Enrollment{State: EnrollmentInjected, Pods: 2, Injected: 2},
+		},
+		{
+			name: "partial",
+			pods: []*corev1.Pod{
+				enrollmentPod("a", "StatefulSet", "db", "", injected),
+				enrollmentPod("b", "StatefulSet", "db", "", nil),
+			},
+			want: Enrollment{State: EnrollmentPartial, Pods: 2, Injected: 1, Skipped: map[string]int{skipReasonUnknown: 1}},
+		},
+		{
+			name: "skipped",
+			pods: []*corev1.Pod{
+				enrollmentPod("a", "StatefulSet", "db", "", ambient),
+				enrollmentPod("b", "StatefulSet", "db", "", ambient),
+			},
+			want: Enrollment{State: EnrollmentSkipped, Pods: 2, Skipped: map[string]int{skipReasonAmbient: 2}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := enrollmentOf(c.pods); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestInjectionEnabled(t *testing.T) {
+	cases := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{nil, false},
+		{map[string]string{injectionLabel: "enabled"}, true},
+		{map[string]string{injectionLabel: "disabled"}, false},
+		{map[string]string{label.IstioRev: "canary"}, true},
+	}
+	for _, c := range cases {
+		if got := injectionEnabled(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: c.labels}}); got != c.want {
+			t.Fatalf("namespace labeled %v: got %v, want %v", c.labels, got, c.want)
+		}
+	}
+}
+
+// newTestEnrollmentController returns a controller with the pods and
+// namespaces added to its informers, which are not started.
+func newTestEnrollmentController(t *testing.T, client *fake.Clientset, objs ...metav1.Object) *EnrollmentController {
+	factory := informers.NewSharedInformerFactory(client, 0)
+	c := NewEnrollmentController(client, factory.Core().V1().Pods(), factory.Core().V1().Namespaces())
+	for _, obj := range objs {
+		store := factory.Core().V1().Pods().Informer().GetIndexer()
+		if _, ok := obj.(*corev1.Namespace); ok {
+			store = factory.Core().V1().Namespaces().Informer().GetIndexer()
+		}
+		if err := store.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestEnrollmentControllerReconcile(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "foo"},
+	})
+	objs := []metav1.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo", Labels: map[string]string{injectionLabel: "enabled"}}},
+		enrollmentPod("a", "ReplicaSet", "frontend-5d4f8", "5d4f8", map[string]string{annotation.SidecarStatus.Name: "{}"}),
+		enrollmentPod("b", "ReplicaSet", "frontend-7c9b2", "7c9b2", nil),
+		enrollmentPod("c", "StatefulSet", "missing", "", nil),
+	}
+	c := newTestEnrollmentController(t, client, objs...)
+
+	if err := c.reconcile("Deployment/foo/frontend"); err != nil {
+		t.Fatal(err)
+	}
+	deployment, err := client.AppsV1().Deployments("foo").Get(context.TODO(), "frontend", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Enrollment
+	if err := json.Unmarshal([]byte(deployment.Annotations[EnrollmentAnnotation]), &got); err != nil {
+		t.Fatalf("invalid enrollment annotation %q: %v", deployment.Annotations[EnrollmentAnnotation], err)
+	}
+	if got.State != EnrollmentPartial || got.Pods != 2 || got.Injected != 1 {
+		t.Fatalf("unexpected enrollment %+v", got)
+	}
+
+	// unchanged state is not patched again
+	patches := len(client.Actions())
+	if err := c.reconcile("Deployment/foo/frontend"); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Actions()) != patches {
+		t.Fatalf("unchanged enrollment patched again")
+	}
+
+	// workloads which do not exist are skipped
+	if err := c.reconcile("StatefulSet/foo/missing"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new leader reads the annotation written by the previous one
+	c = newTestEnrollmentController(t, client, objs...)
+	patches = len(client.Actions())
+	if err := c.reconcile("Deployment/foo/frontend"); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range client.Actions()[patches:] {
+		if action.GetVerb() == "patch" {
+			t.Fatalf("enrollment patched again after a failover")
+		}
+	}
+}
+
+func TestEnrollmentControllerNamespaceNotInjected(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "foo"},
+	})
+	c := newTestEnrollmentController(t, client,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+		enrollmentPod("a", "ReplicaSet", "coredns-5d4f8", "5d4f8", nil))
+	if err := c.reconcile("Deployment/foo/coredns"); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Fatalf("workload of a namespace without injection enrolled: %v", client.Actions())
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/pkg/log"
+)
+
+// InjectionEvent is an admission decision, passed to the handlers registered
+// with OnInjected and OnSkipped by the programs embedding the webhook. Fields
+// are only ever added to it.
+type InjectionEvent struct {
+	// Pod is the metadata of the admitted pod, before injection.
+	Pod metav1.ObjectMeta
+	// OwnerKind and OwnerName identify the workload owning the pod, or the pod itself.
+	OwnerKind string
+	OwnerName string
+	// Decision is DecisionInjected or DecisionSkipped.
+	Decision string
+	// Reason the pod was skipped, empty when injected.
+	Reason string
+	// Template is the version hash of the injection template in use.
+	Template string
+	// Patch summarizes the JSON patch returned to the API server.
+	Patch PatchSummary
+}
+
+// PatchSummary describes a JSON patch without its values.
+type PatchSummary struct {
+	// Size of the patch in bytes.
+	Size int
+	// Paths are the JSON pointers of the operations, in order.
+	Paths []string
+}
+
+// InjectionEventHandler handles an admission decision. It is called
+// synchronously with the context of the admission request, so it must return
+// quickly; a panic is logged and does not fail the admission.
+type InjectionEventHandler func(ctx context.Context, event InjectionEvent)
+
+// injectionEvents holds the registered handlers.
+type injectionEvents struct {
+	mu       sync.RWMutex
+	injected []InjectionEventHandler
+	skipped  []InjectionEventHandler
+}
+
+// OnInjected registers a handler called after each pod injected.
+func (wh *Webhook) OnInjected(h InjectionEventHandler) {
+	wh.events.mu.Lock()
+	defer wh.events.mu.Unlock()
+	wh.events.injected = append(wh.events.injected, h)
+}
+
+// OnSkipped registers a handler called after each pod not injected, along
+// with the reason.
+func (wh *Webhook) OnSkipped(h InjectionEventHandler) {
+	wh.events.mu.Lock()
+	defer wh.events.mu.Unlock()
+	wh.events.skipped = append(wh.events.skipped, h)
+}
+
+// handlers returns the handlers of the decision.
+func (e *injectionEvents) handlers(decision string) []InjectionEventHandler {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	switch decision {
+	case DecisionInjected:
+		return e.injected
+	case DecisionSkipped:
+		return e.skipped
+	}
+	return nil
+}
+
+// dispatch calls the handlers of the decision of the event. The pod metadata
+// and the patch summary are only built when handlers are registered.
+func (e *injectionEvents) dispatch(ctx context.Context, decision string, build func() InjectionEvent) {
+	handlers := e.handlers(decision)
+	if len(handlers) == 0 {
+		return
+	}
+	event := build()
+	for _, h := range handlers {
+		callInjectionEventHandler(ctx, h, event)
+	}
+}
+
+func callInjectionEventHandler(ctx context.Context, h InjectionEventHandler, event InjectionEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Injection event handler of %s/%s panicked: %v", event.Pod.Namespace, event.OwnerName, r)
+		}
+	}()
+	h(ctx, event)
+}
+
+// summarizePatch returns the size and the paths of the JSON patch.
+func summarizePatch(patch []byte) PatchSummary {
+	summary := PatchSummary{Size: len(patch)}
+	var ops []struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return summary
+	}
+	for _, op := range ops {
+		summary.Paths = append(summary.Paths, op.Path)
+	}
+	return summary
+}
@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pkg/kube"
+)
+
+func TestInjectionEvents(t *testing.T) {
+	wh, cleanup := createWebhook(t, minimalSidecarTemplate)
+	defer cleanup()
+	var injected, skipped []InjectionEvent
+	wh.OnInjected(func(_ context.Context, e InjectionEvent) {
+		injected = append(injected, e)
+	})
+	wh.OnSkipped(func(_ context.Context, e InjectionEvent) {
+		skipped = append(skipped, e)
+	})
+	wh.OnSkipped(func(context.Context, InjectionEvent) {
+		panic("handlers must not fail the admission")
+	})
+
+	admit := func(annotations map[string]string) *kube.AdmissionResponse {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+		}
+		raw, err := json.Marshal(pod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wh.inject(context.Background(), &kube.AdmissionReview{
+			Request: &kube.AdmissionRequest{Namespace: "default", Object: runtime.RawExtension{Raw: raw}},
+		}, "")
+	}
+
+	t.Run("injected", func(t *testing.T) {
+		resp := admit(nil)
+		if len(injected) != 1 {
+			t.Fatalf("got %d injected events, want 1", len(injected))
+		}
+		e := injected[0]
+		if e.Pod.Name != "app" || e.Decision != DecisionInjected || e.Patch.Size != len(resp.Patch) || len(e.Patch.Paths) == 0 {
+			t.Fatalf("unexpected event %+v", e)
+		}
+	})
+	t.Run("skipped", func(t *testing.T) {
+		resp := admit(map[string]string{annotation.SidecarInject.Name: "false"})
+		if !resp.Allowed {
+			t.Fatalf("admission failed: %v", resp.Result)
+		}
+		if len(skipped) != 1 || skipped[0].Reason != skipReasonPolicy || skipped[0].Decision != DecisionSkipped {
+			t.Fatalf("unexpected skipped events %+v", skipped)
+		}
+		if len(injected) != 1 {
+			t.Fatalf("got %d injected events, want 1", len(injected))
+		}
+	})
+}
+
+func TestSummarizePatch(t *testing.T) {
+	got := summarizePatch([]byte(`[{"op":"add","path":"/spec/containers/-","value":{"name":"istio-proxy"}},` +
+		`{"op":"add","path":"/metadata/annotations","value":{}}]`))
+	if got.Size == 0 || len(got.Paths) != 2 || got.Paths[0] != "/spec/containers/-" || got.Paths[1] != "/metadata/annotations" {
+		t.Fatalf("unexpected summary %+v", got)
+	}
+}
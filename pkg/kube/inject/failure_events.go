@@ -0,0 +1,40 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordFailureEvent records the failure of the injection of the pod on its
+// namespace and, when it is owned by one, on its ReplicaSet, where users look
+// when pods are not created, rather than only in the injector logs.
+func recordFailureEvent(ctx context.Context, events *eventRecorder, pod *corev1.Pod, err error) {
+	const reason = "InjectionFailed"
+	name := potentialPodName(&pod.ObjectMeta)
+	events.warn(ctx, namespaceReference(pod.Namespace), reason, "Sidecar injection of pod %s failed: %v", name, err)
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "ReplicaSet" {
+		events.warn(ctx, &corev1.ObjectReference{
+			Kind:       ref.Kind,
+			APIVersion: ref.APIVersion,
+			Name:       ref.Name,
+			Namespace:  pod.Namespace,
+			UID:        ref.UID,
+		}, reason, "Sidecar injection of pod %s failed: %v", name, err)
+	}
+}
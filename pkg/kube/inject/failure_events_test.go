@@ -0,0 +1,63 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordFailureEvent(t *testing.T) {
+	r, recorded := newFakeEventRecorder()
+	controller := true
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		GenerateName: "app-5d8f7-",
+		Namespace:    "default",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "app-5d8f7", Controller: &controller,
+		}},
+	}}
+
+	recordFailureEvent(context.Background(), r, pod, errors.New("template: unknown function"))
+	if len(recorded.events) != 2 {
+		t.Fatalf("got %d events, want one on the namespace and one on the ReplicaSet", len(recorded.events))
+	}
+	kinds := map[string]string{}
+	for _, e := range recorded.events {
+		if e.obj.Namespace != "default" {
+			t.Fatalf("event on %v recorded out of the namespace of the pod", e.obj)
+		}
+		kinds[e.obj.Kind] = e.obj.Name
+		if e.reason != "InjectionFailed" || !strings.Contains(e.message, "template: unknown function") {
+			t.Fatalf("unexpected event %s: %s", e.reason, e.message)
+		}
+	}
+	if kinds["Namespace"] != "default" || kinds["ReplicaSet"] != "app-5d8f7" {
+		t.Fatalf("unexpected involved objects %v", kinds)
+	}
+
+	// only the namespace for a pod without a ReplicaSet
+	recorded.events = nil
+	pod.OwnerReferences = nil
+	recordFailureEvent(context.Background(), r, pod, errors.New("template: unknown function"))
+	if len(recorded.events) != 1 || recorded.events[0].obj.Kind != "Namespace" {
+		t.Fatalf("got events %v, want one on the namespace", recorded.events)
+	}
+}
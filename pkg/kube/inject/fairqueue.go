@@ -0,0 +1,196 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// fairQueue dispatches admission work to a fixed number of workers. Work is
+// queued per namespace and the namespaces are served round robin, so one
+// namespace mass-creating pods cannot starve the others. Work beyond
+// maxQueued, waiting longer than queueTimeout, or whose context is done, is
+// shed.
+type fairQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues map[string][]*queuedTask
+	// order holds the namespaces with pending work, in the order they are served.
+	order []string
+	// pending is the number of tasks queued across namespaces.
+	pending      int
+	maxQueued    int
+	queueTimeout time.Duration
+	closed       bool
+}
+
+// queuedTask is run by whichever of a worker or its shedding claims it first.
+type queuedTask struct {
+	ctx     context.Context
+	run     func()
+	claimed atomic.Bool
+}
+
+// expired returns true once the context of the task is done, e.g. the API
+// server gave up on the admission request, so it is not worth running.
+func (t *queuedTask) expired() bool {
+	return t.ctx != nil && t.ctx.Err() != nil
+}
+
+func (t *queuedTask) claim() bool {
+	return t.claimed.CAS(false, true)
+}
+
+func newFairQueue(o LoadSheddingOptions) *fairQueue {
+	q := &fairQueue{
+		queues:       map[string][]*queuedTask{},
+		maxQueued:    o.MaxQueued,
+		queueTimeout: o.QueueTimeout,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// start runs the given number of workers until the queue is closed.
+func (q *fairQueue) start(workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				task, ok := q.pop()
+				if !ok {
+					return
+				}
+				// an expired task is left for its shedding to claim
+				if !task.expired() && task.claim() {
+					task.run()
+				}
+			}
+		}()
+	}
+}
+
+// do runs the task for the namespace on a worker and waits for it. It returns
+// the reason the task is shed, without running it, or "" once it ran.
+func (q *fairQueue) do(ctx context.Context, namespace string, task func()) string {
+	done := make(chan struct{})
+	t := &queuedTask{ctx: ctx, run: func() {
+		defer close(done)
+		task()
+	}}
+	if !q.push(namespace, t) {
+		return shedReasonQueueFull
+	}
+	var timeout <-chan time.Time
+	if q.queueTimeout > 0 {
+		timer := time.NewTimer(q.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	reason := ""
+	select {
+	case <-done:
+		return ""
+	case <-timeout:
+		reason = shedReasonQueueTimeout
+	case <-ctx.Done():
+		reason = shedReasonCanceled
+	}
+	if t.claim() {
+		return reason
+	}
+	// a worker took the task just in time
+	<-done
+	return ""
+}
+
+// push queues the task for the namespace, or returns false if the queue is
+// full. Once the queue is closed tasks run inline.
+func (q *fairQueue) push(namespace string, task *queuedTask) bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		if task.claim() {
+			task.run()
+		}
+		return true
+	}
+	if q.maxQueued > 0 && q.pending >= q.maxQueued {
+		q.mu.Unlock()
+		return false
+	}
+	if len(q.queues[namespace]) == 0 {
+		q.order = append(q.order, namespace)
+	}
+	q.queues[namespace] = append(q.queues[namespace], task)
+	q.pending++
+	admissionQueuedNamespaces.Record(float64(len(q.order)))
+	admissionsQueued.Record(float64(q.pending))
+	q.mu.Unlock()
+	q.cond.Signal()
+	return true
+}
+
+// pop returns the next task, blocking until one is available. It returns
+// false once the queue is closed. Tasks shed or expired while queued are
+// returned too, and left unclaimed.
+func (q *fairQueue) pop() (*queuedTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.order) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+	namespace := q.order[0]
+	q.order = q.order[1:]
+	tasks := q.queues[namespace]
+	task := tasks[0]
+	if len(tasks) > 1 {
+		q.queues[namespace] = tasks[1:]
+		// go to the back of the line, so that other namespaces get their turn
+		q.order = append(q.order, namespace)
+	} else {
+		delete(q.queues, namespace)
+	}
+	q.pending--
+	admissionQueuedNamespaces.Record(float64(len(q.order)))
+	admissionsQueued.Record(float64(q.pending))
+	return task, true
+}
+
+// close stops the workers and runs the pending tasks inline, so no admission
+// request is left waiting.
+func (q *fairQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	pending := q.queues
+	q.queues = map[string][]*queuedTask{}
+	q.order = nil
+	q.pending = 0
+	q.mu.Unlock()
+	q.cond.Broadcast()
+	for _, tasks := range pending {
+		for _, task := range tasks {
+			if task.claim() {
+				task.run()
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFairQueueRoundRobin(t *testing.T) {
+	q := newFairQueue(LoadSheddingOptions{})
+	var got []string
+	record := func(s string) *queuedTask {
+		return &queuedTask{run: func() { got = append(got, s) }}
+	}
+	// A noisy namespace queues many requests before a quiet one.
+	q.push("noisy", record("noisy-1"))
+	q.push("noisy", record("noisy-2"))
+	q.push("noisy", record("noisy-3"))
+	q.push("quiet", record("quiet-1"))
+
+	for i := 0; i < 4; i++ {
+		task, ok := q.pop()
+		if !ok {
+			t.Fatalf("queue unexpectedly closed")
+		}
+		task.run()
+	}
+	want := []string{"noisy-1", "quiet-1", "noisy-2", "noisy-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+}
+
+func TestFairQueueWorkers(t *testing.T) {
+	q := newFairQueue(LoadSheddingOptions{})
+	q.start(2)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			if reason := q.do(context.Background(), "ns", func() {}); reason != "" {
+				t.Errorf("task shed: %s", reason)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	q.close()
+	if _, ok := q.pop(); ok {
+		t.Fatalf("expected closed queue")
+	}
+	ran := false
+	q.do(context.Background(), "ns", func() { ran = true })
+	if !ran {
+		t.Fatalf("expected task to run inline on a closed queue")
+	}
+}
+
+func TestFairQueueMaxQueued(t *testing.T) {
+	q := newFairQueue(LoadSheddingOptions{MaxQueued: 1})
+	if !q.push("ns", &queuedTask{run: func() {}}) {
+		t.Fatalf("expected the first task to be queued")
+	}
+	if reason := q.do(context.Background(), "other", func() { t.Fatalf("shed task ran") }); reason != shedReasonQueueFull {
+		t.Fatalf("got reason %q, want %s", reason, shedReasonQueueFull)
+	}
+	if task, _ := q.pop(); !task.claim() {
+		t.Fatalf("expected the queued task to be claimed")
+	}
+	if !q.push("other", &queuedTask{run: func() {}}) {
+		t.Fatalf("expected a task to be queued once a worker took the first")
+	}
+}
+
+func TestFairQueueTimeout(t *testing.T) {
+	// no workers, the task is never taken
+	q := newFairQueue(LoadSheddingOptions{QueueTimeout: 10 * time.Millisecond})
+	if reason := q.do(context.Background(), "ns", func() { t.Fatalf("shed task ran") }); reason != shedReasonQueueTimeout {
+		t.Fatalf("got reason %q, want %s", reason, shedReasonQueueTimeout)
+	}
+	task, _ := q.pop()
+	if task.claim() {
+		t.Fatalf("expected the shed task to be claimed already")
+	}
+
+	q = newFairQueue(LoadSheddingOptions{QueueTimeout: time.Minute})
+	q.start(1)
+	defer q.close()
+	ran := false
+	if reason := q.do(context.Background(), "ns", func() { ran = true }); reason != "" || !ran {
+		t.Fatalf("expected the task to run, got reason %q", reason)
+	}
+}
+
+func TestFairQueueCanceled(t *testing.T) {
+	// no workers, the task waits until its context is done
+	q := newFairQueue(LoadSheddingOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if reason := q.do(ctx, "ns", func() { t.Fatalf("canceled task ran") }); reason != shedReasonCanceled {
+		t.Fatalf("got reason %q, want %s", reason, shedReasonCanceled)
+	}
+
+	// a worker skips a queued task whose context is done
+	q = newFairQueue(LoadSheddingOptions{})
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	task := &queuedTask{ctx: ctx, run: func() { t.Fatalf("expired task ran") }}
+	q.push("ns", task)
+	q.start(1)
+	defer q.close()
+	if reason := q.do(context.Background(), "ns", func() {}); reason != "" {
+		t.Fatalf("expected the next task to run, got reason %q", reason)
+	}
+	if !task.claim() {
+		t.Fatalf("expected the expired task to be left unclaimed")
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/pkg/filewatcher"
+	"istio.io/pkg/log"
+)
+
+const (
+	// localCluster is the cluster label of the requests of callers not mapped to
+	// a cluster of the fan-in configuration.
+	localCluster = "local"
+
+	// maxUnmappedCallersLogged bounds the unmapped callers remembered as logged.
+	maxUnmappedCallersLogged = 100
+)
+
+// FanInCluster is a cluster served by the injector through a URL webhook
+// clientConfig, with its own injection configuration. Its API servers are
+// identified by the user of their tokens, see TokenAuthOptions, or by the
+// common name of their client certificates, see ClientAuthOptions.
+type FanInCluster struct {
+	// Name of the cluster, also forced as the cluster ID of its proxies.
+	Name string `json:"name"`
+
+	// Users authenticated by the tokens of the API servers of the cluster.
+	Users []string `json:"users,omitempty"`
+
+	// CommonNames of the client certificates of the API servers of the cluster.
+	CommonNames []string `json:"commonNames,omitempty"`
+
+	// ConfigFile and ValuesFile are the injection configuration of the cluster.
+	ConfigFile string `json:"configFile"`
+	ValuesFile string `json:"valuesFile"`
+
+	// MeshConfigFile is the mesh configuration of the cluster. Defaults to
+	// the mesh configuration of the injector.
+	MeshConfigFile string `json:"meshConfigFile,omitempty"`
+}
+
+type fanInConfig struct {
+	Clusters []FanInCluster `json:"clusters"`
+}
+
+// readFanInClusters reads the clusters of a fan-in configuration file.
+func readFanInClusters(file string) ([]FanInCluster, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config fanInConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid fan-in configuration %s: %v", file, err)
+	}
+	if err := validateFanInClusters(config.Clusters); err != nil {
+		return nil, fmt.Errorf("invalid fan-in configuration %s: %v", file, err)
+	}
+	return config.Clusters, nil
+}
+
+func validateFanInClusters(clusters []FanInCluster) error {
+	names := map[string]bool{}
+	identities := map[string]string{}
+	for i, c := range clusters {
+		if c.Name == "" || strings.Contains(c.Name, "/") || c.Name == localCluster {
+			return fmt.Errorf("cluster %d: invalid name %q", i, c.Name)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("cluster %s: duplicate name", c.Name)
+		}
+		names[c.Name] = true
+		if c.ConfigFile == "" || c.ValuesFile == "" {
+			return fmt.Errorf("cluster %s: configFile and valuesFile are required", c.Name)
+		}
+		if len(c.Users) == 0 && len(c.CommonNames) == 0 {
+			return fmt.Errorf("cluster %s: no users or commonNames identifying its API servers", c.Name)
+		}
+		for _, id := range append(prefixed("user:", c.Users), prefixed("cn:", c.CommonNames)...) {
+			if other, f := identities[id]; f {
+				return fmt.Errorf("cluster %s: %s already identifies cluster %s", c.Name, id, other)
+			}
+			identities[id] = c.Name
+		}
+	}
+	return nil
+}
+
+func prefixed(prefix string, values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, prefix+v)
+	}
+	return out
+}
+
+type fanInMember struct {
+	name    string
+	webhook *Webhook
+}
+
+// fanIn serves the injection of the clusters of a fan-in configuration, each
+// with a webhook of its own configuration.
+type fanIn struct {
+	members      []*fanInMember
+	byUser       map[string]*fanInMember
+	byCommonName map[string]*fanInMember
+	fileWatcher  filewatcher.FileWatcher
+
+	mu sync.Mutex
+	// unmapped are the callers served the local configuration already logged.
+	unmapped map[string]bool
+}
+
+// newFanIn returns nil if the parameters have no fan-in configuration. The
+// webhooks of the clusters share the revision and admission settings of the
+// injector, but not its Kubernetes client, which is of another cluster: the
+// namespaces, owners and limit ranges of their pods are not looked up. The
+// webhooks of the clusters serve their requests on the queue of the injector,
+// so all the clusters share its workers.
+func newFanIn(p WebhookParameters, queue *fairQueue) (*fanIn, error) {
+	if p.FanInConfigFile == "" {
+		return nil, nil
+	}
+	clusters, err := readFanInClusters(p.FanInConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	f := &fanIn{
+		byUser:       map[string]*fanInMember{},
+		byCommonName: map[string]*fanInMember{},
+		fileWatcher:  filewatcher.NewWatcher(),
+		unmapped:     map[string]bool{},
+	}
+	// the clusters are loaded concurrently, so their number does not delay
+	// the start of the injector
+	f.members = make([]*fanInMember, len(clusters))
+	errs := make([]error, len(clusters))
+	var wg sync.WaitGroup
+	for i := range clusters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			f.members[i], errs[i] = f.newMember(p, clusters[i], queue)
+		}(i)
+	}
+	wg.Wait()
+	for i, c := range clusters {
+		if errs[i] != nil {
+			_ = f.fileWatcher.Close()
+			return nil, fmt.Errorf("cluster %s: %v", c.Name, errs[i])
+		}
+		m := f.members[i]
+		for _, u := range c.Users {
+			f.byUser[u] = m
+		}
+		for _, cn := range c.CommonNames {
+			f.byCommonName[cn] = m
+		}
+		log.Infof("Serving the injection of cluster %s with %s", c.Name, c.ConfigFile)
+	}
+	return f, nil
+}
+
+// newMember loads the webhook of the cluster, serving its requests on queue.
+func (f *fanIn) newMember(p WebhookParameters, c FanInCluster, queue *fairQueue) (*fanInMember, error) {
+	env := p.Env
+	if c.MeshConfigFile != "" {
+		watcher, err := mesh.NewWatcher(f.fileWatcher, c.MeshConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		env = &model.Environment{Watcher: watcher}
+	}
+	wh, err := NewWebhook(WebhookParameters{
+		ConfigFile:          c.ConfigFile,
+		ValuesFile:          c.ValuesFile,
+		Env:                 env,
+		Revision:            p.Revision,
+		MonitoringPort:      -1,
+		Mux:                 http.NewServeMux(),
+		ShutdownGracePeriod: p.ShutdownGracePeriod,
+		FieldManager:        p.FieldManager,
+		NamespaceFilter:     p.NamespaceFilter,
+		ValuesKeys:          p.ValuesKeys,
+	})
+	if err != nil {
+		return nil, err
+	}
+	wh.queue = queue
+	return &fanInMember{name: c.Name, webhook: wh}, nil
+}
+
+// run runs the webhooks of the clusters, reloading their configuration on changes.
+func (f *fanIn) run(stop <-chan struct{}) {
+	for _, m := range f.members {
+		go m.webhook.Run(stop)
+	}
+	<-stop
+	_ = f.fileWatcher.Close()
+}
+
+// resolve returns the cluster of the caller of the request, by the user of
+// its token then the common name of its certificate, or nil.
+func (f *fanIn) resolve(r *http.Request) *fanInMember {
+	if user := tokenUser(r); user != "" {
+		if m, found := f.byUser[user]; found {
+			return m
+		}
+	}
+	if cn := clientCommonName(r); cn != "" {
+		if m, found := f.byCommonName[cn]; found {
+			return m
+		}
+	}
+	return nil
+}
+
+// logUnmapped logs the first request of each caller not mapped to a cluster.
+func (f *fanIn) logUnmapped(r *http.Request) {
+	caller := "anonymous"
+	if user := tokenUser(r); user != "" {
+		caller = "user " + user
+	} else if cn := clientCommonName(r); cn != "" {
+		caller = "common name " + cn
+	}
+	f.mu.Lock()
+	logged := f.unmapped[caller]
+	if !logged && len(f.unmapped) < maxUnmappedCallersLogged {
+		f.unmapped[caller] = true
+	}
+	f.mu.Unlock()
+	if !logged {
+		log.Warnf("Serving the injection requests of %s, not mapped to a fan-in cluster, with the local configuration", caller)
+	}
+}
+
+func clientCommonName(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		return r.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+	return ""
+}
+
+// serveFanIn serves the injection requests of the clusters of the fan-in
+// configuration with their webhook. Callers not mapped to a cluster, e.g. the
+// API server of the cluster of the injector, are served the local
+// configuration, and logged.
+func (wh *Webhook) serveFanIn(w http.ResponseWriter, r *http.Request) {
+	m := wh.fanIn.resolve(r)
+	if m == nil {
+		clusterInjections.With(clusterTag.Value(localCluster)).Increment()
+		wh.fanIn.logUnmapped(r)
+		wh.serveInject(w, r)
+		return
+	}
+	clusterInjections.With(clusterTag.Value(m.name)).Increment()
+	log.Debugf("Serving the injection request of %s for cluster %s", r.RemoteAddr, m.name)
+	r = r.Clone(r.Context())
+	r.URL.Path = clusterInjectPath(r.URL.Path, m.name)
+	m.webhook.serveInject(w, r)
+}
+
+// clusterInjectPath appends the cluster to the environment of the injection
+// path, overriding the one the caller may have set.
+func clusterInjectPath(path, cluster string) string {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(segments)%2 == 1 {
+		// drop the last key without value, as parseInjectEnvs would
+		segments = segments[:len(segments)-1]
+	}
+	return strings.Join(segments, "/") + "/cluster/" + cluster
+}
@@ -0,0 +1,165 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestValidateFanInClusters(t *testing.T) {
+	cluster := func(name string, users ...string) FanInCluster {
+		return FanInCluster{Name: name, Users: users, ConfigFile: "config", ValuesFile: "values"}
+	}
+	cases := []struct {
+		name     string
+		clusters []FanInCluster
+		valid    bool
+	}{
+		{"none", nil, true},
+		{"valid", []FanInCluster{cluster("a", "user-a"), cluster("b", "user-b")}, true},
+		{"no name", []FanInCluster{cluster("", "user-a")}, false},
+		{"name with a slash", []FanInCluster{cluster("a/b", "user-a")}, false},
+		{"reserved name", []FanInCluster{cluster(localCluster, "user-a")}, false},
+		{"duplicate name", []FanInCluster{cluster("a", "user-a"), cluster("a", "user-b")}, false},
+		{"no identity", []FanInCluster{cluster("a")}, false},
+		{"shared identity", []FanInCluster{cluster("a", "user"), cluster("b", "user")}, false},
+		{"no config", []FanInCluster{{Name: "a", CommonNames: []string{"a"}}}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := validateFanInClusters(c.clusters); (err == nil) != c.valid {
+				t.Fatalf("got error %v, want valid %v", err, c.valid)
+			}
+		})
+	}
+}
+
+func TestClusterInjectPath(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/inject", "/inject/cluster/east"},
+		{"/inject/", "/inject/cluster/east"},
+		{"/inject/net/network1", "/inject/net/network1/cluster/east"},
+		{"/inject/cluster/west", "/inject/cluster/west/cluster/east"},
+		{"/inject/net", "/inject/cluster/east"},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			got := clusterInjectPath(c.path, "east")
+			if got != c.want {
+				t.Fatalf("got %s, want %s", got, c.want)
+			}
+			if env := parseInjectEnvs(got)["ISTIO_META_CLUSTER_ID"]; env != "east" {
+				t.Fatalf("got cluster ID %q", env)
+			}
+		})
+	}
+}
+
+func TestFanInResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fan_in_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configBytes, err := yaml.Marshal(minimalSidecarTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, values, _ := loadInjectionSettings(t, nil, "")
+	configFile := filepath.Join(dir, "config")
+	valuesFile := filepath.Join(dir, "values")
+	if err := ioutil.WriteFile(configFile, configBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(valuesFile, []byte(values), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := yaml.Marshal(fanInConfig{Clusters: []FanInCluster{
+		{Name: "east", Users: []string{"east-apiserver"}, ConfigFile: configFile, ValuesFile: valuesFile},
+		{Name: "west", CommonNames: []string{"west-apiserver"}, ConfigFile: configFile, ValuesFile: valuesFile},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clustersFile := filepath.Join(dir, "clusters")
+	if err := ioutil.WriteFile(clustersFile, clusters, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := mesh.DefaultMeshConfig()
+	queue := newFairQueue(LoadSheddingOptions{})
+	f, err := newFanIn(WebhookParameters{
+		FanInConfigFile: clustersFile,
+		Env:             &model.Environment{Watcher: mesh.NewFixedWatcher(&m)},
+	}, queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.fileWatcher.Close()
+	if len(f.members) != 2 {
+		t.Fatalf("got %d clusters, want 2", len(f.members))
+	}
+	for _, m := range f.members {
+		if m.webhook.queue != queue {
+			t.Fatalf("cluster %s has its own queue, want the queue of the injector", m.name)
+		}
+	}
+
+	withCN := func(r *http.Request, cn string) *http.Request {
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}}}
+		return r
+	}
+	cases := []struct {
+		name    string
+		request *http.Request
+		want    string
+	}{
+		{"token user", httptest.NewRequest("POST", "/inject", nil).WithContext(
+			context.WithValue(context.Background(), tokenUserKey{}, "east-apiserver")), "east"},
+		{"certificate", withCN(httptest.NewRequest("POST", "/inject", nil), "west-apiserver"), "west"},
+		{"unknown user", httptest.NewRequest("POST", "/inject", nil).WithContext(
+			context.WithValue(context.Background(), tokenUserKey{}, "other")), ""},
+		{"anonymous", httptest.NewRequest("POST", "/inject", nil), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := ""
+			if m := f.resolve(c.request); m != nil {
+				got = m.name
+			}
+			if got != c.want {
+				t.Fatalf("got cluster %q, want %q", got, c.want)
+			}
+		})
+	}
+}
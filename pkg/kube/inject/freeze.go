@@ -0,0 +1,183 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FreezeWindow is a recurring period during which the injection
+// configuration in use is kept and reloads are refused. Changes of the
+// template override directory are still reloaded while an override is in
+// place, so that an emergency fix can be applied.
+type FreezeWindow struct {
+	// Schedule is when the window starts, in cron format:
+	// minute hour day-of-month month day-of-week.
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window lasts, e.g. "72h".
+	Duration string `json:"duration"`
+
+	// TimeZone is the IANA time zone of the schedule. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type freezeWindow struct {
+	schedule *cronSchedule
+	duration time.Duration
+	location *time.Location
+}
+
+func parseFreezeWindow(w FreezeWindow) (*freezeWindow, error) {
+	schedule, err := parseCronSchedule(w.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid freeze window schedule %q: %v", w.Schedule, err)
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid freeze window duration %q: %v", w.Duration, err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("freeze window duration must be positive: %v", duration)
+	}
+	location := time.UTC
+	if w.TimeZone != "" {
+		if location, err = time.LoadLocation(w.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid freeze window time zone %q: %v", w.TimeZone, err)
+		}
+	}
+	return &freezeWindow{schedule: schedule, duration: duration, location: location}, nil
+}
+
+func validateFreezeWindows(windows []FreezeWindow) error {
+	for _, w := range windows {
+		if _, err := parseFreezeWindow(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// frozenUntil returns the end of the latest freeze window in progress at
+// now, or the zero time if the configuration is not frozen. Windows which
+// cannot be parsed are ignored, as they are rejected when loading.
+func frozenUntil(windows []FreezeWindow, now time.Time) time.Time {
+	var until time.Time
+	for _, w := range windows {
+		fw, err := parseFreezeWindow(w)
+		if err != nil {
+			continue
+		}
+		// the most recent start within the duration ends the latest
+		start := now.In(fw.location).Truncate(time.Minute)
+		for t := start; now.Sub(t) < fw.duration; t = t.Add(-time.Minute) {
+			if fw.schedule.matches(t) {
+				if end := t.Add(fw.duration); end.After(until) {
+					until = end
+				}
+				break
+			}
+		}
+	}
+	return until
+}
+
+// cronSchedule is a parsed cron expression, each field a bitset of the
+// allowed values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record unrestricted fields: as in cron, when both
+	// day fields are restricted a time matching either is allowed.
+	domStar, dowStar bool
+}
+
+func parseCronSchedule(s string) (*cronSchedule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+	c := &cronSchedule{domStar: fields[2] == "*", dowStar: fields[4] == "*"}
+	var err error
+	for i, f := range []struct {
+		bits         *uint64
+		lower, upper int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 6},
+	} {
+		if *f.bits, err = parseCronField(fields[i], f.lower, f.upper); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges (a-b) and
+// steps (*/n or a-b/n).
+func parseCronField(field string, lower, upper int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step, stepped := 1, false
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part, stepped = part[:i], true
+		}
+		from, to := lower, upper
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			if !stepped {
+				// a/n steps from a to the end of the range
+				to = from
+			}
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid range %q", part)
+				}
+			}
+		}
+		if from < lower || to > upper || from > to {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, lower, upper)
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (c *cronSchedule) matches(t time.Time) bool {
+	has := func(bits uint64, v int) bool { return bits&(1<<uint(v)) != 0 }
+	if !has(c.minute, t.Minute()) || !has(c.hour, t.Hour()) || !has(c.month, int(t.Month())) {
+		return false
+	}
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+	if !c.domStar && !c.dowStar {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,124 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	cases := []struct {
+		schedule string
+		time     string
+		want     bool
+		wantErr  bool
+	}{
+		{schedule: "0 18 * * 5", time: "2020-12-18T18:00:00Z", want: true},
+		{schedule: "0 18 * * 5", time: "2020-12-18T18:01:00Z", want: false},
+		{schedule: "0 18 * * 5", time: "2020-12-17T18:00:00Z", want: false},
+		{schedule: "*/15 * * * *", time: "2020-12-17T18:45:00Z", want: true},
+		{schedule: "5/20 * * * *", time: "2020-12-17T18:45:00Z", want: true},
+		{schedule: "0 0 20-31 12 *", time: "2020-12-24T00:00:00Z", want: true},
+		{schedule: "0 0 20-31 12 *", time: "2020-11-24T00:00:00Z", want: false},
+		// both day fields restricted: either matches
+		{schedule: "0 0 1 * 1", time: "2020-12-14T00:00:00Z", want: true},
+		{schedule: "0 0 1,15 * *", time: "2020-12-15T00:00:00Z", want: true},
+		{schedule: "0 0 * *", wantErr: true},
+		{schedule: "60 0 * * *", wantErr: true},
+		{schedule: "0 0 * * */0", wantErr: true},
+		{schedule: "0 0 5-1 * *", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.schedule+" "+c.time, func(t *testing.T) {
+			s, err := parseCronSchedule(c.schedule)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			tm, err := time.Parse(time.RFC3339, c.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.matches(tm); got != c.want {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFrozenUntil(t *testing.T) {
+	// weekend freeze starting Fridays at 18:00 UTC
+	windows := []FreezeWindow{{Schedule: "0 18 * * 5", Duration: "48h"}}
+	parse := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	cases := []struct {
+		name    string
+		windows []FreezeWindow
+		now     string
+		want    string
+	}{
+		{"before the window", windows, "2020-12-18T17:59:00Z", ""},
+		{"at the start", windows, "2020-12-18T18:00:00Z", "2020-12-20T18:00:00Z"},
+		{"during the window", windows, "2020-12-19T09:30:30Z", "2020-12-20T18:00:00Z"},
+		{"after the window", windows, "2020-12-20T18:00:00Z", ""},
+		{"time zone", []FreezeWindow{{Schedule: "0 18 * * 5", Duration: "1h", TimeZone: "America/New_York"}},
+			"2020-12-18T23:30:00Z", "2020-12-19T00:00:00Z"},
+		{"no windows", nil, "2020-12-19T09:30:00Z", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := frozenUntil(c.windows, parse(c.now))
+			if c.want == "" {
+				if !got.IsZero() {
+					t.Fatalf("got frozen until %v, want not frozen", got)
+				}
+				return
+			}
+			if !got.Equal(parse(c.want)) {
+				t.Fatalf("got frozen until %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateFreezeWindows(t *testing.T) {
+	cases := []struct {
+		name    string
+		window  FreezeWindow
+		wantErr bool
+	}{
+		{"valid", FreezeWindow{Schedule: "0 0 24 12 *", Duration: "72h"}, false},
+		{"invalid schedule", FreezeWindow{Schedule: "christmas", Duration: "72h"}, true},
+		{"invalid duration", FreezeWindow{Schedule: "0 0 24 12 *", Duration: "3 days"}, true},
+		{"negative duration", FreezeWindow{Schedule: "0 0 24 12 *", Duration: "-1h"}, true},
+		{"invalid time zone", FreezeWindow{Schedule: "0 0 24 12 *", Duration: "72h", TimeZone: "Mars/Olympus"}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateFreezeWindows([]FreezeWindow{c.window})
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Tool to generate pkg/kube/inject/template_variables.gen.go
+// Example run command:
+// REPO_ROOT=`pwd` go generate ./pkg/kube/inject/...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/types"
+
+	"istio.io/istio/operator/pkg/apis/istio/v1alpha1"
+	"istio.io/istio/pkg/kube/inject"
+)
+
+// maxDepth bounds the nesting of the variables listed.
+const maxDepth = 12
+
+type variable struct {
+	Path string
+	Type string
+}
+
+type function struct {
+	Name      string
+	Signature string
+}
+
+// fieldNameFunc returns the path element of a struct field, or "" to skip it,
+// and whether an embedded field is promoted into its parent.
+type fieldNameFunc func(f reflect.StructField) (name string, inline bool)
+
+// typeNameFunc returns the type listed for t, and whether its elements or
+// fields are listed too.
+type typeNameFunc func(t reflect.Type) (name string, expand bool)
+
+// templateFieldName names fields as the template accesses them, by Go name.
+func templateFieldName(f reflect.StructField) (string, bool) {
+	if strings.HasPrefix(f.Name, "XXX_") {
+		return "", false
+	}
+	return f.Name, f.Anonymous
+}
+
+// yamlFieldName names fields as the rendered YAML sets them: the YAML is
+// converted to JSON before it is decoded, so by JSON name, else YAML name.
+func yamlFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	name := strings.Split(tag, ",")[0]
+	if name == "-" || f.Tag.Get("yaml") == "-" {
+		return "", false
+	}
+	if strings.Contains(tag, ",inline") || (f.Anonymous && name == "") {
+		return "", true
+	}
+	if name == "" {
+		name = strings.Split(f.Tag.Get("yaml"), ",")[0]
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, false
+}
+
+// valuesFieldName names fields as the values map keys them, by JSON name.
+func valuesFieldName(f reflect.StructField) (string, bool) {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "-" || strings.HasPrefix(f.Name, "XXX_") {
+		return "", false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, false
+}
+
+// goTypeName lists Go types as they are.
+func goTypeName(t reflect.Type) (string, bool) {
+	return t.String(), true
+}
+
+// valuesScalars are the messages of the values schema set as a single value.
+var valuesScalars = map[reflect.Type]string{
+	reflect.TypeOf(types.BoolValue{}):           "bool",
+	reflect.TypeOf(types.Duration{}):            "duration",
+	reflect.TypeOf(v1alpha1.IntOrStringForPB{}): "intOrString",
+}
+
+// valuesTypeName lists the types of the values schema as the values map holds
+// them: the values are decoded from YAML, not into the schema types.
+func valuesTypeName(t reflect.Type) (string, bool) {
+	if name, f := valuesScalars[t]; f {
+		return name, false
+	}
+	if _, enum := reflect.Zero(t).Interface().(interface{ EnumDescriptor() ([]byte, []int) }); enum {
+		return "string", false
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", false
+	case reflect.Bool:
+		return "bool", false
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "integer", false
+	case reflect.Float32, reflect.Float64:
+		return "number", false
+	case reflect.Struct, reflect.Map:
+		return "object", true
+	case reflect.Slice, reflect.Array:
+		return "array", true
+	}
+	return "any", false
+}
+
+// listVariables walks the exported fields of t, depth first in declaration
+// order, with the paths of its fields starting with root. Types are not
+// expanded again within themselves, and structs without exported fields,
+// such as time.Time, are leaves.
+func listVariables(t reflect.Type, root string, name fieldNameFunc, typeName typeNameFunc) []variable {
+	var out []variable
+	var walk func(t reflect.Type, path string, depth int, seen map[reflect.Type]bool)
+	walk = func(t reflect.Type, path string, depth int, seen map[reflect.Type]bool) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		typ, expand := typeName(t)
+		if path != "" {
+			out = append(out, variable{Path: path, Type: typ})
+		}
+		if !expand || depth >= maxDepth || seen[t] {
+			return
+		}
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() != reflect.Uint8 {
+				walkElem(walk, t.Elem(), path+"[]", depth, seen)
+			}
+		case reflect.Map:
+			walkElem(walk, t.Elem(), path+"[key]", depth, seen)
+		case reflect.Struct:
+			seen[t] = true
+			walkFields(t, name, func(f reflect.StructField, p string) {
+				if path == "" {
+					p = root + p
+				} else {
+					p = path + "." + p
+				}
+				walk(f.Type, p, depth+1, seen)
+			})
+			delete(seen, t)
+		}
+	}
+	walk(t, "", 0, map[reflect.Type]bool{})
+	return out
+}
+
+// walkElem walks the elements of a slice or map, skipped unless they are composite.
+func walkElem(walk func(reflect.Type, string, int, map[reflect.Type]bool), elem reflect.Type, path string,
+	depth int, seen map[reflect.Type]bool) {
+	base := elem
+	for base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	switch base.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		walk(elem, path, depth+1, seen)
+	}
+}
+
+// walkFields calls fn for the exported fields of the struct t, with the fields
+// of inline embedded structs promoted.
+func walkFields(t reflect.Type, name fieldNameFunc, fn func(f reflect.StructField, path string)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		p, inline := name(f)
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				walkFields(ft, name, fn)
+				continue
+			}
+		}
+		if p == "" {
+			continue
+		}
+		fn(f, p)
+	}
+}
+
+func listFunctions() []function {
+	var out []function
+	for name, f := range inject.TemplateFuncs() {
+		out = append(out, function{Name: name, Signature: reflect.TypeOf(f).String()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func writeVariables(b *bytes.Buffer, field string, vars []variable) {
+	fmt.Fprintf(b, "\t%s: []templateVariable{\n", field)
+	for _, v := range vars {
+		fmt.Fprintf(b, "\t\t{Path: %q, Type: %q},\n", v.Path, v.Type)
+	}
+	b.WriteString("\t},\n")
+}
+
+func main() {
+	outputFile := flag.String("output", "", "Output file. Leave blank to go to stdout")
+	flag.Parse()
+
+	var b bytes.Buffer
+	b.WriteString(`// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by pkg/kube/inject/gen/main.go. DO NOT EDIT!
+
+package inject
+
+// nolint: lll
+var templateVariables = templateVariablesDoc{
+`)
+	writeVariables(&b, "Variables", listVariables(reflect.TypeOf(inject.SidecarTemplateData{}), ".", templateFieldName, goTypeName))
+	writeVariables(&b, "Values", listVariables(reflect.TypeOf(v1alpha1.Values{}), ".Values.", valuesFieldName, valuesTypeName))
+	b.WriteString("\tFunctions: []templateFunction{\n")
+	for _, f := range listFunctions() {
+		fmt.Fprintf(&b, "\t\t{Name: %q, Signature: %q},\n", f.Name, f.Signature)
+	}
+	b.WriteString("\t},\n")
+	writeVariables(&b, "Output", listVariables(reflect.TypeOf(inject.SidecarInjectionSpec{}), "", yamlFieldName, goTypeName))
+	b.WriteString("}\n")
+
+	// Format source code.
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Output
+	if *outputFile == "" {
+		fmt.Println(string(out))
+	} else if err := ioutil.WriteFile(*outputFile, out, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+)
+
+// HostNamespacePolicy selects how pods sharing host namespaces, with hostPort,
+// hostPID or hostIPC, are handled. Pods with hostNetwork are never injected.
+type HostNamespacePolicy string
+
+const (
+	// HostNamespaceInject injects the pods like any other. This is the default.
+	HostNamespaceInject HostNamespacePolicy = "inject"
+	// HostNamespaceSkip does not inject the pods, and records an event on
+	// their workload.
+	HostNamespaceSkip HostNamespacePolicy = "skip"
+	// HostNamespaceAdjust injects the pods with host ports, excluding the
+	// container ports bound to host ports from inbound capture. Nothing makes
+	// the proxy safe in the host PID or IPC namespace, so the pods with
+	// hostPID or hostIPC are skipped as with HostNamespaceSkip.
+	HostNamespaceAdjust HostNamespacePolicy = "adjust"
+	// HostNamespaceReject rejects the pods.
+	HostNamespaceReject HostNamespacePolicy = "reject"
+
+	skipReasonHostNamespaces = "hostNamespaces"
+)
+
+func validateHostNamespacePolicy(p HostNamespacePolicy) error {
+	switch p {
+	case "", HostNamespaceInject, HostNamespaceSkip, HostNamespaceAdjust, HostNamespaceReject:
+		return nil
+	}
+	return fmt.Errorf("unknown host namespace policy %q: must be %s, %s, %s or %s",
+		p, HostNamespaceInject, HostNamespaceSkip, HostNamespaceAdjust, HostNamespaceReject)
+}
+
+// podHostNamespacePolicy returns the policy applied to the pod: the adjustment
+// only covers the host ports.
+func podHostNamespacePolicy(p HostNamespacePolicy, spec *corev1.PodSpec) HostNamespacePolicy {
+	if p == HostNamespaceAdjust && (spec.HostPID || spec.HostIPC) {
+		return HostNamespaceSkip
+	}
+	return p
+}
+
+// hostNamespaceUsage returns the host namespace settings used by the pod, in
+// a fixed order.
+func hostNamespaceUsage(spec *corev1.PodSpec) []string {
+	var usage []string
+	if len(hostPorts(spec)) > 0 {
+		usage = append(usage, "hostPort")
+	}
+	if spec.HostPID {
+		usage = append(usage, "hostPID")
+	}
+	if spec.HostIPC {
+		usage = append(usage, "hostIPC")
+	}
+	return usage
+}
+
+// hostPorts returns the sorted container ports of the pod bound to host ports.
+func hostPorts(spec *corev1.PodSpec) []int32 {
+	seen := map[int32]bool{}
+	var ports []int32
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range containers {
+			for _, p := range c.Ports {
+				if p.HostPort != 0 && !seen[p.ContainerPort] {
+					seen[p.ContainerPort] = true
+					ports = append(ports, p.ContainerPort)
+				}
+			}
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// withHostPortsExcluded returns a copy of the pod with the container ports
+// bound to host ports added to the ports excluded from inbound capture, or nil
+// if the pod has none.
+func withHostPortsExcluded(pod *corev1.Pod) *corev1.Pod {
+	ports := hostPorts(&pod.Spec)
+	if len(ports) == 0 {
+		return nil
+	}
+	var excluded []string
+	if v := pod.Annotations[annotation.SidecarTrafficExcludeInboundPorts.Name]; v != "" {
+		excluded = strings.Split(v, ",")
+	}
+	present := map[string]bool{}
+	for _, p := range excluded {
+		present[strings.TrimSpace(p)] = true
+	}
+	for _, p := range ports {
+		if s := strconv.Itoa(int(p)); !present[s] {
+			excluded = append(excluded, s)
+		}
+	}
+	pod = pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[annotation.SidecarTrafficExcludeInboundPorts.Name] = strings.Join(excluded, ",")
+	return pod
+}
+
+// createHostNamespacePatch returns a patch which records the skip decision.
+func createHostNamespacePatch(pod *corev1.Pod) ([]byte, error) {
+	return json.Marshal(updateAnnotation(pod.Annotations, map[string]string{SkipReasonAnnotation: skipReasonHostNamespaces}))
+}
+
+// recordHostNamespaceEvent records an event on the workload of a pod not
+// injected because it shares host namespaces.
+func recordHostNamespaceEvent(ctx context.Context, events *eventRecorder, deploy *metav1.ObjectMeta,
+	typeMeta *metav1.TypeMeta, usage []string) {
+	events.warn(ctx, &corev1.ObjectReference{
+		Kind:       typeMeta.Kind,
+		APIVersion: typeMeta.APIVersion,
+		Name:       deploy.Name,
+		Namespace:  deploy.Namespace,
+	}, "InjectionSkippedHostNamespaces", "Sidecar injection skipped, the pod uses %s", strings.Join(usage, ", "))
+}
@@ -0,0 +1,135 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+)
+
+func hostPortPod(annotations map[string]string, ports ...corev1.ContainerPort) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", Annotations: annotations},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: ports}}},
+	}
+}
+
+func TestHostNamespaceUsage(t *testing.T) {
+	cases := []struct {
+		name string
+		spec corev1.PodSpec
+		want []string
+	}{
+		{"none", corev1.PodSpec{Containers: []corev1.Container{{Ports: []corev1.ContainerPort{{ContainerPort: 80}}}}}, nil},
+		{"host port", hostPortPod(nil, corev1.ContainerPort{ContainerPort: 80, HostPort: 8080}).Spec, []string{"hostPort"}},
+		{"host PID and IPC", corev1.PodSpec{HostPID: true, HostIPC: true}, []string{"hostPID", "hostIPC"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := hostNamespaceUsage(&c.spec); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWithHostPortsExcluded(t *testing.T) {
+	excludeAnnotation := annotation.SidecarTrafficExcludeInboundPorts.Name
+	cases := []struct {
+		name string
+		pod  *corev1.Pod
+		want string
+	}{
+		{"no host port", hostPortPod(nil, corev1.ContainerPort{ContainerPort: 80}), ""},
+		{"host ports", hostPortPod(nil,
+			corev1.ContainerPort{ContainerPort: 9090, HostPort: 9090},
+			corev1.ContainerPort{ContainerPort: 80, HostPort: 8080},
+			corev1.ContainerPort{ContainerPort: 443}), "80,9090"},
+		{"merged with the annotation", hostPortPod(map[string]string{excludeAnnotation: "80,15000"},
+			corev1.ContainerPort{ContainerPort: 80, HostPort: 8080},
+			corev1.ContainerPort{ContainerPort: 9090, HostPort: 9090}), "80,15000,9090"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := withHostPortsExcluded(c.pod)
+			if c.want == "" {
+				if got != nil {
+					t.Fatalf("unexpected adjusted pod %v", got.Annotations)
+				}
+				return
+			}
+			if got == nil || got.Annotations[excludeAnnotation] != c.want {
+				t.Fatalf("got excluded ports %v, want %q", got, c.want)
+			}
+			if c.pod.Annotations[excludeAnnotation] == c.want {
+				t.Fatalf("original pod modified")
+			}
+		})
+	}
+}
+
+func TestHostNamespacePolicyValidation(t *testing.T) {
+	for _, p := range []HostNamespacePolicy{"", HostNamespaceInject, HostNamespaceSkip, HostNamespaceAdjust, HostNamespaceReject} {
+		if err := validateHostNamespacePolicy(p); err != nil {
+			t.Fatalf("policy %q rejected: %v", p, err)
+		}
+	}
+	if err := validateHostNamespacePolicy("ignore"); err == nil {
+		t.Fatalf("expected error for an unknown policy")
+	}
+}
+
+func TestPodHostNamespacePolicy(t *testing.T) {
+	cases := []struct {
+		policy HostNamespacePolicy
+		spec   corev1.PodSpec
+		want   HostNamespacePolicy
+	}{
+		{HostNamespaceAdjust, corev1.PodSpec{}, HostNamespaceAdjust},
+		{HostNamespaceAdjust, corev1.PodSpec{HostPID: true}, HostNamespaceSkip},
+		{HostNamespaceAdjust, corev1.PodSpec{HostIPC: true}, HostNamespaceSkip},
+		{HostNamespaceInject, corev1.PodSpec{HostPID: true}, HostNamespaceInject},
+		{HostNamespaceReject, corev1.PodSpec{HostIPC: true}, HostNamespaceReject},
+	}
+	for _, c := range cases {
+		if got := podHostNamespacePolicy(c.policy, &c.spec); got != c.want {
+			t.Errorf("podHostNamespacePolicy(%s, %+v) = %s, want %s", c.policy, c.spec, got, c.want)
+		}
+	}
+}
+
+func TestRecordHostNamespaceEvent(t *testing.T) {
+	r, recorded := newFakeEventRecorder()
+	deploy := &metav1.ObjectMeta{Name: "node-agent", Namespace: "default"}
+	recordHostNamespaceEvent(context.Background(), r, deploy,
+		&metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"}, []string{"hostPort", "hostPID"})
+	if len(recorded.events) != 1 || recorded.events[0].obj.Kind != "DaemonSet" || recorded.events[0].obj.Name != "node-agent" ||
+		recorded.events[0].message != "Sidecar injection skipped, the pod uses hostPort, hostPID" {
+		t.Fatalf("unexpected events %v", recorded.events)
+	}
+
+	// dry runs have no side effects
+	recordHostNamespaceEvent(withDryRun(context.Background(), true), r, deploy,
+		&metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"}, []string{"hostPort"})
+	if len(recorded.events) != 1 {
+		t.Fatalf("event recorded for a dry run: %v", recorded.events)
+	}
+}
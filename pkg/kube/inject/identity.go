@@ -0,0 +1,67 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/spiffe"
+)
+
+// WorkloadIdentityEnv is the proxy metadata holding the SPIFFE identity the
+// workload is expected to be issued.
+const WorkloadIdentityEnv = "ISTIO_META_WORKLOAD_IDENTITY"
+
+// trustDomainRegexp matches the characters allowed in a SPIFFE trust domain.
+var trustDomainRegexp = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// workloadIdentity returns the SPIFFE identity of a pod in the namespace
+// running as the service account, in the trust domain of the mesh.
+func workloadIdentity(mc *meshconfig.MeshConfig, namespace, serviceAccount string) (spiffe.Identity, error) {
+	trustDomain := mc.GetTrustDomain()
+	if trustDomain == "" {
+		trustDomain = constants.DefaultKubernetesDomain
+	}
+	// Same normalization as spiffe.SetTrustDomain
+	trustDomain = strings.Replace(trustDomain, "@", ".", -1)
+	if !trustDomainRegexp.MatchString(trustDomain) {
+		return spiffe.Identity{}, fmt.Errorf("mesh trust domain %q is not a valid SPIFFE trust domain", mc.GetTrustDomain())
+	}
+	if namespace == "" {
+		return spiffe.Identity{}, fmt.Errorf("cannot determine workload identity without a namespace")
+	}
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	return spiffe.Identity{
+		TrustDomain:    trustDomain,
+		Namespace:      namespace,
+		ServiceAccount: serviceAccount,
+	}, nil
+}
+
+// applyWorkloadIdentity renders the workload identity into the metadata of the proxy.
+func applyWorkloadIdentity(sidecar *corev1.Container, identity spiffe.Identity) {
+	if sidecar == nil {
+		return
+	}
+	updateClusterEnvs(sidecar, map[string]string{WorkloadIdentityEnv: identity.String()})
+}
@@ -0,0 +1,101 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+)
+
+func TestWorkloadIdentity(t *testing.T) {
+	cases := []struct {
+		name           string
+		trustDomain    string
+		namespace      string
+		serviceAccount string
+		want           string
+		wantErr        bool
+	}{
+		{
+			name:           "explicit",
+			trustDomain:    "example.com",
+			namespace:      "foo",
+			serviceAccount: "bar",
+			want:           "spiffe://example.com/ns/foo/sa/bar",
+		},
+		{
+			name:      "defaults",
+			namespace: "foo",
+			want:      "spiffe://cluster.local/ns/foo/sa/default",
+		},
+		{
+			name:           "normalized",
+			trustDomain:    "team@example.com",
+			namespace:      "foo",
+			serviceAccount: "bar",
+			want:           "spiffe://team.example.com/ns/foo/sa/bar",
+		},
+		{
+			name:        "invalid trust domain",
+			trustDomain: "Example.com/path",
+			namespace:   "foo",
+			wantErr:     true,
+		},
+		{
+			name:        "no namespace",
+			trustDomain: "example.com",
+			wantErr:     true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := workloadIdentity(&meshconfig.MeshConfig{TrustDomain: c.trustDomain}, c.namespace, c.serviceAccount)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if err == nil && got.String() != c.want {
+				t.Fatalf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestApplyWorkloadIdentity(t *testing.T) {
+	sidecar := &corev1.Container{
+		Name: ProxyContainerName,
+		Env:  []corev1.EnvVar{{Name: WorkloadIdentityEnv, Value: "stale"}, {Name: "FOO", Value: "bar"}},
+	}
+	identity, err := workloadIdentity(&meshconfig.MeshConfig{}, "foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyWorkloadIdentity(sidecar, identity)
+
+	found := 0
+	for _, e := range sidecar.Env {
+		if e.Name == WorkloadIdentityEnv {
+			found++
+			if e.Value != "spiffe://cluster.local/ns/foo/sa/bar" {
+				t.Fatalf("got identity %q", e.Value)
+			}
+		}
+	}
+	if found != 1 || len(sidecar.Env) != 2 {
+		t.Fatalf("unexpected env %v", sidecar.Env)
+	}
+}
@@ -17,25 +17,28 @@ package inject
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"os"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/docker/distribution/reference"
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/ghodss/yaml"
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 	"github.com/hashicorp/go-multierror"
+	lru "github.com/hashicorp/golang-lru"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/batch/v2alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -53,6 +56,7 @@ import (
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/config/mesh"
 	"istio.io/istio/pkg/config/validation"
+	"istio.io/istio/pkg/kube/inject/annotations"
 	"istio.io/istio/pkg/util/gogoprotomarshal"
 	"istio.io/pkg/log"
 )
@@ -65,37 +69,8 @@ var (
 		return nil
 	}
 
-	AnnotationValidation = map[string]annotationValidationFunc{
-		annotation.SidecarInject.Name:                             alwaysValidFunc,
-		annotation.SidecarStatus.Name:                             alwaysValidFunc,
-		annotation.SidecarRewriteAppHTTPProbers.Name:              alwaysValidFunc,
-		annotation.SidecarControlPlaneAuthPolicy.Name:             alwaysValidFunc,
-		annotation.SidecarDiscoveryAddress.Name:                   alwaysValidFunc,
-		annotation.SidecarProxyImage.Name:                         alwaysValidFunc,
-		annotation.SidecarProxyCPU.Name:                           alwaysValidFunc,
-		annotation.SidecarProxyMemory.Name:                        alwaysValidFunc,
-		annotation.SidecarInterceptionMode.Name:                   validateInterceptionMode,
-		annotation.SidecarBootstrapOverride.Name:                  alwaysValidFunc,
-		annotation.SidecarStatsInclusionPrefixes.Name:             alwaysValidFunc,
-		annotation.SidecarStatsInclusionSuffixes.Name:             alwaysValidFunc,
-		annotation.SidecarStatsInclusionRegexps.Name:              alwaysValidFunc,
-		annotation.SidecarUserVolume.Name:                         alwaysValidFunc,
-		annotation.SidecarUserVolumeMount.Name:                    alwaysValidFunc,
-		annotation.SidecarEnableCoreDump.Name:                     validateBool,
-		annotation.SidecarStatusPort.Name:                         validateStatusPort,
-		annotation.SidecarStatusReadinessInitialDelaySeconds.Name: validateUInt32,
-		annotation.SidecarStatusReadinessPeriodSeconds.Name:       validateUInt32,
-		annotation.SidecarStatusReadinessFailureThreshold.Name:    validateUInt32,
-		annotation.SidecarTrafficIncludeOutboundIPRanges.Name:     ValidateIncludeIPRanges,
-		annotation.SidecarTrafficExcludeOutboundIPRanges.Name:     ValidateExcludeIPRanges,
-		annotation.SidecarTrafficIncludeInboundPorts.Name:         ValidateIncludeInboundPorts,
-		annotation.SidecarTrafficExcludeInboundPorts.Name:         ValidateExcludeInboundPorts,
-		annotation.SidecarTrafficExcludeOutboundPorts.Name:        ValidateExcludeOutboundPorts,
-		annotation.SidecarTrafficKubevirtInterfaces.Name:          alwaysValidFunc,
-		annotation.PrometheusMergeMetrics.Name:                    validateBool,
-		annotation.ProxyConfig.Name:                               validateProxyConfig,
-		"k8s.v1.cni.cncf.io/networks":                             alwaysValidFunc,
-	}
+	// AnnotationValidation maps the annotations honored by the injector to their validation function.
+	AnnotationValidation = buildAnnotationValidation(injectAnnotations)
 )
 
 func validateProxyConfig(value string) error {
@@ -106,8 +81,24 @@ func validateProxyConfig(value string) error {
 	return validation.ValidateProxyConfig(&config)
 }
 
-func validateAnnotations(annotations map[string]string) (err error) {
-	for name, value := range annotations {
+// maxLoggedDeprecations bounds the number of workload annotations whose
+// deprecation is remembered as logged.
+const maxLoggedDeprecations = 1024
+
+// loggedDeprecations holds the deprecated annotations of workloads already
+// logged, so that the warning is logged once per workload rather than for
+// each of its admissions.
+var loggedDeprecations, _ = lru.New(maxLoggedDeprecations)
+
+// validateAnnotations validates the annotations of a pod of the workload, a
+// key identifying the workload in the deprecation warnings.
+func validateAnnotations(workload string, annos map[string]string) (err error) {
+	for _, name := range annotations.Deprecated(annos) {
+		if seen, _ := loggedDeprecations.ContainsOrAdd(workload+"/"+name, true); !seen {
+			log.Warnf("Annotation '%s' of %s is deprecated", name, workload)
+		}
+	}
+	for name, value := range annos {
 		if v, ok := AnnotationValidation[name]; ok {
 			if e := v(value); e != nil {
 				err = multierror.Append(err, fmt.Errorf("invalid value '%s' for annotation '%s': %v", value, name, e))
@@ -146,6 +137,10 @@ const (
 	// ValidationContainerName is the name of the init container that validates
 	// if CNI has made the necessary changes to iptables
 	ValidationContainerName = "istio-validation"
+
+	// InitContainerName is the name of the init container that sets up the
+	// iptables redirection of the pod traffic
+	InitContainerName = "istio-init"
 )
 
 // SidecarInjectionSpec collects all container types and volumes for
@@ -161,6 +156,29 @@ type SidecarInjectionSpec struct {
 	Volumes                         []corev1.Volume               `yaml:"volumes"`
 	DNSConfig                       *corev1.PodDNSConfig          `yaml:"dnsConfig"`
 	ImagePullSecrets                []corev1.LocalObjectReference `yaml:"imagePullSecrets"`
+	// AppEnv is added to the environment of the application containers.
+	AppEnv []corev1.EnvVar `yaml:"-" json:"-"`
+	// Annotations are added to the pod, e.g. replacing fields the Kubernetes version does not support.
+	Annotations map[string]string `yaml:"-" json:"-"`
+	// NativeSidecar adds the proxy as a native sidecar, an init container restarted always.
+	NativeSidecar bool `yaml:"-" json:"-"`
+}
+
+// podContainers returns the injected init containers and containers as they
+// are added to the pod, with a native sidecar proxy added as an init container.
+func (s *SidecarInjectionSpec) podContainers() (initContainers, containers []corev1.Container) {
+	if !s.NativeSidecar {
+		return s.InitContainers, s.Containers
+	}
+	initContainers = append([]corev1.Container{}, s.InitContainers...)
+	for _, c := range s.Containers {
+		if c.Name == ProxyContainerName {
+			initContainers = append(initContainers, c)
+		} else {
+			containers = append(containers, c)
+		}
+	}
+	return initContainers, containers
 }
 
 // SidecarTemplateData is the data object to which the templated
@@ -199,48 +217,102 @@ type Config struct {
 	// InjectedAnnotations are additional annotations that will be added to the pod spec after injection
 	// This is primarily to support PSP annotations.
 	InjectedAnnotations map[string]string `json:"injectedAnnotations"`
-}
-
-func validateCIDRList(cidrs string) error {
-	if len(cidrs) > 0 {
-		for _, cidr := range strings.Split(cidrs, ",") {
-			if _, _, err := net.ParseCIDR(cidr); err != nil {
-				return fmt.Errorf("failed parsing cidr '%s': %v", cidr, err)
-			}
-		}
-	}
-	return nil
-}
-
-func splitPorts(portsString string) []string {
-	return strings.Split(portsString, ",")
-}
 
-func parsePort(portStr string) (int, error) {
-	port, err := strconv.ParseUint(strings.TrimSpace(portStr), 10, 16)
-	if err != nil {
-		return 0, fmt.Errorf("failed parsing port '%d': %v", port, err)
-	}
-	return int(port), nil
-}
-
-func parsePorts(portsString string) ([]int, error) {
-	portsString = strings.TrimSpace(portsString)
-	ports := make([]int, 0)
-	if len(portsString) > 0 {
-		for _, portStr := range splitPorts(portsString) {
-			port, err := parsePort(portStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed parsing port '%d': %v", port, err)
-			}
-			ports = append(ports, port)
-		}
-	}
-	return ports, nil
+	// ServiceAccountPolicies restricts injection to pods running as, or not
+	// running as, the given service accounts. Keyed by namespace; the "*" key
+	// applies to namespaces without their own entry.
+	ServiceAccountPolicies map[string]ServiceAccountPolicy `json:"serviceAccountPolicies,omitempty"`
+
+	// CompatibilityProfile restricts the mutations applied to pods to those
+	// allowed by the platform, e.g. ManagedCompatibilityProfile.
+	CompatibilityProfile string `json:"compatibilityProfile,omitempty"`
+
+	// DeclareStatusPort adds the agent's merged health and stats port to the
+	// ports of the proxy container, so it can be addressed by name.
+	DeclareStatusPort bool `json:"declareStatusPort,omitempty"`
+
+	// StatusPortRange, if set, moves the status port of pods whose containers
+	// declare it to a free port of the range, instead of rejecting them. The
+	// other proxy ports are fixed, so collisions with them are still rejected.
+	StatusPortRange *PortRange `json:"statusPortRange,omitempty"`
+
+	// PortCollisionPolicy selects how pods whose application containers
+	// declare a port of the proxy are handled. Defaults to PortCollisionWarn.
+	PortCollisionPolicy PortCollisionPolicy `json:"portCollisionPolicy,omitempty"`
+
+	// NamespaceInjectionQuota is the number of injected pods allowed in
+	// namespaces without an InjectionQuotaAnnotation. Zero means unlimited.
+	NamespaceInjectionQuota int `json:"namespaceInjectionQuota,omitempty"`
+
+	// LimitRangeAware adjusts the resources of the injected containers to the
+	// container LimitRanges of the namespace, so the pod is neither rejected
+	// nor given defaulted resources inconsistent with the ones requested.
+	LimitRangeAware bool `json:"limitRangeAware,omitempty"`
+
+	// ProxyPriority checks the resources of the proxy against the priority
+	// class and QoS class of the pod.
+	ProxyPriority *ProxyPriorityPolicy `json:"proxyPriority,omitempty"`
+
+	// VerticalPodAutoscaler, if set, annotates injected pods with the
+	// VerticalPodAutoscaler resource policy recommended for the proxy.
+	VerticalPodAutoscaler *VPAPolicy `json:"verticalPodAutoscaler,omitempty"`
+
+	// WorkloadIdentity renders the SPIFFE identity expected for the workload
+	// into the proxy metadata, rejecting pods whose identity cannot be formed.
+	WorkloadIdentity bool `json:"workloadIdentity,omitempty"`
+
+	// EgressGateways is the egress gateway preferred by the workloads of each
+	// zone, with the "*" key for the others. It is rendered into the proxy
+	// metadata so routing can select the gateway of the zone declaratively.
+	EgressGateways map[string]string `json:"egressGateways,omitempty"`
+
+	// StatsInclusion selects the Envoy stats included by injected proxies,
+	// from presets, for pods without StatsInclusionAnnotation.
+	StatsInclusion *StatsInclusionConfig `json:"statsInclusion,omitempty"`
+
+	// MetadataPropagation limits the pod labels and annotations propagated
+	// into the metadata of injected proxies. All are propagated if unset.
+	MetadataPropagation *MetadataPropagationConfig `json:"metadataPropagation,omitempty"`
+
+	// TrustedProxies configures X-Forwarded-For and client certificate forwarding
+	// handling for injected proxies, for workloads behind L7 load balancers.
+	TrustedProxies *TrustedProxiesConfig `json:"trustedProxies,omitempty"`
+
+	// NamespaceTrustedProxies overrides TrustedProxies for the given namespaces.
+	NamespaceTrustedProxies map[string]TrustedProxiesConfig `json:"namespaceTrustedProxies,omitempty"`
+
+	// FreezeWindows are recurring periods during which the configuration in
+	// use is kept and reloads are refused, e.g. to enforce change freezes.
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+
+	// ProxyVersionSkew bounds the skew between the injector version and the
+	// version of the proxy image requested by the values.
+	ProxyVersionSkew *ProxyVersionSkewPolicy `json:"proxyVersionSkew,omitempty"`
+
+	// HostNamespacePolicy selects how pods using hostPort, hostPID or hostIPC
+	// are handled. Defaults to HostNamespaceInject.
+	HostNamespacePolicy HostNamespacePolicy `json:"hostNamespacePolicy,omitempty"`
+
+	// NativeSidecar runs the proxy as a Kubernetes native sidecar, an init
+	// container restarted always, on clusters supporting them, so a Job
+	// completes once its application exits. Other clusters get the proxy as a
+	// regular container.
+	NativeSidecar bool `json:"nativeSidecar,omitempty"`
+
+	// AppProxy injects the HTTP_PROXY, HTTPS_PROXY and NO_PROXY settings of a
+	// corporate proxy into the application containers. Namespaces override it
+	// with AppProxyAnnotation.
+	AppProxy *AppProxyConfig `json:"appProxy,omitempty"`
+
+	// PodValuesAllowlist are the values, as dot separated paths such as
+	// global.proxy.resources, the ValuesAnnotation of pods and of their
+	// workloads may set. The other values of the annotation are ignored, all
+	// of them if unset.
+	PodValuesAllowlist []string `json:"podValuesAllowlist,omitempty"`
 }
 
 func validatePortList(parameterName, ports string) error {
-	if _, err := parsePorts(ports); err != nil {
+	if _, err := annotations.ParsePortList(ports); err != nil {
 		return fmt.Errorf("%s invalid: %v", parameterName, err)
 	}
 	return nil
@@ -261,7 +333,7 @@ func validateInterceptionMode(mode string) error {
 // ValidateIncludeIPRanges validates the includeIPRanges parameter
 func ValidateIncludeIPRanges(ipRanges string) error {
 	if ipRanges != "*" {
-		if e := validateCIDRList(ipRanges); e != nil {
+		if _, e := annotations.ParseCIDRList(ipRanges); e != nil {
 			return fmt.Errorf("includeIPRanges invalid: %v", e)
 		}
 	}
@@ -270,7 +342,7 @@ func ValidateIncludeIPRanges(ipRanges string) error {
 
 // ValidateExcludeIPRanges validates the excludeIPRanges parameter
 func ValidateExcludeIPRanges(ipRanges string) error {
-	if e := validateCIDRList(ipRanges); e != nil {
+	if _, e := annotations.ParseCIDRList(ipRanges); e != nil {
 		return fmt.Errorf("excludeIPRanges invalid: %v", e)
 	}
 	return nil
@@ -296,21 +368,33 @@ func ValidateExcludeOutboundPorts(ports string) error {
 
 // validateStatusPort validates the statusPort parameter
 func validateStatusPort(port string) error {
-	if _, e := parsePort(port); e != nil {
-		return fmt.Errorf("excludeInboundPorts invalid: %v", e)
+	if _, e := annotations.ParsePort(port); e != nil {
+		return fmt.Errorf("statusPort invalid: %v", e)
+	}
+	return nil
+}
+
+// validateProxyImage validates the proxyImage annotation. The template only
+// honors full references, anything else would silently run the default image.
+func validateProxyImage(image string) error {
+	if !strings.Contains(image, "/") {
+		return fmt.Errorf("proxyImage invalid: %q is not a full image reference, e.g. docker.io/istio/proxyv2:1.8.0", image)
+	}
+	if _, err := reference.Parse(image); err != nil {
+		return fmt.Errorf("proxyImage invalid: %v", err)
 	}
 	return nil
 }
 
 // validateUInt32 validates that the given annotation value is a positive integer.
 func validateUInt32(value string) error {
-	_, err := strconv.ParseUint(value, 10, 32)
+	_, err := annotations.ParseUInt32(value)
 	return err
 }
 
 // validateBool validates that the given annotation value is a boolean.
 func validateBool(value string) error {
-	_, err := strconv.ParseBool(value)
+	_, err := annotations.ParseBool(value)
 	return err
 }
 
@@ -332,6 +416,12 @@ func injectRequired(ignored []string, config *Config, podSpec *corev1.PodSpec, m
 		}
 	}
 
+	if !serviceAccountAllowed(config, metadata.Namespace, podSpec.ServiceAccountName) {
+		log.Debugf("Skipping injection for pod %s/%s due to service account %q not allowed by policy",
+			metadata.Namespace, potentialPodName(metadata), podSpec.ServiceAccountName)
+		return false
+	}
+
 	annos := metadata.GetAnnotations()
 	if annos == nil {
 		annos = map[string]string{}
@@ -458,11 +548,26 @@ func InjectionData(params InjectionParameters, typeMetadata *metav1.TypeMeta, de
 			metadata.Namespace+"/"+podName, corev1.DNSClusterFirst)
 	}
 
-	if err := validateAnnotations(metadata.GetAnnotations()); err != nil {
+	workload := metadata.Namespace + "/" + potentialPodName(metadata)
+	if typeMetadata != nil && deploymentMetadata != nil && deploymentMetadata != metadata {
+		workload = metadata.Namespace + "/" + typeMetadata.Kind + "/" + deploymentMetadata.Name
+	}
+	if err := validateAnnotations(workload, metadata.GetAnnotations()); err != nil {
+		log.Errorf("Injection failed due to invalid annotations: %v", err)
+		return nil, "", err
+	}
+	if err := validateProxyResources(metadata.GetAnnotations()); err != nil {
 		log.Errorf("Injection failed due to invalid annotations: %v", err)
 		return nil, "", err
 	}
 
+	valuesConfig, err := inheritedValues(params)
+	if err != nil {
+		log.Errorf("Injection failed due to invalid values: %v", err)
+		return nil, "", err
+	}
+	params.valuesConfig = valuesConfig
+
 	if pca, f := metadata.GetAnnotations()[annotation.ProxyConfig.Name]; f {
 		var merr error
 		meshConfig, merr = mesh.ApplyProxyConfig(pca, *meshConfig)
@@ -471,6 +576,12 @@ func InjectionData(params InjectionParameters, typeMetadata *metav1.TypeMeta, de
 		}
 	}
 
+	statusPort := podStatusPort(metadata.GetAnnotations(), meshConfig.GetDefaultConfig().GetStatusPort())
+	if err := checkPortCollisions(params.portCollisionPolicy, params.pod, statusPort); err != nil {
+		log.Errorf("Injection failed due to port collisions: %v", err)
+		return nil, "", err
+	}
+
 	valuesStruct := &opconfig.Values{}
 	if err := gogoprotomarshal.ApplyYAML(params.valuesConfig, valuesStruct); err != nil {
 		log.Infof("Failed to parse values config: %v [%v]\n", err, params.valuesConfig)
@@ -515,6 +626,108 @@ func InjectionData(params InjectionParameters, typeMetadata *metav1.TypeMeta, de
 		Values:         values,
 	}
 
+	funcMap := templateFuncMap(data)
+
+	t, err := parsedTemplates.get(params.template, funcMap)
+	if err != nil {
+		log.Infof("Failed to parse template: %v %v\n", err, params.template)
+		return nil, "", err
+	}
+	var bbuf bytes.Buffer
+	if err := t.Execute(&bbuf, &data); err != nil {
+		templateRenderFailures.Increment()
+		log.Infof("Invalid template: %v %v\n", err, params.template)
+		return nil, "", err
+	}
+
+	var sic SidecarInjectionSpec
+	if err := yaml.Unmarshal(bbuf.Bytes(), &sic); err != nil {
+		// This usually means an invalid injector template; we can't check
+		// the template itself because it is merely a string.
+		templateRenderFailures.Increment()
+		log.Warnf("Failed to unmarshal template: %v\n %s", err, bbuf.String())
+		return nil, "", multierror.Prefix(err, "failed parsing generated injected YAML (check Istio sidecar injector configuration):")
+	}
+
+	// set sidecar --concurrency
+	applyConcurrency(sic.Containers)
+	applyProxyPriority(params.proxyPriority, spec, FindSidecar(sic.Containers))
+	applyLimitRanges(sic.InitContainers, params.limitRanges)
+	applyLimitRanges(sic.Containers, params.limitRanges)
+	overwriteClusterInfo(sic.Containers, params)
+	if params.declareStatusPort {
+		declareStatusPort(FindSidecar(sic.Containers), statusPort)
+	}
+	if params.workloadIdentity {
+		identity, err := workloadIdentity(meshConfig, metadata.Namespace, spec.ServiceAccountName)
+		if err != nil {
+			log.Errorf("Injection failed: %v", err)
+			return nil, "", err
+		}
+		applyWorkloadIdentity(FindSidecar(sic.Containers), identity)
+	}
+	applyEgressGateways(FindSidecar(sic.Containers), params.egressGateways, podZone(spec))
+	appEnv, err := appProxyEnvs(namespaceAppProxy(params.appProxy, params.namespaceAppProxy), podZone(spec),
+		appProxyTemplateData{Namespace: metadata.Namespace, ClusterDomain: valuesStruct.GetGlobal().GetProxy().GetClusterDomain()})
+	if err != nil {
+		log.Errorf("Injection failed: %v", err)
+		return nil, "", err
+	}
+	sic.AppEnv = appEnv
+	workloadKind := ""
+	if typeMetadata != nil {
+		workloadKind = typeMetadata.Kind
+	}
+	if err := applyStatsInclusion(FindSidecar(sic.Containers),
+		statsInclusionPreset(params.statsInclusion, metadata.Annotations, workloadKind)); err != nil {
+		log.Errorf("Injection failed: %v", err)
+		return nil, "", err
+	}
+	if err := applyMetadataPropagation(FindSidecar(sic.Containers), params.metadataPropagation,
+		metadata.Labels, metadata.Annotations); err != nil {
+		log.Errorf("Injection failed: %v", err)
+		return nil, "", err
+	}
+	if err := checkCompatibilityProfile(params.compatibilityProfile, &sic); err != nil {
+		log.Errorf("Injection failed: %v", err)
+		return nil, "", err
+	}
+	sic.Annotations = applyKubernetesCapabilities(params.kubernetes, &sic)
+	sic.NativeSidecar = params.nativeSidecar && params.kubernetes != nil && params.kubernetes.NativeSidecars
+	if err := validateDNSCapture(spec, &sic); err != nil {
+		log.Errorf("Injection failed: %v", err)
+		return nil, "", err
+	}
+
+	status := &SidecarInjectionStatus{Version: params.version, Revision: params.revision}
+	initContainers, containers := sic.podContainers()
+	for _, c := range initContainers {
+		status.InitContainers = append(status.InitContainers, c.Name)
+	}
+	for _, c := range containers {
+		status.Containers = append(status.Containers, c.Name)
+	}
+	for _, c := range sic.Volumes {
+		status.Volumes = append(status.Volumes, c.Name)
+	}
+	for _, c := range sic.ImagePullSecrets {
+		status.ImagePullSecrets = append(status.ImagePullSecrets, c.Name)
+	}
+	if _, f := metadata.Annotations[annotation.SidecarProxyImage.Name]; f {
+		if proxy := findContainer(sic.Containers, ProxyContainerName); proxy != nil {
+			status.ProxyImage = proxy.Image
+		}
+	}
+	statusAnnotationValue, err := json.Marshal(status)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoded injection status: %v", err)
+	}
+	sic.HoldApplicationUntilProxyStarts, _, _ = unstructured.NestedBool(data.Values, "global", "proxy", "holdApplicationUntilProxyStarts")
+	return &sic, string(statusAnnotationValue), nil
+}
+
+// templateFuncMap returns the functions available to the injection template rendering data.
+func templateFuncMap(data SidecarTemplateData) template.FuncMap {
 	funcMap := template.FuncMap{
 		"formatDuration":      formatDuration,
 		"isset":               isset,
@@ -557,43 +770,13 @@ func InjectionData(params InjectionParameters, typeMetadata *metav1.TypeMeta, de
 
 		return bbuf.String()
 	}
+	return funcMap
+}
 
-	bbuf, err := parseTemplate(params.template, funcMap, data)
-	if err != nil {
-		return nil, "", err
-	}
-
-	var sic SidecarInjectionSpec
-	if err := yaml.Unmarshal(bbuf.Bytes(), &sic); err != nil {
-		// This usually means an invalid injector template; we can't check
-		// the template itself because it is merely a string.
-		log.Warnf("Failed to unmarshal template: %v\n %s", err, bbuf.String())
-		return nil, "", multierror.Prefix(err, "failed parsing generated injected YAML (check Istio sidecar injector configuration):")
-	}
-
-	// set sidecar --concurrency
-	applyConcurrency(sic.Containers)
-	overwriteClusterInfo(sic.Containers, params)
-
-	status := &SidecarInjectionStatus{Version: params.version}
-	for _, c := range sic.InitContainers {
-		status.InitContainers = append(status.InitContainers, c.Name)
-	}
-	for _, c := range sic.Containers {
-		status.Containers = append(status.Containers, c.Name)
-	}
-	for _, c := range sic.Volumes {
-		status.Volumes = append(status.Volumes, c.Name)
-	}
-	for _, c := range sic.ImagePullSecrets {
-		status.ImagePullSecrets = append(status.ImagePullSecrets, c.Name)
-	}
-	statusAnnotationValue, err := json.Marshal(status)
-	if err != nil {
-		return nil, "", fmt.Errorf("error encoded injection status: %v", err)
-	}
-	sic.HoldApplicationUntilProxyStarts, _, _ = unstructured.NestedBool(data.Values, "global", "proxy", "holdApplicationUntilProxyStarts")
-	return &sic, string(statusAnnotationValue), nil
+// TemplateFuncs returns the functions available to injection templates, for
+// documentation generators.
+func TemplateFuncs() template.FuncMap {
+	return templateFuncMap(SidecarTemplateData{})
 }
 
 func parseTemplate(tmplStr string, funcMap map[string]interface{}, data SidecarTemplateData) (bytes.Buffer, error) {
@@ -616,6 +799,21 @@ func parseTemplate(tmplStr string, funcMap map[string]interface{}, data SidecarT
 // kubernetes YAML file.
 // nolint: lll
 func IntoResourceFile(sidecarTemplate string, valuesConfig string, revision string, meshconfig *meshconfig.MeshConfig, in io.Reader, out io.Writer, warningHandler func(string)) error {
+	return IntoResourceFileForKubernetes(nil, sidecarTemplate, valuesConfig, revision, meshconfig, in, out, warningHandler)
+}
+
+// IntoResourceFileForKubernetes injects the istio proxy into the specified
+// kubernetes YAML file, rendered for the capabilities of a Kubernetes version.
+// nolint: lll
+func IntoResourceFileForKubernetes(kubernetes *KubernetesCapabilities, sidecarTemplate string, valuesConfig string, revision string, meshconfig *meshconfig.MeshConfig, in io.Reader, out io.Writer, warningHandler func(string)) error {
+	return IntoResourceFileWithConfig(kubernetes, &Config{Template: sidecarTemplate}, valuesConfig, revision, meshconfig, in, out, warningHandler)
+}
+
+// IntoResourceFileWithConfig injects the istio proxy into the specified
+// kubernetes YAML file with the settings of the injection config, e.g. the
+// application proxy environment, as the webhook does.
+// nolint: lll
+func IntoResourceFileWithConfig(kubernetes *KubernetesCapabilities, c *Config, valuesConfig string, revision string, meshconfig *meshconfig.MeshConfig, in io.Reader, out io.Writer, warningHandler func(string)) error {
 	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(in, 4096))
 	for {
 		raw, err := reader.Read()
@@ -633,7 +831,7 @@ func IntoResourceFile(sidecarTemplate string, valuesConfig string, revision stri
 
 		var updated []byte
 		if err == nil {
-			outObject, err := IntoObject(sidecarTemplate, valuesConfig, revision, meshconfig, obj, warningHandler) // nolint: vetshadow
+			outObject, err := intoObject(kubernetes, c, valuesConfig, revision, meshconfig, obj, warningHandler) // nolint: vetshadow
 			if err != nil {
 				return err
 			}
@@ -676,6 +874,18 @@ func FromRawToObject(raw []byte) (runtime.Object, error) {
 // IntoObject convert the incoming resources into Injected resources
 // nolint: lll
 func IntoObject(sidecarTemplate string, valuesConfig string, revision string, meshconfig *meshconfig.MeshConfig, in runtime.Object, warningHandler func(string)) (interface{}, error) {
+	return intoObject(nil, &Config{Template: sidecarTemplate}, valuesConfig, revision, meshconfig, in, warningHandler)
+}
+
+// IntoObjectWithConfig converts the incoming resources into injected resources with the settings of the
+// whole injection config, as the webhook injects them, rather than the template alone.
+// nolint: lll
+func IntoObjectWithConfig(c *Config, valuesConfig string, revision string, meshconfig *meshconfig.MeshConfig, in runtime.Object, warningHandler func(string)) (interface{}, error) {
+	return intoObject(nil, c, valuesConfig, revision, meshconfig, in, warningHandler)
+}
+
+// nolint: lll
+func intoObject(kubernetes *KubernetesCapabilities, c *Config, valuesConfig string, revision string, meshconfig *meshconfig.MeshConfig, in runtime.Object, warningHandler func(string)) (interface{}, error) {
 	out := in.DeepCopyObject()
 
 	var deploymentMetadata *metav1.ObjectMeta
@@ -696,7 +906,7 @@ func IntoObject(sidecarTemplate string, valuesConfig string, revision string, me
 				return nil, err
 			}
 
-			r, err := IntoObject(sidecarTemplate, valuesConfig, revision, meshconfig, obj, warningHandler) // nolint: vetshadow
+			r, err := intoObject(kubernetes, c, valuesConfig, revision, meshconfig, obj, warningHandler) // nolint: vetshadow
 			if err != nil {
 				return nil, err
 			}
@@ -785,19 +995,25 @@ func IntoObject(sidecarTemplate string, valuesConfig string, revision string, me
 		warningHandler(fmt.Sprintf("===> Skipping injection because %q has sidecar injection disabled\n", name))
 		return out, nil
 	}
+	// the workload overlay is read from the workload injected, a pod has none
+	workloadValues := ""
+	if deploymentMetadata != metadata {
+		workloadValues = deploymentMetadata.Annotations[ValuesAnnotation]
+	}
+	// the same injection data as the webhook, without the lookups of the namespace
 	params := InjectionParameters{
-		pod:                 pod,
-		deployMeta:          deploymentMetadata,
-		typeMeta:            typeMeta,
-		template:            sidecarTemplate,
-		version:             sidecarTemplateVersionHash(sidecarTemplate),
-		meshConfig:          meshconfig,
-		valuesConfig:        valuesConfig,
-		revision:            revision,
-		proxyEnvs:           map[string]string{},
-		injectedAnnotations: nil,
-	}
-	patchBytes, err := injectPod(params)
+		pod:            pod,
+		deployMeta:     deploymentMetadata,
+		typeMeta:       typeMeta,
+		revision:       revision,
+		proxyEnvs:      map[string]string{},
+		workloadValues: workloadValues,
+		kubernetes:     kubernetes,
+	}.withConfig(c, valuesConfig, sidecarTemplateVersionHash(c.Template), meshconfig)
+	// the patched pod is decoded into API types without the restartPolicy of
+	// containers, so the proxy is kept a regular container
+	params.nativeSidecar = false
+	patchBytes, err := injectPod(context.Background(), params)
 	if err != nil {
 		return nil, err
 	}
@@ -1033,7 +1249,7 @@ func excludeInboundPort(port interface{}, excludedInboundPorts string) string {
 	}
 
 	// Exclude the readiness port if not already excluded.
-	ports := splitPorts(excludedInboundPorts)
+	ports := annotations.SplitList(excludedInboundPorts)
 	outPorts := make([]string, 0, len(ports))
 	for _, port := range ports {
 		if port == portStr {
@@ -1067,6 +1283,16 @@ type SidecarInjectionStatus struct {
 	Containers       []string `json:"containers"`
 	Volumes          []string `json:"volumes"`
 	ImagePullSecrets []string `json:"imagePullSecrets"`
+
+	// Revision of the injector, empty for the default revision.
+	Revision string `json:"revision,omitempty"`
+
+	// ProxyImage is the image of the proxy set by the proxyImage annotation.
+	ProxyImage string `json:"proxyImage,omitempty"`
+
+	// Ref references the full status stored in the StatusConfigMapName
+	// ConfigMap, when it does not fit in the annotations of the pod.
+	Ref string `json:"ref,omitempty"`
 }
 
 // helper function to generate a template version identifier from a
@@ -1108,8 +1334,14 @@ func updateClusterEnvs(container *corev1.Container, newKVs map[string]string) {
 			envVars = append(envVars, env)
 		}
 	}
-	for k, v := range newKVs {
-		envVars = append(envVars, corev1.EnvVar{Name: k, Value: v, ValueFrom: nil})
+	// in the order of the keys, so the patch is the same for every admission
+	keys := make([]string, 0, len(newKVs))
+	for k := range newKVs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		envVars = append(envVars, corev1.EnvVar{Name: k, Value: newKVs[k], ValueFrom: nil})
 	}
 
 	container.Env = envVars
@@ -301,7 +301,7 @@ func TestInjection(t *testing.T) {
 			// First we test kube-inject. This will run exactly what kube-inject does, and write output to the golden files
 			t.Run("kube-inject", func(t *testing.T) {
 				var got bytes.Buffer
-				if err = IntoResourceFile(sidecarTemplate.Template, valuesConfig, "", mc, in, &got, nullWarningHandler); err != nil {
+				if err = IntoResourceFileWithConfig(nil, sidecarTemplate, valuesConfig, "", mc, in, &got, nullWarningHandler); err != nil {
 					if c.expectedError != "" {
 						if !strings.Contains(strings.ToLower(err.Error()), c.expectedError) {
 							t.Fatalf("expected error %q got %q", c.expectedError, err)
@@ -586,3 +586,24 @@ func TestAppendMultusNetwork(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateProxyImage(t *testing.T) {
+	cases := []struct {
+		image   string
+		wantErr bool
+	}{
+		{"docker.io/istio/proxyv2:1.8.0", false},
+		{"gcr.io/istio-testing/proxyv2@sha256:" + strings.Repeat("a", 64), false},
+		{"proxyv2:debug", true},
+		{"docker.io/istio/Proxyv2", true},
+		{"docker.io/istio/proxyv2:", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.image, func(t *testing.T) {
+			err := validateProxyImage(tc.image)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateProxyImage(%q) got err %v, wantErr %v", tc.image, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// eventRecorder records the events of the injector on the workloads and
+// namespaces of the admitted pods. The events are sent in the background, off
+// the admission path, and the similar ones are aggregated so a controller
+// creating pods in a loop does not flood the API server.
+type eventRecorder struct {
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
+}
+
+func newEventRecorder(client kubernetes.Interface) *eventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	return &eventRecorder{
+		broadcaster: broadcaster,
+		recorder:    broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "sidecar-injector"}),
+	}
+}
+
+// warn records a warning event on the object, unless the admission is a dry
+// run, which must have no side effects.
+func (r *eventRecorder) warn(ctx context.Context, obj *corev1.ObjectReference, reason, format string, args ...interface{}) {
+	if r == nil || isDryRun(ctx) {
+		return
+	}
+	r.recorder.Eventf(obj, corev1.EventTypeWarning, reason, format, args...)
+}
+
+// close stops sending the events.
+func (r *eventRecorder) close() {
+	if r == nil || r.broadcaster == nil {
+		return
+	}
+	r.broadcaster.Shutdown()
+}
+
+// namespaceReference returns the reference of a namespace, with its own name
+// as namespace so its events are recorded in it rather than in default.
+func namespaceReference(namespace string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{Kind: "Namespace", APIVersion: "v1", Name: namespace, Namespace: namespace}
+}
+
+type dryRunKey struct{}
+
+// withDryRun marks the context of a dry run admission.
+func withDryRun(ctx context.Context, dryRun bool) context.Context {
+	if !dryRun {
+		return ctx
+	}
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// isDryRun reports whether the context is of a dry run admission.
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
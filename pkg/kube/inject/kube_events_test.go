@@ -0,0 +1,101 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeEvent struct {
+	obj     *corev1.ObjectReference
+	reason  string
+	message string
+}
+
+// fakeEventRecorder keeps the events with the objects they are recorded on.
+type fakeEventRecorder struct {
+	events []fakeEvent
+}
+
+func (r *fakeEventRecorder) Event(obj runtime.Object, _, reason, message string) {
+	r.events = append(r.events, fakeEvent{obj: obj.(*corev1.ObjectReference), reason: reason, message: message})
+}
+
+func (r *fakeEventRecorder) Eventf(obj runtime.Object, eventtype, reason, format string, args ...interface{}) {
+	r.Event(obj, eventtype, reason, fmt.Sprintf(format, args...))
+}
+
+func (r *fakeEventRecorder) AnnotatedEventf(obj runtime.Object, _ map[string]string, eventtype, reason, format string,
+	args ...interface{}) {
+	r.Eventf(obj, eventtype, reason, format, args...)
+}
+
+func newFakeEventRecorder() (*eventRecorder, *fakeEventRecorder) {
+	recorded := &fakeEventRecorder{}
+	return &eventRecorder{recorder: recorded}, recorded
+}
+
+func TestEventRecorder(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	r := newEventRecorder(client)
+	defer r.close()
+
+	r.warn(context.Background(), namespaceReference("foo"), "InjectionQuotaExceeded", "quota of %d", 3)
+	// the events are sent in the background
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		events, err := client.CoreV1().Events("foo").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events.Items) == 1 {
+			e := events.Items[0]
+			if e.InvolvedObject.Kind != "Namespace" || e.InvolvedObject.Name != "foo" || e.Message != "quota of 3" ||
+				e.Source.Component != "sidecar-injector" {
+				t.Fatalf("unexpected event %v", e)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got events %v, want one in the namespace", events.Items)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// nil without a Kubernetes client
+	var disabled *eventRecorder
+	disabled.warn(context.Background(), namespaceReference("foo"), "InjectionQuotaExceeded", "ignored")
+	disabled.close()
+}
+
+func TestEventRecorderDryRun(t *testing.T) {
+	r, recorded := newFakeEventRecorder()
+	r.warn(withDryRun(context.Background(), true), namespaceReference("foo"), "InjectionFailed", "dry run")
+	if len(recorded.events) != 0 {
+		t.Fatalf("got events %v for a dry run", recorded.events)
+	}
+	r.warn(withDryRun(context.Background(), false), namespaceReference("foo"), "InjectionFailed", "admission")
+	if len(recorded.events) != 1 {
+		t.Fatalf("got events %v, want one", recorded.events)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// seccompContainerAnnotationPrefix is the annotation setting the seccomp
+	// profile of a container before securityContext.seccompProfile.
+	seccompContainerAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+
+	// kubernetesDiscoveryPeriod is how often the capabilities of the cluster
+	// are discovered again, so upgrades of the cluster are followed.
+	kubernetesDiscoveryPeriod = 10 * time.Minute
+)
+
+var kubernetesVersionRE = regexp.MustCompile(`^\s*v?([0-9]+)\.([0-9]+)`)
+
+// KubernetesCapabilities are the version dependent features of Kubernetes
+// the injected containers are rendered with. Unknown versions are rendered
+// as the template is, without adjustments.
+type KubernetesCapabilities struct {
+	// Version is the major.minor Kubernetes version.
+	Version string `json:"version"`
+
+	// SeccompProfileField is securityContext.seccompProfile, since 1.19.
+	// Older versions use the seccomp annotations.
+	SeccompProfileField bool `json:"seccompProfileField"`
+
+	// StartupProbes are enabled by default since 1.18. They are dropped on
+	// older versions, whose schema validation rejects them.
+	StartupProbes bool `json:"startupProbes"`
+
+	// NativeSidecars, init containers restarted always, are enabled by
+	// default since 1.29.
+	NativeSidecars bool `json:"nativeSidecars"`
+}
+
+// nativeSidecarRestartPolicy is the restartPolicy of a native sidecar.
+const nativeSidecarRestartPolicy = "Always"
+
+// nativeSidecarContainer is a container with the restartPolicy of native
+// sidecars, which the vendored API types predate.
+type nativeSidecarContainer struct {
+	corev1.Container
+	RestartPolicy string `json:"restartPolicy"`
+}
+
+// kubernetesCapabilityMatrix is the Kubernetes 1.x minor version introducing each capability.
+var kubernetesCapabilityMatrix = []struct {
+	minor  int
+	enable func(c *KubernetesCapabilities)
+}{
+	{18, func(c *KubernetesCapabilities) { c.StartupProbes = true }},
+	{19, func(c *KubernetesCapabilities) { c.SeccompProfileField = true }},
+	{29, func(c *KubernetesCapabilities) { c.NativeSidecars = true }},
+}
+
+// KubernetesCapabilitiesForVersion returns the capabilities of the Kubernetes
+// version, such as 1.18 or the v1.18.9-eks-d1db3c git version of a server.
+func KubernetesCapabilitiesForVersion(v string) (*KubernetesCapabilities, error) {
+	parts := kubernetesVersionRE.FindStringSubmatch(v)
+	if parts == nil {
+		return nil, fmt.Errorf("invalid Kubernetes version %q", v)
+	}
+	major, _ := strconv.Atoi(parts[1])
+	minor, _ := strconv.Atoi(parts[2])
+	if major != 1 {
+		return nil, fmt.Errorf("unsupported Kubernetes version %q", v)
+	}
+	c := &KubernetesCapabilities{Version: fmt.Sprintf("%d.%d", major, minor)}
+	for _, m := range kubernetesCapabilityMatrix {
+		if minor >= m.minor {
+			m.enable(c)
+		}
+	}
+	return c, nil
+}
+
+// DiscoverKubernetesCapabilities returns the capabilities of the version of the cluster.
+func DiscoverKubernetesCapabilities(client kubernetes.Interface) (*KubernetesCapabilities, error) {
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the Kubernetes version: %v", err)
+	}
+	return KubernetesCapabilitiesForVersion(info.GitVersion)
+}
+
+// discoverKubernetesCapabilities discovers the capabilities of the cluster once,
+// closing kubernetesDiscovered when done.
+func (wh *Webhook) discoverKubernetesCapabilities(client kubernetes.Interface) {
+	defer close(wh.kubernetesDiscovered)
+	c, err := DiscoverKubernetesCapabilities(client)
+	if err != nil {
+		log.Warnf("Rendering the injected containers without Kubernetes version adjustments: %v", err)
+		return
+	}
+	log.Infof("Rendering the injected containers for Kubernetes %s", c.Version)
+	wh.mu.Lock()
+	wh.kubernetes = c
+	wh.mu.Unlock()
+}
+
+// awaitKubernetesCapabilities waits for the first discovery of the capabilities
+// of the cluster, or until ctx is done.
+func (wh *Webhook) awaitKubernetesCapabilities(ctx context.Context) {
+	if wh.kubernetesDiscovered == nil {
+		return
+	}
+	select {
+	case <-wh.kubernetesDiscovered:
+	case <-ctx.Done():
+	}
+}
+
+// rediscoverKubernetesCapabilities discovers the capabilities of the cluster
+// every kubernetesDiscoveryPeriod until the stop channel is closed. The last
+// capabilities discovered are kept when the discovery fails.
+func (wh *Webhook) rediscoverKubernetesCapabilities(stop <-chan struct{}) {
+	ticker := time.NewTicker(kubernetesDiscoveryPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		c, err := DiscoverKubernetesCapabilities(wh.kubeClient)
+		if err != nil {
+			log.Debugf("Keeping the Kubernetes capabilities: %v", err)
+			continue
+		}
+		wh.mu.Lock()
+		changed := wh.kubernetes == nil || *wh.kubernetes != *c
+		wh.kubernetes = c
+		wh.mu.Unlock()
+		if changed {
+			log.Infof("Rendering the injected containers for Kubernetes %s", c.Version)
+		}
+	}
+}
+
+// applyKubernetesCapabilities adjusts the injected containers to the
+// capabilities, returning the pod annotations replacing the fields dropped.
+func applyKubernetesCapabilities(c *KubernetesCapabilities, sic *SidecarInjectionSpec) map[string]string {
+	if c == nil {
+		return nil
+	}
+	var annotations map[string]string
+	adjust := func(containers []corev1.Container) {
+		for i := range containers {
+			container := &containers[i]
+			if !c.StartupProbes && container.StartupProbe != nil {
+				log.Debugf("Dropping the startup probe of %s, not supported by Kubernetes %s", container.Name, c.Version)
+				container.StartupProbe = nil
+			}
+			if c.SeccompProfileField || container.SecurityContext == nil || container.SecurityContext.SeccompProfile == nil {
+				continue
+			}
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[seccompContainerAnnotationPrefix+container.Name] = seccompAnnotationValue(container.SecurityContext.SeccompProfile)
+			container.SecurityContext.SeccompProfile = nil
+		}
+	}
+	adjust(sic.InitContainers)
+	adjust(sic.Containers)
+	return annotations
+}
+
+// seccompAnnotationValue is the annotation value of the seccomp profile.
+func seccompAnnotationValue(p *corev1.SeccompProfile) string {
+	switch p.Type {
+	case corev1.SeccompProfileTypeUnconfined:
+		return corev1.SeccompProfileNameUnconfined
+	case corev1.SeccompProfileTypeLocalhost:
+		if p.LocalhostProfile != nil {
+			return corev1.SeccompLocalhostProfileNamePrefix + *p.LocalhostProfile
+		}
+	}
+	return corev1.SeccompProfileRuntimeDefault
+}
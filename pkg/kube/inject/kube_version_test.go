@@ -0,0 +1,168 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesCapabilitiesForVersion(t *testing.T) {
+	cases := []struct {
+		version string
+		want    *KubernetesCapabilities
+		wantErr bool
+	}{
+		{"1.17", &KubernetesCapabilities{Version: "1.17"}, false},
+		{"v1.18.9-eks-d1db3c", &KubernetesCapabilities{Version: "1.18", StartupProbes: true}, false},
+		{"1.19", &KubernetesCapabilities{Version: "1.19", StartupProbes: true, SeccompProfileField: true}, false},
+		{"v1.20.1+k3s1", &KubernetesCapabilities{Version: "1.20", StartupProbes: true, SeccompProfileField: true}, false},
+		{"1.29", &KubernetesCapabilities{Version: "1.29", StartupProbes: true, SeccompProfileField: true, NativeSidecars: true}, false},
+		{"2.0", nil, true},
+		{"latest", nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.version, func(t *testing.T) {
+			got, err := KubernetesCapabilitiesForVersion(c.version)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverKubernetesCapabilities(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.18.3"}
+	got, err := DiscoverKubernetesCapabilities(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "1.18" || got.SeccompProfileField {
+		t.Fatalf("unexpected capabilities %+v", got)
+	}
+}
+
+func TestDiscoverKubernetesCapabilitiesConcurrently(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.19.0"}
+	wh := &Webhook{kubernetesDiscovered: make(chan struct{})}
+	go wh.discoverKubernetesCapabilities(client)
+	wh.awaitKubernetesCapabilities(context.Background())
+	wh.mu.RLock()
+	got := wh.kubernetes
+	wh.mu.RUnlock()
+	if got == nil || got.Version != "1.19" {
+		t.Fatalf("unexpected capabilities %+v", got)
+	}
+
+	// without a client there is nothing to wait for
+	(&Webhook{}).awaitKubernetesCapabilities(context.Background())
+}
+
+func TestApplyKubernetesCapabilities(t *testing.T) {
+	localhost := "profiles/proxy.json"
+	spec := func() *SidecarInjectionSpec {
+		return &SidecarInjectionSpec{
+			InitContainers: []corev1.Container{{
+				Name: "istio-init",
+				SecurityContext: &corev1.SecurityContext{
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				},
+			}},
+			Containers: []corev1.Container{{
+				Name:         ProxyContainerName,
+				StartupProbe: &corev1.Probe{},
+				SecurityContext: &corev1.SecurityContext{
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhost},
+				},
+			}},
+		}
+	}
+	cases := []struct {
+		name            string
+		version         string
+		wantAnnotations map[string]string
+		wantSeccomp     bool
+		wantStartup     bool
+	}{
+		{"unknown version", "", nil, true, true},
+		{"1.19", "1.19", nil, true, true},
+		{"1.18", "1.18", map[string]string{
+			seccompContainerAnnotationPrefix + "istio-init":       "runtime/default",
+			seccompContainerAnnotationPrefix + ProxyContainerName: "localhost/profiles/proxy.json",
+		}, false, true},
+		{"1.17", "1.17", map[string]string{
+			seccompContainerAnnotationPrefix + "istio-init":       "runtime/default",
+			seccompContainerAnnotationPrefix + ProxyContainerName: "localhost/profiles/proxy.json",
+		}, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var caps *KubernetesCapabilities
+			if c.version != "" {
+				var err error
+				if caps, err = KubernetesCapabilitiesForVersion(c.version); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sic := spec()
+			got := applyKubernetesCapabilities(caps, sic)
+			if !reflect.DeepEqual(got, c.wantAnnotations) {
+				t.Errorf("got annotations %v, want %v", got, c.wantAnnotations)
+			}
+			proxy := sic.Containers[0]
+			if seccomp := proxy.SecurityContext.SeccompProfile != nil; seccomp != c.wantSeccomp {
+				t.Errorf("got seccompProfile %v, want %v", seccomp, c.wantSeccomp)
+			}
+			if startup := proxy.StartupProbe != nil; startup != c.wantStartup {
+				t.Errorf("got startupProbe %v, want %v", startup, c.wantStartup)
+			}
+		})
+	}
+}
+
+func TestNativeSidecarPatch(t *testing.T) {
+	sic := &SidecarInjectionSpec{
+		InitContainers: []corev1.Container{{Name: InitContainerName}},
+		Containers:     []corev1.Container{{Name: ProxyContainerName}},
+		NativeSidecar:  true,
+	}
+	initContainers, containers := sic.podContainers()
+	if len(initContainers) != 2 || initContainers[1].Name != ProxyContainerName || len(containers) != 0 {
+		t.Fatalf("got init containers %v and containers %v, want the proxy as an init container", initContainers, containers)
+	}
+
+	patch := addContainer(sic, nil, initContainers, "/spec/initContainers")
+	got, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"op":"add","path":"/spec/initContainers","value":[{"name":"istio-init","resources":{}}]},` +
+		`{"op":"add","path":"/spec/initContainers/-","value":{"name":"istio-proxy","resources":{},"restartPolicy":"Always"}}]`
+	if string(got) != want {
+		t.Fatalf("got patch %s, want %s", got, want)
+	}
+}
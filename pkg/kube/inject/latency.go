@@ -0,0 +1,73 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples is the number of recent admission latencies kept.
+const maxLatencySamples = 1000
+
+// latencyWindow keeps the latencies of the most recent admission requests.
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (l *latencyWindow) record(d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.samples) < maxLatencySamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % maxLatencySamples
+}
+
+// quantile returns the q quantile of the recorded latencies, and the number
+// of latencies it was computed from.
+func (l *latencyWindow) quantile(q float64) (time.Duration, int) {
+	if l == nil {
+		return 0, 0
+	}
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.samples...)
+	l.mu.Unlock()
+	if len(sorted) == 0 {
+		return 0, 0
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(q*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i], len(sorted)
+}
+
+// AdmissionLatencyP99 returns the 99th percentile latency of the recent
+// admission requests, and the number of requests it was computed from.
+func (wh *Webhook) AdmissionLatencyP99() (time.Duration, int) {
+	return wh.latencies.quantile(0.99)
+}
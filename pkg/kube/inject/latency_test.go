@@ -0,0 +1,44 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyWindow(t *testing.T) {
+	var nilWindow *latencyWindow
+	nilWindow.record(time.Second)
+	if p, n := nilWindow.quantile(0.99); p != 0 || n != 0 {
+		t.Fatalf("got %v over %d requests from a nil window", p, n)
+	}
+
+	l := &latencyWindow{}
+	for i := 1; i <= 100; i++ {
+		l.record(time.Duration(i) * time.Millisecond)
+	}
+	if p, n := l.quantile(0.99); p != 99*time.Millisecond || n != 100 {
+		t.Fatalf("got p99 %v over %d requests, want 99ms over 100", p, n)
+	}
+
+	// older latencies are dropped once the window is full
+	for i := 0; i < maxLatencySamples; i++ {
+		l.record(time.Second)
+	}
+	if p, n := l.quantile(0.5); p != time.Second || n != maxLatencySamples {
+		t.Fatalf("got p50 %v over %d requests, want 1s over %d", p, n, maxLatencySamples)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/pkg/log"
+)
+
+// limitRangeCacheTTL is how long the LimitRanges of a namespace are cached.
+const limitRangeCacheTTL = time.Minute
+
+// limitRangeCache caches the container LimitRange items of namespaces.
+type limitRangeCache struct {
+	client kubernetes.Interface
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]limitRangeEntry
+}
+
+type limitRangeEntry struct {
+	items   []corev1.LimitRangeItem
+	expires time.Time
+}
+
+func newLimitRangeCache(client kubernetes.Interface) *limitRangeCache {
+	return &limitRangeCache{
+		client:  client,
+		now:     time.Now,
+		entries: map[string]limitRangeEntry{},
+	}
+}
+
+// get returns the container LimitRange items of the namespace.
+func (c *limitRangeCache) get(ctx context.Context, namespace string) []corev1.LimitRangeItem {
+	now := c.now()
+	c.mu.Lock()
+	e, f := c.entries[namespace]
+	c.mu.Unlock()
+	if f && now.Before(e.expires) {
+		return e.items
+	}
+
+	var items []corev1.LimitRangeItem
+	ranges, err := c.client.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// cached as empty, so an unavailable API does not slow down every admission
+		log.Warnf("Failed to list LimitRanges of namespace %s: %v", namespace, err)
+	} else {
+		for _, lr := range ranges.Items {
+			for _, item := range lr.Spec.Limits {
+				if item.Type == corev1.LimitTypeContainer {
+					items = append(items, item)
+				}
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ns, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, ns)
+		}
+	}
+	c.entries[namespace] = limitRangeEntry{items: items, expires: now.Add(limitRangeCacheTTL)}
+	return items
+}
+
+// applyLimitRanges adjusts the resources of the containers so the
+// LimitRange admission controller neither rejects the pod nor defaults the
+// resources in a way inconsistent with the ones requested.
+func applyLimitRanges(containers []corev1.Container, items []corev1.LimitRangeItem) {
+	for i := range containers {
+		for _, item := range items {
+			fitLimitRange(&containers[i], item)
+		}
+	}
+}
+
+func fitLimitRange(c *corev1.Container, item corev1.LimitRangeItem) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		req, hasReq := c.Resources.Requests[name]
+		lim, hasLim := c.Resources.Limits[name]
+
+		// A defaulted limit below the request would get the pod rejected.
+		if def, f := item.Default[name]; f && !hasLim && hasReq && def.Cmp(req) < 0 {
+			lim, hasLim = req.DeepCopy(), true
+		}
+		if upper, f := item.Max[name]; f {
+			if hasLim && lim.Cmp(upper) > 0 {
+				lim = upper.DeepCopy()
+			}
+			if hasReq && req.Cmp(upper) > 0 {
+				req = upper.DeepCopy()
+			}
+		}
+		if lower, f := item.Min[name]; f {
+			if hasReq && req.Cmp(lower) < 0 {
+				req = lower.DeepCopy()
+			}
+			if hasLim && lim.Cmp(lower) < 0 {
+				lim = lower.DeepCopy()
+			}
+		}
+		if ratio, f := item.MaxLimitRequestRatio[name]; f && hasReq && hasLim && !req.IsZero() {
+			maxLimit := req.MilliValue() * ratio.MilliValue() / 1000
+			if lim.MilliValue() > maxLimit {
+				lim = *resource.NewMilliQuantity(maxLimit, lim.Format)
+			}
+		}
+		if hasReq && hasLim && req.Cmp(lim) > 0 {
+			req = lim.DeepCopy()
+		}
+
+		if hasReq && !req.Equal(c.Resources.Requests[name]) {
+			log.Debugf("Adjusting %s request of %s to %s to fit LimitRange", name, c.Name, req.String())
+			c.Resources.Requests[name] = req
+		}
+		if hasLim {
+			if old, f := c.Resources.Limits[name]; !f || !lim.Equal(old) {
+				log.Debugf("Adjusting %s limit of %s to %s to fit LimitRange", name, c.Name, lim.String())
+				if c.Resources.Limits == nil {
+					c.Resources.Limits = corev1.ResourceList{}
+				}
+				c.Resources.Limits[name] = lim
+			}
+		}
+	}
+}
+
+// defaultResources sets the resources the API server defaults on the
+// containers: the requests missing from the limits, then the default limits
+// and requests of the LimitRange items, as the LimitRanger admission plugin
+// does.
+func defaultResources(containers []corev1.Container, items []corev1.LimitRangeItem) {
+	for i := range containers {
+		c := &containers[i]
+		for name, lim := range c.Resources.Limits {
+			if _, f := c.Resources.Requests[name]; !f {
+				if c.Resources.Requests == nil {
+					c.Resources.Requests = corev1.ResourceList{}
+				}
+				c.Resources.Requests[name] = lim.DeepCopy()
+			}
+		}
+		for _, item := range items {
+			for name, def := range item.Default {
+				if _, f := c.Resources.Limits[name]; !f {
+					if c.Resources.Limits == nil {
+						c.Resources.Limits = corev1.ResourceList{}
+					}
+					c.Resources.Limits[name] = def.DeepCopy()
+				}
+			}
+			for name, def := range item.DefaultRequest {
+				if _, f := c.Resources.Requests[name]; !f {
+					if c.Resources.Requests == nil {
+						c.Resources.Requests = corev1.ResourceList{}
+					}
+					c.Resources.Requests[name] = def.DeepCopy()
+				}
+			}
+		}
+	}
+}
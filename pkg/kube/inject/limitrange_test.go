@@ -0,0 +1,150 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func resources(requestCPU, limitCPU string) corev1.ResourceRequirements {
+	r := corev1.ResourceRequirements{}
+	if requestCPU != "" {
+		r.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(requestCPU)}
+	}
+	if limitCPU != "" {
+		r.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(limitCPU)}
+	}
+	return r
+}
+
+func TestFitLimitRange(t *testing.T) {
+	cpu := func(v string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(v)}
+	}
+	cases := []struct {
+		name      string
+		item      corev1.LimitRangeItem
+		resources corev1.ResourceRequirements
+		wantReq   string
+		wantLim   string
+	}{
+		{
+			name:      "within range",
+			item:      corev1.LimitRangeItem{Max: cpu("2"), Min: cpu("10m")},
+			resources: resources("100m", "1"),
+			wantReq:   "100m",
+			wantLim:   "1",
+		},
+		{
+			name:      "limit above max",
+			item:      corev1.LimitRangeItem{Max: cpu("500m")},
+			resources: resources("100m", "2"),
+			wantReq:   "100m",
+			wantLim:   "500m",
+		},
+		{
+			name:      "request below min",
+			item:      corev1.LimitRangeItem{Min: cpu("200m")},
+			resources: resources("100m", "1"),
+			wantReq:   "200m",
+			wantLim:   "1",
+		},
+		{
+			name:      "default limit below request",
+			item:      corev1.LimitRangeItem{Default: cpu("50m")},
+			resources: resources("100m", ""),
+			wantReq:   "100m",
+			wantLim:   "100m",
+		},
+		{
+			name:      "default limit above request",
+			item:      corev1.LimitRangeItem{Default: cpu("500m")},
+			resources: resources("100m", ""),
+			wantReq:   "100m",
+		},
+		{
+			name:      "limit request ratio",
+			item:      corev1.LimitRangeItem{MaxLimitRequestRatio: cpu("4")},
+			resources: resources("100m", "2"),
+			wantReq:   "100m",
+			wantLim:   "400m",
+		},
+		{
+			name:      "request clamped below limit",
+			item:      corev1.LimitRangeItem{Max: cpu("500m")},
+			resources: resources("1", "2"),
+			wantReq:   "500m",
+			wantLim:   "500m",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			containers := []corev1.Container{{Name: ProxyContainerName, Resources: c.resources}}
+			applyLimitRanges(containers, []corev1.LimitRangeItem{c.item})
+			container := containers[0]
+			req, lim := container.Resources.Requests[corev1.ResourceCPU], container.Resources.Limits[corev1.ResourceCPU]
+			if c.wantReq != "" && req.Cmp(resource.MustParse(c.wantReq)) != 0 {
+				t.Fatalf("got request %s, want %s", req.String(), c.wantReq)
+			}
+			if c.wantLim == "" {
+				if _, f := container.Resources.Limits[corev1.ResourceCPU]; f {
+					t.Fatalf("got limit %s, want none", lim.String())
+				}
+			} else if lim.Cmp(resource.MustParse(c.wantLim)) != 0 {
+				t.Fatalf("got limit %s, want %s", lim.String(), c.wantLim)
+			}
+		})
+	}
+}
+
+func TestLimitRangeCache(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "foo"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+			{Type: corev1.LimitTypeContainer},
+			{Type: corev1.LimitTypePod},
+		}},
+	})
+	lists := 0
+	client.PrependReactor("list", "limitranges", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	now := time.Now()
+	cache := newLimitRangeCache(client)
+	cache.now = func() time.Time { return now }
+
+	if got := cache.get(context.TODO(), "foo"); len(got) != 1 || got[0].Type != corev1.LimitTypeContainer {
+		t.Fatalf("got items %v, want the container item", got)
+	}
+	cache.get(context.TODO(), "foo")
+	if lists != 1 {
+		t.Fatalf("got %d lists, want 1", lists)
+	}
+	now = now.Add(limitRangeCacheTTL)
+	cache.get(context.TODO(), "foo")
+	if lists != 2 {
+		t.Fatalf("got %d lists after expiry, want 2", lists)
+	}
+}
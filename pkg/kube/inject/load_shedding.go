@@ -0,0 +1,88 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/webhooks"
+	"istio.io/pkg/log"
+)
+
+// Reasons of the admission requests shed.
+const (
+	shedReasonQueueFull    = "queue_full"
+	shedReasonQueueTimeout = "queue_timeout"
+	shedReasonCanceled     = "canceled"
+)
+
+// LoadSheddingOptions bounds the admission requests waiting for one of the
+// AdmissionWorkers, so a spike of pod creations, e.g. from node drains or
+// scale ups, is answered at once with failed admission reviews, rather than
+// piling up until the webhook times out.
+type LoadSheddingOptions struct {
+	// MaxQueued is the number of admission requests waiting for a worker.
+	// Requests beyond it are rejected at once. Zero disables the limit.
+	MaxQueued int
+
+	// QueueTimeout is how long a request waits for a worker before it is
+	// rejected. Zero waits until a worker is free.
+	QueueTimeout time.Duration
+}
+
+func validateLoadSheddingOptions(o LoadSheddingOptions, workers int) error {
+	if o.MaxQueued < 0 || o.QueueTimeout < 0 {
+		return fmt.Errorf("invalid admission load shedding options %+v: must not be negative", o)
+	}
+	if (o.MaxQueued > 0 || o.QueueTimeout > 0) && workers <= 0 {
+		return fmt.Errorf("invalid admission load shedding options %+v: requires admission workers", o)
+	}
+	return nil
+}
+
+// admissionNamespace returns the namespace of the request of the admission
+// review, read ahead of serving it so it is queued with its namespace.
+func admissionNamespace(body []byte) string {
+	var review struct {
+		Request *struct {
+			Namespace string `json:"namespace"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		return ""
+	}
+	return review.Request.Namespace
+}
+
+// shedAdmission answers a request refused by the admission queue with a
+// failed AdmissionReview, so the API server reports the saturation.
+func shedAdmission(w http.ResponseWriter, r *http.Request, reason string) {
+	admissionsShed.With(reasonTag.Value(reason)).Increment()
+	log.Debugf("Rejecting AdmissionRequest for path=%s: injector saturated (%s)", r.URL.Path, reason)
+	webhooks.ServeAdmission(w, r, func(*kube.AdmissionReview) *kube.AdmissionResponse {
+		return &kube.AdmissionResponse{Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusServiceUnavailable,
+			Reason:  metav1.StatusReasonServiceUnavailable,
+			Message: fmt.Sprintf("sidecar injector saturated: %s", reason),
+		}}
+	}, webhooks.ServeOptions{})
+}
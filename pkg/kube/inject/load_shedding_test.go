@@ -0,0 +1,87 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+func TestValidateLoadSheddingOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		options LoadSheddingOptions
+		workers int
+		wantErr bool
+	}{
+		{name: "disabled"},
+		{name: "limited", options: LoadSheddingOptions{MaxQueued: 100, QueueTimeout: time.Second}, workers: 10},
+		{name: "without workers", options: LoadSheddingOptions{MaxQueued: 100}, wantErr: true},
+		{name: "negative queue", options: LoadSheddingOptions{MaxQueued: -1}, workers: 10, wantErr: true},
+		{name: "negative timeout", options: LoadSheddingOptions{QueueTimeout: -time.Second}, workers: 10, wantErr: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateLoadSheddingOptions(tt.options, tt.workers); (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdmissionNamespace(t *testing.T) {
+	cases := []struct {
+		body string
+		want string
+	}{
+		{`{"kind":"AdmissionReview","request":{"uid":"1","namespace":"foo","object":{}}}`, "foo"},
+		{`{"kind":"AdmissionReview"}`, ""},
+		{`not json`, ""},
+	}
+	for _, tt := range cases {
+		if got := admissionNamespace([]byte(tt.body)); got != tt.want {
+			t.Fatalf("admissionNamespace(%s) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestShedAdmission(t *testing.T) {
+	body := `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"1","namespace":"foo","object":{}}}`
+	r := httptest.NewRequest("POST", "/inject", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	shedAdmission(w, r, shedReasonQueueFull)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got HTTP status %d, want an admission review", w.Code)
+	}
+	var review admissionv1.AdmissionReview
+	if err := json.Unmarshal(w.Body.Bytes(), &review); err != nil {
+		t.Fatal(err)
+	}
+	resp := review.Response
+	if resp == nil || resp.UID != "1" || resp.Allowed || resp.Result == nil {
+		t.Fatalf("got response %+v, want a failure of request 1", resp)
+	}
+	if resp.Result.Code != http.StatusServiceUnavailable || !strings.Contains(resp.Result.Message, shedReasonQueueFull) {
+		t.Fatalf("got result %+v, want service unavailable with the reason", resp.Result)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+
+	"istio.io/pkg/log"
+)
+
+// FieldManager is the field manager the injected fields are attributed to
+// when WebhookParameters.FieldManager is set to it.
+const FieldManager = "istio-sidecar-injector"
+
+// podSchema is the structured-merge-diff schema of the pod fields the
+// injector patches, with the list keys of the Kubernetes API. The fields it
+// does not declare are deduced: objects are owned field by field, lists as a
+// whole.
+const podSchema typed.YAMLObject = `types:
+- name: io.k8s.api.core.v1.Pod
+  map:
+    fields:
+    - name: metadata
+      type:
+        namedType: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+    - name: spec
+      type:
+        namedType: io.k8s.api.core.v1.PodSpec
+    elementType:
+      namedType: __untyped_deduced_
+- name: io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta
+  map:
+    fields:
+    - name: finalizers
+      type:
+        list:
+          elementType:
+            scalar: string
+          elementRelationship: associative
+    - name: ownerReferences
+      type:
+        list:
+          elementType:
+            namedType: __untyped_deduced_
+          elementRelationship: associative
+          keys:
+          - uid
+    elementType:
+      namedType: __untyped_deduced_
+- name: io.k8s.api.core.v1.PodSpec
+  map:
+    fields:
+    - name: containers
+      type:
+        namedType: containers
+    - name: initContainers
+      type:
+        namedType: containers
+    - name: imagePullSecrets
+      type:
+        namedType: namedList
+    - name: volumes
+      type:
+        namedType: namedList
+    elementType:
+      namedType: __untyped_deduced_
+- name: containers
+  list:
+    elementType:
+      namedType: io.k8s.api.core.v1.Container
+    elementRelationship: associative
+    keys:
+    - name
+- name: io.k8s.api.core.v1.Container
+  map:
+    fields:
+    - name: env
+      type:
+        namedType: namedList
+    - name: ports
+      type:
+        list:
+          elementType:
+            namedType: __untyped_deduced_
+          elementRelationship: associative
+          keys:
+          - containerPort
+          - protocol
+    - name: volumeDevices
+      type:
+        list:
+          elementType:
+            namedType: __untyped_deduced_
+          elementRelationship: associative
+          keys:
+          - devicePath
+    - name: volumeMounts
+      type:
+        list:
+          elementType:
+            namedType: __untyped_deduced_
+          elementRelationship: associative
+          keys:
+          - mountPath
+    elementType:
+      namedType: __untyped_deduced_
+- name: namedList
+  list:
+    elementType:
+      namedType: __untyped_deduced_
+    elementRelationship: associative
+    keys:
+    - name
+- name: __untyped_atomic_
+  scalar: untyped
+  list:
+    elementType:
+      namedType: __untyped_atomic_
+    elementRelationship: atomic
+  map:
+    elementType:
+      namedType: __untyped_atomic_
+    elementRelationship: atomic
+- name: __untyped_deduced_
+  scalar: untyped
+  list:
+    elementType:
+      namedType: __untyped_atomic_
+    elementRelationship: atomic
+  map:
+    elementType:
+      namedType: __untyped_deduced_
+    elementRelationship: separable
+`
+
+var podType = func() typed.ParseableType {
+	parser, err := typed.NewParser(podSchema)
+	if err != nil {
+		panic(fmt.Sprintf("invalid pod schema: %v", err))
+	}
+	return parser.Type("io.k8s.api.core.v1.Pod")
+}()
+
+// attributeManagedFields adds to the patch an entry of the managedFields of
+// the pod, attributing the fields set by the patch to the field manager.
+// Otherwise the API server attributes them to the manager creating the pod,
+// so a later server-side apply of the manifest, which lacks them, conflicts
+// with them or tries to remove them. A previous entry of the field manager,
+// e.g. of a reinjection, is replaced rather than appended to. Pods without
+// managedFields, whose fields are not tracked, are left as is, as are the
+// pods the schema cannot type, e.g. with duplicate environment variables.
+func attributeManagedFields(pod *corev1.Pod, patch []byte, fieldManager string, now time.Time) ([]byte, error) {
+	if len(pod.ManagedFields) == 0 {
+		return patch, nil
+	}
+	patchedJSON, err := applyJSONPatchToPod(pod, patch)
+	if err != nil {
+		return nil, err
+	}
+	var patched corev1.Pod
+	if err := json.Unmarshal(patchedJSON, &patched); err != nil {
+		return nil, err
+	}
+	fields, err := patchedFields(pod, &patched)
+	if err != nil {
+		log.Warnf("Not attributing the injected fields of %s/%s to %s: %v", pod.Namespace, potentialPodName(&pod.ObjectMeta),
+			fieldManager, err)
+		return patch, nil
+	}
+	if fields.Empty() {
+		return patch, nil
+	}
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	var ops []rfc6902PatchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+	entry := metav1.ManagedFieldsEntry{
+		Manager:    fieldManager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		Time:       &metav1.Time{Time: now.UTC().Truncate(time.Second)},
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: raw},
+	}
+	op := rfc6902PatchOperation{Op: "add", Path: "/metadata/managedFields/-", Value: entry}
+	for i, e := range pod.ManagedFields {
+		if e.Manager == fieldManager && e.Operation == metav1.ManagedFieldsOperationUpdate {
+			op = rfc6902PatchOperation{Op: "replace", Path: fmt.Sprintf("/metadata/managedFields/%d", i), Value: entry}
+			break
+		}
+	}
+	return json.Marshal(append(ops, op))
+}
+
+// patchedFields returns the fields the patched pod adds to or changes in the
+// pod, as the API server computes them for an update.
+func patchedFields(pod, patched *corev1.Pod) (*fieldpath.Set, error) {
+	before, err := typedPod(pod)
+	if err != nil {
+		return nil, err
+	}
+	after, err := typedPod(patched)
+	if err != nil {
+		return nil, err
+	}
+	comparison, err := before.Compare(after)
+	if err != nil {
+		return nil, err
+	}
+	return comparison.Modified.Union(comparison.Added), nil
+}
+
+func typedPod(pod *corev1.Pod) (*typed.TypedValue, error) {
+	pod = pod.DeepCopy()
+	pod.ManagedFields = nil
+	// the protocol is a key of the ports, defaulted by the API server
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			for j := range containers[i].Ports {
+				if containers[i].Ports[j].Protocol == "" {
+					containers[i].Ports[j].Protocol = corev1.ProtocolTCP
+				}
+			}
+		}
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return nil, err
+	}
+	return podType.FromUnstructured(u)
+}
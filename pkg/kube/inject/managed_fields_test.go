@@ -0,0 +1,263 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pkg/config/mesh"
+)
+
+// appliedPod returns a pod created by a server-side apply of kubectl.
+func appliedPod() *corev1.Pod {
+	applied := metav1.Time{Time: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "app"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:    "kubectl",
+				Operation:  metav1.ManagedFieldsOperationApply,
+				APIVersion: "v1",
+				Time:       &applied,
+				FieldsType: "FieldsV1",
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{}}},` +
+					`"f:spec":{"f:containers":{"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:name":{},` +
+					`"f:env":{"k:{\"name\":\"MODE\"}":{".":{},"f:name":{},"f:value":{}}},` +
+					`"f:ports":{"k:{\"containerPort\":8080,\"protocol\":\"TCP\"}":{".":{},"f:containerPort":{}}},` +
+					`"f:volumeMounts":{"k:{\"mountPath\":\"/data\"}":{".":{},"f:mountPath":{},"f:name":{}}}}}}}`)},
+			}},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:         "app",
+				Image:        "app:latest",
+				Env:          []corev1.EnvVar{{Name: "MODE", Value: "prod"}},
+				Ports:        []corev1.ContainerPort{{ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			}},
+			Volumes: []corev1.Volume{{Name: "data"}},
+		},
+	}
+}
+
+// injectorFields returns the fields the managedFields of the pod attribute
+// to the injector.
+func injectorFields(t *testing.T, pod *corev1.Pod) map[string]interface{} {
+	t.Helper()
+	for _, e := range pod.ManagedFields {
+		if e.Manager != FieldManager {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(e.FieldsV1.Raw, &fields); err != nil {
+			t.Fatal(err)
+		}
+		return fields
+	}
+	t.Fatalf("no managedFields entry of the injector: %+v", pod.ManagedFields)
+	return nil
+}
+
+func hasField(fields map[string]interface{}, path ...string) bool {
+	var v interface{} = fields
+	for _, p := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if v, ok = m[p]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func injectAppliedPod(t *testing.T, pod *corev1.Pod, fieldManager string) *corev1.Pod {
+	t.Helper()
+	m := mesh.DefaultMeshConfig()
+	sic := &SidecarInjectionSpec{
+		InitContainers: []corev1.Container{{Name: "istio-init", Image: "proxyv2"}},
+		Containers:     []corev1.Container{{Name: ProxyContainerName, Image: "proxyv2", Args: []string{"proxy", "sidecar"}}},
+		Volumes:        []corev1.Volume{{Name: "istio-envoy"}},
+		AppEnv:         []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "127.0.0.1:15001"}},
+	}
+	annotations := map[string]string{annotation.SidecarStatus.Name: `{"version":"test"}`}
+	// as set by injectPod
+	fsGroup := int64(1337)
+	pod.Spec.SecurityContext = &corev1.PodSecurityContext{FSGroup: &fsGroup}
+	patch, err := createPatch(pod, &SidecarInjectionStatus{}, "default", annotations, sic, "app", &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldManager != "" {
+		if patch, err = attributeManagedFields(pod, patch, fieldManager, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	patched, err := applyJSONPatchToPod(pod, patch)
+	if err != nil {
+		t.Fatalf("invalid patch %s: %v", patch, err)
+	}
+	var out corev1.Pod
+	if err := json.Unmarshal(patched, &out); err != nil {
+		t.Fatal(err)
+	}
+	return &out
+}
+
+func TestAttributeManagedFields(t *testing.T) {
+	pod := appliedPod()
+
+	// without a field manager the managedFields are left as is
+	out := injectAppliedPod(t, pod.DeepCopy(), "")
+	if len(out.ManagedFields) != 1 || !bytes.Equal(out.ManagedFields[0].FieldsV1.Raw, pod.ManagedFields[0].FieldsV1.Raw) {
+		t.Fatalf("managedFields changed without a field manager: %+v", out.ManagedFields)
+	}
+
+	out = injectAppliedPod(t, pod.DeepCopy(), FieldManager)
+	if len(out.ManagedFields) != 2 {
+		t.Fatalf("expected the managedFields of kubectl and the injector, got %+v", out.ManagedFields)
+	}
+	if applied := out.ManagedFields[0]; applied.Manager != "kubectl" || applied.Operation != metav1.ManagedFieldsOperationApply ||
+		!bytes.Equal(applied.FieldsV1.Raw, pod.ManagedFields[0].FieldsV1.Raw) {
+		t.Fatalf("the managedFields of kubectl changed: %+v", applied)
+	}
+	injected := out.ManagedFields[1]
+	if injected.Manager != FieldManager || injected.Operation != metav1.ManagedFieldsOperationUpdate ||
+		injected.FieldsType != "FieldsV1" || injected.APIVersion != "v1" {
+		t.Fatalf("unexpected managedFields entry of the injector: %+v", injected)
+	}
+	fields := injectorFields(t, out)
+	for _, path := range [][]string{
+		{"f:metadata", "f:annotations", "f:" + annotation.SidecarStatus.Name},
+		{"f:metadata", "f:labels", "f:service.istio.io/canonical-name"},
+		{"f:spec", "f:containers", `k:{"name":"istio-proxy"}`, "."},
+		{"f:spec", "f:containers", `k:{"name":"istio-proxy"}`, "f:args"},
+		{"f:spec", "f:containers", `k:{"name":"app"}`, "f:env", `k:{"name":"HTTP_PROXY"}`, "f:value"},
+		{"f:spec", "f:initContainers", `k:{"name":"istio-init"}`, "f:image"},
+		{"f:spec", "f:volumes", `k:{"name":"istio-envoy"}`, "f:name"},
+		{"f:spec", "f:securityContext", "f:fsGroup"},
+	} {
+		if !hasField(fields, path...) {
+			t.Errorf("expected %s to be owned by the injector: %s", strings.Join(path, "."), injected.FieldsV1.Raw)
+		}
+	}
+	for _, path := range [][]string{
+		{"f:metadata", "f:labels", "f:app"},
+		{"f:spec", "f:containers", `k:{"name":"app"}`, "f:image"},
+		{"f:spec", "f:containers", `k:{"name":"app"}`, "."},
+		{"f:spec", "f:containers", `k:{"name":"app"}`, "f:env", `k:{"name":"MODE"}`},
+		{"f:spec", "f:containers", `k:{"name":"app"}`, "f:ports"},
+		{"f:spec", "f:containers", `k:{"name":"app"}`, "f:volumeMounts", `k:{"mountPath":"/data"}`},
+		{"f:spec", "f:volumes", `k:{"name":"data"}`},
+	} {
+		if hasField(fields, path...) {
+			t.Errorf("expected %s not to be owned by the injector: %s", strings.Join(path, "."), injected.FieldsV1.Raw)
+		}
+	}
+
+	// reinjection replaces the entry of the injector rather than adding one
+	reinjected := pod.DeepCopy()
+	reinjected.ManagedFields = out.ManagedFields
+	if out := injectAppliedPod(t, reinjected, FieldManager); len(out.ManagedFields) != 2 ||
+		out.ManagedFields[0].Manager != "kubectl" || out.ManagedFields[1].Manager != FieldManager {
+		t.Fatalf("expected the entry of the injector to be replaced, got %+v", out.ManagedFields)
+	}
+
+	// pods not tracking managedFields are left as is
+	untracked := pod.DeepCopy()
+	untracked.ManagedFields = nil
+	if out := injectAppliedPod(t, untracked, FieldManager); len(out.ManagedFields) != 0 {
+		t.Fatalf("expected no managedFields, got %+v", out.ManagedFields)
+	}
+}
+
+func TestAttributeManagedFieldsPatchedLists(t *testing.T) {
+	pod := appliedPod()
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "worker", Image: "worker:latest"})
+	attribute := func(t *testing.T, pod *corev1.Pod, ops ...rfc6902PatchOperation) map[string]interface{} {
+		t.Helper()
+		patch, err := json.Marshal(ops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if patch, err = attributeManagedFields(pod, patch, FieldManager, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		patched, err := applyJSONPatchToPod(pod, patch)
+		if err != nil {
+			t.Fatalf("invalid patch %s: %v", patch, err)
+		}
+		var out corev1.Pod
+		if err := json.Unmarshal(patched, &out); err != nil {
+			t.Fatal(err)
+		}
+		return injectorFields(t, &out)
+	}
+
+	t.Run("index after an insertion", func(t *testing.T) {
+		fields := attribute(t, pod.DeepCopy(),
+			rfc6902PatchOperation{Op: "add", Path: "/spec/containers/0", Value: corev1.Container{Name: "istio-proxy", Image: "proxyv2"}},
+			rfc6902PatchOperation{Op: "replace", Path: "/spec/containers/2/image", Value: "worker:patched"})
+		if !hasField(fields, "f:spec", "f:containers", `k:{"name":"worker"}`, "f:image") {
+			t.Fatalf("expected the image of the worker to be owned by the injector: %v", fields)
+		}
+		if !hasField(fields, "f:spec", "f:containers", `k:{"name":"istio-proxy"}`, ".") {
+			t.Fatalf("expected the proxy to be owned by the injector: %v", fields)
+		}
+		if hasField(fields, "f:spec", "f:containers", `k:{"name":"app"}`) {
+			t.Fatalf("expected the app not to be owned by the injector: %v", fields)
+		}
+	})
+
+	t.Run("port appended", func(t *testing.T) {
+		fields := attribute(t, pod.DeepCopy(),
+			rfc6902PatchOperation{Op: "add", Path: "/spec/containers/0/ports/-", Value: corev1.ContainerPort{Name: "http-envoy-prom", ContainerPort: 15090}})
+		ports := []string{"f:spec", "f:containers", `k:{"name":"app"}`, "f:ports"}
+		if !hasField(fields, append(ports, `k:{"containerPort":15090,"protocol":"TCP"}`, "f:name")...) {
+			t.Fatalf("expected the appended port to be owned by the injector: %v", fields)
+		}
+		if hasField(fields, append(ports, `k:{"containerPort":8080,"protocol":"TCP"}`)...) {
+			t.Fatalf("expected the applied port not to be owned by the injector: %v", fields)
+		}
+	})
+}
+
+func TestAttributeManagedFieldsUntyped(t *testing.T) {
+	// duplicate environment variables are accepted by the API server, not by the schema
+	pod := appliedPod()
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "MODE", Value: "dev"})
+	patch, err := json.Marshal([]rfc6902PatchOperation{{Op: "add", Path: "/metadata/labels/injected", Value: "true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := attributeManagedFields(pod, patch, FieldManager, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, patch) {
+		t.Fatalf("expected the patch to be left as is, got %s", got)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/pkg/log"
+)
+
+const (
+	// propagatedLabelsEnv and propagatedAnnotationsEnv are the proxy metadata
+	// the pod labels and annotations are rendered into.
+	propagatedLabelsEnv      = "ISTIO_METAJSON_LABELS"
+	propagatedAnnotationsEnv = "ISTIO_METAJSON_ANNOTATIONS"
+
+	defaultPropagationMaxValueLength = 256
+	defaultPropagationMaxEntries     = 32
+)
+
+var (
+	// defaultPropagatedLabels are the labels used by Istio and its telemetry.
+	defaultPropagatedLabels = []string{
+		"app",
+		"version",
+		"app.kubernetes.io/name",
+		"app.kubernetes.io/version",
+		"service.istio.io/*",
+		"istio.io/*",
+		"security.istio.io/*",
+		"topology.istio.io/*",
+	}
+
+	// defaultPropagatedAnnotations are the annotations read by the proxy.
+	defaultPropagatedAnnotations = []string{
+		"sidecar.istio.io/*",
+		"proxy.istio.io/*",
+		"status.sidecar.istio.io/*",
+	}
+)
+
+// MetadataPropagationConfig selects the pod labels and annotations
+// propagated into the metadata of injected proxies, which is sent to Istiod
+// and used by telemetry.
+type MetadataPropagationConfig struct {
+	// Labels are the keys of the propagated labels, or prefixes of keys
+	// ending with "*". Defaults to the labels used by Istio.
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations are the keys of the propagated annotations, or prefixes of
+	// keys ending with "*". Defaults to the annotations read by the proxy.
+	Annotations []string `json:"annotations,omitempty"`
+
+	// MaxValueLength drops the labels and annotations with longer values.
+	// Defaults to 256.
+	MaxValueLength int `json:"maxValueLength,omitempty"`
+
+	// MaxEntries bounds the number of labels, and of annotations, propagated.
+	// Defaults to 32.
+	MaxEntries int `json:"maxEntries,omitempty"`
+}
+
+func validateMetadataPropagation(c *MetadataPropagationConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxValueLength < 0 || c.MaxEntries < 0 {
+		return fmt.Errorf("metadata propagation limits must not be negative")
+	}
+	for _, key := range append(append([]string{}, c.Labels...), c.Annotations...) {
+		if key == "" || strings.Contains(strings.TrimSuffix(key, "*"), "*") {
+			return fmt.Errorf("invalid metadata propagation key %q: must be a key or a prefix ending with *", key)
+		}
+	}
+	return nil
+}
+
+func propagationKeyAllowed(allowed []string, key string) bool {
+	for _, a := range allowed {
+		if strings.HasSuffix(a, "*") {
+			if strings.HasPrefix(key, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+		} else if a == key {
+			return true
+		}
+	}
+	return false
+}
+
+// propagatedMetadata returns the entries of m allowed by the config, in key
+// order up to its entry limit.
+func (c *MetadataPropagationConfig) propagatedMetadata(m map[string]string, allowed, defaults []string) map[string]string {
+	if len(allowed) == 0 {
+		allowed = defaults
+	}
+	maxValueLength := c.MaxValueLength
+	if maxValueLength == 0 {
+		maxValueLength = defaultPropagationMaxValueLength
+	}
+	maxEntries := c.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = defaultPropagationMaxEntries
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	propagated := map[string]string{}
+	for _, k := range keys {
+		if !propagationKeyAllowed(allowed, k) {
+			continue
+		}
+		if len(propagated) == maxEntries {
+			log.Debugf("Metadata propagation limited to %d entries, dropping %s", maxEntries, k)
+			continue
+		}
+		if len(m[k]) > maxValueLength {
+			log.Debugf("Metadata propagation dropping %s: value longer than %d", k, maxValueLength)
+			continue
+		}
+		propagated[k] = m[k]
+	}
+	return propagated
+}
+
+// applyMetadataPropagation renders the allowed labels and annotations of the
+// pod into the metadata of the proxy. The proxy then ignores the labels of
+// the pod it reads from the downward API.
+func applyMetadataPropagation(sidecar *corev1.Container, c *MetadataPropagationConfig, labels, annotations map[string]string) error {
+	if sidecar == nil || c == nil {
+		return nil
+	}
+	for _, m := range []struct {
+		env      string
+		metadata map[string]string
+	}{
+		{propagatedLabelsEnv, c.propagatedMetadata(labels, c.Labels, defaultPropagatedLabels)},
+		{propagatedAnnotationsEnv, c.propagatedMetadata(annotations, c.Annotations, defaultPropagatedAnnotations)},
+	} {
+		value, err := json.Marshal(m.metadata)
+		if err != nil {
+			return err
+		}
+		updateClusterEnvs(sidecar, map[string]string{m.env: string(value)})
+	}
+	updateClusterEnvs(sidecar, map[string]string{constants.MetadataLabelsFilteredEnv: "true"})
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/istio/pkg/config/constants"
+)
+
+func TestApplyMetadataPropagation(t *testing.T) {
+	labels := map[string]string{
+		"app":               "reviews",
+		"version":           "v1",
+		"istio.io/rev":      "canary",
+		"pod-template-hash": "5d4c8fc7f",
+		"team":              "bookinfo",
+		"build":             strings.Repeat("x", 300),
+	}
+	annotations := map[string]string{
+		"sidecar.istio.io/statsInclusionPrefixes": "cluster.outbound",
+		"kubectl.kubernetes.io/last-applied":      "{}",
+	}
+	cases := []struct {
+		name            string
+		config          *MetadataPropagationConfig
+		wantLabels      string
+		wantAnnotations string
+	}{
+		{
+			name:            "defaults",
+			config:          &MetadataPropagationConfig{},
+			wantLabels:      `{"app":"reviews","istio.io/rev":"canary","version":"v1"}`,
+			wantAnnotations: `{"sidecar.istio.io/statsInclusionPrefixes":"cluster.outbound"}`,
+		},
+		{
+			name:            "allowlist",
+			config:          &MetadataPropagationConfig{Labels: []string{"team", "build"}, Annotations: []string{"kubectl.kubernetes.io/*"}},
+			wantLabels:      `{"team":"bookinfo"}`,
+			wantAnnotations: `{"kubectl.kubernetes.io/last-applied":"{}"}`,
+		},
+		{
+			name:            "limits",
+			config:          &MetadataPropagationConfig{Labels: []string{"*"}, MaxEntries: 2, MaxValueLength: 8},
+			wantLabels:      `{"app":"reviews","istio.io/rev":"canary"}`,
+			wantAnnotations: `{"sidecar.istio.io/statsInclusionPrefixes":"cluster.outbound"}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sidecar := &corev1.Container{Name: ProxyContainerName, Env: []corev1.EnvVar{
+				{Name: propagatedAnnotationsEnv, Value: `{"kubectl.kubernetes.io/last-applied":"{}"}`},
+			}}
+			if err := applyMetadataPropagation(sidecar, c.config, labels, annotations); err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			for _, e := range sidecar.Env {
+				got[e.Name] = e.Value
+			}
+			if got[propagatedLabelsEnv] != c.wantLabels {
+				t.Errorf("got labels %s, want %s", got[propagatedLabelsEnv], c.wantLabels)
+			}
+			if got[propagatedAnnotationsEnv] != c.wantAnnotations {
+				t.Errorf("got annotations %s, want %s", got[propagatedAnnotationsEnv], c.wantAnnotations)
+			}
+			if got[constants.MetadataLabelsFilteredEnv] != "true" || len(sidecar.Env) != 3 {
+				t.Errorf("unexpected env %v", sidecar.Env)
+			}
+		})
+	}
+
+	sidecar := &corev1.Container{Name: ProxyContainerName}
+	if err := applyMetadataPropagation(sidecar, nil, labels, annotations); err != nil || len(sidecar.Env) != 0 {
+		t.Fatalf("metadata filtered without config: %v", sidecar.Env)
+	}
+	for _, config := range []*MetadataPropagationConfig{
+		{MaxEntries: -1},
+		{Labels: []string{""}},
+		{Annotations: []string{"*.istio.io"}},
+	} {
+		if err := validateMetadataPropagation(config); err == nil {
+			t.Errorf("expected error for %+v", config)
+		}
+	}
+}
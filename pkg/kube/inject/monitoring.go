@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/pprof"
 
 	ocprom "contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,6 +29,9 @@ import (
 
 const (
 	metricsPath = "/metrics"
+
+	reloadSuccess = "success"
+	reloadFailure = "failure"
 )
 
 type monitor struct {
@@ -37,6 +41,12 @@ type monitor struct {
 }
 
 var (
+	lookupTag  = monitoring.MustCreateLabel("lookup")
+	resultTag  = monitoring.MustCreateLabel("result")
+	reasonTag  = monitoring.MustCreateLabel("reason")
+	clusterTag = monitoring.MustCreateLabel("cluster")
+	driftTag   = monitoring.MustCreateLabel("drift")
+
 	totalInjections = monitoring.NewSum(
 		"sidecar_injection_requests_total",
 		"Total number of sidecar injection requests.",
@@ -54,20 +64,158 @@ var (
 
 	totalSkippedInjections = monitoring.NewSum(
 		"sidecar_injection_skip_total",
-		"Total number of skipped sidecar injection requests.",
+		"Total number of skipped sidecar injection requests, by reason.",
+		monitoring.WithLabels(reasonTag),
+	)
+
+	totalUnauthorizedInjections = monitoring.NewSum(
+		"sidecar_injection_unauthorized_total",
+		"Total number of sidecar injection requests rejected because the client was not allowed.",
+	)
+
+	configCanaryHolds = monitoring.NewSum(
+		"sidecar_injection_config_canary_holds_total",
+		"Total number of injection configurations not activated because recently injected pods failed injection with them.",
+	)
+
+	configReloads = monitoring.NewSum(
+		"sidecar_injection_config_reloads_total",
+		"Total number of injection configuration reloads, by result.",
+		monitoring.WithLabels(resultTag),
+	)
+
+	configFreezeHolds = monitoring.NewSum(
+		"sidecar_injection_config_freeze_holds_total",
+		"Total number of injection configuration reloads refused during a freeze window.",
+	)
+
+	watchdogTrips = monitoring.NewSum(
+		"sidecar_injection_watchdog_trips_total",
+		"Total number of times the injector watchdog detected a resource threshold violation.",
+	)
+
+	templateCacheHits = monitoring.NewSum(
+		"sidecar_injection_template_cache_hits_total",
+		"Total number of injection requests served with an already parsed template.",
+	)
+
+	templateCacheMisses = monitoring.NewSum(
+		"sidecar_injection_template_cache_misses_total",
+		"Total number of injection requests that required parsing the template.",
+	)
+
+	templateOverrides = monitoring.NewGauge(
+		"sidecar_injection_template_overrides",
+		"Number of injection configuration files currently loaded from the template override directory.",
+	)
+
+	admissionQueuedNamespaces = monitoring.NewGauge(
+		"sidecar_injection_queued_namespaces",
+		"Number of namespaces with admission requests waiting for a worker.",
+	)
+
+	admissionsQueued = monitoring.NewGauge(
+		"sidecar_injection_admissions_queued",
+		"Number of admission requests waiting for a worker, across namespaces.",
+	)
+
+	admissionsShed = monitoring.NewSum(
+		"sidecar_injection_admissions_shed_total",
+		"Total number of admission requests rejected because the injector was saturated, by reason: queue_full, queue_timeout or canceled.",
+		monitoring.WithLabels(reasonTag),
+	)
+
+	clusterInjections = monitoring.NewSum(
+		"sidecar_injection_cluster_requests_total",
+		"Total number of injection requests served for the clusters of the fan-in configuration, by cluster. "+
+			"Callers not mapped to a cluster are counted as local.",
+		monitoring.WithLabels(clusterTag),
+	)
+
+	driftedPods = monitoring.NewGauge(
+		"sidecar_injection_drifted_pods",
+		"Number of the injected pods of the last drift verification whose injection differs from the active "+
+			"configuration, by drift: image, resources, missingContainer or missingVolume.",
+		monitoring.WithLabels(driftTag),
+	)
+
+	driftVerifiedPods = monitoring.NewGauge(
+		"sidecar_injection_drift_verified_pods",
+		"Number of injected pods checked by the last drift verification.",
+	)
+
+	skippedLookups = monitoring.NewSum(
+		"sidecar_injection_lookups_skipped_total",
+		"Total number of optional Kubernetes lookups skipped because the admission deadline had passed, by lookup.",
+		monitoring.WithLabels(lookupTag),
+	)
+
+	templateRenderFailures = monitoring.NewSum(
+		"sidecar_injection_template_render_failures_total",
+		"Total number of injection requests failed because the template could not be rendered into a valid sidecar.",
+	)
+
+	admissionDuration = monitoring.NewDistribution(
+		"sidecar_injection_admission_duration_seconds",
+		"Time in seconds taken to handle an injection admission request.",
+		[]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	)
+
+	namespaceCacheLookups = monitoring.NewSum(
+		"sidecar_injection_namespace_cache_lookups_total",
+		"Total number of namespace lookups of injection requests in the namespace cache, by result: hit, miss or unsynced.",
+		monitoring.WithLabels(resultTag),
+	)
+
+	namespaceCacheStaleness = monitoring.NewGauge(
+		"sidecar_injection_namespace_cache_staleness_seconds",
+		"Seconds since the namespace cache last computed the decisions of all namespaces, from its informer or a refresh.",
+	)
+
+	templateParseTime = monitoring.NewDistribution(
+		"sidecar_injection_template_parse_time",
+		"Time in seconds taken to parse a new version of the injection template.",
+		[]float64{.001, .005, .01, .05, .1, .5, 1},
 	)
 )
 
+// injectorMetrics are the metrics of the injector, the only ones pushed by the
+// statsd backend.
+var injectorMetrics = []monitoring.Metric{
+	totalInjections,
+	totalSuccessfulInjections,
+	totalFailedInjections,
+	totalSkippedInjections,
+	totalUnauthorizedInjections,
+	configCanaryHolds,
+	configReloads,
+	configFreezeHolds,
+	watchdogTrips,
+	templateCacheHits,
+	templateCacheMisses,
+	templateParseTime,
+	templateRenderFailures,
+	admissionDuration,
+	admissionQueuedNamespaces,
+	admissionsQueued,
+	admissionsShed,
+	clusterInjections,
+	driftedPods,
+	driftVerifiedPods,
+	templateOverrides,
+	skippedLookups,
+	namespaceCacheLookups,
+	namespaceCacheStaleness,
+}
+
 func init() {
-	monitoring.MustRegister(
-		totalInjections,
-		totalSuccessfulInjections,
-		totalFailedInjections,
-		totalSkippedInjections,
-	)
+	monitoring.MustRegister(injectorMetrics...)
+	// the config info is collected directly, so the superseded versions are
+	// deleted instead of kept with 0
+	prometheus.MustRegister(configInfos)
 }
 
-func startMonitor(mux *http.ServeMux, port int) (*monitor, error) {
+func startMonitor(mux *http.ServeMux, port int, profiling bool) (*monitor, error) {
 	m := &monitor{
 		shutdown: make(chan struct{}),
 	}
@@ -88,6 +236,9 @@ func startMonitor(mux *http.ServeMux, port int) (*monitor, error) {
 		return nil, fmt.Errorf("could not establish self-monitoring: %v", err)
 	}
 	m.exporter = exporter
+	if profiling {
+		addProfiling(mux)
+	}
 	m.monitoringServer = &http.Server{
 		Handler: mux,
 	}
@@ -116,3 +267,13 @@ func addMonitor(mux *http.ServeMux) (*ocprom.Exporter, error) {
 
 	return exporter, nil
 }
+
+// addProfiling serves the runtime profiles, to capture the CPU and heap
+// profiles of the injector when its latency rises during mass pod creations.
+func addProfiling(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
@@ -0,0 +1,260 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/api/label"
+	"istio.io/pkg/log"
+)
+
+const (
+	namespaceCacheHit      = "hit"
+	namespaceCacheMiss     = "miss"
+	namespaceCacheUnsynced = "unsynced"
+
+	// namespaceCacheResync is how often the decisions of all namespaces are
+	// computed again from the informer, in case an event was missed.
+	namespaceCacheResync = 10 * time.Minute
+)
+
+// namespaceEligibilityAnnotations are the annotations of a namespace the
+// injection of its pods depends on.
+var namespaceEligibilityAnnotations = []string{InjectionQuotaAnnotation, ValuesAnnotation, AppProxyAnnotation}
+
+// namespaceEligibility is what the injection decisions take from a
+// namespace: whether its pods are of the revision of the injector or in
+// ambient mode, and its quota, values and proxy settings. It is computed once
+// per change of the namespace rather than on every admission.
+type namespaceEligibility struct {
+	// revisionSkip is the skip reason of the pods without a revision label,
+	// empty when they are injected.
+	revisionSkip string
+	// ambient is whether the pods without a dataplane mode label are in ambient mode.
+	ambient     bool
+	annotations map[string]string
+}
+
+func newNamespaceEligibility(ns *corev1.Namespace, revision string) *namespaceEligibility {
+	e := &namespaceEligibility{
+		revisionSkip: revisionSkipReason(revision, nil, ns.Labels, true),
+		ambient:      ambientEnabled(nil, ns.Labels),
+	}
+	for _, name := range namespaceEligibilityAnnotations {
+		if v, f := ns.Annotations[name]; f {
+			if e.annotations == nil {
+				e.annotations = map[string]string{}
+			}
+			e.annotations[name] = v
+		}
+	}
+	return e
+}
+
+// revisionSkipReason returns the reason a pod of the namespace is not
+// injected by the revision, empty if it is. The namespace is nil when unknown.
+func (e *namespaceEligibility) revisionSkipReason(revision string, podLabels map[string]string) string {
+	if _, f := podLabels[label.IstioRev]; f || e == nil {
+		return revisionSkipReason(revision, podLabels, nil, e != nil)
+	}
+	return e.revisionSkip
+}
+
+// ambientEnabled reports whether a pod of the namespace is in ambient mode.
+func (e *namespaceEligibility) ambientEnabled(podLabels map[string]string) bool {
+	if _, f := podLabels[DataplaneModeLabel]; f || e == nil {
+		return ambientEnabled(podLabels, nil)
+	}
+	return e.ambient
+}
+
+// nsAnnotations returns the annotations of the namespace the injection
+// depends on, nil when the namespace is unknown.
+func (e *namespaceEligibility) nsAnnotations() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.annotations
+}
+
+// namespaceCache keeps the eligibility of all namespaces, so admission
+// requests need no API call for namespaces already known. It is kept up to
+// date by the namespace informer shared with the rest of istiod.
+type namespaceCache struct {
+	client   kubernetes.Interface
+	revision string
+	informer cache.SharedIndexInformer
+	lister   corelisters.NamespaceLister
+	now      func() time.Time
+
+	mu         sync.RWMutex
+	namespaces map[string]*namespaceEligibility
+	synced     bool
+	// resynced is when the decisions of all namespaces were last computed,
+	// from the informer or a relist.
+	resynced time.Time
+}
+
+func newNamespaceCache(client kubernetes.Interface, namespaces coreinformers.NamespaceInformer, revision string) *namespaceCache {
+	c := &namespaceCache{
+		client:     client,
+		revision:   revision,
+		informer:   namespaces.Informer(),
+		lister:     namespaces.Lister(),
+		now:        time.Now,
+		namespaces: map[string]*namespaceEligibility{},
+	}
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.set,
+		UpdateFunc: func(_, obj interface{}) { c.set(obj) },
+		DeleteFunc: c.delete,
+	})
+	return c
+}
+
+// run keeps the cache up to date until the stop channel is closed. The
+// shared informer is started with the others of istiod.
+func (c *namespaceCache) run(stop <-chan struct{}) {
+	if !cache.WaitForCacheSync(stop, c.informer.HasSynced) {
+		log.Errorf("Failed to sync the namespace cache of the injector")
+		return
+	}
+	c.resync()
+
+	resync := time.NewTicker(namespaceCacheResync)
+	defer resync.Stop()
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-resync.C:
+			c.resync()
+		case <-ticker.C:
+			c.mu.RLock()
+			staleness := c.now().Sub(c.resynced)
+			c.mu.RUnlock()
+			namespaceCacheStaleness.Record(staleness.Seconds())
+		}
+	}
+}
+
+// resync computes the decisions of all namespaces again from the informer.
+func (c *namespaceCache) resync() {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		log.Warnf("Failed to list the namespaces of the namespace cache: %v", err)
+		return
+	}
+	c.replace(list)
+}
+
+// replace replaces the decisions of all namespaces.
+func (c *namespaceCache) replace(list []*corev1.Namespace) {
+	namespaces := make(map[string]*namespaceEligibility, len(list))
+	for _, ns := range list {
+		namespaces[ns.Name] = newNamespaceEligibility(ns, c.revision)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.namespaces = namespaces
+	c.synced = true
+	c.resynced = c.now()
+}
+
+func (c *namespaceCache) set(obj interface{}) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	e := newNamespaceEligibility(ns, c.revision)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.namespaces[ns.Name] = e
+}
+
+func (c *namespaceCache) delete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.namespaces, ns.Name)
+}
+
+// get returns the eligibility of the namespace, and false if it is not cached
+// yet, e.g. before the cache synced or for a namespace just created.
+func (c *namespaceCache) get(name string) (*namespaceEligibility, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synced {
+		namespaceCacheLookups.With(resultTag.Value(namespaceCacheUnsynced)).Increment()
+		return nil, false
+	}
+	e, f := c.namespaces[name]
+	if !f {
+		namespaceCacheLookups.With(resultTag.Value(namespaceCacheMiss)).Increment()
+		return nil, false
+	}
+	namespaceCacheLookups.With(resultTag.Value(namespaceCacheHit)).Increment()
+	return e, true
+}
+
+// refresh replaces the cached decisions with the ones of the namespaces
+// listed from the API server.
+func (c *namespaceCache) refresh(ctx context.Context) (int, error) {
+	list, err := c.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	namespaces := make([]*corev1.Namespace, 0, len(list.Items))
+	for i := range list.Items {
+		namespaces = append(namespaces, &list.Items[i])
+	}
+	c.replace(namespaces)
+	return len(namespaces), nil
+}
+
+func (c *namespaceCache) serveRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	n, err := c.refresh(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to refresh the namespace cache: %v", err), http.StatusInternalServerError)
+		return
+	}
+	log.Infof("Refreshed the namespace cache with %d namespaces", n)
+	_, _ = fmt.Fprintf(w, "refreshed %d namespaces\n", n)
+}
@@ -0,0 +1,170 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestNamespaceCache(client kubernetes.Interface, revision string) (*namespaceCache, informers.SharedInformerFactory) {
+	factory := informers.NewSharedInformerFactory(client, 0)
+	return newNamespaceCache(client, factory.Core().V1().Namespaces(), revision), factory
+}
+
+func TestNamespaceEligibility(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "foo",
+		Labels:      map[string]string{"istio.io/rev": "canary", DataplaneModeLabel: DataplaneModeAmbient, "team": "a"},
+		Annotations: map[string]string{ValuesAnnotation: "global: {}", "owner": "a"},
+	}}
+	e := newNamespaceEligibility(ns, "canary")
+	if reason := e.revisionSkipReason("canary", nil); reason != "" {
+		t.Fatalf("pod of a namespace of the revision skipped: %s", reason)
+	}
+	if reason := e.revisionSkipReason("canary", map[string]string{"istio.io/rev": "stable"}); reason != skipReasonRevision {
+		t.Fatalf("pod of another revision not skipped")
+	}
+	if reason := newNamespaceEligibility(ns, "stable").revisionSkipReason("stable", nil); reason != skipReasonRevision {
+		t.Fatalf("pod of a namespace of another revision not skipped")
+	}
+	if !e.ambientEnabled(nil) || e.ambientEnabled(map[string]string{DataplaneModeLabel: "sidecar"}) {
+		t.Fatalf("ambient mode not taken from the pod, then the namespace")
+	}
+	if got := e.nsAnnotations(); len(got) != 1 || got[ValuesAnnotation] != "global: {}" {
+		t.Fatalf("got annotations %v, want only the values", got)
+	}
+
+	// unknown namespaces
+	var unknown *namespaceEligibility
+	if unknown.revisionSkipReason("canary", nil) != "" || unknown.ambientEnabled(nil) || unknown.nsAnnotations() != nil {
+		t.Fatalf("unknown namespace not injected by default")
+	}
+}
+
+func TestNamespaceCache(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "foo",
+		Labels:      map[string]string{"istio-injection": "enabled"},
+		Annotations: map[string]string{ValuesAnnotation: "global: {}"},
+	}})
+	c, factory := newTestNamespaceCache(client, "")
+	if _, f := c.get("foo"); f {
+		t.Fatalf("namespace returned before the cache synced")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	go c.run(stop)
+	waitForNamespace := func(name string, want func(*namespaceEligibility) bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if ns, f := c.get(name); f && want(ns) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("namespace %s not cached as expected", name)
+	}
+	waitForNamespace("foo", func(e *namespaceEligibility) bool {
+		return e.revisionSkip == "" && e.annotations[ValuesAnnotation] == "global: {}"
+	})
+
+	// admission lookups are served from the cache
+	calls := 0
+	client.PrependReactor("get", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return false, nil, nil
+	})
+	wh := &Webhook{kubeClient: client, namespaces: c}
+	if e := wh.getNamespace(context.Background(), "foo"); e == nil || e.annotations[ValuesAnnotation] != "global: {}" {
+		t.Fatalf("got namespace %v", e)
+	}
+	if calls != 0 {
+		t.Fatalf("got %d namespace API calls, want none", calls)
+	}
+
+	// updates are picked up by the informer
+	if _, err := client.CoreV1().Namespaces().Update(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "foo",
+		Labels: map[string]string{"istio.io/rev": "canary"},
+	}}, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForNamespace("foo", func(e *namespaceEligibility) bool { return e.revisionSkip == skipReasonRevision })
+
+	// namespaces not cached yet are got from the API server
+	if _, err := client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "bar",
+	}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForNamespace("bar", func(*namespaceEligibility) bool { return true })
+	// as if the informer had not received it yet
+	c.mu.Lock()
+	delete(c.namespaces, "bar")
+	c.mu.Unlock()
+	if e := wh.getNamespace(context.Background(), "bar"); e == nil || calls != 1 {
+		t.Fatalf("got namespace %v with %d API calls", e, calls)
+	}
+
+	// a resync computes the decisions of all namespaces again from the informer
+	c.resync()
+	if _, f := c.get("bar"); !f {
+		t.Fatalf("namespace bar not cached after the resync")
+	}
+}
+
+func TestNamespaceCacheRefresh(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bar"}},
+	)
+	c, _ := newTestNamespaceCache(client, "")
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	w := httptest.NewRecorder()
+	c.serveRefresh(w, httptest.NewRequest("GET", "/debug/inject_namespaces/refresh", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d for GET", w.Code)
+	}
+	w = httptest.NewRecorder()
+	c.serveRefresh(w, httptest.NewRequest("POST", "/debug/inject_namespaces/refresh", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	for _, name := range []string{"foo", "bar"} {
+		if _, f := c.get(name); !f {
+			t.Errorf("namespace %s not cached after the refresh", name)
+		}
+	}
+	if !c.resynced.Equal(now) {
+		t.Fatalf("refresh not recorded as a resync")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"path"
+)
+
+const (
+	skipReasonNamespaceNotAllowed = "namespaceNotAllowed"
+	skipReasonNamespaceSkipped    = "namespaceSkipped"
+)
+
+// NamespaceFilterOptions restricts the namespaces injected by the webhook
+// itself, whatever the namespaceSelector the API server matched the pods
+// with, e.g. after an edit of the webhook configuration. The patterns are
+// globs, e.g. team-*.
+type NamespaceFilterOptions struct {
+	// Inject, if set, are the only namespaces injected.
+	Inject []string
+
+	// Skip are namespaces never injected, even if matched by Inject.
+	Skip []string
+}
+
+func validateNamespaceFilterOptions(o NamespaceFilterOptions) error {
+	for _, pattern := range append(append([]string{}, o.Inject...), o.Skip...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// skipReason returns why the pods of the namespace are not injected, or ""
+// if they may be.
+func (o NamespaceFilterOptions) skipReason(namespace string) string {
+	if matchesNamespace(o.Skip, namespace) {
+		return skipReasonNamespaceSkipped
+	}
+	if len(o.Inject) > 0 && !matchesNamespace(o.Inject, namespace) {
+		return skipReasonNamespaceNotAllowed
+	}
+	return ""
+}
+
+func matchesNamespace(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		// patterns are validated up front
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
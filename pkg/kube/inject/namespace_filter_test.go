@@ -0,0 +1,50 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	if err := validateNamespaceFilterOptions(NamespaceFilterOptions{Skip: []string{"team-["}}); err == nil {
+		t.Fatalf("expected an invalid pattern to be rejected")
+	}
+
+	cases := []struct {
+		name      string
+		options   NamespaceFilterOptions
+		namespace string
+		want      string
+	}{
+		{"no filter", NamespaceFilterOptions{}, "default", ""},
+		{"allowed", NamespaceFilterOptions{Inject: []string{"team-*"}}, "team-a", ""},
+		{"not allowed", NamespaceFilterOptions{Inject: []string{"team-*"}}, "default", skipReasonNamespaceNotAllowed},
+		{"skipped", NamespaceFilterOptions{Skip: []string{"kube-*"}}, "kube-system", skipReasonNamespaceSkipped},
+		{"not skipped", NamespaceFilterOptions{Skip: []string{"kube-*"}}, "default", ""},
+		{"skip takes precedence", NamespaceFilterOptions{Inject: []string{"team-*"}, Skip: []string{"team-legacy"}},
+			"team-legacy", skipReasonNamespaceSkipped},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := validateNamespaceFilterOptions(c.options); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.options.skipReason(c.namespace); got != c.want {
+				t.Fatalf("got skip reason %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"istio.io/pkg/log"
+)
+
+// Lifecycle events of the injector sent to the notification URL.
+const (
+	EventConfigReloaded    = "io.istio.injection.config.reloaded"
+	EventTemplateChanged   = "io.istio.injection.template.changed"
+	EventCertRotated       = "io.istio.injection.cert.rotated"
+	EventAdmissionFailures = "io.istio.injection.admission.failures"
+)
+
+// Formats of the notifications.
+const (
+	// NotificationFormatJSON posts the Notification as JSON.
+	NotificationFormatJSON = "json"
+	// NotificationFormatCloudEvents posts a CloudEvent in the structured JSON mode.
+	NotificationFormatCloudEvents = "cloudevents"
+)
+
+const (
+	defaultNotificationTimeout          = 5 * time.Second
+	defaultNotificationFailureThreshold = 5
+
+	// maxPendingNotifications bounds the notifications waiting to be sent,
+	// newer ones are dropped while the URL is slow.
+	maxPendingNotifications = 64
+)
+
+// NotificationOptions configures the notifications of the injector lifecycle
+// events, e.g. to a Slack or pager relay.
+type NotificationOptions struct {
+	// URL the notifications are posted to. Empty disables them.
+	URL string
+
+	// Format is NotificationFormatJSON, the default, or NotificationFormatCloudEvents.
+	Format string
+
+	// FailureThreshold is the number of consecutive failed admissions
+	// notified as EventAdmissionFailures. Defaults to 5.
+	FailureThreshold int
+
+	// Timeout bounds each post. Defaults to 5s.
+	Timeout time.Duration
+}
+
+// Notification is a lifecycle event of the injector.
+type Notification struct {
+	Type string            `json:"type"`
+	Time time.Time         `json:"time"`
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// cloudEvent is a CloudEvents 1.0 event in the structured JSON mode.
+type cloudEvent struct {
+	SpecVersion     string            `json:"specversion"`
+	ID              string            `json:"id"`
+	Source          string            `json:"source"`
+	Type            string            `json:"type"`
+	Time            time.Time         `json:"time"`
+	DataContentType string            `json:"datacontenttype"`
+	Data            map[string]string `json:"data,omitempty"`
+}
+
+// notifier posts the notifications in order, dropping them when too many are pending.
+type notifier struct {
+	options NotificationOptions
+	source  string
+	client  *http.Client
+	pending chan Notification
+
+	mu       sync.Mutex
+	failures int
+}
+
+func validateNotificationOptions(o NotificationOptions) error {
+	switch o.Format {
+	case "", NotificationFormatJSON, NotificationFormatCloudEvents:
+		return nil
+	default:
+		return fmt.Errorf("unknown notification format %q: must be %s or %s",
+			o.Format, NotificationFormatJSON, NotificationFormatCloudEvents)
+	}
+}
+
+func newNotifier(o NotificationOptions, source string) *notifier {
+	if o.URL == "" {
+		return nil
+	}
+	if o.FailureThreshold <= 0 {
+		o.FailureThreshold = defaultNotificationFailureThreshold
+	}
+	timeout := o.Timeout
+	if timeout <= 0 {
+		timeout = defaultNotificationTimeout
+	}
+	return &notifier{
+		options: o,
+		source:  source,
+		client:  &http.Client{Timeout: timeout},
+		pending: make(chan Notification, maxPendingNotifications),
+	}
+}
+
+// run posts the pending notifications until stop is closed.
+func (n *notifier) run(stop <-chan struct{}) {
+	if n == nil {
+		return
+	}
+	for {
+		select {
+		case e := <-n.pending:
+			if err := n.post(e); err != nil {
+				log.Warnf("Failed to send the %s notification: %v", e.Type, err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// notify queues the event, without blocking.
+func (n *notifier) notify(eventType string, data map[string]string) {
+	if n == nil {
+		return
+	}
+	select {
+	case n.pending <- Notification{Type: eventType, Time: time.Now(), Data: data}:
+	default:
+		log.Warnf("Dropping the %s notification, too many are pending", eventType)
+	}
+}
+
+// admission notifies when the failures of consecutive admissions reach the threshold.
+func (n *notifier) admission(outcome, reason string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	if outcome != DecisionFailed {
+		n.failures = 0
+		n.mu.Unlock()
+		return
+	}
+	n.failures++
+	failures := n.failures
+	n.mu.Unlock()
+	if failures == n.options.FailureThreshold {
+		n.notify(EventAdmissionFailures, map[string]string{
+			"failures":   strconv.Itoa(failures),
+			"lastReason": reason,
+		})
+	}
+}
+
+func (n *notifier) post(e Notification) error {
+	var body interface{} = e
+	contentType := "application/json"
+	if n.options.Format == NotificationFormatCloudEvents {
+		body = cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              uuid.New().String(),
+			Source:          n.source,
+			Type:            e.Type,
+			Time:            e.Time,
+			DataContentType: "application/json",
+			Data:            e.Data,
+		}
+		contentType = "application/cloudevents+json"
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.options.URL, contentType, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", n.options.URL, resp.Status)
+	}
+	return nil
+}
+
+// Notify sends a lifecycle event of the injector, such as EventCertRotated
+// for the certificates managed by its host, if notifications are configured.
+func (wh *Webhook) Notify(eventType string, data map[string]string) {
+	wh.notifier.notify(eventType, data)
+}
+
+// notificationSource is the CloudEvents source of the notifications of the injector of the revision.
+func notificationSource(revision string) string {
+	if revision == "" {
+		revision = "default"
+	}
+	return "istiod/sidecar-injector/" + revision
+}
@@ -0,0 +1,111 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type postedNotification struct {
+	contentType string
+	body        map[string]interface{}
+}
+
+func TestNotifier(t *testing.T) {
+	cases := []struct {
+		name            string
+		format          string
+		wantContentType string
+		wantFields      []string
+	}{
+		{"json", NotificationFormatJSON, "application/json", []string{"type", "time", "data"}},
+		{"cloudevents", NotificationFormatCloudEvents, "application/cloudevents+json",
+			[]string{"specversion", "id", "source", "type", "time", "datacontenttype", "data"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			posted := make(chan postedNotification, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := ioutil.ReadAll(r.Body)
+				var body map[string]interface{}
+				if err := json.Unmarshal(data, &body); err != nil {
+					t.Errorf("invalid notification %s: %v", data, err)
+				}
+				posted <- postedNotification{r.Header.Get("Content-Type"), body}
+			}))
+			defer server.Close()
+
+			n := newNotifier(NotificationOptions{URL: server.URL, Format: c.format, FailureThreshold: 2}, notificationSource(""))
+			stop := make(chan struct{})
+			defer close(stop)
+			go n.run(stop)
+
+			// a success resets the consecutive failures, only the third failure reaches the threshold
+			n.admission(DecisionFailed, "first")
+			n.admission(DecisionInjected, "")
+			n.admission(DecisionFailed, "second")
+			n.admission(DecisionFailed, "third")
+			n.admission(DecisionFailed, "fourth")
+			n.notify(EventConfigReloaded, map[string]string{"template": "v1"})
+
+			for _, want := range []string{EventAdmissionFailures, EventConfigReloaded} {
+				select {
+				case p := <-posted:
+					if p.contentType != c.wantContentType {
+						t.Errorf("got content type %q, want %q", p.contentType, c.wantContentType)
+					}
+					if p.body["type"] != want {
+						t.Errorf("got event %v, want %v", p.body["type"], want)
+					}
+					for _, f := range c.wantFields {
+						if _, ok := p.body[f]; !ok {
+							t.Errorf("missing field %q in %v", f, p.body)
+						}
+					}
+					if want == EventAdmissionFailures {
+						if data := p.body["data"].(map[string]interface{}); data["lastReason"] != "third" {
+							t.Errorf("got data %v, want the last reason of the third failure", data)
+						}
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out waiting for the %s notification", want)
+				}
+			}
+			select {
+			case p := <-posted:
+				t.Fatalf("unexpected notification %v", p.body)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestNotifierDisabled(t *testing.T) {
+	n := newNotifier(NotificationOptions{}, "")
+	if n != nil {
+		t.Fatalf("expected no notifier without a URL")
+	}
+	// the webhook calls the notifier unconditionally
+	n.notify(EventConfigReloaded, nil)
+	n.admission(DecisionFailed, "failed")
+	if err := validateNotificationOptions(NotificationOptions{Format: "xml"}); err == nil {
+		t.Fatalf("expected error for an unknown format")
+	}
+}
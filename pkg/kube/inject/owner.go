@@ -0,0 +1,147 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/pkg/log"
+)
+
+// maxCachedOwners bounds the number of ReplicaSet owners, and of workload
+// values, kept by ownerResolver.
+const maxCachedOwners = 1024
+
+// workloadValuesTTL is how long the ValuesAnnotation of a workload is cached,
+// as it may change unlike the owner of a ReplicaSet.
+const workloadValuesTTL = 30 * time.Second
+
+type cachedWorkloadValues struct {
+	values  string
+	expires time.Time
+}
+
+// ownerResolver looks up the controller of ReplicaSets, so pods created by a
+// Deployment are attributed to it even when its name cannot be derived from
+// the ReplicaSet name. Owners are cached, as a ReplicaSet does not change owner.
+type ownerResolver struct {
+	client kubernetes.Interface
+	cache  *lru.Cache
+	values *lru.Cache
+}
+
+func newOwnerResolver(client kubernetes.Interface) *ownerResolver {
+	cache, _ := lru.New(maxCachedOwners)
+	values, _ := lru.New(maxCachedOwners)
+	return &ownerResolver{client: client, cache: cache, values: values}
+}
+
+// replicaSetController returns the controller of the ReplicaSet, or nil if it has none or it cannot be found.
+func (o *ownerResolver) replicaSetController(ctx context.Context, namespace, name string) *metav1.OwnerReference {
+	key := namespace + "/" + name
+	if owner, f := o.cache.Get(key); f {
+		return owner.(*metav1.OwnerReference)
+	}
+	rs, err := o.client.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		// not cached, the ReplicaSet may not be visible yet
+		log.Debugf("Failed to get ReplicaSet %s: %v", key, err)
+		return nil
+	}
+	owner := metav1.GetControllerOf(rs)
+	o.cache.Add(key, owner)
+	return owner
+}
+
+// workloadValues returns the ValuesAnnotation of the Deployment, StatefulSet
+// or DaemonSet, or "" for other kinds or when it cannot be found.
+func (o *ownerResolver) workloadValues(ctx context.Context, namespace, kind, name string) string {
+	key := kind + "/" + namespace + "/" + name
+	if cached, f := o.values.Get(key); f && time.Now().Before(cached.(cachedWorkloadValues).expires) {
+		return cached.(cachedWorkloadValues).values
+	}
+	var meta *metav1.ObjectMeta
+	var err error
+	switch kind {
+	case "Deployment":
+		var d *appsv1.Deployment
+		if d, err = o.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			meta = &d.ObjectMeta
+		}
+	case "StatefulSet":
+		var s *appsv1.StatefulSet
+		if s, err = o.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			meta = &s.ObjectMeta
+		}
+	case "DaemonSet":
+		var d *appsv1.DaemonSet
+		if d, err = o.client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+			meta = &d.ObjectMeta
+		}
+	default:
+		return ""
+	}
+	if err != nil {
+		// not cached, the workload may not be visible yet
+		log.Debugf("Failed to get %s %s/%s: %v", kind, namespace, name, err)
+		return ""
+	}
+	values := meta.Annotations[ValuesAnnotation]
+	o.values.Add(key, cachedWorkloadValues{values: values, expires: time.Now().Add(workloadValuesTTL)})
+	return values
+}
+
+// getWorkloadValues returns the ValuesAnnotation of the workload of the pod,
+// the workload overlay of inheritedValues.
+func (wh *Webhook) getWorkloadValues(ctx context.Context, pod *corev1.Pod, deployMeta *metav1.ObjectMeta, typeMeta *metav1.TypeMeta) string {
+	if wh.owners == nil || deployMeta.Name == "" || !lookupAllowed(ctx, "workload") {
+		return ""
+	}
+	return wh.owners.workloadValues(ctx, pod.Namespace, typeMeta.Kind, deployMeta.Name)
+}
+
+// getDeployMeta returns the metadata of the workload of the pod, as
+// getDeployMetaFromPod, resolving the owner of ReplicaSets when possible.
+func (wh *Webhook) getDeployMeta(ctx context.Context, pod *corev1.Pod) (*metav1.ObjectMeta, *metav1.TypeMeta) {
+	deployMeta, typeMeta := getDeployMetaFromPod(pod)
+	if wh.owners == nil || typeMeta.Kind != "ReplicaSet" || !lookupAllowed(ctx, "owner") {
+		return deployMeta, typeMeta
+	}
+	if owner := wh.owners.replicaSetController(ctx, pod.Namespace, deployMeta.Name); owner != nil {
+		deployMeta.Name = owner.Name
+		typeMeta.Kind = owner.Kind
+		typeMeta.APIVersion = owner.APIVersion
+	}
+	return deployMeta, typeMeta
+}
+
+// workloadLogName returns the name used to refer to the pod in logs. Pods
+// are usually admitted before their name is generated, they are then
+// referred to by their workload.
+func workloadLogName(pod *corev1.Pod, deployMeta *metav1.ObjectMeta, typeMeta *metav1.TypeMeta) string {
+	if pod.Name != "" || deployMeta.Name == "" {
+		return potentialPodName(&pod.ObjectMeta)
+	}
+	return fmt.Sprintf("%s/%s", strings.ToLower(typeMeta.Kind), deployMeta.Name)
+}
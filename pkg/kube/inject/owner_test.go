@@ -0,0 +1,125 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetDeployMeta(t *testing.T) {
+	controller := true
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "renamed-rs",
+			Namespace: "foo",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "frontend", Controller: &controller},
+			},
+		},
+	}
+	pod := func(owner string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			GenerateName: owner + "-",
+			Namespace:    "foo",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: owner, Controller: &controller},
+			},
+		}}
+	}
+
+	client := fake.NewSimpleClientset(replicaSet)
+	gets := 0
+	client.PrependReactor("get", "replicasets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	wh := &Webhook{owners: newOwnerResolver(client)}
+
+	cases := []struct {
+		name     string
+		pod      *corev1.Pod
+		wantKind string
+		wantName string
+		wantLog  string
+	}{
+		{"resolved", pod("renamed-rs"), "Deployment", "frontend", "deployment/frontend"},
+		{"cached", pod("renamed-rs"), "Deployment", "frontend", "deployment/frontend"},
+		{"unknown replicaset", pod("orphan"), "ReplicaSet", "orphan", "replicaset/orphan"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deployMeta, typeMeta := wh.getDeployMeta(context.TODO(), c.pod)
+			if typeMeta.Kind != c.wantKind || deployMeta.Name != c.wantName {
+				t.Fatalf("got %s %s, want %s %s", typeMeta.Kind, deployMeta.Name, c.wantKind, c.wantName)
+			}
+			if got := workloadLogName(c.pod, deployMeta, typeMeta); got != c.wantLog {
+				t.Fatalf("got log name %q, want %q", got, c.wantLog)
+			}
+		})
+	}
+	// one lookup for the resolved ReplicaSet, one for the unknown ReplicaSet that failed
+	if gets != 2 {
+		t.Fatalf("got %d ReplicaSet lookups, want 2", gets)
+	}
+}
+
+func TestWorkloadValues(t *testing.T) {
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "frontend", Namespace: "foo", Annotations: map[string]string{ValuesAnnotation: "global: {}"},
+	}}
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "db", Namespace: "foo", Annotations: map[string]string{ValuesAnnotation: "global: {hub: x}"},
+	}}
+	client := fake.NewSimpleClientset(deploy, sts)
+	gets := 0
+	client.PrependReactor("get", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	wh := &Webhook{owners: newOwnerResolver(client)}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "foo"}}
+
+	cases := []struct {
+		kind, name, want string
+	}{
+		{"Deployment", "frontend", "global: {}"},
+		{"Deployment", "frontend", "global: {}"},
+		{"StatefulSet", "db", "global: {hub: x}"},
+		{"Deployment", "missing", ""},
+		{"Job", "batch", ""},
+	}
+	for _, c := range cases {
+		got := wh.getWorkloadValues(context.TODO(), pod, &metav1.ObjectMeta{Name: c.name}, &metav1.TypeMeta{Kind: c.kind})
+		if got != c.want {
+			t.Fatalf("%s %s: got %q, want %q", c.kind, c.name, got, c.want)
+		}
+	}
+	// the values of the Deployment are cached, the missing Deployment is not
+	if gets != 2 {
+		t.Fatalf("got %d Deployment lookups, want 2", gets)
+	}
+	if got := (&Webhook{}).getWorkloadValues(context.TODO(), pod, &metav1.ObjectMeta{Name: "frontend"},
+		&metav1.TypeMeta{Kind: "Deployment"}); got != "" {
+		t.Fatalf("got %q without an owner resolver", got)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// ProxyPriorityWarn logs a warning when the proxy does not match the criticality of the pod.
+	ProxyPriorityWarn = "warn"
+	// ProxyPriorityAlign adjusts the resources of the proxy to the criticality of the pod.
+	ProxyPriorityAlign = "align"
+)
+
+// defaultCriticalPriorityClasses are the priority classes Kubernetes reserves
+// for critical pods.
+var defaultCriticalPriorityClasses = []string{"system-cluster-critical", "system-node-critical"}
+
+// ProxyPriorityPolicy checks the resources of the proxy against the priority
+// of the pod. The priority class applies to the whole pod, so the proxy always
+// inherits it; its resources are the scheduling hints that can be set apart.
+type ProxyPriorityPolicy struct {
+	// Mode is ProxyPriorityWarn or ProxyPriorityAlign. Defaults to ProxyPriorityWarn.
+	Mode string `json:"mode,omitempty"`
+
+	// CriticalPriorityClasses are the priority classes of the pods whose proxy
+	// must not be best-effort. Defaults to the system critical classes.
+	CriticalPriorityClasses []string `json:"criticalPriorityClasses,omitempty"`
+
+	// Requests are the resources requested by the proxy of critical pods when
+	// the template requests none, in ProxyPriorityAlign mode.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+}
+
+func validateProxyPriority(policy *ProxyPriorityPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	switch policy.Mode {
+	case "", ProxyPriorityWarn, ProxyPriorityAlign:
+		return nil
+	default:
+		return fmt.Errorf("unknown proxy priority mode %q", policy.Mode)
+	}
+}
+
+func (p *ProxyPriorityPolicy) critical(priorityClassName string) bool {
+	classes := p.CriticalPriorityClasses
+	if len(classes) == 0 {
+		classes = defaultCriticalPriorityClasses
+	}
+	for _, c := range classes {
+		if c == priorityClassName {
+			return true
+		}
+	}
+	return false
+}
+
+// applyProxyPriority checks that the proxy of a critical pod is not
+// best-effort, and that the proxy of a pod whose containers are all
+// guaranteed does not lower the QoS class of the pod.
+func applyProxyPriority(policy *ProxyPriorityPolicy, spec *corev1.PodSpec, proxy *corev1.Container) {
+	if policy == nil || proxy == nil {
+		return
+	}
+	align := policy.Mode == ProxyPriorityAlign
+
+	if policy.critical(spec.PriorityClassName) && len(proxy.Resources.Requests) == 0 && len(proxy.Resources.Limits) == 0 {
+		if align && len(policy.Requests) > 0 {
+			proxy.Resources.Requests = policy.Requests.DeepCopy()
+			log.Debugf("Setting the proxy requests of %s pod to %v", spec.PriorityClassName, proxy.Resources.Requests)
+		} else {
+			log.Warnf("Pod with priority class %s is injected with a best-effort proxy", spec.PriorityClassName)
+		}
+	}
+
+	if guaranteed(spec.Containers) && !guaranteed([]corev1.Container{*proxy}) {
+		if align && hasCPUAndMemory(proxy.Resources.Limits) {
+			proxy.Resources.Requests = proxy.Resources.Limits.DeepCopy()
+			log.Debugf("Setting the proxy requests to its limits to keep the pod guaranteed")
+		} else {
+			log.Warnf("Injected proxy lowers the QoS class of the pod from Guaranteed")
+		}
+	}
+}
+
+// guaranteed returns true if all containers request and are limited to
+// the same cpu and memory, as required for the Guaranteed QoS class.
+func guaranteed(containers []corev1.Container) bool {
+	if len(containers) == 0 {
+		return false
+	}
+	for _, c := range containers {
+		if !hasCPUAndMemory(c.Resources.Limits) {
+			return false
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			// requests default to the limits when not set
+			if req, f := c.Resources.Requests[name]; f && !req.Equal(c.Resources.Limits[name]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func hasCPUAndMemory(resources corev1.ResourceList) bool {
+	_, cpu := resources[corev1.ResourceCPU]
+	_, memory := resources[corev1.ResourceMemory]
+	return cpu && memory
+}
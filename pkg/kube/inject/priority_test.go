@@ -0,0 +1,126 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyProxyPriority(t *testing.T) {
+	list := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+	guaranteedApp := corev1.Container{Name: "app", Resources: corev1.ResourceRequirements{Limits: list("1", "1Gi")}}
+	burstableApp := corev1.Container{Name: "app", Resources: corev1.ResourceRequirements{Requests: list("1", "1Gi")}}
+
+	cases := []struct {
+		name          string
+		policy        *ProxyPriorityPolicy
+		spec          corev1.PodSpec
+		proxy         corev1.ResourceRequirements
+		wantResources corev1.ResourceRequirements
+	}{
+		{
+			name: "no policy",
+			spec: corev1.PodSpec{PriorityClassName: "system-node-critical", Containers: []corev1.Container{burstableApp}},
+		},
+		{
+			name:   "critical pod with best-effort proxy in warn mode",
+			policy: &ProxyPriorityPolicy{Requests: list("100m", "128Mi")},
+			spec:   corev1.PodSpec{PriorityClassName: "system-node-critical", Containers: []corev1.Container{burstableApp}},
+		},
+		{
+			name:          "critical pod with best-effort proxy in align mode",
+			policy:        &ProxyPriorityPolicy{Mode: ProxyPriorityAlign, Requests: list("100m", "128Mi")},
+			spec:          corev1.PodSpec{PriorityClassName: "system-node-critical", Containers: []corev1.Container{burstableApp}},
+			wantResources: corev1.ResourceRequirements{Requests: list("100m", "128Mi")},
+		},
+		{
+			name:          "custom critical class",
+			policy:        &ProxyPriorityPolicy{Mode: ProxyPriorityAlign, CriticalPriorityClasses: []string{"payments"}, Requests: list("100m", "128Mi")},
+			spec:          corev1.PodSpec{PriorityClassName: "payments", Containers: []corev1.Container{burstableApp}},
+			wantResources: corev1.ResourceRequirements{Requests: list("100m", "128Mi")},
+		},
+		{
+			name:   "non critical pod",
+			policy: &ProxyPriorityPolicy{Mode: ProxyPriorityAlign, Requests: list("100m", "128Mi")},
+			spec:   corev1.PodSpec{PriorityClassName: "low", Containers: []corev1.Container{burstableApp}},
+		},
+		{
+			name:   "guaranteed pod in align mode",
+			policy: &ProxyPriorityPolicy{Mode: ProxyPriorityAlign},
+			spec:   corev1.PodSpec{Containers: []corev1.Container{guaranteedApp}},
+			proxy:  corev1.ResourceRequirements{Requests: list("100m", "128Mi"), Limits: list("2", "1Gi")},
+			wantResources: corev1.ResourceRequirements{
+				Requests: list("2", "1Gi"),
+				Limits:   list("2", "1Gi"),
+			},
+		},
+		{
+			name:   "guaranteed pod in align mode without proxy limits",
+			policy: &ProxyPriorityPolicy{Mode: ProxyPriorityAlign},
+			spec:   corev1.PodSpec{Containers: []corev1.Container{guaranteedApp}},
+			proxy:  corev1.ResourceRequirements{Requests: list("100m", "128Mi")},
+			wantResources: corev1.ResourceRequirements{
+				Requests: list("100m", "128Mi"),
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			proxy := &corev1.Container{Name: ProxyContainerName, Resources: c.proxy}
+			applyProxyPriority(c.policy, &c.spec, proxy)
+			if !reflect.DeepEqual(proxy.Resources, c.wantResources) {
+				t.Fatalf("got resources %v, want %v", proxy.Resources, c.wantResources)
+			}
+		})
+	}
+}
+
+func TestGuaranteed(t *testing.T) {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	cases := []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		want      bool
+	}{
+		{"limits only", corev1.ResourceRequirements{Limits: limits}, true},
+		{"equal requests", corev1.ResourceRequirements{Limits: limits, Requests: limits}, true},
+		{"lower requests", corev1.ResourceRequirements{Limits: limits, Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("500m"),
+		}}, false},
+		{"cpu limit only", corev1.ResourceRequirements{Limits: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}}, false},
+		{"best effort", corev1.ResourceRequirements{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := guaranteed([]corev1.Container{{Resources: c.resources}}); got != c.want {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
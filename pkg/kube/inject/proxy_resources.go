@@ -0,0 +1,73 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"istio.io/api/annotation"
+)
+
+const (
+	// ProxyCPULimitAnnotation and ProxyMemoryLimitAnnotation set the limits of
+	// the proxy of the pod, as proxyCPU and proxyMemory set its requests.
+	ProxyCPULimitAnnotation    = "sidecar.istio.io/proxyCPULimit"
+	ProxyMemoryLimitAnnotation = "sidecar.istio.io/proxyMemoryLimit"
+)
+
+// validateQuantity validates a resource quantity annotation, which must be
+// positive as the template does not render zero requests or limits.
+func validateQuantity(value string) error {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return fmt.Errorf("invalid quantity, e.g. 100m or 128Mi: %v", err)
+	}
+	if q.Sign() <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	return nil
+}
+
+// validateProxyResources checks the proxy requests of the annotations do not
+// exceed their limits, which the API server would reject the pod for with no
+// mention of the annotations. The quantities are validated with the other
+// annotations.
+func validateProxyResources(annotations map[string]string) error {
+	pairs := []struct{ request, limit string }{
+		{annotation.SidecarProxyCPU.Name, ProxyCPULimitAnnotation},
+		{annotation.SidecarProxyMemory.Name, ProxyMemoryLimitAnnotation},
+	}
+	for _, p := range pairs {
+		request, rf := annotations[p.request]
+		limit, lf := annotations[p.limit]
+		if !rf || !lf {
+			continue
+		}
+		r, err := resource.ParseQuantity(request)
+		if err != nil {
+			continue
+		}
+		l, err := resource.ParseQuantity(limit)
+		if err != nil {
+			continue
+		}
+		if r.Cmp(l) > 0 {
+			return fmt.Errorf("annotation '%s' of %s exceeds annotation '%s' of %s", p.request, request, p.limit, limit)
+		}
+	}
+	return nil
+}
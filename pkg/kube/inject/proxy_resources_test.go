@@ -0,0 +1,69 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	"istio.io/api/annotation"
+)
+
+func TestValidateQuantity(t *testing.T) {
+	cases := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"100m", false},
+		{"1Gi", false},
+		{"2", false},
+		{"0", true},
+		{"-1", true},
+		{"100mb", true},
+		{"", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			err := validateQuantity(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateQuantity(%q) got err %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProxyResources(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{"none", nil, false},
+		{"requests only", map[string]string{annotation.SidecarProxyCPU.Name: "2"}, false},
+		{"within limits", map[string]string{
+			annotation.SidecarProxyCPU.Name: "100m", ProxyCPULimitAnnotation: "1",
+			annotation.SidecarProxyMemory.Name: "1Gi", ProxyMemoryLimitAnnotation: "1Gi",
+		}, false},
+		{"cpu over limit", map[string]string{annotation.SidecarProxyCPU.Name: "2", ProxyCPULimitAnnotation: "1000m"}, true},
+		{"memory over limit", map[string]string{annotation.SidecarProxyMemory.Name: "2Gi", ProxyMemoryLimitAnnotation: "1Gi"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateProxyResources(tc.annotations)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateProxyResources(%v) got err %v, wantErr %v", tc.annotations, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/api/annotation"
+	"istio.io/pkg/log"
+)
+
+const (
+	// InjectionQuotaAnnotation on a namespace limits the number of injected
+	// pods it may have. Pods created beyond the limit are not injected.
+	InjectionQuotaAnnotation = "sidecar.istio.io/injectionQuota"
+
+	skipReasonQuota = "quota"
+
+	// injectedPodsPendingTTL is how long an injection is added to the count
+	// of injected pods of its namespace when the informer does not observe
+	// its pod, e.g. because the pod creation failed after the admission.
+	injectedPodsPendingTTL = 10 * time.Second
+)
+
+// namespaceQuota returns the injection quota of the namespace, from its
+// annotation or the default of the configuration. Zero means unlimited.
+func namespaceQuota(nsAnnotations map[string]string, defaultQuota int) int {
+	v, f := nsAnnotations[InjectionQuotaAnnotation]
+	if !f {
+		return defaultQuota
+	}
+	quota, err := strconv.Atoi(v)
+	if err != nil || quota < 0 {
+		log.Warnf("Ignoring invalid %s annotation %q", InjectionQuotaAnnotation, v)
+		return defaultQuota
+	}
+	return quota
+}
+
+// injectedPodCounter counts the injected pods of namespaces, from the shared
+// pod informer and the injections it has not observed yet.
+type injectedPodCounter struct {
+	pods   corelisters.PodLister
+	synced cache.InformerSynced
+	now    func() time.Time
+
+	mu sync.Mutex
+	// pending are the times of the injections of each namespace, counted
+	// until the informer observes their pods.
+	pending map[string][]time.Time
+}
+
+func newInjectedPodCounter(pods coreinformers.PodInformer) *injectedPodCounter {
+	c := &injectedPodCounter{
+		pods:    pods.Lister(),
+		synced:  pods.Informer().HasSynced,
+		now:     time.Now,
+		pending: map[string][]time.Time{},
+	}
+	pods.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.observed(pod)
+			}
+		},
+	})
+	return c
+}
+
+// admit returns true if the namespace is below its quota, and then accounts
+// for one more injected pod until release is called, when the pod is not
+// injected after all. Dry runs are checked against the quota without being
+// accounted for.
+func (c *injectedPodCounter) admit(ctx context.Context, namespace string, quota int) (admitted bool, release func(), err error) {
+	if !c.synced() {
+		return false, nil, errors.New("the pods are not synced yet")
+	}
+	count, err := c.countInjectedPods(namespace)
+	if err != nil {
+		return false, nil, err
+	}
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := c.pending[namespace][:0]
+	for _, t := range c.pending[namespace] {
+		if now.Before(t.Add(injectedPodsPendingTTL)) {
+			pending = append(pending, t)
+		}
+	}
+	c.pending[namespace] = pending
+	if count+len(pending) >= quota {
+		return false, nil, nil
+	}
+	if isDryRun(ctx) {
+		return true, func() {}, nil
+	}
+	c.pending[namespace] = append(pending, now)
+	return true, func() { c.release(namespace, now) }, nil
+}
+
+func (c *injectedPodCounter) release(namespace string, t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := c.pending[namespace]
+	for i := range pending {
+		if pending[i] == t {
+			c.pending[namespace] = append(pending[:i], pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// observed stops counting a pending injection of the namespace of an
+// injected pod the informer has added, as the pod is now counted from the
+// informer instead. The pods do not identify their admission, so the oldest
+// pending injection is dropped.
+func (c *injectedPodCounter) observed(pod *corev1.Pod) {
+	if !isInjectedPod(pod) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if pending := c.pending[pod.Namespace]; len(pending) > 0 {
+		c.pending[pod.Namespace] = pending[1:]
+	}
+}
+
+func (c *injectedPodCounter) countInjectedPods(namespace string) (int, error) {
+	pods, err := c.pods.Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, pod := range pods {
+		if isInjectedPod(pod) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// isInjectedPod returns true if the pod is injected and not terminated.
+func isInjectedPod(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	_, f := pod.Annotations[annotation.SidecarStatus.Name]
+	return f
+}
+
+// recordQuotaEvent records an event on a namespace which has reached its
+// injection quota.
+func recordQuotaEvent(ctx context.Context, events *eventRecorder, namespace string, quota int) {
+	events.warn(ctx, namespaceReference(namespace), "InjectionQuotaExceeded",
+		"Sidecar injection skipped, namespace has reached its quota of %d injected pods", quota)
+}
+
+// createQuotaPatch returns a patch which records the skip decision.
+func createQuotaPatch(pod *corev1.Pod) ([]byte, error) {
+	return json.Marshal(updateAnnotation(pod.Annotations, map[string]string{SkipReasonAnnotation: skipReasonQuota}))
+}
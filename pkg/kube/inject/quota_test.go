@@ -0,0 +1,165 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/api/annotation"
+)
+
+func TestNamespaceQuota(t *testing.T) {
+	cases := []struct {
+		name         string
+		annotations  map[string]string
+		defaultQuota int
+		want         int
+	}{
+		{"no annotation", nil, 0, 0},
+		{"default", nil, 10, 10},
+		{"annotation", map[string]string{InjectionQuotaAnnotation: "5"}, 10, 5},
+		{"unlimited annotation", map[string]string{InjectionQuotaAnnotation: "0"}, 10, 0},
+		{"invalid annotation", map[string]string{InjectionQuotaAnnotation: "many"}, 10, 10},
+		{"negative annotation", map[string]string{InjectionQuotaAnnotation: "-1"}, 0, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := namespaceQuota(c.annotations, c.defaultQuota); got != c.want {
+				t.Fatalf("got quota %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestInjectedPodCounter(t *testing.T) {
+	pod := func(name string, injected bool, phase corev1.PodPhase) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "foo"},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+		if injected {
+			p.Annotations = map[string]string{annotation.SidecarStatus.Name: "{}"}
+		}
+		return p
+	}
+	client := fake.NewSimpleClientset(
+		pod("injected", true, corev1.PodRunning),
+		pod("plain", false, corev1.PodRunning),
+		pod("completed", true, corev1.PodSucceeded),
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	counter := newInjectedPodCounter(factory.Core().V1().Pods())
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+	now := time.Now()
+	counter.now = func() time.Time { return now }
+
+	// one injected pod running, so two more are admitted, and a dry run is not counted
+	dryRun := withDryRun(context.TODO(), true)
+	var release func()
+	for i, c := range []struct {
+		ctx  context.Context
+		want bool
+	}{{dryRun, true}, {context.TODO(), true}, {context.TODO(), true}, {dryRun, false}, {context.TODO(), false}} {
+		admitted, r, err := counter.admit(c.ctx, "foo", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if admitted != c.want {
+			t.Fatalf("admission %d: got %v, want %v", i, admitted, c.want)
+		}
+		if admitted {
+			release = r
+		}
+	}
+
+	// a pod admitted but not injected is not counted
+	release()
+	if admitted, _, _ := counter.admit(context.TODO(), "foo", 3); !admitted {
+		t.Fatalf("pod not admitted after an admission was released")
+	}
+
+	// the pending injections whose pods the informer never observes expire
+	now = now.Add(injectedPodsPendingTTL)
+	if admitted, _, _ := counter.admit(context.TODO(), "foo", 3); !admitted {
+		t.Fatalf("pod not admitted after the pending injections expired")
+	}
+}
+
+func TestInjectedPodCounterBurst(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	factory := informers.NewSharedInformerFactory(client, 0)
+	counter := newInjectedPodCounter(factory.Core().V1().Pods())
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+	pending := func() int {
+		counter.mu.Lock()
+		defer counter.mu.Unlock()
+		return len(counter.pending["foo"])
+	}
+
+	// scaling from 0 to the quota admits every pod, whether or not the
+	// informer has observed the previous ones
+	const quota = 10
+	for i := 0; i < quota; i++ {
+		admitted, _, err := counter.admit(context.TODO(), "foo", quota)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !admitted {
+			t.Fatalf("admission %d: pod not admitted below the quota", i)
+		}
+		if i%2 == 1 {
+			continue
+		}
+		created := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("pod-%d", i),
+			Namespace:   "foo",
+			Annotations: map[string]string{annotation.SidecarStatus.Name: "{}"},
+		}}
+		if _, err := client.CoreV1().Pods("foo").Create(context.TODO(), created, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		for deadline := time.Now().Add(5 * time.Second); pending() != i/2; {
+			if time.Now().After(deadline) {
+				t.Fatalf("admission %d: got %d pending injections, want %d", i, pending(), i/2)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if admitted, _, _ := counter.admit(context.TODO(), "foo", quota); admitted {
+		t.Fatalf("pod admitted over the quota")
+	}
+}
+
+func TestRecordQuotaEvent(t *testing.T) {
+	r, recorded := newFakeEventRecorder()
+	recordQuotaEvent(context.TODO(), r, "foo", 3)
+	if len(recorded.events) != 1 || recorded.events[0].reason != "InjectionQuotaExceeded" || recorded.events[0].obj.Namespace != "foo" {
+		t.Fatalf("got events %v, want one quota event in the namespace", recorded.events)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/config/validation"
+)
+
+// readyzPath serves the readiness of the webhook to inject pods.
+const readyzPath = "/readyz"
+
+// readinessCheck is the result of one of the checks of readyzPath.
+type readinessCheck struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// readiness is the response of readyzPath.
+type readiness struct {
+	Ready  bool             `json:"ready"`
+	Checks []readinessCheck `json:"checks"`
+}
+
+// readinessPod is injected to check the template renders.
+var readinessPod = corev1.Pod{
+	ObjectMeta: metav1.ObjectMeta{Name: "readyz", Namespace: "default"},
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "app", Image: "app"}},
+	},
+}
+
+// checkTemplate injects a minimal pod with the current configuration.
+func (wh *Webhook) checkTemplate() error {
+	wh.mu.RLock()
+	config, valuesConfig, version, mc := wh.Config, wh.valuesConfig, wh.sidecarTemplateVersion, wh.meshConfig
+	wh.mu.RUnlock()
+	pod := readinessPod.DeepCopy()
+	params := InjectionParameters{
+		pod:        pod,
+		deployMeta: &pod.ObjectMeta,
+		typeMeta:   &metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		revision:   wh.revision,
+	}.withConfig(config, valuesConfig, version, mc)
+	_, _, err := InjectionData(params, params.typeMeta, params.deployMeta)
+	return err
+}
+
+func (wh *Webhook) checkMeshConfig() error {
+	wh.mu.RLock()
+	mc := wh.meshConfig
+	wh.mu.RUnlock()
+	return validation.ValidateMeshConfig(mc)
+}
+
+// checkServingCertificate returns an error if the serving certificate cannot
+// be parsed or is not valid now.
+func checkServingCertificate(servingCertificate func() (*tls.Certificate, error), now time.Time) error {
+	cert, err := servingCertificate()
+	if err != nil {
+		return err
+	}
+	if cert == nil || len(cert.Certificate) == 0 {
+		return errors.New("no serving certificate is loaded")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid serving certificate: %v", err)
+	}
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("serving certificate is not valid until %v", leaf.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("serving certificate expired at %v", leaf.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// readinessProbe is one of the checks of readyzPath.
+type readinessProbe struct {
+	name  string
+	check func() error
+}
+
+// readiness runs the checks of readyzPath.
+func (wh *Webhook) readiness() readiness {
+	checks := []readinessProbe{
+		{"template", wh.checkTemplate},
+		{"meshConfig", wh.checkMeshConfig},
+	}
+	if wh.servingCertificate != nil {
+		checks = append(checks, readinessProbe{"servingCertificate", func() error {
+			return checkServingCertificate(wh.servingCertificate, time.Now())
+		}})
+	}
+	r := readiness{Ready: true}
+	for _, c := range checks {
+		result := readinessCheck{Name: c.name}
+		if err := c.check(); err != nil {
+			result.Error = err.Error()
+			r.Ready = false
+		}
+		r.Checks = append(r.Checks, result)
+	}
+	return r
+}
+
+// Ready reports whether the webhook is ready to inject pods, with the first
+// failed check, as a readiness probe of the server running it.
+func (wh *Webhook) Ready() (bool, error) {
+	for _, c := range wh.readiness().Checks {
+		if c.Error != "" {
+			return false, fmt.Errorf("%s: %s", c.Name, c.Error)
+		}
+	}
+	return true, nil
+}
+
+// serveReadyz serves the readiness of the webhook to inject pods: the
+// current template renders, the mesh config is valid and the serving
+// certificate is valid, with the reasons of the failed checks.
+func (wh *Webhook) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	r := wh.readiness()
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to marshal readiness: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !r.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write(out)
+}
@@ -0,0 +1,90 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/security/pkg/pki/util"
+)
+
+func TestServeReadyz(t *testing.T) {
+	certPEM, keyPEM, err := util.GenCertKeyFromOptions(util.CertOptions{
+		Host: "istiod.istio-system.svc", TTL: time.Hour, RSAKeySize: 2048, IsSelfSigned: true, IsServer: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validCert := func() (*tls.Certificate, error) { return &cert, nil }
+
+	cases := []struct {
+		name       string
+		template   string
+		cert       func() (*tls.Certificate, error)
+		wantStatus int
+		wantFailed string
+	}{
+		{"ready", "containers:\n- name: istio-proxy\n  image: proxy\n", validCert, http.StatusOK, ""},
+		{"no serving certificate check", "containers:\n- name: istio-proxy\n  image: proxy\n", nil, http.StatusOK, ""},
+		{"broken template", "containers: {{ .Unknown }}", validCert, http.StatusServiceUnavailable, "template"},
+		{"missing certificate", "containers:\n- name: istio-proxy\n  image: proxy\n",
+			func() (*tls.Certificate, error) { return nil, errors.New("not loaded") },
+			http.StatusServiceUnavailable, "servingCertificate"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := mesh.DefaultMeshConfig()
+			wh := &Webhook{
+				Config:             &Config{Policy: InjectionPolicyEnabled, Template: c.template},
+				meshConfig:         &m,
+				valuesConfig:       "{}",
+				servingCertificate: c.cert,
+			}
+			w := httptest.NewRecorder()
+			wh.serveReadyz(w, httptest.NewRequest("GET", readyzPath, nil))
+			if w.Code != c.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", w.Code, c.wantStatus, w.Body.String())
+			}
+			var r readiness
+			if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+				t.Fatal(err)
+			}
+			for _, check := range r.Checks {
+				if failed := check.Error != ""; failed != (check.Name == c.wantFailed) {
+					t.Errorf("unexpected result of check %s: %q", check.Name, check.Error)
+				}
+			}
+			// the same checks gate the readiness of istiod
+			if ready, err := wh.Ready(); ready != (c.wantFailed == "") {
+				t.Errorf("got ready %v, %v", ready, err)
+			}
+		})
+	}
+
+	if err := checkServingCertificate(validCert, time.Now().Add(2*time.Hour)); err == nil {
+		t.Fatalf("expected error for an expired certificate")
+	}
+}
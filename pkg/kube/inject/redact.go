@@ -0,0 +1,170 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const redactedValue = "redacted"
+
+// redactPod returns a copy of the pod without the material that may hold or
+// locate secrets: the values of the environment, the command and arguments of
+// its containers, the names of the secrets it references and its image pull
+// secrets. Every pod the injector logs or keeps must go through it.
+func redactPod(pod *corev1.Pod) *corev1.Pod {
+	if pod == nil {
+		return nil
+	}
+	out := pod.DeepCopy()
+	redactContainers(out.Spec.InitContainers)
+	redactContainers(out.Spec.Containers)
+	redactVolumes(out.Spec.Volumes)
+	redactImagePullSecrets(out.Spec.ImagePullSecrets)
+	return out
+}
+
+func redactContainers(containers []corev1.Container) {
+	for i := range containers {
+		c := &containers[i]
+		for j := range c.Env {
+			e := &c.Env[j]
+			if e.Value != "" {
+				e.Value = redactedValue
+			}
+			if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+				e.ValueFrom.SecretKeyRef.Name = redactedValue
+			}
+		}
+		for j := range c.EnvFrom {
+			if ref := c.EnvFrom[j].SecretRef; ref != nil {
+				ref.Name = redactedValue
+			}
+		}
+		c.Command = nil
+		c.Args = nil
+	}
+}
+
+func redactVolumes(volumes []corev1.Volume) {
+	for i := range volumes {
+		v := &volumes[i].VolumeSource
+		if v.Secret != nil {
+			v.Secret.SecretName = redactedValue
+		}
+		if v.Projected != nil {
+			for j := range v.Projected.Sources {
+				if s := v.Projected.Sources[j].Secret; s != nil {
+					s.Name = redactedValue
+				}
+			}
+		}
+	}
+}
+
+func redactImagePullSecrets(secrets []corev1.LocalObjectReference) {
+	for i := range secrets {
+		secrets[i].Name = redactedValue
+	}
+}
+
+// redactedPodJSON returns the pod encoded in raw, redacted, for logging.
+func redactedPodJSON(raw []byte) string {
+	var pod corev1.Pod
+	if err := json.Unmarshal(raw, &pod); err != nil {
+		return fmt.Sprintf("<%d bytes, not a pod>", len(raw))
+	}
+	out, err := json.Marshal(redactPod(&pod))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(raw))
+	}
+	return string(out)
+}
+
+// redactedPatch returns the JSON patch with the containers, volumes and image
+// pull secrets it adds redacted, for logging.
+func redactedPatch(patch []byte) string {
+	var ops []rfc6902PatchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return fmt.Sprintf("<%d bytes, not a patch>", len(patch))
+	}
+	for i := range ops {
+		ops[i].Value = redactPatchValue(ops[i].Path, ops[i].Value)
+	}
+	out, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(patch))
+	}
+	return string(out)
+}
+
+// redactedPatchPath matches the paths of patch operations on a list of the
+// pod spec to redact, or on one of its items.
+var redactedPatchPath = regexp.MustCompile(`^/spec/(initContainers|containers|volumes|imagePullSecrets)(/([0-9]+|-))?$`)
+
+// redactPatchValue redacts the value of a patch operation on path. Values
+// which cannot be redacted are replaced, so nothing is logged unredacted.
+func redactPatchValue(path string, value interface{}) interface{} {
+	m := redactedPatchPath.FindStringSubmatch(path)
+	if m == nil || value == nil {
+		return value
+	}
+	field := m[1]
+	redact := map[string]func(pod *corev1.Pod){
+		"initContainers":   func(p *corev1.Pod) { redactContainers(p.Spec.InitContainers) },
+		"containers":       func(p *corev1.Pod) { redactContainers(p.Spec.Containers) },
+		"volumes":          func(p *corev1.Pod) { redactVolumes(p.Spec.Volumes) },
+		"imagePullSecrets": func(p *corev1.Pod) { redactImagePullSecrets(p.Spec.ImagePullSecrets) },
+	}[field]
+
+	// the value is either the list, when it is created, or one of its items
+	_, list := value.([]interface{})
+	if list != (m[2] == "") {
+		return redactedValue
+	}
+	items := value
+	if !list {
+		items = []interface{}{value}
+	}
+	data, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{field: items}})
+	if err != nil {
+		return redactedValue
+	}
+	var pod corev1.Pod
+	if err := json.Unmarshal(data, &pod); err != nil {
+		return redactedValue
+	}
+	redact(&pod)
+	data, err = json.Marshal(pod.Spec)
+	if err != nil {
+		return redactedValue
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return redactedValue
+	}
+	redacted, _ := spec[field].([]interface{})
+	if list {
+		return redacted
+	}
+	if len(redacted) != 1 {
+		return redactedValue
+	}
+	return redacted[0]
+}
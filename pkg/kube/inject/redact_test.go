@@ -0,0 +1,116 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// secret is the marker of the material which must never be logged.
+const secret = "s3cr3t"
+
+func secretPod() *corev1.Pod {
+	container := corev1.Container{
+		Name:    "app",
+		Image:   "app",
+		Command: []string{"app", "--token=" + secret},
+		Args:    []string{"--password=" + secret},
+		Env: []corev1.EnvVar{
+			{Name: "TOKEN", Value: secret},
+			{Name: "FROM_SECRET", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret}, Key: "key"},
+			}},
+			{Name: "EMPTY"},
+		},
+		EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret}}}},
+	}
+	return &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{*container.DeepCopy()},
+		Containers:     []corev1.Container{container},
+		Volumes: []corev1.Volume{
+			{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secret}}},
+			{Name: "projected", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: secret}}}},
+			}}},
+		},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: secret}},
+	}}
+}
+
+func TestRedactPod(t *testing.T) {
+	pod := secretPod()
+	got := redactPod(pod)
+	out, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), secret) {
+		t.Fatalf("secret material in redacted pod: %s", out)
+	}
+	c := got.Spec.Containers[0]
+	if c.Name != "app" || c.Image != "app" || c.Env[0].Name != "TOKEN" || c.Env[2].Value != "" || got.Spec.Volumes[0].Name != "certs" {
+		t.Fatalf("redacted more than secret material: %+v", got.Spec)
+	}
+	if pod.Spec.Containers[0].Env[0].Value != secret {
+		t.Fatalf("original pod modified")
+	}
+}
+
+func TestRedactedPodJSON(t *testing.T) {
+	raw, err := json.Marshal(secretPod())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range [][]byte{raw, []byte(`{"spec": "` + secret + `"}`), nil} {
+		if out := redactedPodJSON(in); strings.Contains(out, secret) {
+			t.Fatalf("secret material logged: %s", out)
+		}
+	}
+}
+
+func TestRedactedPatch(t *testing.T) {
+	pod := secretPod()
+	patch, err := json.Marshal([]rfc6902PatchOperation{
+		{Op: "add", Path: "/spec/initContainers", Value: pod.Spec.InitContainers},
+		{Op: "add", Path: "/spec/containers/-", Value: pod.Spec.Containers[0]},
+		{Op: "add", Path: "/spec/volumes/0", Value: pod.Spec.Volumes[0]},
+		{Op: "add", Path: "/spec/volumes/-", Value: pod.Spec.Volumes[1]},
+		{Op: "add", Path: "/spec/imagePullSecrets", Value: pod.Spec.ImagePullSecrets},
+		{Op: "add", Path: "/spec/containers/0", Value: pod.Spec.Containers},
+		{Op: "remove", Path: "/spec/containers/1"},
+		{Op: "add", Path: "/metadata/annotations/example.com~1key", Value: "value"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := redactedPatch(patch)
+	if strings.Contains(out, secret) {
+		t.Fatalf("secret material logged: %s", out)
+	}
+	var ops []rfc6902PatchOperation
+	if err := json.Unmarshal([]byte(out), &ops); err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 8 || ops[6].Value != nil || ops[7].Value != "value" {
+		t.Fatalf("patch operations not kept: %s", out)
+	}
+	if strings.Contains(redactedPatch([]byte(secret)), secret) {
+		t.Fatalf("invalid patch logged")
+	}
+}
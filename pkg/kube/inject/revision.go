@@ -0,0 +1,55 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"istio.io/api/label"
+)
+
+const skipReasonRevision = "revision"
+
+// defaultRevision is the istio.io/rev label value of the default revision.
+const defaultRevision = "default"
+
+// revisionSkipReason returns why the pod is not injected by the injector of
+// the revision, or "" if it may be. The istio.io/rev label of the pod takes
+// precedence over the one of its namespace. The injector of the default
+// revision injects the pods without a revision, and skips the pods of the
+// other revisions. The pods whose namespace is not known are left to the
+// selectors of the webhook config.
+func revisionSkipReason(revision string, podLabels, nsLabels map[string]string, nsKnown bool) string {
+	matches := func(rev string) bool {
+		return rev == revision || (revision == "" && rev == defaultRevision)
+	}
+	if rev, f := podLabels[label.IstioRev]; f {
+		if matches(rev) {
+			return ""
+		}
+		return skipReasonRevision
+	}
+	if !nsKnown {
+		return ""
+	}
+	if rev, f := nsLabels[label.IstioRev]; f {
+		if matches(rev) {
+			return ""
+		}
+		return skipReasonRevision
+	}
+	if revision == "" {
+		return ""
+	}
+	return skipReasonRevision
+}
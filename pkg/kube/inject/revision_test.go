@@ -0,0 +1,50 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+)
+
+func TestRevisionSkipReason(t *testing.T) {
+	canary := map[string]string{"istio.io/rev": "canary"}
+	stable := map[string]string{"istio.io/rev": "stable"}
+	cases := []struct {
+		name      string
+		revision  string
+		pod, ns   map[string]string
+		nsUnknown bool
+		want      string
+	}{
+		{name: "default revision"},
+		{name: "default revision, pod of another revision", pod: stable, ns: stable, want: skipReasonRevision},
+		{name: "default revision, namespace of another revision", ns: canary, want: skipReasonRevision},
+		{name: "default revision, labeled default", pod: map[string]string{"istio.io/rev": "default"}, ns: stable},
+		{name: "default revision, unknown namespace", nsUnknown: true},
+		{name: "namespace of the revision", revision: "canary", ns: canary},
+		{name: "namespace of another revision", revision: "canary", ns: stable, want: skipReasonRevision},
+		{name: "namespace without revision", revision: "canary", want: skipReasonRevision},
+		{name: "pod of the revision", revision: "canary", pod: canary, ns: stable},
+		{name: "pod of another revision", revision: "canary", pod: stable, ns: canary, want: skipReasonRevision},
+		{name: "unknown namespace", revision: "canary", nsUnknown: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := revisionSkipReason(c.revision, c.pod, c.ns, !c.nsUnknown); got != c.want {
+				t.Fatalf("got skip reason %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+const (
+	// defaultServiceAccountName is the service account pods run as when none is set.
+	defaultServiceAccountName = "default"
+
+	// allNamespaces is the ServiceAccountPolicies key matching any namespace.
+	allNamespaces = "*"
+)
+
+// ServiceAccountPolicy selects the service accounts eligible for injection.
+// Deny takes precedence over Allow. An empty Allow list allows every service
+// account not denied.
+type ServiceAccountPolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+func (p ServiceAccountPolicy) allowed(serviceAccount string) bool {
+	for _, sa := range p.Deny {
+		if sa == serviceAccount {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, sa := range p.Allow {
+		if sa == serviceAccount {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceAccountAllowed reports whether pods of the service account in the
+// namespace may be injected according to the config.
+func serviceAccountAllowed(config *Config, namespace, serviceAccount string) bool {
+	if len(config.ServiceAccountPolicies) == 0 {
+		return true
+	}
+	if serviceAccount == "" {
+		serviceAccount = defaultServiceAccountName
+	}
+	policy, f := config.ServiceAccountPolicies[namespace]
+	if !f {
+		if policy, f = config.ServiceAccountPolicies[allNamespaces]; !f {
+			return true
+		}
+	}
+	return policy.allowed(serviceAccount)
+}
@@ -0,0 +1,48 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+)
+
+func TestServiceAccountAllowed(t *testing.T) {
+	config := &Config{
+		ServiceAccountPolicies: map[string]ServiceAccountPolicy{
+			"shared": {Allow: []string{"meshed"}},
+			"*":      {Deny: []string{"legacy"}},
+		},
+	}
+	cases := []struct {
+		namespace      string
+		serviceAccount string
+		want           bool
+	}{
+		{"shared", "meshed", true},
+		{"shared", "other", false},
+		{"shared", "", false},
+		{"team", "other", true},
+		{"team", "legacy", false},
+		{"team", "", true},
+	}
+	for _, c := range cases {
+		if got := serviceAccountAllowed(config, c.namespace, c.serviceAccount); got != c.want {
+			t.Errorf("serviceAccountAllowed(%q, %q) got %v, want %v", c.namespace, c.serviceAccount, got, c.want)
+		}
+	}
+	if !serviceAccountAllowed(&Config{}, "any", "any") {
+		t.Errorf("expected all service accounts to be allowed without policies")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/kube"
+	"istio.io/pkg/log"
+)
+
+const (
+	// skipReasonStartup is the reason of the pods admitted without injection
+	// before the configuration is validated.
+	skipReasonStartup = "startup"
+
+	// startupCheckInterval bounds how often the configuration is validated
+	// while admission requests arrive during startup.
+	startupCheckInterval = time.Second
+)
+
+// StartupGateOptions answers the admission requests received before the
+// checks of /readyz first pass with a well-formed admission response rather
+// than attempting the injection, so the behavior of a cold start is the same
+// whether the listener is not yet open or the configuration not yet valid.
+type StartupGateOptions struct {
+	// Enabled gates the admissions until the configuration and the serving
+	// certificate are validated.
+	Enabled bool
+
+	// SoftFail admits the pods without injection until then, as a webhook
+	// with the Ignore failure policy would. Otherwise they are rejected with
+	// a 503 status.
+	SoftFail bool
+}
+
+// startupGate remembers once the configuration has been validated.
+type startupGate struct {
+	options StartupGateOptions
+
+	mu        sync.Mutex
+	validated bool
+	lastCheck time.Time
+	lastError string
+}
+
+func newStartupGate(o StartupGateOptions) *startupGate {
+	if !o.Enabled {
+		return nil
+	}
+	return &startupGate{options: o}
+}
+
+// admission returns the response to the admission request while the
+// configuration is not validated, nil once it is.
+func (g *startupGate) admission(wh *Webhook, ar *kube.AdmissionReview) *kube.AdmissionResponse {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	if !g.validated && time.Since(g.lastCheck) >= startupCheckInterval {
+		g.lastCheck = time.Now()
+		g.validated, g.lastError = startupReadiness(wh.readiness())
+		if g.validated {
+			log.Infof("Injection configuration validated, serving injection")
+		}
+	}
+	validated, reason := g.validated, g.lastError
+	g.mu.Unlock()
+	if validated {
+		return nil
+	}
+
+	namespace := ""
+	if ar != nil && ar.Request != nil {
+		namespace = ar.Request.Namespace
+	}
+	if g.options.SoftFail {
+		log.Infof("Admitting a pod of %s without injection, the injection configuration is not validated: %s", namespace, reason)
+		totalSkippedInjections.With(reasonTag.Value(skipReasonStartup)).Increment()
+		return &kube.AdmissionResponse{Allowed: true}
+	}
+	handleError(fmt.Sprintf("Rejecting a pod of %s, the injection configuration is not validated: %s", namespace, reason))
+	return &kube.AdmissionResponse{
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusServiceUnavailable,
+			Reason:  metav1.StatusReasonServiceUnavailable,
+			Message: "sidecar injector is starting: " + reason,
+		},
+	}
+}
+
+// startupReadiness returns whether all the checks passed, or the failed ones.
+func startupReadiness(r readiness) (bool, string) {
+	if r.Ready {
+		return true, ""
+	}
+	var failed []string
+	for _, c := range r.Checks {
+		if c.Error != "" {
+			failed = append(failed, c.Name+": "+c.Error)
+		}
+	}
+	return false, strings.Join(failed, "; ")
+}
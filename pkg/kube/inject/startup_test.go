@@ -0,0 +1,89 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube"
+	"istio.io/istio/security/pkg/pki/util"
+)
+
+func TestStartupGate(t *testing.T) {
+	certPEM, keyPEM, err := util.GenCertKeyFromOptions(util.CertOptions{
+		Host: "istiod.istio-system.svc", TTL: time.Hour, RSAKeySize: 2048, IsSelfSigned: true, IsServer: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name        string
+		softFail    bool
+		wantAllowed bool
+	}{
+		{"reject", false, false},
+		{"soft fail", true, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			loaded := false
+			m := mesh.DefaultMeshConfig()
+			wh := &Webhook{
+				Config:       &Config{Policy: InjectionPolicyEnabled, Template: "containers:\n- name: istio-proxy\n  image: proxy\n"},
+				meshConfig:   &m,
+				valuesConfig: "{}",
+				servingCertificate: func() (*tls.Certificate, error) {
+					if !loaded {
+						return nil, errors.New("not loaded")
+					}
+					return &cert, nil
+				},
+			}
+			gate := newStartupGate(StartupGateOptions{Enabled: true, SoftFail: c.softFail})
+			ar := &kube.AdmissionReview{Request: &kube.AdmissionRequest{Namespace: "default"}}
+
+			resp := gate.admission(wh, ar)
+			if resp == nil || resp.Allowed != c.wantAllowed || len(resp.Patch) != 0 {
+				t.Fatalf("unexpected response before validation: %+v", resp)
+			}
+			if !c.softFail && resp.Result.Code != http.StatusServiceUnavailable {
+				t.Fatalf("got status %d, want %d", resp.Result.Code, http.StatusServiceUnavailable)
+			}
+
+			loaded = true
+			if resp := gate.admission(wh, ar); resp == nil {
+				t.Fatal("the configuration should not be validated again before the check interval")
+			}
+			gate.lastCheck = time.Time{}
+			if resp := gate.admission(wh, ar); resp != nil {
+				t.Fatalf("unexpected response once validated: %+v", resp)
+			}
+		})
+	}
+
+	if resp := newStartupGate(StartupGateOptions{}).admission(&Webhook{}, nil); resp != nil {
+		t.Fatalf("disabled gate answered %+v", resp)
+	}
+}
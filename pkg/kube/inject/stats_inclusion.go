@@ -0,0 +1,133 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/api/annotation"
+)
+
+const (
+	// StatsInclusionAnnotation selects the stats inclusion preset of the proxy
+	// of the pod, overriding the StatsInclusion of the injection config.
+	StatsInclusionAnnotation = "sidecar.istio.io/statsInclusion"
+
+	// statsInclusionEnv is the proxy metadata the preset is rendered into, as
+	// the inclusion annotations read by the bootstrap of the proxy.
+	statsInclusionEnv = "ISTIO_METAJSON_STATS_INCLUSION"
+)
+
+// Stats inclusion presets. The stats required by Istio are always included.
+const (
+	// StatsInclusionMinimal only includes the stats required by Istio.
+	StatsInclusionMinimal = "minimal"
+	// StatsInclusionStandard adds the stats of the inbound and outbound
+	// clusters and of the listeners and HTTP connection managers.
+	StatsInclusionStandard = "standard"
+	// StatsInclusionFull includes all Envoy stats.
+	StatsInclusionFull = "full"
+)
+
+// StatsInclusionConfig selects the stats inclusion preset of injected proxies.
+type StatsInclusionConfig struct {
+	// Default is the preset of the proxies of the mesh.
+	Default string `json:"default,omitempty"`
+
+	// WorkloadKinds overrides Default for the workloads of a kind, e.g. Job.
+	WorkloadKinds map[string]string `json:"workloadKinds,omitempty"`
+}
+
+// statsInclusionPresets are the inclusion annotations set by each preset.
+var statsInclusionPresets = map[string]map[string]string{
+	StatsInclusionMinimal: {},
+	StatsInclusionStandard: {
+		annotation.SidecarStatsInclusionPrefixes.Name: "cluster.inbound,cluster.outbound,listener,http",
+	},
+	StatsInclusionFull: {
+		annotation.SidecarStatsInclusionRegexps.Name: ".*",
+	},
+}
+
+func validateStatsInclusionPreset(preset string) error {
+	if _, f := statsInclusionPresets[preset]; !f {
+		return fmt.Errorf("unknown stats inclusion preset %q: must be %s, %s or %s",
+			preset, StatsInclusionMinimal, StatsInclusionStandard, StatsInclusionFull)
+	}
+	return nil
+}
+
+func validateStatsInclusion(c *StatsInclusionConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.Default != "" {
+		if err := validateStatsInclusionPreset(c.Default); err != nil {
+			return err
+		}
+	}
+	for kind, preset := range c.WorkloadKinds {
+		if err := validateStatsInclusionPreset(preset); err != nil {
+			return fmt.Errorf("workload kind %s: %v", kind, err)
+		}
+	}
+	return nil
+}
+
+// statsInclusionPreset returns the preset of the pod: the one of its
+// annotation, else the one of its workload kind, else the default. Pods
+// setting the inclusion annotations themselves get no preset.
+func statsInclusionPreset(c *StatsInclusionConfig, annotations map[string]string, workloadKind string) string {
+	for _, a := range []string{annotation.SidecarStatsInclusionPrefixes.Name,
+		annotation.SidecarStatsInclusionSuffixes.Name, annotation.SidecarStatsInclusionRegexps.Name} {
+		if _, f := annotations[a]; f {
+			return ""
+		}
+	}
+	if preset, f := annotations[StatsInclusionAnnotation]; f {
+		return preset
+	}
+	if c == nil {
+		return ""
+	}
+	if preset, f := c.WorkloadKinds[workloadKind]; f {
+		return preset
+	}
+	return c.Default
+}
+
+// applyStatsInclusion renders the inclusion annotations of the preset into
+// the metadata of the proxy, which its bootstrap reads them from.
+func applyStatsInclusion(sidecar *corev1.Container, preset string) error {
+	if sidecar == nil || preset == "" {
+		return nil
+	}
+	if err := validateStatsInclusionPreset(preset); err != nil {
+		return err
+	}
+	inclusion := statsInclusionPresets[preset]
+	if len(inclusion) == 0 {
+		return nil
+	}
+	value, err := json.Marshal(inclusion)
+	if err != nil {
+		return err
+	}
+	updateClusterEnvs(sidecar, map[string]string{statsInclusionEnv: string(value)})
+	return nil
+}
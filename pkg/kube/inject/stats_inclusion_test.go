@@ -0,0 +1,90 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/api/annotation"
+)
+
+func TestStatsInclusionPreset(t *testing.T) {
+	config := &StatsInclusionConfig{
+		Default:       StatsInclusionStandard,
+		WorkloadKinds: map[string]string{"Job": StatsInclusionMinimal},
+	}
+	cases := []struct {
+		name        string
+		config      *StatsInclusionConfig
+		annotations map[string]string
+		kind        string
+		want        string
+	}{
+		{"no config", nil, nil, "Deployment", ""},
+		{"default", config, nil, "Deployment", StatsInclusionStandard},
+		{"workload kind", config, nil, "Job", StatsInclusionMinimal},
+		{"annotation", config, map[string]string{StatsInclusionAnnotation: StatsInclusionFull}, "Job", StatsInclusionFull},
+		{"annotation without config", nil, map[string]string{StatsInclusionAnnotation: StatsInclusionFull}, "", StatsInclusionFull},
+		{"explicit inclusion", config, map[string]string{
+			StatsInclusionAnnotation:                      StatsInclusionFull,
+			annotation.SidecarStatsInclusionPrefixes.Name: "cluster.outbound",
+		}, "Deployment", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := statsInclusionPreset(c.config, c.annotations, c.kind); got != c.want {
+				t.Fatalf("got preset %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestApplyStatsInclusion(t *testing.T) {
+	cases := []struct {
+		preset string
+		want   string
+	}{
+		{"", ""},
+		{StatsInclusionMinimal, ""},
+		{StatsInclusionStandard, `{"sidecar.istio.io/statsInclusionPrefixes":"cluster.inbound,cluster.outbound,listener,http"}`},
+		{StatsInclusionFull, `{"sidecar.istio.io/statsInclusionRegexps":".*"}`},
+	}
+	for _, c := range cases {
+		t.Run(c.preset, func(t *testing.T) {
+			sidecar := &corev1.Container{Name: ProxyContainerName}
+			if err := applyStatsInclusion(sidecar, c.preset); err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			for _, e := range sidecar.Env {
+				if e.Name == statsInclusionEnv {
+					got = e.Value
+				}
+			}
+			if got != c.want {
+				t.Fatalf("got %s %q, want %q", statsInclusionEnv, got, c.want)
+			}
+		})
+	}
+
+	if err := applyStatsInclusion(&corev1.Container{}, "everything"); err == nil {
+		t.Fatalf("expected error for an unknown preset")
+	}
+	if err := validateStatsInclusion(&StatsInclusionConfig{WorkloadKinds: map[string]string{"Job": "none"}}); err == nil {
+		t.Fatalf("expected error for an unknown workload kind preset")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"go.opencensus.io/stats/view"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// PrometheusMetricsBackend serves metrics for scraping on the monitoring port.
+	PrometheusMetricsBackend = "prometheus"
+
+	// StatsdMetricsBackend pushes metrics to a statsd server.
+	StatsdMetricsBackend = "statsd"
+
+	// maxStatsdPacketSize keeps packets below the typical network MTU.
+	maxStatsdPacketSize = 1432
+)
+
+// statsdExporter is an OpenCensus exporter pushing the views of the injector
+// metrics to statsd. The monitoring library records cumulative values, so
+// sums and counts are sent as counters of the increase since the last
+// export, last values as gauges, and distributions, all durations in
+// seconds, as timers of the mean of the new samples, sampled at the rate
+// their count makes up. Tag values are appended to the metric name.
+type statsdExporter struct {
+	prefix string
+	conn   net.Conn
+	// views are the names of the views exported, the other metrics of istiod
+	// are left to its own exporters.
+	views map[string]bool
+
+	mu sync.Mutex
+	// sums and dists hold the values exported last, by statsd name.
+	sums  map[string]float64
+	dists map[string]statsdDistribution
+}
+
+type statsdDistribution struct {
+	count int64
+	sum   float64
+}
+
+func newStatsdExporter(address, prefix string) (*statsdExporter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to statsd at %s: %v", address, err)
+	}
+	views := make(map[string]bool, len(injectorMetrics))
+	for _, m := range injectorMetrics {
+		views[m.Name()] = true
+	}
+	return &statsdExporter{
+		prefix: prefix,
+		conn:   conn,
+		views:  views,
+		sums:   map[string]float64{},
+		dists:  map[string]statsdDistribution{},
+	}, nil
+}
+
+// ExportView implements view.Exporter.
+func (e *statsdExporter) ExportView(vd *view.Data) {
+	if !e.views[vd.View.Name] {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var packet bytes.Buffer
+	for _, row := range vd.Rows {
+		line := e.format(vd.View.Name, row)
+		if line == "" {
+			continue
+		}
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > maxStatsdPacketSize {
+			e.send(packet.Bytes())
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() > 0 {
+		e.send(packet.Bytes())
+	}
+}
+
+// format returns the statsd line of the row, or "" if nothing changed since
+// it was exported last.
+func (e *statsdExporter) format(name string, row *view.Row) string {
+	parts := []string{sanitizeStatsdName(name)}
+	if e.prefix != "" {
+		parts = append([]string{e.prefix}, parts...)
+	}
+	for _, t := range row.Tags {
+		parts = append(parts, sanitizeStatsdName(t.Value))
+	}
+	metric := strings.Join(parts, ".")
+
+	switch d := row.Data.(type) {
+	case *view.CountData:
+		return e.counter(metric, float64(d.Value))
+	case *view.SumData:
+		return e.counter(metric, d.Value)
+	case *view.LastValueData:
+		return fmt.Sprintf("%s:%g|g", metric, d.Value)
+	case *view.DistributionData:
+		last := e.dists[metric]
+		cur := statsdDistribution{count: d.Count, sum: d.Mean * float64(d.Count)}
+		e.dists[metric] = cur
+		if cur.count < last.count {
+			// the view was reset, e.g. unregistered and registered again
+			last = statsdDistribution{}
+		}
+		samples := cur.count - last.count
+		if samples <= 0 {
+			return ""
+		}
+		mean := (cur.sum - last.sum) / float64(samples) * 1000
+		if samples == 1 {
+			return fmt.Sprintf("%s:%g|ms", metric, mean)
+		}
+		return fmt.Sprintf("%s:%g|ms|@%g", metric, mean, 1/float64(samples))
+	}
+	return ""
+}
+
+// counter returns the counter line of the increase of the cumulative value.
+func (e *statsdExporter) counter(metric string, value float64) string {
+	last, f := e.sums[metric]
+	e.sums[metric] = value
+	if value < last {
+		// the view was reset
+		last = 0
+	}
+	if f && value == last {
+		return ""
+	}
+	return fmt.Sprintf("%s:%g|c", metric, value-last)
+}
+
+func (e *statsdExporter) send(packet []byte) {
+	if _, err := e.conn.Write(packet); err != nil {
+		log.Debugf("Failed to push metrics to statsd: %v", err)
+	}
+}
+
+func (e *statsdExporter) close() error {
+	view.UnregisterExporter(e)
+	return e.conn.Close()
+}
+
+// sanitizeStatsdName replaces characters with a special meaning in statsd.
+func sanitizeStatsdName(name string) string {
+	return strings.NewReplacer(":", "_", "|", "_", "@", "_", ".", "_", " ", "_", "\n", "_").Replace(name)
+}
@@ -0,0 +1,86 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestStatsdExporter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	e, err := newStatsdExporter(conn.LocalAddr().String(), "istio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.conn.Close()
+
+	read := func() string {
+		buf := make([]byte, maxStatsdPacketSize)
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf[:n])
+	}
+
+	key := tag.MustNewKey("type")
+	sum := func(v float64) *view.Data {
+		return &view.Data{
+			View: &view.View{Name: totalInjections.Name()},
+			Rows: []*view.Row{{Tags: []tag.Tag{{Key: key, Value: "ok"}}, Data: &view.SumData{Value: v}}},
+		}
+	}
+	e.ExportView(sum(3))
+	if got, want := read(), "istio.sidecar_injection_requests_total.ok:3|c"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	// counters are sent the increase since the last export
+	e.ExportView(sum(5))
+	if got, want := read(), "istio.sidecar_injection_requests_total.ok:2|c"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// views of other metrics are not exported
+	e.ExportView(&view.Data{
+		View: &view.View{Name: "pilot_xds"},
+		Rows: []*view.Row{{Data: &view.SumData{Value: 1}}},
+	})
+	dist := func(count int64, mean float64) *view.Data {
+		return &view.Data{
+			View: &view.View{Name: admissionDuration.Name()},
+			Rows: []*view.Row{{Data: &view.DistributionData{Count: count, Mean: mean}}},
+		}
+	}
+	e.ExportView(dist(1, 0.5))
+	if got, want := read(), "istio.sidecar_injection_admission_duration_seconds:500|ms"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	// the mean of the new samples, sampled at their count
+	e.ExportView(dist(3, 0.75))
+	if got, want := read(), "istio.sidecar_injection_admission_duration_seconds:875|ms|@0.5"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
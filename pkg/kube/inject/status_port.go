@@ -0,0 +1,181 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pkg/kube/inject/annotations"
+	"istio.io/pkg/log"
+)
+
+// statusPortName is the name of the container port declared on the proxy for
+// the agent's merged health and stats endpoint.
+const statusPortName = "http-status"
+
+// fixedProxyPorts are the ports the proxy listens on regardless of configuration.
+var fixedProxyPorts = map[int32]string{
+	15000: "envoy admin",
+	15001: "outbound capture",
+	15006: "inbound capture",
+	15021: "health check",
+	15090: "envoy prometheus",
+}
+
+// podStatusPort returns the agent status port of the pod, taking the status
+// port annotation into account. Zero means the status port is disabled.
+func podStatusPort(annos map[string]string, defaultPort int32) int32 {
+	p, err := annotations.GetPort(annos, annotation.SidecarStatusPort.Name, int(defaultPort))
+	if err != nil {
+		return defaultPort
+	}
+	return int32(p)
+}
+
+// validatePortCollisions returns an error if an application container
+// declares a port used by the proxy.
+func validatePortCollisions(spec *corev1.PodSpec, statusPort int32) (err error) {
+	for _, c := range spec.Containers {
+		if c.Name == ProxyContainerName {
+			continue
+		}
+		for _, p := range c.Ports {
+			if p.Protocol == corev1.ProtocolUDP || p.Protocol == corev1.ProtocolSCTP {
+				continue
+			}
+			if use, f := fixedProxyPorts[p.ContainerPort]; f {
+				err = multierror.Append(err, fmt.Errorf("port %d of container %q collides with the proxy %s port",
+					p.ContainerPort, c.Name, use))
+			} else if statusPort != 0 && p.ContainerPort == statusPort {
+				err = multierror.Append(err, fmt.Errorf("port %d of container %q collides with the proxy status port",
+					p.ContainerPort, c.Name))
+			}
+		}
+	}
+	return err
+}
+
+// PortCollisionPolicy selects how pods whose application containers declare
+// a port used by the proxy are handled.
+type PortCollisionPolicy string
+
+const (
+	// PortCollisionWarn injects the pods, logging the collisions. This is the
+	// default, as the application may never listen on the declared port.
+	PortCollisionWarn PortCollisionPolicy = "warn"
+	// PortCollisionReject rejects the pods.
+	PortCollisionReject PortCollisionPolicy = "reject"
+)
+
+func validatePortCollisionPolicy(p PortCollisionPolicy) error {
+	switch p {
+	case "", PortCollisionWarn, PortCollisionReject:
+		return nil
+	}
+	return fmt.Errorf("unknown port collision policy %q: must be %s or %s", p, PortCollisionWarn, PortCollisionReject)
+}
+
+// checkPortCollisions applies the port collision policy to the pod: the
+// collisions are returned with PortCollisionReject, and logged otherwise.
+func checkPortCollisions(policy PortCollisionPolicy, pod *corev1.Pod, statusPort int32) error {
+	err := validatePortCollisions(&pod.Spec, statusPort)
+	if err == nil || policy == PortCollisionReject {
+		return err
+	}
+	log.Warnf("Injecting %s/%s with ports colliding with the proxy: %v", pod.Namespace, potentialPodName(&pod.ObjectMeta), err)
+	return nil
+}
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+}
+
+func validateStatusPortRange(r *PortRange) error {
+	if r == nil {
+		return nil
+	}
+	if r.From <= 0 || r.To > 65535 || r.From > r.To {
+		return fmt.Errorf("invalid status port range %d-%d", r.From, r.To)
+	}
+	return nil
+}
+
+// allocateStatusPort returns the port the status port of the pod is moved
+// to when an application container declares it, picked from the range
+// among the ports not declared by any container nor used by the proxy. No
+// port is allocated if the pod sets its status port with the annotation, in
+// which case collisions are left to the port collision policy.
+func allocateStatusPort(pod *corev1.Pod, defaultPort int32, r *PortRange) (int32, error) {
+	if r == nil || defaultPort == 0 {
+		return 0, nil
+	}
+	if _, f := pod.Annotations[annotation.SidecarStatusPort.Name]; f {
+		return 0, nil
+	}
+	declared := map[int32]bool{}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == ProxyContainerName {
+			continue
+		}
+		for _, p := range c.Ports {
+			declared[p.ContainerPort] = true
+		}
+	}
+	if !declared[defaultPort] {
+		return 0, nil
+	}
+	for port := r.From; port <= r.To; port++ {
+		if _, f := fixedProxyPorts[port]; !f && !declared[port] {
+			return port, nil
+		}
+	}
+	return 0, errors.New("no free port in the status port range")
+}
+
+// withStatusPort returns a copy of the pod with its status port set by annotation.
+func withStatusPort(pod *corev1.Pod, port int32) *corev1.Pod {
+	pod = pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[annotation.SidecarStatusPort.Name] = strconv.Itoa(int(port))
+	return pod
+}
+
+// declareStatusPort adds the status port to the proxy container ports so
+// scrapers and kubelet can address the merged endpoint by name.
+func declareStatusPort(sidecar *corev1.Container, statusPort int32) {
+	if sidecar == nil || statusPort == 0 {
+		return
+	}
+	for _, p := range sidecar.Ports {
+		if p.ContainerPort == statusPort {
+			return
+		}
+	}
+	sidecar.Ports = append(sidecar.Ports, corev1.ContainerPort{
+		Name:          statusPortName,
+		ContainerPort: statusPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/api/annotation"
+)
+
+func TestValidatePortCollisions(t *testing.T) {
+	container := func(name string, port int32, proto corev1.Protocol) corev1.Container {
+		return corev1.Container{Name: name, Ports: []corev1.ContainerPort{{ContainerPort: port, Protocol: proto}}}
+	}
+	cases := []struct {
+		name       string
+		containers []corev1.Container
+		statusPort int32
+		wantErr    bool
+	}{
+		{"no collision", []corev1.Container{container("app", 8080, corev1.ProtocolTCP)}, 15020, false},
+		{"status port", []corev1.Container{container("app", 15020, corev1.ProtocolTCP)}, 15020, true},
+		{"status port disabled", []corev1.Container{container("app", 15020, corev1.ProtocolTCP)}, 0, false},
+		{"fixed port", []corev1.Container{container("app", 15001, corev1.ProtocolTCP)}, 15020, true},
+		{"udp", []corev1.Container{container("app", 15001, corev1.ProtocolUDP)}, 15020, false},
+		{"proxy", []corev1.Container{container(ProxyContainerName, 15090, corev1.ProtocolTCP)}, 15020, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validatePortCollisions(&corev1.PodSpec{Containers: c.containers}, c.statusPort)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got err %v, wantErr %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPortCollisions(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 15020}}},
+	}}}
+	for _, policy := range []PortCollisionPolicy{"", PortCollisionWarn} {
+		if err := checkPortCollisions(policy, pod, 15020); err != nil {
+			t.Fatalf("policy %q: unexpected error %v", policy, err)
+		}
+	}
+	if err := checkPortCollisions(PortCollisionReject, pod, 15020); err == nil {
+		t.Fatalf("collision not rejected")
+	}
+	if err := validatePortCollisionPolicy("block"); err == nil {
+		t.Fatalf("unknown policy accepted")
+	}
+}
+
+func TestDeclareStatusPort(t *testing.T) {
+	if got := podStatusPort(map[string]string{annotation.SidecarStatusPort.Name: "15021"}, 15020); got != 15021 {
+		t.Fatalf("podStatusPort() got %d, want 15021", got)
+	}
+	sidecar := &corev1.Container{Name: ProxyContainerName}
+	declareStatusPort(sidecar, 15020)
+	declareStatusPort(sidecar, 15020)
+	if len(sidecar.Ports) != 1 || sidecar.Ports[0].Name != statusPortName || sidecar.Ports[0].ContainerPort != 15020 {
+		t.Fatalf("unexpected ports %v", sidecar.Ports)
+	}
+}
+
+func TestAllocateStatusPort(t *testing.T) {
+	pod := func(annotations map[string]string, ports ...int32) *corev1.Pod {
+		app := corev1.Container{Name: "app"}
+		for _, p := range ports {
+			app.Ports = append(app.Ports, corev1.ContainerPort{ContainerPort: p})
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{app}},
+		}
+	}
+	r := &PortRange{From: 15020, To: 15030}
+	cases := []struct {
+		name      string
+		pod       *corev1.Pod
+		portRange *PortRange
+		want      int32
+		wantErr   bool
+	}{
+		{"no collision", pod(nil, 8080), r, 0, false},
+		{"no range", pod(nil, 15020), nil, 0, false},
+		{"collision", pod(nil, 15020), r, 15022, false},
+		{"declared ports skipped", pod(nil, 15020, 15022), r, 15023, false},
+		{"annotated", pod(map[string]string{annotation.SidecarStatusPort.Name: "15020"}, 15020), r, 0, false},
+		{"range exhausted", pod(nil, 15020), &PortRange{From: 15020, To: 15021}, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := allocateStatusPort(c.pod, 15020, c.portRange)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got err %v, wantErr %v", err, c.wantErr)
+			}
+			if got != c.want {
+				t.Fatalf("got port %d, want %d", got, c.want)
+			}
+		})
+	}
+
+	renumbered := withStatusPort(pod(nil, 15020), 15022)
+	if got := podStatusPort(renumbered.Annotations, 15020); got != 15022 {
+		t.Fatalf("podStatusPort() got %d, want 15022", got)
+	}
+	if err := validatePortCollisions(&renumbered.Spec, 15022); err != nil {
+		t.Fatalf("unexpected collision after renumbering: %v", err)
+	}
+}
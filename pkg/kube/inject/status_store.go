@@ -0,0 +1,301 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"istio.io/api/annotation"
+	"istio.io/pkg/log"
+)
+
+const (
+	// StatusConfigMapName is the ConfigMap of each namespace holding the
+	// injection status records too large for the status annotation.
+	StatusConfigMapName = "istio-sidecar-injection-status"
+
+	statusRefPrefix = "sha256:"
+
+	// statusStoreRetry is the number of retries of the deletion of the
+	// unused records of a namespace.
+	statusStoreRetry = 5
+
+	// maxAnnotationsSize is the total size of the annotations of an object
+	// allowed by the API server.
+	maxAnnotationsSize = 256 * 1024
+
+	// statusRecordGracePeriod is how long a stored record is kept without a
+	// pod referencing it, the time for the informer to observe the pod.
+	statusRecordGracePeriod = time.Minute
+)
+
+// statusStore keeps injection status records in the StatusConfigMapName
+// ConfigMap of the namespace of the pod, keyed by the hash of the record,
+// replacing them in the status annotation with a reference. When the pods are
+// watched, the records no pod references anymore are deleted as the pods are.
+type statusStore struct {
+	client kubernetes.Interface
+	pods   corelisters.PodLister
+	synced cache.InformerSynced
+	queue  workqueue.RateLimitingInterface
+	now    func() time.Time
+
+	mu sync.Mutex
+	// stored are the times the records were stored, by namespace and key,
+	// so the records of pods not observed yet are not deleted.
+	stored map[string]time.Time
+}
+
+// newStatusStore creates a store, garbage collecting its records if the pods
+// are not nil.
+func newStatusStore(client kubernetes.Interface, pods coreinformers.PodInformer) *statusStore {
+	s := &statusStore{client: client, now: time.Now, stored: map[string]time.Time{}}
+	if pods != nil {
+		s.pods = pods.Lister()
+		s.synced = pods.Informer().HasSynced
+		s.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		pods.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{DeleteFunc: s.podDeleted})
+	}
+	return s
+}
+
+// annotationsSize returns the size of the annotations as counted by the API server.
+func annotationsSize(annotations map[string]string) int {
+	size := 0
+	for k, v := range annotations {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// fit returns the status annotation to set on the pod. If the annotations
+// of the pod would exceed the size allowed with the full status, the status
+// is stored in the ConfigMap and a reference to it is returned.
+func (s *statusStore) fit(ctx context.Context, pod *corev1.Pod, annotations map[string]string) string {
+	status := annotations[annotation.SidecarStatus.Name]
+	if s == nil {
+		return status
+	}
+	merged := make(map[string]string, len(pod.Annotations)+len(annotations))
+	for k, v := range pod.Annotations {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	if annotationsSize(merged) <= maxAnnotationsSize {
+		return status
+	}
+
+	var full SidecarInjectionStatus
+	if err := json.Unmarshal([]byte(status), &full); err != nil {
+		return status
+	}
+	hash := sha256.Sum256([]byte(status))
+	key := hex.EncodeToString(hash[:])
+	// a dry run must have no side effects, it gets the reference the pod would
+	if !isDryRun(ctx) {
+		if err := s.put(ctx, pod.Namespace, key, status); err != nil {
+			log.Warnf("Failed to store the injection status in namespace %s, keeping it in the annotation: %v",
+				pod.Namespace, err)
+			return status
+		}
+		s.mu.Lock()
+		s.stored[pod.Namespace+"/"+key] = s.now()
+		s.mu.Unlock()
+		log.Infof("Injection status of %d bytes stored in ConfigMap %s/%s", len(status), pod.Namespace, StatusConfigMapName)
+	}
+	ref, err := json.Marshal(SidecarInjectionStatus{Version: full.Version, Revision: full.Revision, Ref: statusRefPrefix + key})
+	if err != nil {
+		return status
+	}
+	return string(ref)
+}
+
+func (s *statusStore) put(ctx context.Context, namespace, key, status string) error {
+	configMaps := s.client.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(ctx, StatusConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: StatusConfigMapName, Namespace: namespace},
+			Data:       map[string]string{key: status},
+		}, metav1.CreateOptions{})
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		// created concurrently, add the record to it
+	} else if err != nil {
+		return err
+	} else if _, f := cm.Data[key]; f {
+		// records are content addressed, so an existing one is identical
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": map[string]string{key: status}})
+	if err != nil {
+		return err
+	}
+	_, err = configMaps.Patch(ctx, StatusConfigMapName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// statusRecordKey returns the key of the record the status annotation of the
+// pod refers to, empty if the annotation holds the status.
+func statusRecordKey(pod *corev1.Pod) string {
+	var status SidecarInjectionStatus
+	if err := json.Unmarshal([]byte(pod.Annotations[annotation.SidecarStatus.Name]), &status); err != nil ||
+		!strings.HasPrefix(status.Ref, statusRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(status.Ref, statusRefPrefix)
+}
+
+// resolve returns the injection status of the pod, reading it from the
+// ConfigMap if the status annotation is a reference.
+func (s *statusStore) resolve(ctx context.Context, pod *corev1.Pod) (*SidecarInjectionStatus, error) {
+	key := statusRecordKey(pod)
+	if key == "" {
+		return injectionStatus(pod), nil
+	}
+	if s == nil {
+		return nil, fmt.Errorf("injection status of the pod is stored in ConfigMap %s, which cannot be read",
+			StatusConfigMapName)
+	}
+	cm, err := s.client.CoreV1().ConfigMaps(pod.Namespace).Get(ctx, StatusConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the injection status of the pod: %v", err)
+	}
+	record, f := cm.Data[key]
+	if !f {
+		return nil, fmt.Errorf("injection status %s%s of the pod not found in ConfigMap %s", statusRefPrefix, key, StatusConfigMapName)
+	}
+	var full SidecarInjectionStatus
+	if err := json.Unmarshal([]byte(record), &full); err != nil {
+		return nil, fmt.Errorf("invalid injection status %s%s: %v", statusRefPrefix, key, err)
+	}
+	return &full, nil
+}
+
+func (s *statusStore) podDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if pod, ok := obj.(*corev1.Pod); ok && statusRecordKey(pod) != "" {
+		s.queue.Add(pod.Namespace)
+	}
+}
+
+// run deletes the records of the deleted pods until the stop channel is
+// closed. The records left by pods deleted while not running are deleted
+// with the next pod deleted in their namespace.
+func (s *statusStore) run(stop <-chan struct{}) {
+	if s == nil || s.queue == nil {
+		return
+	}
+	defer s.queue.ShutDown()
+	if !cache.WaitForCacheSync(stop, s.synced) {
+		log.Errorf("Failed to sync pods of the injection status store")
+		return
+	}
+	go func() {
+		for s.processNext() {
+		}
+	}()
+	<-stop
+}
+
+func (s *statusStore) processNext() bool {
+	namespace, quit := s.queue.Get()
+	if quit {
+		return false
+	}
+	defer s.queue.Done(namespace)
+
+	if err := s.collect(context.TODO(), namespace.(string)); err != nil {
+		if s.queue.NumRequeues(namespace) < statusStoreRetry {
+			s.queue.AddRateLimited(namespace)
+			return true
+		}
+		log.Errorf("Failed to delete the unused injection status records of namespace %s: %v", namespace, err)
+	}
+	s.queue.Forget(namespace)
+	return true
+}
+
+// collect deletes the records of the namespace no pod refers to.
+func (s *statusStore) collect(ctx context.Context, namespace string) error {
+	pods, err := s.pods.Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	referenced := map[string]bool{}
+	for _, pod := range pods {
+		if key := statusRecordKey(pod); key != "" {
+			referenced[key] = true
+		}
+	}
+	configMaps := s.client.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(ctx, StatusConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	now := s.now()
+	unused := map[string]interface{}{}
+	s.mu.Lock()
+	for key := range cm.Data {
+		stored, f := s.stored[namespace+"/"+key]
+		if f && now.Sub(stored) >= statusRecordGracePeriod {
+			delete(s.stored, namespace+"/"+key)
+			f = false
+		}
+		if !referenced[key] && !f {
+			// null deletes the key in a merge patch
+			unused[key] = nil
+		}
+	}
+	s.mu.Unlock()
+	if len(unused) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": unused})
+	if err != nil {
+		return err
+	}
+	if _, err := configMaps.Patch(ctx, StatusConfigMapName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	log.Debugf("Deleted %d unused injection status records of namespace %s", len(unused), namespace)
+	return nil
+}
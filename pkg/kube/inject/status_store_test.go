@@ -0,0 +1,144 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/api/annotation"
+)
+
+func TestStatusStore(t *testing.T) {
+	full := SidecarInjectionStatus{
+		Version:        "v1",
+		InitContainers: []string{"istio-init"},
+		Containers:     []string{ProxyContainerName},
+		Volumes:        []string{"istio-envoy"},
+	}
+	status, err := json.Marshal(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := map[string]string{annotation.SidecarStatus.Name: string(status)}
+	client := fake.NewSimpleClientset()
+	store := newStatusStore(client, nil)
+
+	small := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "foo"}}
+	if got := store.fit(context.TODO(), small, annotations); got != string(status) {
+		t.Fatalf("status of a small pod changed to %q", got)
+	}
+
+	large := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "large",
+		Namespace:   "foo",
+		Annotations: map[string]string{"example.com/large": strings.Repeat("x", maxAnnotationsSize)},
+	}}
+	// a dry run gets the reference without storing the record
+	dryRunRef := store.fit(withDryRun(context.TODO(), true), large, annotations)
+	if _, err := client.CoreV1().ConfigMaps("foo").Get(context.TODO(), StatusConfigMapName, metav1.GetOptions{}); err == nil {
+		t.Fatalf("record stored for a dry run")
+	}
+	ref := store.fit(context.TODO(), large, annotations)
+	if dryRunRef != ref {
+		t.Fatalf("got dry run reference %q, want %q", dryRunRef, ref)
+	}
+	var compact SidecarInjectionStatus
+	if err := json.Unmarshal([]byte(ref), &compact); err != nil {
+		t.Fatal(err)
+	}
+	if compact.Version != "v1" || !strings.HasPrefix(compact.Ref, statusRefPrefix) || len(compact.Containers) != 0 {
+		t.Fatalf("unexpected compact status %q", ref)
+	}
+	// storing the same record again is a no-op
+	if again := store.fit(context.TODO(), large, annotations); again != ref {
+		t.Fatalf("got reference %q, want %q", again, ref)
+	}
+	cm, err := client.CoreV1().ConfigMaps("foo").Get(context.TODO(), StatusConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cm.Data) != 1 {
+		t.Fatalf("got %d records, want 1", len(cm.Data))
+	}
+
+	large.Annotations[annotation.SidecarStatus.Name] = ref
+	resolved, err := store.resolve(context.TODO(), large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*resolved, full) {
+		t.Fatalf("got status %+v, want %+v", *resolved, full)
+	}
+	if _, err := (*statusStore)(nil).resolve(context.TODO(), large); err == nil {
+		t.Fatalf("expected error resolving a reference without a store")
+	}
+
+	small.Annotations = annotations
+	if resolved, err := (*statusStore)(nil).resolve(context.TODO(), small); err != nil || !reflect.DeepEqual(*resolved, full) {
+		t.Fatalf("got status %+v, %v, want %+v", resolved, err, full)
+	}
+}
+
+func TestStatusStoreCollect(t *testing.T) {
+	ref := func(key string) string {
+		return `{"version":"v1","ref":"` + statusRefPrefix + key + `"}`
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "foo",
+			Annotations: map[string]string{annotation.SidecarStatus.Name: ref("used")}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: StatusConfigMapName, Namespace: "foo"},
+			Data: map[string]string{"used": "{}", "unused": "{}", "pending": "{}"}},
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	store := newStatusStore(client, factory.Core().V1().Pods())
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	// stored for a pod the informer has not observed yet
+	store.stored["foo/pending"] = now
+
+	if err := store.collect(context.TODO(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	cm, err := client.CoreV1().ConfigMaps("foo").Get(context.TODO(), StatusConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, f := cm.Data["unused"]; f || len(cm.Data) != 2 {
+		t.Fatalf("got records %v, want the used and pending ones", cm.Data)
+	}
+
+	// once the grace period is over, the record of a pod never created is deleted
+	now = now.Add(statusRecordGracePeriod)
+	if err := store.collect(context.TODO(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if cm, _ = client.CoreV1().ConfigMaps("foo").Get(context.TODO(), StatusConfigMapName, metav1.GetOptions{}); len(cm.Data) != 1 {
+		t.Fatalf("got records %v, want the used one", cm.Data)
+	}
+}
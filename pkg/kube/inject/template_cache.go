@@ -0,0 +1,73 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"sync"
+	"text/template"
+	"time"
+)
+
+// maxCachedTemplates bounds the number of parsed templates kept around. Only
+// a handful of template versions are live at any time, typically the current
+// one and the one it replaced.
+const maxCachedTemplates = 8
+
+// templateCache holds parsed injection templates keyed by the hash of their
+// contents, so that a template is parsed once per config version rather than
+// once per request.
+type templateCache struct {
+	mu        sync.Mutex
+	templates map[string]*template.Template
+}
+
+var parsedTemplates = &templateCache{templates: map[string]*template.Template{}}
+
+// get returns a template for tmplStr bound to funcMap.
+func (c *templateCache) get(tmplStr string, funcMap template.FuncMap) (*template.Template, error) {
+	version := sidecarTemplateVersionHash(tmplStr)
+
+	c.mu.Lock()
+	t, f := c.templates[version]
+	c.mu.Unlock()
+
+	if f {
+		templateCacheHits.Increment()
+	} else {
+		templateCacheMisses.Increment()
+		start := time.Now()
+		parsed, err := template.New("inject").Funcs(funcMap).Parse(tmplStr)
+		if err != nil {
+			return nil, err
+		}
+		templateParseTime.Record(time.Since(start).Seconds())
+
+		c.mu.Lock()
+		if len(c.templates) >= maxCachedTemplates {
+			c.templates = map[string]*template.Template{}
+		}
+		c.templates[version] = parsed
+		c.mu.Unlock()
+		t = parsed
+	}
+
+	// The function map holds closures over per-request data, so each request
+	// works on its own copy bound to its own functions.
+	clone, err := t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Funcs(funcMap), nil
+}
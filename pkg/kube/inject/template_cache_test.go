@@ -0,0 +1,63 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateCache(t *testing.T) {
+	c := &templateCache{templates: map[string]*template.Template{}}
+	tmpl := `{{ greet }}`
+
+	render := func(greeting string) string {
+		parsed, err := c.get(tmpl, template.FuncMap{"greet": func() string { return greeting }})
+		if err != nil {
+			t.Fatalf("get() failed: %v", err)
+		}
+		var out bytes.Buffer
+		if err := parsed.Execute(&out, nil); err != nil {
+			t.Fatalf("Execute() failed: %v", err)
+		}
+		return out.String()
+	}
+
+	// The same cached template must use the functions of each request.
+	if got := render("hello"); got != "hello" {
+		t.Fatalf("got %q, want %q", got, "hello")
+	}
+	if got := render("bye"); got != "bye" {
+		t.Fatalf("got %q, want %q", got, "bye")
+	}
+	if len(c.templates) != 1 {
+		t.Fatalf("expected 1 cached template, got %d", len(c.templates))
+	}
+
+	if _, err := c.get(`{{ unknown }}`, template.FuncMap{}); err == nil {
+		t.Fatalf("expected parse error")
+	}
+
+	for i := 0; i < maxCachedTemplates+1; i++ {
+		if _, err := c.get(fmt.Sprintf("template-%d", i), template.FuncMap{}); err != nil {
+			t.Fatalf("get() failed: %v", err)
+		}
+	}
+	if len(c.templates) > maxCachedTemplates {
+		t.Fatalf("cache grew beyond %d entries: %d", maxCachedTemplates, len(c.templates))
+	}
+}
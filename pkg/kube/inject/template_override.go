@@ -0,0 +1,67 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"os"
+	"path/filepath"
+
+	"istio.io/pkg/log"
+)
+
+// templateOverrideFiles returns the injection config and values files to
+// load. Each is replaced by the file of the same name in the override
+// directory when one exists there, so the injector can be hotfixed when the
+// ConfigMap cannot be updated.
+func templateOverrideFiles(dir, configFile, valuesFile string) (string, string) {
+	overrides := 0
+	pick := func(file string) string {
+		override := templateOverride(dir, file)
+		if override == "" {
+			return file
+		}
+		overrides++
+		log.Warnf("EMERGENCY OVERRIDE: loading %s in place of %s. Remove it once the injection ConfigMap is fixed.",
+			override, file)
+		return override
+	}
+	configFile, valuesFile = pick(configFile), pick(valuesFile)
+	templateOverrides.Record(float64(overrides))
+	return configFile, valuesFile
+}
+
+// templateOverride returns the file of the override directory replacing file,
+// or "" if there is none.
+func templateOverride(dir, file string) string {
+	if dir == "" {
+		return ""
+	}
+	override := filepath.Join(dir, filepath.Base(file))
+	if _, err := os.Stat(override); err != nil {
+		return ""
+	}
+	return override
+}
+
+// hasTemplateOverride returns whether the override directory replaces the
+// injection config or values file.
+func hasTemplateOverride(dir, configFile, valuesFile string) bool {
+	return templateOverride(dir, configFile) != "" || templateOverride(dir, valuesFile) != ""
+}
+
+// inTemplateOverrideDir returns whether file is in the override directory.
+func inTemplateOverrideDir(dir, file string) bool {
+	return dir != "" && filepath.Dir(file) == filepath.Clean(dir)
+}
@@ -0,0 +1,79 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateOverrideFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "template-override")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "config"), []byte("policy: enabled"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		dir        string
+		wantConfig string
+		wantValues string
+	}{
+		{"no override directory", "", "inject/config", "inject/values"},
+		{"missing override directory", filepath.Join(dir, "missing"), "inject/config", "inject/values"},
+		{"config overridden", dir, filepath.Join(dir, "config"), "inject/values"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, values := templateOverrideFiles(c.dir, "inject/config", "inject/values")
+			if config != c.wantConfig || values != c.wantValues {
+				t.Fatalf("got files %q, %q, want %q, %q", config, values, c.wantConfig, c.wantValues)
+			}
+		})
+	}
+}
+
+func TestHasTemplateOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "template-override")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if hasTemplateOverride(dir, "inject/config", "inject/values") {
+		t.Fatal("empty override directory reported as an override")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "values"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !hasTemplateOverride(dir, "inject/config", "inject/values") {
+		t.Fatal("values override not reported")
+	}
+	if hasTemplateOverride("", "inject/config", "inject/values") {
+		t.Fatal("override reported without an override directory")
+	}
+
+	if !inTemplateOverrideDir(dir+"/", filepath.Join(dir, "config")) {
+		t.Fatal("file of the override directory not recognized")
+	}
+	if inTemplateOverrideDir(dir, "inject/config") || inTemplateOverrideDir("", "config") {
+		t.Fatal("file outside of the override directory recognized")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"istio.io/pkg/log"
+)
+
+// templateVariablesPath serves the variables and functions available to the injection template.
+const templateVariablesPath = "/template-variables"
+
+// nolint: lll
+//go:generate go run $REPO_ROOT/pkg/kube/inject/gen/main.go --output $REPO_ROOT/pkg/kube/inject/template_variables.gen.go
+
+// templateVariable is a value reachable from the injection template context,
+// or a field of the injection template output.
+type templateVariable struct {
+	// Path is the template path of the variable, e.g. .ObjectMeta.Annotations
+	// or .Spec.Containers[].Name for the name of every container. For the
+	// output it is the YAML path, e.g. containers[].name.
+	Path string `json:"path"`
+	// Type is the Go type of the variable. For the values it is the type of
+	// the values schema: string, bool, integer, number, duration,
+	// intOrString, object, array or any.
+	Type string `json:"type"`
+}
+
+// templateFunction is a function available to the injection template.
+type templateFunction struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+// templateVariablesDoc lists what injection template authors can use: the
+// template context, the keys of .Values known to the values schema, the
+// functions and the fields of the rendered SidecarInjectionSpec. It is
+// generated from the types of the source tree, so it matches the version of
+// the injector serving it.
+type templateVariablesDoc struct {
+	Variables []templateVariable `json:"variables"`
+	Values    []templateVariable `json:"values"`
+	Functions []templateFunction `json:"functions"`
+	Output    []templateVariable `json:"output"`
+}
+
+var (
+	templateVariablesOnce sync.Once
+	templateVariablesJSON []byte
+)
+
+func serveTemplateVariables(w http.ResponseWriter, _ *http.Request) {
+	templateVariablesOnce.Do(func() {
+		var err error
+		if templateVariablesJSON, err = json.MarshalIndent(templateVariables, "", "  "); err != nil {
+			log.Errorf("Failed to marshal template variables: %v", err)
+		}
+	})
+	if templateVariablesJSON == nil {
+		http.Error(w, "template variables unavailable", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(templateVariablesJSON); err != nil {
+		log.Errorf("Failed to write template variables: %v", err)
+	}
+}
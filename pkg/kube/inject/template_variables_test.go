@@ -0,0 +1,97 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTemplateVariables(t *testing.T) {
+	w := httptest.NewRecorder()
+	serveTemplateVariables(w, httptest.NewRequest("GET", templateVariablesPath, nil))
+	if w.Code != 200 {
+		t.Fatalf("got status %d", w.Code)
+	}
+	var doc templateVariablesDoc
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	types := func(vars []templateVariable) map[string]string {
+		out := map[string]string{}
+		for _, v := range vars {
+			if strings.Contains(v.Path, "XXX_") {
+				t.Errorf("protobuf internal field %s listed", v.Path)
+			}
+			out[v.Path] = v.Type
+		}
+		return out
+	}
+	variables := types(doc.Variables)
+	for path, want := range map[string]string{
+		".ObjectMeta.Annotations":           "map[string]string",
+		".ObjectMeta.CreationTimestamp":     "v1.Time",
+		".Spec.Containers[].Name":           "string",
+		".Spec.Containers[].Ports[].Name":   "string",
+		".ProxyConfig.DiscoveryAddress":     "string",
+		".MeshConfig.DefaultConfig":         "v1alpha1.ProxyConfig",
+		".Values":                           "map[string]interface {}",
+		".DeploymentMeta.OwnerReferences[]": "v1.OwnerReference",
+	} {
+		if got, f := variables[path]; !f || got != want {
+			t.Errorf("got variable %s of type %q, want %q", path, got, want)
+		}
+	}
+
+	values := types(doc.Values)
+	for path, want := range map[string]string{
+		".Values.global.proxy.image":                           "string",
+		".Values.global.proxy.holdApplicationUntilProxyStarts": "bool",
+		".Values.global.proxy.readinessPeriodSeconds":          "integer",
+		".Values.pilot.rollingMaxSurge":                        "intOrString",
+		".Values.sidecarInjectorWebhook.injectedAnnotations":   "object",
+	} {
+		if got, f := values[path]; !f || got != want {
+			t.Errorf("got value %s of type %q, want %q", path, got, want)
+		}
+	}
+
+	output := types(doc.Output)
+	for path, want := range map[string]string{
+		"containers[].name":     "string",
+		"rewriteAppHTTPProbe":   "bool",
+		"dnsConfig.nameservers": "[]string",
+	} {
+		if got, f := output[path]; !f || got != want {
+			t.Errorf("got output %s of type %q, want %q", path, got, want)
+		}
+	}
+	if _, f := output["AppEnv"]; f {
+		t.Errorf("fields not decoded from the template listed in the output")
+	}
+
+	functions := map[string]string{}
+	for _, f := range doc.Functions {
+		functions[f.Name] = f.Signature
+	}
+	for _, name := range []string{"annotation", "env", "render", "toYaml"} {
+		if functions[name] == "" {
+			t.Errorf("function %s missing", name)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package templatecheck validates injection configurations before they are
+// rolled out: representative pods are injected as the webhook patches them and
+// the injected pods are checked against the rules the API server enforces on
+// pod specs, or created with a server-side dry run, so a configuration change
+// breaking pod creation is caught in CI rather than by the webhook.
+package templatecheck
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/kube/inject"
+)
+
+// Error is a problem of the injection of a sample pod.
+type Error struct {
+	// Pod is the name of the sample pod.
+	Pod string
+	// Field is the path of the invalid field of the injected pod, if any.
+	Field   string
+	Message string
+}
+
+func (e Error) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("pod %s: %s", e.Pod, e.Message)
+	}
+	return fmt.Sprintf("pod %s: %s: %s", e.Pod, e.Field, e.Message)
+}
+
+// SamplePods returns the representative pods configurations are validated
+// against: a minimal pod, a pod with ports and probes, a pod with several
+// containers and an init container, and a pod customizing the proxy with
+// annotations.
+func SamplePods() []corev1.Pod {
+	httpProbe := &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{
+		Path: "/healthz", Port: intstr.FromInt(8080)}}}
+	return []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "minimal", Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "app", Image: "app"},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "probes", Namespace: "default", Labels: map[string]string{"app": "probes"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:           "app",
+				Image:          "app",
+				Ports:          []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				ReadinessProbe: httpProbe,
+				LivenessProbe:  httpProbe,
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "multi-container", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "setup", Image: "setup"}},
+				Containers: []corev1.Container{
+					{Name: "app", Image: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+					{Name: "worker", Image: "worker"},
+				},
+				Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "annotated", Namespace: "default", Annotations: map[string]string{
+				"sidecar.istio.io/proxyCPU":                     "200m",
+				"sidecar.istio.io/proxyMemory":                  "256Mi",
+				"traffic.sidecar.istio.io/excludeOutboundPorts": "3306",
+				"proxy.istio.io/config":                         "concurrency: 1",
+			}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "app", Image: "app"},
+			}},
+		},
+	}
+}
+
+// Validate injects the pods with the configuration and returns the problems
+// of the injected pods. No error means every pod was injected and is valid.
+func Validate(c *inject.Config, values string, mesh *meshconfig.MeshConfig, pods []corev1.Pod) []Error {
+	injected, errs := Inject(c, values, mesh, pods)
+	for i := range injected {
+		errs = append(errs, ValidatePod(&injected[i])...)
+	}
+	return errs
+}
+
+// Inject injects the pods with the configuration as the webhook patches them,
+// returning the injected pods and the problems of the pods that could not be
+// injected.
+func Inject(c *inject.Config, values string, mesh *meshconfig.MeshConfig, pods []corev1.Pod) ([]corev1.Pod, []Error) {
+	var injected []corev1.Pod
+	var errs []Error
+	for i := range pods {
+		in := pods[i].DeepCopy()
+		in.TypeMeta.APIVersion, in.TypeMeta.Kind = "v1", "Pod"
+		defaultPod(in)
+		out, err := inject.IntoObjectWithConfig(c, values, "", mesh, in, func(string) {})
+		if err != nil {
+			errs = append(errs, Error{Pod: in.Name, Message: fmt.Sprintf("injection failed: %v", err)})
+			continue
+		}
+		pod, ok := out.(*corev1.Pod)
+		if !ok {
+			errs = append(errs, Error{Pod: in.Name, Message: fmt.Sprintf("unexpected injection result %T", out)})
+			continue
+		}
+		injected = append(injected, *pod)
+	}
+	return injected, errs
+}
+
+// defaultPod applies the defaults of the API server the patch of the webhook
+// depends on: the pod security context is always set before the webhook is
+// called, so the patch replaces its fields rather than adding it.
+func defaultPod(pod *corev1.Pod) {
+	if pod.Spec.SecurityContext == nil {
+		pod.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+}
+
+// ValidatePod checks the pod against the rules of the API server the
+// injected fields may break: names, images, ports, environment variables,
+// volume mounts and resources. It is not the complete validation of the API
+// server, which ValidateServerSide runs.
+func ValidatePod(pod *corev1.Pod) []Error {
+	var errs []Error
+	add := func(field, format string, args ...interface{}) {
+		errs = append(errs, Error{Pod: pod.Name, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	spec := &pod.Spec
+
+	volumes := map[string]bool{}
+	for i, v := range spec.Volumes {
+		field := fmt.Sprintf("spec.volumes[%d]", i)
+		for _, msg := range validation.IsDNS1123Label(v.Name) {
+			add(field+".name", "invalid name %q: %s", v.Name, msg)
+		}
+		if volumes[v.Name] {
+			add(field+".name", "duplicate volume %q", v.Name)
+		}
+		volumes[v.Name] = true
+	}
+
+	containers := map[string]bool{}
+	check := func(list string, cs []corev1.Container) {
+		for i := range cs {
+			c := &cs[i]
+			field := fmt.Sprintf("spec.%s[%d]", list, i)
+			for _, msg := range validation.IsDNS1123Label(c.Name) {
+				add(field+".name", "invalid name %q: %s", c.Name, msg)
+			}
+			if containers[c.Name] {
+				add(field+".name", "duplicate container %q", c.Name)
+			}
+			containers[c.Name] = true
+			if c.Image == "" {
+				add(field+".image", "required value")
+			}
+			checkContainer(field, c, volumes, add)
+		}
+	}
+	check("initContainers", spec.InitContainers)
+	check("containers", spec.Containers)
+	return errs
+}
+
+func checkContainer(field string, c *corev1.Container, volumes map[string]bool, add func(field, format string, args ...interface{})) {
+	ports := map[string]bool{}
+	for i, p := range c.Ports {
+		portField := fmt.Sprintf("%s.ports[%d]", field, i)
+		for _, msg := range validation.IsValidPortNum(int(p.ContainerPort)) {
+			add(portField+".containerPort", "invalid port %d: %s", p.ContainerPort, msg)
+		}
+		if p.Name == "" {
+			continue
+		}
+		for _, msg := range validation.IsValidPortName(p.Name) {
+			add(portField+".name", "invalid name %q: %s", p.Name, msg)
+		}
+		if ports[p.Name] {
+			add(portField+".name", "duplicate port %q", p.Name)
+		}
+		ports[p.Name] = true
+	}
+	for i, e := range c.Env {
+		for _, msg := range validation.IsEnvVarName(e.Name) {
+			add(fmt.Sprintf("%s.env[%d].name", field, i), "invalid name %q: %s", e.Name, msg)
+		}
+	}
+	mounts := map[string]bool{}
+	for i, m := range c.VolumeMounts {
+		mountField := fmt.Sprintf("%s.volumeMounts[%d]", field, i)
+		if !volumes[m.Name] {
+			add(mountField+".name", "volume %q not found in the pod", m.Name)
+		}
+		if m.MountPath == "" {
+			add(mountField+".mountPath", "required value")
+		} else if mounts[m.MountPath] {
+			add(mountField+".mountPath", "duplicate mount path %q", m.MountPath)
+		}
+		mounts[m.MountPath] = true
+	}
+	for name, request := range c.Resources.Requests {
+		if limit, f := c.Resources.Limits[name]; f && request.Cmp(limit) > 0 {
+			add(fmt.Sprintf("%s.resources.requests[%s]", field, name), "request %s exceeds the limit %s",
+				request.String(), limit.String())
+		}
+	}
+}
+
+// ValidateServerSide creates the injected pod in the cluster with a dry run,
+// so it is checked with the complete validation and admission of the API
+// server without being persisted. The pod is created with a generated name,
+// so it does not collide with an existing pod.
+func ValidateServerSide(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) []Error {
+	pod = pod.DeepCopy()
+	name := pod.Name
+	pod.GenerateName, pod.Name, pod.ResourceVersion = name+"-", "", ""
+	namespace := pod.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	_, err := client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err == nil {
+		return nil
+	}
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil || len(status.Status().Details.Causes) == 0 {
+		return []Error{{Pod: name, Message: err.Error()}}
+	}
+	var errs []Error
+	for _, cause := range status.Status().Details.Causes {
+		errs = append(errs, Error{Pod: name, Field: cause.Field, Message: cause.Message})
+	}
+	return errs
+}
@@ -0,0 +1,168 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatecheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/kube/inject"
+)
+
+const values = `{"global": {"tag": "1.8"}}`
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name: "valid",
+			template: `
+containers:
+- name: istio-proxy
+  image: "proxy:{{ .Values.global.tag }}"
+  env:
+  - name: POD_NAME
+    value: {{ .ObjectMeta.Name }}
+  volumeMounts:
+  - name: istio-envoy
+    mountPath: /etc/istio/proxy
+volumes:
+- name: istio-envoy
+  emptyDir: {}
+`,
+		},
+		{
+			name: "unparsable",
+			template: `
+containers:
+- name: istio-proxy
+  image: "proxy:{{ .Values.global.tag "
+`,
+			want: []string{"injection failed"},
+		},
+		{
+			name: "invalid containers",
+			template: `
+containers:
+- name: Istio_Proxy
+  env:
+  - name: "1BAD"
+  ports:
+  - containerPort: 70000
+  volumeMounts:
+  - name: missing
+    mountPath: /etc/istio/proxy
+  resources:
+    requests:
+      cpu: "2"
+    limits:
+      cpu: "1"
+`,
+			want: []string{
+				"spec.containers[1].name: invalid name",
+				"spec.containers[1].image: required value",
+				"spec.containers[1].ports[0].containerPort: invalid port 70000",
+				"spec.containers[1].env[0].name: invalid name",
+				`spec.containers[1].volumeMounts[0].name: volume "missing" not found`,
+				"spec.containers[1].resources.requests[cpu]: request 2 exceeds the limit 1",
+			},
+		},
+		{
+			// the pod is defaulted with a security context, as the API server
+			// does, so the patch replaces its fsGroup rather than adding it
+			name: "security context",
+			template: `
+containers:
+- name: istio-proxy
+  image: proxy
+  securityContext:
+    runAsUser: 1337
+`,
+		},
+		{
+			name: "duplicate container",
+			template: `
+containers:
+- name: app
+  image: proxy
+`,
+			want: []string{`duplicate container "app"`},
+		},
+	}
+	m := mesh.DefaultMeshConfig()
+	pods := SamplePods()[:1]
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := Validate(&inject.Config{Template: c.template}, values, &m, pods)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if len(c.want) == 0 && len(got) > 0 {
+				t.Fatalf("unexpected errors: %v", got)
+			}
+			for _, want := range c.want {
+				found := false
+				for _, g := range got {
+					if strings.Contains(g, want) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("missing error %q in %v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSamplePodsValid(t *testing.T) {
+	for _, pod := range SamplePods() {
+		pod := pod
+		if errs := ValidatePod(&pod); len(errs) > 0 {
+			t.Errorf("sample pod %s is invalid: %v", pod.Name, errs)
+		}
+	}
+}
+
+func TestValidateServerSide(t *testing.T) {
+	pod := SamplePods()[0]
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, pod.Name, field.ErrorList{
+			field.Required(field.NewPath("spec", "containers").Index(0).Child("image"), ""),
+		})
+	})
+	errs := ValidateServerSide(context.Background(), client, &pod)
+	if len(errs) != 1 || errs[0].Field != "spec.containers[0].image" || errs[0].Pod != pod.Name {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	if errs := ValidateServerSide(context.Background(), fake.NewSimpleClientset(), &pod); len(errs) > 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
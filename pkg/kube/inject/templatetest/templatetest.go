@@ -0,0 +1,230 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package templatetest runs YAML test suites against injection templates, so
+// templates can be tested without writing Go. A suite is a list of cases,
+// each injecting a pod and checking assertions on the result:
+//
+//	tests:
+//	- name: proxy is configured for the cluster
+//	  pod:
+//	    metadata:
+//	      name: app
+//	    spec:
+//	      containers:
+//	      - name: app
+//	        image: app
+//	  assertions:
+//	  - container: istio-proxy
+//	    env:
+//	      ISTIO_META_CLUSTER_ID: Kubernetes
+//	  - noPrivilegedContainers: true
+package templatetest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/kube/inject"
+)
+
+// Suite is a set of test cases run against a template.
+type Suite struct {
+	Tests []Case `json:"tests"`
+}
+
+// Case injects a pod and checks the result against the assertions.
+type Case struct {
+	Name string     `json:"name"`
+	Pod  corev1.Pod `json:"pod"`
+
+	// Values, if set, replaces the values of the suite for this case.
+	Values string `json:"values,omitempty"`
+
+	Assertions []Assertion `json:"assertions"`
+}
+
+// Assertion is a check on the injected pod. Each field set is checked.
+type Assertion struct {
+	// Container selects the container, or init container, the Absent, Image,
+	// Env and Args checks apply to.
+	Container string `json:"container,omitempty"`
+
+	// Absent asserts the container is not in the pod.
+	Absent bool `json:"absent,omitempty"`
+
+	// Image is the expected image of the container.
+	Image string `json:"image,omitempty"`
+
+	// Env are environment variables the container must have, with their values.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Args are arguments the container must have.
+	Args []string `json:"args,omitempty"`
+
+	// Annotations are annotations the pod must have, with their values.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// NoPrivilegedContainers asserts no container of the pod is privileged.
+	NoPrivilegedContainers bool `json:"noPrivilegedContainers,omitempty"`
+}
+
+// Result is the outcome of a test case. It passed if there are no failures.
+type Result struct {
+	Name     string
+	Failures []string
+}
+
+// Parse reads a suite from YAML.
+func Parse(data []byte) (*Suite, error) {
+	var s Suite
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid test suite: %v", err)
+	}
+	for i, c := range s.Tests {
+		if c.Name == "" {
+			return nil, fmt.Errorf("invalid test suite: test %d has no name", i)
+		}
+		if len(c.Assertions) == 0 {
+			return nil, fmt.Errorf("invalid test suite: test %q has no assertions", c.Name)
+		}
+	}
+	return &s, nil
+}
+
+// Run injects the pod of each case with the template and checks its assertions.
+func (s *Suite) Run(template, values string, mesh *meshconfig.MeshConfig) []Result {
+	results := make([]Result, 0, len(s.Tests))
+	for _, c := range s.Tests {
+		caseValues := values
+		if c.Values != "" {
+			caseValues = c.Values
+		}
+		result := Result{Name: c.Name}
+		pod, err := injectPod(template, caseValues, mesh, &c.Pod)
+		if err != nil {
+			result.Failures = []string{fmt.Sprintf("injection failed: %v", err)}
+		} else {
+			for _, a := range c.Assertions {
+				result.Failures = append(result.Failures, a.check(pod)...)
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func injectPod(template, values string, mesh *meshconfig.MeshConfig, pod *corev1.Pod) (*corev1.Pod, error) {
+	in := pod.DeepCopy()
+	in.TypeMeta.APIVersion, in.TypeMeta.Kind = "v1", "Pod"
+	out, err := inject.IntoObject(template, values, "", mesh, in, func(string) {})
+	if err != nil {
+		return nil, err
+	}
+	injected, ok := out.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("unexpected injection result %T", out)
+	}
+	return injected, nil
+}
+
+func findContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			if containers[i].Name == name {
+				return &containers[i]
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// check returns the failures of the assertion on the pod.
+func (a Assertion) check(pod *corev1.Pod) []string {
+	var failures []string
+	fail := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+
+	if a.Container != "" {
+		c := findContainer(pod, a.Container)
+		switch {
+		case a.Absent && c != nil:
+			fail("container %s is present", a.Container)
+		case !a.Absent && c == nil:
+			fail("container %s not found", a.Container)
+		case c != nil:
+			if a.Image != "" && c.Image != a.Image {
+				fail("container %s has image %s, want %s", a.Container, c.Image, a.Image)
+			}
+			env := map[string]string{}
+			for _, e := range c.Env {
+				env[e.Name] = e.Value
+			}
+			for _, k := range sortedKeys(a.Env) {
+				if v, f := env[k]; !f {
+					fail("container %s has no env %s", a.Container, k)
+				} else if v != a.Env[k] {
+					fail("container %s has env %s=%s, want %s", a.Container, k, v, a.Env[k])
+				}
+			}
+			args := strings.Join(c.Args, " ")
+			for _, arg := range a.Args {
+				found := false
+				for _, ca := range c.Args {
+					if ca == arg {
+						found = true
+						break
+					}
+				}
+				if !found {
+					fail("container %s has no arg %s in %q", a.Container, arg, args)
+				}
+			}
+		}
+	}
+
+	for _, k := range sortedKeys(a.Annotations) {
+		if v, f := pod.Annotations[k]; !f {
+			fail("pod has no annotation %s", k)
+		} else if v != a.Annotations[k] {
+			fail("pod has annotation %s=%s, want %s", k, v, a.Annotations[k])
+		}
+	}
+
+	if a.NoPrivilegedContainers {
+		for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+			for _, c := range containers {
+				if sc := c.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+					fail("container %s is privileged", c.Name)
+				}
+			}
+		}
+	}
+	return failures
+}
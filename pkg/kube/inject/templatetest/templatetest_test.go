@@ -0,0 +1,120 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatetest
+
+import (
+	"reflect"
+	"testing"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+const template = `
+initContainers:
+- name: istio-init
+  image: init
+  securityContext:
+    privileged: {{ .Values.global.proxy.privileged }}
+containers:
+- name: istio-proxy
+  image: "proxy:{{ .Values.global.tag }}"
+  args:
+  - proxy
+  - sidecar
+  env:
+  - name: POD_NAME
+    value: {{ .ObjectMeta.Name }}
+`
+
+const suite = `
+tests:
+- name: passing
+  pod:
+    metadata:
+      name: app
+    spec:
+      containers:
+      - name: app
+        image: app
+  assertions:
+  - container: istio-proxy
+    image: proxy:1.8
+    env:
+      POD_NAME: app
+    args: [sidecar]
+  - container: istio-validation
+    absent: true
+  - noPrivilegedContainers: true
+- name: failing
+  values: '{"global":{"tag":"1.7","proxy":{"privileged":true}}}'
+  pod:
+    metadata:
+      name: app
+    spec:
+      containers:
+      - name: app
+        image: app
+  assertions:
+  - container: istio-proxy
+    image: proxy:1.8
+    env:
+      POD_NAME: other
+      MISSING: x
+  - container: missing
+  - annotations:
+      example.com/missing: "true"
+  - noPrivilegedContainers: true
+`
+
+func TestSuite(t *testing.T) {
+	s, err := Parse([]byte(suite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := mesh.DefaultMeshConfig()
+	results := s.Run(template, `{"global":{"tag":"1.8","proxy":{"privileged":false}}}`, &m)
+	want := []Result{
+		{Name: "passing"},
+		{Name: "failing", Failures: []string{
+			"container istio-proxy has image proxy:1.7, want proxy:1.8",
+			"container istio-proxy has no env MISSING",
+			"container istio-proxy has env POD_NAME=app, want other",
+			"container missing not found",
+			"pod has no annotation example.com/missing",
+			"container istio-init is privileged",
+		}},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("got results %+v, want %+v", results, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		suite string
+	}{
+		{"invalid yaml", "tests: ["},
+		{"no name", "tests:\n- assertions:\n  - noPrivilegedContainers: true"},
+		{"no assertions", "tests:\n- name: empty"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := Parse([]byte(c.suite)); err == nil {
+				t.Fatalf("expected error parsing %q", c.suite)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tlsVersions are the TLS versions accepted by TLSOptions.MinVersion.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSOptions restricts the TLS versions and cipher suites of the HTTPS server
+// serving the webhook. The zero value keeps the Go defaults.
+type TLSOptions struct {
+	// MinVersion is the lowest TLS version accepted, e.g. "1.2".
+	MinVersion string
+
+	// CipherSuites are the names of the cipher suites allowed for TLS 1.2 and
+	// older, e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256". The cipher suites
+	// of TLS 1.3 are not configurable.
+	CipherSuites []string
+}
+
+// Apply sets the TLS version and cipher suites of the options on the config,
+// returning an error for unknown versions, or unknown or insecure cipher suites.
+func (o TLSOptions) Apply(cfg *tls.Config) error {
+	if o.MinVersion != "" {
+		v, f := tlsVersions[o.MinVersion]
+		if !f {
+			return fmt.Errorf("unknown TLS version %q: must be 1.0, 1.1, 1.2 or 1.3", o.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+	if len(o.CipherSuites) == 0 {
+		return nil
+	}
+	suites := map[string]uint16{}
+	for _, s := range tls.CipherSuites() {
+		suites[s.Name] = s.ID
+	}
+	ids := make([]uint16, 0, len(o.CipherSuites))
+	for _, name := range o.CipherSuites {
+		id, f := suites[strings.TrimSpace(name)]
+		if !f {
+			names := make([]string, 0, len(suites))
+			for n := range suites {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown or insecure cipher suite %q: must be one of %s", name, strings.Join(names, ", "))
+		}
+		ids = append(ids, id)
+	}
+	cfg.CipherSuites = ids
+	return nil
+}
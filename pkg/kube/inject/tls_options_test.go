@@ -0,0 +1,62 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+func TestTLSOptionsApply(t *testing.T) {
+	cases := []struct {
+		name        string
+		options     TLSOptions
+		wantVersion uint16
+		wantSuites  []uint16
+		wantErr     bool
+	}{
+		{name: "defaults"},
+		{name: "min version", options: TLSOptions{MinVersion: "1.2"}, wantVersion: tls.VersionTLS12},
+		{
+			name: "cipher suites",
+			options: TLSOptions{CipherSuites: []string{
+				"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", " TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			}},
+			wantSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+		},
+		{name: "unknown version", options: TLSOptions{MinVersion: "1.4"}, wantErr: true},
+		{name: "unknown cipher suite", options: TLSOptions{CipherSuites: []string{"TLS_FAST"}}, wantErr: true},
+		{name: "insecure cipher suite", options: TLSOptions{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &tls.Config{}
+			err := c.options.Apply(cfg)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.MinVersion != c.wantVersion || !reflect.DeepEqual(cfg.CipherSuites, c.wantSuites) {
+				t.Fatalf("got version %x and suites %v, want %x and %v", cfg.MinVersion, cfg.CipherSuites, c.wantVersion, c.wantSuites)
+			}
+		})
+	}
+}
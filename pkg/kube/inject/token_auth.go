@@ -0,0 +1,202 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	defaultTokenCacheTTL = 10 * time.Second
+	// maxCachedTokens bounds the token reviews cached.
+	maxCachedTokens = 1000
+)
+
+// TokenAuthOptions requires the callers of the injection endpoint to present
+// a bearer token bound to one of the audiences, as the API servers send when
+// their admission configuration has a kubeconfig for the webhook. It protects
+// an injector reached through a URL clientConfig, e.g. hosted outside the
+// cluster, from callers other than the intended API servers. The tokens are
+// verified with the TokenReview API, so it requires a Kubernetes client.
+// Leaving Audiences empty allows any caller.
+type TokenAuthOptions struct {
+	// Audiences the token must be bound to, one of them at least.
+	Audiences []string
+
+	// AllowedUsers, if set, are the users the token may authenticate, e.g.
+	// system:serviceaccount:kube-system:kube-apiserver.
+	AllowedUsers []string
+
+	// CacheTTL is how long the review of a token is reused. Defaults to 10s.
+	CacheTTL time.Duration
+}
+
+func (o TokenAuthOptions) enabled() bool {
+	return len(o.Audiences) > 0
+}
+
+type tokenReview struct {
+	user    string
+	err     error
+	expires time.Time
+}
+
+// tokenAuthenticator verifies the bearer tokens of requests, caching the
+// reviews by token hash.
+type tokenAuthenticator struct {
+	options TokenAuthOptions
+	client  kubernetes.Interface
+	now     func() time.Time
+
+	mu      sync.Mutex
+	reviews map[[sha256.Size]byte]tokenReview
+}
+
+// newTokenAuthenticator returns nil if the options do not require tokens.
+func newTokenAuthenticator(o TokenAuthOptions, client kubernetes.Interface) (*tokenAuthenticator, error) {
+	if !o.enabled() {
+		return nil, nil
+	}
+	if client == nil {
+		return nil, errors.New("token authentication of injection requests requires a Kubernetes client")
+	}
+	if o.CacheTTL <= 0 {
+		o.CacheTTL = defaultTokenCacheTTL
+	}
+	return &tokenAuthenticator{
+		options: o,
+		client:  client,
+		now:     time.Now,
+		reviews: map[[sha256.Size]byte]tokenReview{},
+	}, nil
+}
+
+// authenticate returns the user of the token of the request, or an error if
+// the request has no token bound to the audiences of an allowed user.
+func (a *tokenAuthenticator) authenticate(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return "", errors.New("no bearer token")
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if token == "" {
+		return "", errors.New("no bearer token")
+	}
+
+	key := sha256.Sum256([]byte(token))
+	now := a.now()
+	a.mu.Lock()
+	cached, f := a.reviews[key]
+	a.mu.Unlock()
+	if f && now.Before(cached.expires) {
+		return cached.user, cached.err
+	}
+
+	review, err := a.client.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.options.Audiences},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		// not cached, the next request reviews the token again
+		return "", fmt.Errorf("failed to review the token: %v", err)
+	}
+	user, err := review.Status.User.Username, a.check(review.Status)
+	if err != nil {
+		user = ""
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.reviews) >= maxCachedTokens {
+		for k, v := range a.reviews {
+			if !now.Before(v.expires) {
+				delete(a.reviews, k)
+			}
+		}
+	}
+	if len(a.reviews) < maxCachedTokens {
+		a.reviews[key] = tokenReview{user: user, err: err, expires: now.Add(a.options.CacheTTL)}
+	}
+	return user, err
+}
+
+func (a *tokenAuthenticator) check(status authenticationv1.TokenReviewStatus) error {
+	if !status.Authenticated {
+		if status.Error != "" {
+			return fmt.Errorf("token not authenticated: %s", status.Error)
+		}
+		return errors.New("token not authenticated")
+	}
+	// authenticators not supporting audiences return none, the token may not be bound
+	bound := false
+	for _, aud := range status.Audiences {
+		for _, want := range a.options.Audiences {
+			if aud == want {
+				bound = true
+			}
+		}
+	}
+	if !bound {
+		return fmt.Errorf("token of %q not bound to the audiences %v", status.User.Username, a.options.Audiences)
+	}
+	if len(a.options.AllowedUsers) == 0 {
+		return nil
+	}
+	for _, u := range a.options.AllowedUsers {
+		if status.User.Username == u {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %q is not allowed", status.User.Username)
+}
+
+type tokenUserKey struct{}
+
+// tokenUser returns the user authenticated by the token of the request, if any.
+func tokenUser(r *http.Request) string {
+	user, _ := r.Context().Value(tokenUserKey{}).(string)
+	return user
+}
+
+// authenticateClient wraps the handler, rejecting requests without a valid
+// token. The user of the token is available to the handler with tokenUser.
+// A nil authenticator allows every request.
+func (a *tokenAuthenticator) authenticateClient(next http.HandlerFunc) http.HandlerFunc {
+	if a == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := a.authenticate(r)
+		if err != nil {
+			totalUnauthorizedInjections.Increment()
+			log.Warnf("Rejecting injection request from %s: %v", r.RemoteAddr, err)
+			http.Error(w, "client not authenticated", http.StatusUnauthorized)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), tokenUserKey{}, user)))
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestTokenAuthenticator(t *testing.T) {
+	reviews := 0
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview).DeepCopy()
+		switch review.Spec.Token {
+		case "apiserver":
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:kube-apiserver"},
+				Audiences:     review.Spec.Audiences,
+			}
+		case "other-user":
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:default:default"},
+				Audiences:     review.Spec.Audiences,
+			}
+		case "unbound":
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:kube-apiserver"},
+			}
+		case "unavailable":
+			return true, nil, errors.New("connection refused")
+		default:
+			review.Status = authenticationv1.TokenReviewStatus{Error: "invalid token"}
+		}
+		return true, review, nil
+	})
+
+	if a, err := newTokenAuthenticator(TokenAuthOptions{}, nil); a != nil || err != nil {
+		t.Fatalf("expected no authenticator without audiences, got %v, %v", a, err)
+	}
+	if _, err := newTokenAuthenticator(TokenAuthOptions{Audiences: []string{"istio-sidecar-injector"}}, nil); err == nil {
+		t.Fatalf("expected an error without a Kubernetes client")
+	}
+	a, err := newTokenAuthenticator(TokenAuthOptions{
+		Audiences:    []string{"istio-sidecar-injector"},
+		AllowedUsers: []string{"system:serviceaccount:kube-system:kube-apiserver"},
+	}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"basic auth", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"allowed", "Bearer apiserver", http.StatusOK},
+		{"user not allowed", "Bearer other-user", http.StatusUnauthorized},
+		{"not bound to the audience", "Bearer unbound", http.StatusUnauthorized},
+		{"invalid", "Bearer invalid", http.StatusUnauthorized},
+		{"review failed", "Bearer unavailable", http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "https://injector.example.com/inject", nil)
+			if c.header != "" {
+				r.Header.Set("Authorization", c.header)
+			}
+			w := httptest.NewRecorder()
+			user := ""
+			a.authenticateClient(func(w http.ResponseWriter, r *http.Request) {
+				user = tokenUser(r)
+				w.WriteHeader(http.StatusOK)
+			})(w, r)
+			if w.Code != c.want {
+				t.Fatalf("got status %v, want %v", w.Code, c.want)
+			}
+			if c.want == http.StatusOK && user != "system:serviceaccount:kube-system:kube-apiserver" {
+				t.Fatalf("got user %q passed to the handler", user)
+			}
+		})
+	}
+
+	// the reviews are cached, except the failed ones
+	reviews = 0
+	for _, token := range []string{"apiserver", "invalid", "unavailable"} {
+		r := httptest.NewRequest("POST", "https://injector.example.com/inject", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		_, _ = a.authenticate(r)
+	}
+	if reviews != 1 {
+		t.Fatalf("expected only the failed review to be retried, got %d reviews", reviews)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+)
+
+// TrustedProxiesConfig configures how the proxy treats the X-Forwarded-For
+// and X-Forwarded-Client-Cert headers set by load balancers in front of it.
+// It is rendered into the proxy config of injected pods as the gateway topology.
+type TrustedProxiesConfig struct {
+	// NumTrustedProxies is the number of trusted proxies deployed in front of
+	// the workload, used to determine the client address from X-Forwarded-For.
+	NumTrustedProxies *uint32 `json:"numTrustedProxies,omitempty"`
+
+	// ForwardClientCert is one of the meshconfig.Topology_ForwardClientCertDetails
+	// names, e.g. SANITIZE_SET or APPEND_FORWARD.
+	ForwardClientCert string `json:"forwardClientCert,omitempty"`
+}
+
+func (c *TrustedProxiesConfig) validate() error {
+	if c == nil || c.ForwardClientCert == "" {
+		return nil
+	}
+	if _, f := meshconfig.Topology_ForwardClientCertDetails_value[c.ForwardClientCert]; !f {
+		return fmt.Errorf("invalid forwardClientCert %q", c.ForwardClientCert)
+	}
+	return nil
+}
+
+// validateTrustedProxies validates the default and per namespace trusted proxies settings.
+func validateTrustedProxies(c *Config) error {
+	if err := c.TrustedProxies.validate(); err != nil {
+		return fmt.Errorf("trustedProxies: %v", err)
+	}
+	for ns, tp := range c.NamespaceTrustedProxies {
+		tp := tp
+		if err := tp.validate(); err != nil {
+			return fmt.Errorf("namespaceTrustedProxies[%s]: %v", ns, err)
+		}
+	}
+	return nil
+}
+
+// trustedProxiesForNamespace returns the trusted proxies settings for the
+// namespace, falling back to the injector wide default.
+func trustedProxiesForNamespace(c *Config, namespace string) *TrustedProxiesConfig {
+	if tp, f := c.NamespaceTrustedProxies[namespace]; f {
+		return &tp
+	}
+	return c.TrustedProxies
+}
+
+// applyTrustedProxies returns a copy of the mesh config whose default proxy
+// config carries the trusted proxies settings. Settings left unset keep the
+// value from the mesh config.
+func applyTrustedProxies(mc *meshconfig.MeshConfig, tp *TrustedProxiesConfig) *meshconfig.MeshConfig {
+	if tp == nil || (tp.NumTrustedProxies == nil && tp.ForwardClientCert == "") {
+		return mc
+	}
+	out := proto.Clone(mc).(*meshconfig.MeshConfig)
+	if out.DefaultConfig == nil {
+		out.DefaultConfig = &meshconfig.ProxyConfig{}
+	}
+	if out.DefaultConfig.GatewayTopology == nil {
+		out.DefaultConfig.GatewayTopology = &meshconfig.Topology{}
+	}
+	if tp.NumTrustedProxies != nil {
+		out.DefaultConfig.GatewayTopology.NumTrustedProxies = *tp.NumTrustedProxies
+	}
+	if tp.ForwardClientCert != "" {
+		out.DefaultConfig.GatewayTopology.ForwardClientCertDetails =
+			meshconfig.Topology_ForwardClientCertDetails(meshconfig.Topology_ForwardClientCertDetails_value[tp.ForwardClientCert])
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestApplyTrustedProxies(t *testing.T) {
+	two := uint32(2)
+	c := &Config{
+		TrustedProxies: &TrustedProxiesConfig{NumTrustedProxies: &two},
+		NamespaceTrustedProxies: map[string]TrustedProxiesConfig{
+			"lb": {ForwardClientCert: "APPEND_FORWARD"},
+		},
+	}
+	if err := validateTrustedProxies(c); err != nil {
+		t.Fatal(err)
+	}
+	m := mesh.DefaultMeshConfig()
+
+	got := applyTrustedProxies(&m, trustedProxiesForNamespace(c, "default"))
+	if n := got.GetDefaultConfig().GetGatewayTopology().GetNumTrustedProxies(); n != 2 {
+		t.Fatalf("default namespace: got numTrustedProxies %d, want 2", n)
+	}
+
+	got = applyTrustedProxies(&m, trustedProxiesForNamespace(c, "lb"))
+	if f := got.GetDefaultConfig().GetGatewayTopology().GetForwardClientCertDetails(); f != meshconfig.Topology_APPEND_FORWARD {
+		t.Fatalf("lb namespace: got forwardClientCert %v, want APPEND_FORWARD", f)
+	}
+	if n := got.GetDefaultConfig().GetGatewayTopology().GetNumTrustedProxies(); n != 0 {
+		t.Fatalf("lb namespace: got numTrustedProxies %d, want 0", n)
+	}
+
+	if m.GetDefaultConfig().GetGatewayTopology() != nil {
+		t.Fatalf("mesh config was modified")
+	}
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	c := &Config{
+		NamespaceTrustedProxies: map[string]TrustedProxiesConfig{
+			"bad": {ForwardClientCert: "NOT_A_MODE"},
+		},
+	}
+	if err := validateTrustedProxies(c); err == nil {
+		t.Fatalf("expected error for invalid forwardClientCert")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// EncryptedValuesKind identifies the envelope of an encrypted values file.
+const EncryptedValuesKind = "EncryptedInjectionValues"
+
+// encryptedValues is the envelope of an encrypted values file: the values
+// are encrypted with a random data key, itself encrypted by the key provider.
+type encryptedValues struct {
+	Kind         string `json:"kind"`
+	KeyID        string `json:"keyID"`
+	EncryptedKey []byte `json:"encryptedKey"`
+	// Ciphertext is prefixed with its nonce.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ValuesKeyProvider encrypts and decrypts the data keys of encrypted values
+// files, e.g. with a local key or a KMS, so the values files can be stored
+// in Git and decrypted in memory by the injector only.
+type ValuesKeyProvider interface {
+	// WrapKey encrypts a data key, returning the ID of the key encrypting it.
+	WrapKey(dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key encrypted by the key of the ID.
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// fileKeyProvider wraps the data keys with a local AES-256 key.
+type fileKeyProvider struct {
+	id  string
+	key []byte
+}
+
+// NewFileKeyProvider returns a key provider wrapping the data keys with the
+// AES-256 key of the file, base64 encoded, e.g. mounted from a Secret.
+func NewFileKeyProvider(file string) (ValuesKeyProvider, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid values key %s: %v", file, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid values key %s: %d bytes, expected 32", file, len(key))
+	}
+	sum := sha256.Sum256(key)
+	return &fileKeyProvider{id: "file:" + hex.EncodeToString(sum[:8]), key: key}, nil
+}
+
+func (p *fileKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	sealed, err := sealAESGCM(p.key, dataKey, []byte(p.id))
+	return p.id, sealed, err
+}
+
+func (p *fileKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != p.id {
+		return nil, fmt.Errorf("encrypted with key %s, not the configured %s", keyID, p.id)
+	}
+	return openAESGCM(p.key, wrapped, []byte(p.id))
+}
+
+// sealAESGCM encrypts with AES-GCM, prefixing the ciphertext with the nonce.
+func sealAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openAESGCM(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptValues returns the encrypted values file of the values.
+func EncryptValues(values []byte, keys ValuesKeyProvider) ([]byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := keys.WrapKey(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt the data key: %v", err)
+	}
+	sealed, err := sealAESGCM(dataKey, values, []byte(EncryptedValuesKind+keyID))
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(encryptedValues{
+		Kind:         EncryptedValuesKind,
+		KeyID:        keyID,
+		EncryptedKey: wrapped,
+		Ciphertext:   sealed,
+	}, "", "  ")
+}
+
+// decryptValues returns the values of an encrypted values file, or the file
+// as is if it is not encrypted.
+func decryptValues(data []byte, keys ValuesKeyProvider) ([]byte, error) {
+	var envelope encryptedValues
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Kind != EncryptedValuesKind {
+		return data, nil
+	}
+	if keys == nil {
+		return nil, errors.New("the values file is encrypted, but no values key is configured")
+	}
+	dataKey, err := keys.UnwrapKey(envelope.KeyID, envelope.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data key of the values: %v", err)
+	}
+	values, err := openAESGCM(dataKey, envelope.Ciphertext, []byte(EncryptedValuesKind+envelope.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the values: %v", err)
+	}
+	return values, nil
+}
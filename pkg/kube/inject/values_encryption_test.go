@@ -0,0 +1,91 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeValuesKey(t *testing.T, dir, name string, key []byte) ValuesKeyProvider {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := NewFileKeyProvider(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestValuesEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "values_encryption_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keys := writeValuesKey(t, dir, "key", bytes.Repeat([]byte{1}, 32))
+	otherKeys := writeValuesKey(t, dir, "other", bytes.Repeat([]byte{2}, 32))
+	short := filepath.Join(dir, "short")
+	if err := ioutil.WriteFile(short, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileKeyProvider(short); err == nil {
+		t.Fatalf("expected a key of the wrong size to be rejected")
+	}
+
+	values := []byte(`{"global":{"token":"secret"}}`)
+	encrypted, err := EncryptValues(values, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(encrypted, []byte("secret")) {
+		t.Fatalf("encrypted values contain the plaintext: %s", encrypted)
+	}
+	tampered := bytes.Replace(encrypted, []byte(`"ciphertext": "`), []byte(`"ciphertext": "AAAA`), 1)
+
+	cases := []struct {
+		name    string
+		data    []byte
+		keys    ValuesKeyProvider
+		want    []byte
+		wantErr bool
+	}{
+		{"plaintext", values, nil, values, false},
+		{"plaintext with a key", values, keys, values, false},
+		{"encrypted", encrypted, keys, values, false},
+		{"encrypted without a key", encrypted, nil, nil, true},
+		{"encrypted with another key", encrypted, otherKeys, nil, true},
+		{"tampered", tampered, keys, nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := decryptValues(c.data, c.keys)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if !bytes.Equal(got, c.want) {
+				t.Fatalf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// ValuesAnnotation holds a YAML overlay of the injection values. It is read
+	// from the namespace, the workload and the pod, see inheritedValues.
+	ValuesAnnotation = "sidecar.istio.io/values"
+
+	// appendSuffix marks a list in an overlay as appended to, rather than
+	// replacing, the inherited list.
+	appendSuffix = "+"
+)
+
+// inheritedValues returns the values used to render the template. The values
+// are inherited along the chain mesh > namespace > workload > pod: the mesh
+// wide values are overlaid in turn by the namespace, workload and pod
+// ValuesAnnotation, the more specific level taking precedence. The workload
+// is the Deployment, StatefulSet or DaemonSet owning the pod. See mergeValues
+// for the merge semantics.
+//
+// Anyone allowed to create pods or workloads may annotate them, so the
+// workload and pod overlays may only set the values of
+// Config.PodValuesAllowlist, the others are ignored.
+func inheritedValues(params InjectionParameters) (string, error) {
+	overlays := []struct {
+		level string
+		value string
+	}{
+		{"namespace", params.namespaceValues},
+		{"workload", params.workloadValues},
+		{"pod", params.pod.Annotations[ValuesAnnotation]},
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(params.valuesConfig), &values); err != nil {
+		return "", fmt.Errorf("could not parse configuration values: %v", err)
+	}
+	merged := false
+	for _, o := range overlays {
+		if o.value == "" {
+			continue
+		}
+		overlay := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(o.value), &overlay); err != nil {
+			return "", fmt.Errorf("could not parse %s values overlay: %v", o.level, err)
+		}
+		if o.level != "namespace" {
+			var ignored []string
+			overlay, ignored = allowedValues(overlay, "", params.podValuesAllowlist)
+			if len(ignored) > 0 {
+				log.Warnf("Ignoring the values %s of the %s %s annotation of pod %s/%s, not in the pod values allowlist",
+					strings.Join(ignored, ", "), o.level, ValuesAnnotation, params.pod.Namespace, potentialPodName(&params.pod.ObjectMeta))
+			}
+		}
+		values = mergeValues(values, overlay)
+		merged = true
+	}
+	if !merged {
+		return params.valuesConfig, nil
+	}
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// validateValuesOverlay validates the ValuesAnnotation.
+func validateValuesOverlay(value string) error {
+	overlay := map[string]interface{}{}
+	return yaml.Unmarshal([]byte(value), &overlay)
+}
+
+// allowedValues returns the values of the overlay under the allowed paths,
+// which are dot separated keys, and the paths of the values left out. The
+// append suffix of a key does not take part in the match.
+func allowedValues(overlay map[string]interface{}, prefix string, allowed []string) (map[string]interface{}, []string) {
+	out := map[string]interface{}{}
+	var ignored []string
+	for k, v := range overlay {
+		path := prefix + strings.TrimSuffix(k, appendSuffix)
+		within, under := false, false
+		for _, a := range allowed {
+			within = within || path == a || strings.HasPrefix(path, a+".")
+			under = under || strings.HasPrefix(a, path+".")
+		}
+		nested, isMap := v.(map[string]interface{})
+		switch {
+		case within:
+			out[k] = v
+		case under && isMap:
+			kept, left := allowedValues(nested, path+".", allowed)
+			if len(kept) > 0 {
+				out[k] = kept
+			}
+			ignored = append(ignored, left...)
+		default:
+			ignored = append(ignored, path)
+		}
+	}
+	sort.Strings(ignored)
+	return out, ignored
+}
+
+// validatePodValuesAllowlist validates the paths of Config.PodValuesAllowlist.
+func validatePodValuesAllowlist(allowed []string) error {
+	for _, a := range allowed {
+		if a == "" || strings.HasPrefix(a, ".") || strings.HasSuffix(a, ".") || strings.Contains(a, "..") {
+			return fmt.Errorf("invalid pod values allowlist path %q", a)
+		}
+	}
+	return nil
+}
+
+// mergeValues overlays the values of overlay onto base and returns the
+// result. Neither input is modified.
+//
+// Maps are merged recursively. Any other value, including a list, replaces
+// the inherited value, except for a list whose key is suffixed with "+":
+// such a list is appended to the inherited list of the key without the
+// suffix. A "+" key without an inherited list sets the list.
+func mergeValues(base, overlay map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overlay {
+		if strings.HasSuffix(k, appendSuffix) {
+			key := strings.TrimSuffix(k, appendSuffix)
+			added, isList := v.([]interface{})
+			if inherited, f := out[key].([]interface{}); f && isList {
+				out[key] = append(append([]interface{}{}, inherited...), added...)
+			} else {
+				out[key] = v
+			}
+			continue
+		}
+		overlayMap, overlayIsMap := v.(map[string]interface{})
+		baseMap, baseIsMap := out[k].(map[string]interface{})
+		if overlayIsMap && baseIsMap {
+			out[k] = mergeValues(baseMap, overlayMap)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
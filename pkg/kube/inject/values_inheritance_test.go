@@ -0,0 +1,231 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/pkg/config/mesh"
+)
+
+func TestMergeValues(t *testing.T) {
+	cases := []struct {
+		name    string
+		base    string
+		overlay string
+		want    string
+	}{
+		{
+			name:    "deep merge",
+			base:    `global: {proxy: {image: proxyv2, logLevel: warning}}`,
+			overlay: `global: {proxy: {logLevel: debug}}`,
+			want:    `global: {proxy: {image: proxyv2, logLevel: debug}}`,
+		},
+		{
+			name:    "list replaced",
+			base:    `global: {imagePullSecrets: [a, b]}`,
+			overlay: `global: {imagePullSecrets: [c]}`,
+			want:    `global: {imagePullSecrets: [c]}`,
+		},
+		{
+			name:    "list appended",
+			base:    `global: {imagePullSecrets: [a, b]}`,
+			overlay: `global: {imagePullSecrets+: [c]}`,
+			want:    `global: {imagePullSecrets: [a, b, c]}`,
+		},
+		{
+			name:    "append without inherited list",
+			base:    `global: {}`,
+			overlay: `global: {imagePullSecrets+: [c]}`,
+			want:    `global: {imagePullSecrets: [c]}`,
+		},
+		{
+			name:    "scalar replaces map",
+			base:    `sidecarInjectorWebhook: {neverInjectSelector: {a: b}}`,
+			overlay: `sidecarInjectorWebhook: {neverInjectSelector: null}`,
+			want:    `sidecarInjectorWebhook: {neverInjectSelector: null}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			base, overlay, want := map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}
+			for _, v := range []struct {
+				in  string
+				out *map[string]interface{}
+			}{{c.base, &base}, {c.overlay, &overlay}, {c.want, &want}} {
+				if err := yaml.Unmarshal([]byte(v.in), v.out); err != nil {
+					t.Fatal(err)
+				}
+			}
+			baseCopy, _ := yaml.Marshal(base)
+			got := mergeValues(base, overlay)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if after, _ := yaml.Marshal(base); string(after) != string(baseCopy) {
+				t.Fatalf("base modified: got %s, want %s", after, baseCopy)
+			}
+		})
+	}
+}
+
+func TestInheritedValues(t *testing.T) {
+	mesh := "global:\n  proxy:\n    logLevel: warning\n    image: proxyv2\n"
+	cases := []struct {
+		name      string
+		namespace string
+		workload  string
+		pod       string
+		allowlist []string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "no overlays",
+			want: mesh,
+		},
+		{
+			name:      "namespace",
+			namespace: `global: {proxy: {logLevel: info}}`,
+			want:      "global:\n  proxy:\n    image: proxyv2\n    logLevel: info\n",
+		},
+		{
+			name:      "pod overrides namespace",
+			namespace: `global: {proxy: {logLevel: info, image: custom}}`,
+			pod:       `global: {proxy: {logLevel: trace}}`,
+			allowlist: []string{"global.proxy.logLevel"},
+			want:      "global:\n  proxy:\n    image: custom\n    logLevel: trace\n",
+		},
+		{
+			name:      "workload overrides namespace",
+			namespace: `global: {proxy: {logLevel: info, image: custom}}`,
+			workload:  `global: {proxy: {logLevel: debug}}`,
+			allowlist: []string{"global.proxy.logLevel"},
+			want:      "global:\n  proxy:\n    image: custom\n    logLevel: debug\n",
+		},
+		{
+			name:      "mesh > namespace > workload > pod",
+			namespace: `global: {proxy: {logLevel: info, image: custom}, hub: ns.example.com, tag: ns}`,
+			workload:  `global: {proxy: {logLevel: debug}, hub: workload.example.com}`,
+			pod:       `global: {proxy: {logLevel: trace}}`,
+			allowlist: []string{"global.proxy.logLevel", "global.hub"},
+			want: "global:\n  hub: workload.example.com\n  proxy:\n    image: custom\n    logLevel: trace\n" +
+				"  tag: ns\n",
+		},
+		{
+			name:      "workload values not allowed",
+			workload:  `global: {proxy: {image: custom}}`,
+			allowlist: []string{"global.proxy.logLevel"},
+			want:      "global:\n  proxy:\n    image: proxyv2\n    logLevel: warning\n",
+		},
+		{
+			name:      "pod values not allowed",
+			pod:       `global: {proxy: {logLevel: trace, image: custom}, hub: example.com}`,
+			allowlist: []string{"global.proxy.logLevel"},
+			want:      "global:\n  proxy:\n    image: proxyv2\n    logLevel: trace\n",
+		},
+		{
+			name: "pod values without allowlist",
+			pod:  `global: {proxy: {logLevel: trace}}`,
+			want: "global:\n  proxy:\n    image: proxyv2\n    logLevel: warning\n",
+		},
+		{
+			name:      "invalid overlay",
+			namespace: `global: [`,
+			wantErr:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if c.pod != "" {
+				pod.Annotations[ValuesAnnotation] = c.pod
+			}
+			got, err := inheritedValues(InjectionParameters{
+				pod:                pod,
+				valuesConfig:       mesh,
+				namespaceValues:    c.namespace,
+				workloadValues:     c.workload,
+				podValuesAllowlist: c.allowlist,
+			})
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if got != c.want {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestIntoObjectWorkloadValues(t *testing.T) {
+	c := &Config{
+		Template:           "containers:\n- name: istio-proxy\n  image: \"{{ .Values.global.proxy.image }}\"\n",
+		PodValuesAllowlist: []string{"global.proxy.image"},
+	}
+	deploy := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "default", Annotations: map[string]string{
+			ValuesAnnotation: `global: {proxy: {image: workload}}`,
+		}},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+		}},
+	}
+	m := mesh.DefaultMeshConfig()
+	out, err := IntoObjectWithConfig(c, `global: {proxy: {image: mesh}}`, "", &m, deploy, func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers := out.(*appsv1.Deployment).Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[1].Image != "workload" {
+		t.Fatalf("workload values not applied: %+v", containers)
+	}
+}
+
+func TestAllowedValues(t *testing.T) {
+	overlay := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(`{global: {proxy: {resources: {}, image: custom}, "imagePullSecrets+": [a]}, hub: x}`), &overlay); err != nil {
+		t.Fatal(err)
+	}
+	got, ignored := allowedValues(overlay, "", []string{"global.proxy.resources", "global.imagePullSecrets"})
+	want := map[string]interface{}{"global": map[string]interface{}{
+		"proxy":             map[string]interface{}{"resources": map[string]interface{}{}},
+		"imagePullSecrets+": []interface{}{"a"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(ignored, []string{"global.proxy.image", "hub"}) {
+		t.Fatalf("got ignored %v", ignored)
+	}
+}
+
+func TestValidatePodValuesAllowlist(t *testing.T) {
+	if err := validatePodValuesAllowlist([]string{"global.proxy.resources", "sidecarInjectorWebhook"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"", ".global", "global.", "global..proxy"} {
+		if err := validatePodValuesAllowlist([]string{path}); err == nil {
+			t.Fatalf("expected error for path %q", path)
+		}
+	}
+}
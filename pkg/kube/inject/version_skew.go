@@ -0,0 +1,111 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/pkg/log"
+)
+
+const (
+	// VersionSkewReject refuses to load a configuration exceeding the allowed skew.
+	VersionSkewReject = "reject"
+	// VersionSkewWarn loads a configuration exceeding the allowed skew, logging a warning.
+	VersionSkewWarn = "warn"
+)
+
+// ProxyVersionSkewPolicy bounds the difference between the version of the
+// injector and the version of the proxy image it injects.
+type ProxyVersionSkewPolicy struct {
+	// MaxMinorVersions is the number of minor versions the proxy may differ by.
+	MaxMinorVersions int `json:"maxMinorVersions"`
+
+	// Mode is VersionSkewReject or VersionSkewWarn. Defaults to VersionSkewReject.
+	Mode string `json:"mode,omitempty"`
+}
+
+// checkProxyVersionSkew returns an error if the proxy image requested by the
+// values is outside of the skew allowed by the policy. Versions which cannot be
+// parsed, such as development builds, are not checked.
+func checkProxyVersionSkew(policy *ProxyVersionSkewPolicy, injectorVersion, valuesConfig string) error {
+	if policy == nil {
+		return nil
+	}
+	switch policy.Mode {
+	case "", VersionSkewReject, VersionSkewWarn:
+	default:
+		return fmt.Errorf("unknown proxy version skew mode %q", policy.Mode)
+	}
+	if policy.MaxMinorVersions < 0 {
+		return fmt.Errorf("proxy version skew maxMinorVersions must not be negative: %d", policy.MaxMinorVersions)
+	}
+
+	tag, err := proxyImageTag(valuesConfig)
+	if err != nil {
+		return err
+	}
+	injector, proxy := model.ParseIstioVersion(injectorVersion), model.ParseIstioVersion(tag)
+	if injector == model.MaxIstioVersion || proxy == model.MaxIstioVersion {
+		log.Debugf("Skipping proxy version skew check of injector %q and proxy %q", injectorVersion, tag)
+		return nil
+	}
+
+	skew := injector.Minor - proxy.Minor
+	if skew < 0 {
+		skew = -skew
+	}
+	if injector.Major == proxy.Major && skew <= policy.MaxMinorVersions {
+		return nil
+	}
+	err = fmt.Errorf("proxy version %s is not within %d minor versions of injector version %s",
+		tag, policy.MaxMinorVersions, injectorVersion)
+	if policy.Mode == VersionSkewWarn {
+		log.Warnf("%v", err)
+		return nil
+	}
+	return err
+}
+
+// proxyImageTag returns the tag of the proxy image rendered by the template:
+// the tag of global.proxy.image if it is a full image name, otherwise global.tag.
+func proxyImageTag(valuesConfig string) (string, error) {
+	var values struct {
+		Global struct {
+			Tag   interface{} `json:"tag"`
+			Proxy struct {
+				Image string `json:"image"`
+			} `json:"proxy"`
+		} `json:"global"`
+	}
+	if err := yaml.Unmarshal([]byte(valuesConfig), &values); err != nil {
+		return "", fmt.Errorf("could not parse configuration values: %v", err)
+	}
+	if image := values.Global.Proxy.Image; strings.Contains(image, "/") {
+		name := image[strings.LastIndex(image, "/")+1:]
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			return name[i+1:], nil
+		}
+		return "", nil
+	}
+	if values.Global.Tag == nil {
+		return "", nil
+	}
+	return fmt.Sprint(values.Global.Tag), nil
+}
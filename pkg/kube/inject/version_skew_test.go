@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+)
+
+func TestCheckProxyVersionSkew(t *testing.T) {
+	reject := &ProxyVersionSkewPolicy{MaxMinorVersions: 1}
+	cases := []struct {
+		name     string
+		policy   *ProxyVersionSkewPolicy
+		injector string
+		values   string
+		wantErr  bool
+	}{
+		{
+			name:     "no policy",
+			injector: "1.8.0",
+			values:   `global: {tag: 1.5.0}`,
+		},
+		{
+			name:     "within skew",
+			policy:   reject,
+			injector: "1.8.0",
+			values:   `global: {tag: 1.7.3}`,
+		},
+		{
+			name:     "newer proxy within skew",
+			policy:   reject,
+			injector: "1.8.0",
+			values:   `global: {tag: "1.9"}`,
+		},
+		{
+			name:     "outside skew",
+			policy:   reject,
+			injector: "1.8.0",
+			values:   `global: {tag: 1.6.0}`,
+			wantErr:  true,
+		},
+		{
+			name:     "outside skew warns",
+			policy:   &ProxyVersionSkewPolicy{MaxMinorVersions: 1, Mode: VersionSkewWarn},
+			injector: "1.8.0",
+			values:   `global: {tag: 1.6.0}`,
+		},
+		{
+			name:     "full image name",
+			policy:   reject,
+			injector: "1.8.0",
+			values:   `global: {tag: 1.8.0, proxy: {image: "docker.io/istio/proxyv2:1.5.2"}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "development build",
+			policy:   reject,
+			injector: "1.8.0",
+			values:   `global: {tag: latest}`,
+		},
+		{
+			name:     "unknown injector version",
+			policy:   reject,
+			injector: "unknown",
+			values:   `global: {tag: 1.2.0}`,
+		},
+		{
+			name:     "invalid mode",
+			policy:   &ProxyVersionSkewPolicy{Mode: "fail"},
+			injector: "1.8.0",
+			values:   `global: {tag: 1.8.0}`,
+			wantErr:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkProxyVersionSkew(c.policy, c.injector, c.values)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
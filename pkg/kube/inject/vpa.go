@@ -0,0 +1,92 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// VPAContainerPolicyAnnotation holds the resource policy of the proxy
+// container, in the format of the containerPolicies of a
+// VerticalPodAutoscaler, for the autoscaler objects of injected workloads.
+const VPAContainerPolicyAnnotation = "sidecar.istio.io/vpaContainerPolicy"
+
+// Modes and controlled values of a VerticalPodAutoscaler container policy.
+const (
+	VPAModeAuto = "Auto"
+	VPAModeOff  = "Off"
+
+	VPAControlledRequestsAndLimits = "RequestsAndLimits"
+	VPAControlledRequestsOnly      = "RequestsOnly"
+)
+
+// VPAPolicy is the VerticalPodAutoscaler resource policy recommended for
+// the proxy container of injected pods.
+type VPAPolicy struct {
+	// Mode is VPAModeAuto to let the autoscaler tune the proxy, or VPAModeOff.
+	Mode string `json:"mode,omitempty"`
+
+	// MinAllowed and MaxAllowed bound the resources the autoscaler may
+	// recommend for the proxy.
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+
+	// ControlledValues is VPAControlledRequestsOnly to keep the limits of the
+	// proxy from the template, or VPAControlledRequestsAndLimits.
+	ControlledValues string `json:"controlledValues,omitempty"`
+}
+
+// vpaContainerPolicy is a container policy of a VerticalPodAutoscaler.
+type vpaContainerPolicy struct {
+	ContainerName string `json:"containerName"`
+	VPAPolicy     `json:",inline"`
+}
+
+func validateVPAPolicy(policy *VPAPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	switch policy.Mode {
+	case "", VPAModeAuto, VPAModeOff:
+	default:
+		return fmt.Errorf("unknown VPA mode %q", policy.Mode)
+	}
+	switch policy.ControlledValues {
+	case "", VPAControlledRequestsAndLimits, VPAControlledRequestsOnly:
+	default:
+		return fmt.Errorf("unknown VPA controlled values %q", policy.ControlledValues)
+	}
+	for name, lower := range policy.MinAllowed {
+		if upper, f := policy.MaxAllowed[name]; f && lower.Cmp(upper) > 0 {
+			return fmt.Errorf("VPA minAllowed %s of %s is above maxAllowed %s", name, lower.String(), upper.String())
+		}
+	}
+	return nil
+}
+
+// vpaAnnotations returns the annotations describing the resource policy of the proxy.
+func vpaAnnotations(policy *VPAPolicy) (map[string]string, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	by, err := json.Marshal(vpaContainerPolicy{ContainerName: ProxyContainerName, VPAPolicy: *policy})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{VPAContainerPolicyAnnotation: string(by)}, nil
+}
@@ -0,0 +1,70 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestValidateVPAPolicy(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  *VPAPolicy
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"valid", &VPAPolicy{
+			Mode:             VPAModeAuto,
+			ControlledValues: VPAControlledRequestsOnly,
+			MinAllowed:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+			MaxAllowed:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}, false},
+		{"unknown mode", &VPAPolicy{Mode: "Recreate"}, true},
+		{"unknown controlled values", &VPAPolicy{ControlledValues: "LimitsOnly"}, true},
+		{"min above max", &VPAPolicy{
+			MinAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			MaxAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateVPAPolicy(c.policy)
+			if gotErr := err != nil; gotErr != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestVPAAnnotations(t *testing.T) {
+	if annotations, err := vpaAnnotations(nil); err != nil || annotations != nil {
+		t.Fatalf("got annotations %v, %v without a policy", annotations, err)
+	}
+	annotations, err := vpaAnnotations(&VPAPolicy{
+		Mode:             VPAModeAuto,
+		ControlledValues: VPAControlledRequestsOnly,
+		MaxAllowed:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"containerName":"istio-proxy","mode":"Auto","maxAllowed":{"cpu":"1"},"controlledValues":"RequestsOnly"}`
+	if got := annotations[VPAContainerPolicyAnnotation]; got != want {
+		t.Fatalf("got annotation %s, want %s", got, want)
+	}
+}
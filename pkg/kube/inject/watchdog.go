@@ -0,0 +1,181 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"istio.io/pkg/log"
+)
+
+// WatchdogOptions configures the leak watchdog of the injector. A zero
+// threshold disables the corresponding check. The thresholds apply to the
+// whole process: when the injector runs in istiod, they also cover the
+// resources used by discovery and must be sized for it.
+type WatchdogOptions struct {
+	// Interval is how often resource usage is sampled. Zero disables the watchdog.
+	Interval time.Duration
+
+	// MaxHeapGrowthBytes is the heap growth, in bytes, above which the watchdog
+	// trips. The growth is measured from the smallest heap sampled since the
+	// watchdog started.
+	MaxHeapGrowthBytes uint64
+
+	// MaxGoroutines is the goroutine count above which the watchdog trips.
+	MaxGoroutines int
+
+	// MaxWatches is the number of inotify watches held by the process above
+	// which the watchdog trips. Only checked on Linux.
+	MaxWatches int
+
+	// Shutdown, if set, is called once a threshold is exceeded to shut the
+	// process down gracefully, so that Kubernetes restarts the pod. Otherwise
+	// only the diagnostics are logged.
+	Shutdown func()
+}
+
+// watchdog periodically compares resource usage against the configured
+// thresholds, bounding the impact of slow leaks in long-running injectors.
+type watchdog struct {
+	opts WatchdogOptions
+
+	// watches returns the number of file watches currently held by the process.
+	watches func() int
+
+	// heapAlloc returns the number of bytes allocated on the heap.
+	heapAlloc func() uint64
+
+	// heapBaseline is the smallest heap sampled, zero until the first sample.
+	heapBaseline uint64
+}
+
+func newWatchdog(opts WatchdogOptions, watches func() int) *watchdog {
+	return &watchdog{
+		opts:    opts,
+		watches: watches,
+		heapAlloc: func() uint64 {
+			var m runtime.MemStats
+			runtime.ReadMemStats(&m)
+			return m.HeapAlloc
+		},
+	}
+}
+
+// inotifyWatches returns the number of inotify watches held by the process
+// whose proc directory is procDir, e.g. /proc/self, summed over all its inotify
+// instances so that the watches leaked by any watcher are counted. It returns
+// 0 where the proc file system is not available.
+func inotifyWatches(procDir string) int {
+	fds, err := ioutil.ReadDir(filepath.Join(procDir, "fd"))
+	if err != nil {
+		return 0
+	}
+	watches := 0
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join(procDir, "fd", fd.Name())); err != nil || target != "anon_inode:inotify" {
+			continue
+		}
+		f, err := os.Open(filepath.Join(procDir, "fdinfo", fd.Name()))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "inotify wd:") {
+				watches++
+			}
+		}
+		_ = f.Close()
+	}
+	return watches
+}
+
+func (w *watchdog) run(stop <-chan struct{}) {
+	if w.opts.Interval <= 0 {
+		return
+	}
+	t := time.NewTicker(w.opts.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if reason := w.check(); reason != "" {
+				w.trip(reason)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// check returns a non-empty reason if any threshold is exceeded.
+func (w *watchdog) check() string {
+	if w.opts.MaxGoroutines > 0 {
+		if n := runtime.NumGoroutine(); n > w.opts.MaxGoroutines {
+			return fmt.Sprintf("goroutine count %d exceeds limit %d", n, w.opts.MaxGoroutines)
+		}
+	}
+	if w.opts.MaxWatches > 0 && w.watches != nil {
+		if n := w.watches(); n > w.opts.MaxWatches {
+			return fmt.Sprintf("file watch count %d exceeds limit %d", n, w.opts.MaxWatches)
+		}
+	}
+	if w.opts.MaxHeapGrowthBytes > 0 {
+		heap := w.heapAlloc()
+		if w.heapBaseline == 0 || heap < w.heapBaseline {
+			w.heapBaseline = heap
+		}
+		if growth := heap - w.heapBaseline; growth > w.opts.MaxHeapGrowthBytes {
+			return fmt.Sprintf("heap growth %d bytes exceeds limit %d bytes", growth, w.opts.MaxHeapGrowthBytes)
+		}
+	}
+	return ""
+}
+
+func (w *watchdog) trip(reason string) {
+	log.Errorf("Injector watchdog tripped: %s\n%s", reason, w.diagnostics())
+	watchdogTrips.Increment()
+	if w.opts.Shutdown != nil {
+		log.Errorf("Injector watchdog shutting down to allow restart")
+		w.opts.Shutdown()
+	}
+}
+
+// diagnostics returns a bundle of memory statistics and goroutine stacks
+// to help locate the leak.
+func (w *watchdog) diagnostics() string {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "heapAlloc=%d heapObjects=%d heapSys=%d numGC=%d goroutines=%d",
+		m.HeapAlloc, m.HeapObjects, m.HeapSys, m.NumGC, runtime.NumGoroutine())
+	if w.watches != nil {
+		fmt.Fprintf(&buf, " watches=%d", w.watches())
+	}
+	buf.WriteString("\n")
+	if p := pprof.Lookup("goroutine"); p != nil {
+		_ = p.WriteTo(&buf, 1)
+	}
+	return buf.String()
+}
@@ -0,0 +1,137 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWatchdogCheck(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    WatchdogOptions
+		watches int
+		want    string
+	}{
+		{
+			name: "disabled",
+			opts: WatchdogOptions{},
+			want: "",
+		},
+		{
+			name: "goroutines",
+			opts: WatchdogOptions{MaxGoroutines: 1},
+			want: "goroutine count",
+		},
+		{
+			name:    "watches under limit",
+			opts:    WatchdogOptions{MaxWatches: 5},
+			watches: 5,
+			want:    "",
+		},
+		{
+			name:    "watches over limit",
+			opts:    WatchdogOptions{MaxWatches: 5},
+			watches: 6,
+			want:    "file watch count 6",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := newWatchdog(c.opts, func() int { return c.watches })
+			got := w.check()
+			if c.want == "" && got != "" {
+				t.Fatalf("expected no trip, got %q", got)
+			}
+			if !strings.Contains(got, c.want) {
+				t.Fatalf("expected reason containing %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestWatchdogHeapGrowth(t *testing.T) {
+	w := newWatchdog(WatchdogOptions{MaxHeapGrowthBytes: 100}, nil)
+	for _, c := range []struct {
+		heap uint64
+		want string
+	}{
+		// the first sample is the baseline, however large the heap already is
+		{1000, ""},
+		{1100, ""},
+		{1101, "heap growth 101 bytes"},
+		// the baseline follows the heap down after a collection
+		{500, ""},
+		{650, "heap growth 150 bytes"},
+	} {
+		w.heapAlloc = func() uint64 { return c.heap }
+		got := w.check()
+		if c.want == "" && got != "" {
+			t.Fatalf("heap %d: expected no trip, got %q", c.heap, got)
+		}
+		if !strings.Contains(got, c.want) {
+			t.Fatalf("heap %d: expected reason containing %q, got %q", c.heap, c.want, got)
+		}
+	}
+}
+
+func TestWatchdogTripShutdown(t *testing.T) {
+	shutdown := false
+	w := newWatchdog(WatchdogOptions{Shutdown: func() { shutdown = true }}, nil)
+	w.trip("test")
+	if !shutdown {
+		t.Fatalf("watchdog tripped without shutting down")
+	}
+	// without a shutdown function only the diagnostics are logged
+	newWatchdog(WatchdogOptions{}, nil).trip("test")
+}
+
+func TestInotifyWatches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, d := range []string{"fd", "fdinfo"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fds := []struct {
+		fd, target, info string
+	}{
+		{"3", "anon_inode:inotify", "pos:\t0\ninotify wd:1 ino:2 sdev:3\ninotify wd:2 ino:4 sdev:3\n"},
+		{"4", "anon_inode:inotify", "inotify wd:1 ino:5 sdev:3\n"},
+		{"5", "socket:[1234]", "inotify wd:1 ino:6 sdev:3\n"},
+	}
+	for _, fd := range fds {
+		if err := os.Symlink(fd.target, filepath.Join(dir, "fd", fd.fd)); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "fdinfo", fd.fd), []byte(fd.info), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := inotifyWatches(dir); got != 3 {
+		t.Fatalf("got %d watches, want 3", got)
+	}
+	if got := inotifyWatches(filepath.Join(dir, "missing")); got != 0 {
+		t.Fatalf("got %d watches without a proc directory, want 0", got)
+	}
+}
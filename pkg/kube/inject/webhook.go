@@ -15,11 +15,15 @@
 package inject
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"path/filepath"
 	"sort"
@@ -30,13 +34,17 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/howeyc/fsnotify"
+	"go.opencensus.io/stats/view"
+	"go.uber.org/atomic"
 	kubeApiAdmissionv1 "k8s.io/api/admission/v1"
 	kubeApiAdmissionv1beta1 "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"istio.io/api/annotation"
 	"istio.io/api/label"
@@ -44,13 +52,14 @@ import (
 	"istio.io/istio/pilot/cmd/pilot-agent/status"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/inject/annotations"
+	"istio.io/istio/pkg/webhooks"
 	"istio.io/pkg/log"
+	buildversion "istio.io/pkg/version"
 )
 
 var (
 	runtimeScheme     = runtime.NewScheme()
-	codecs            = serializer.NewCodecFactory(runtimeScheme)
-	deserializer      = codecs.UniversalDeserializer()
 	jsonSerializer    = kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, runtimeScheme, runtimeScheme, kjson.SerializerOptions{})
 	URLParameterToEnv = map[string]string{
 		"cluster": "ISTIO_META_CLUSTER_ID",
@@ -66,6 +75,12 @@ func init() {
 
 const (
 	watchDebounceDelay = 100 * time.Millisecond
+
+	// drainPollInterval is how often the in-flight admission requests are
+	// checked while draining.
+	drainPollInterval = 50 * time.Millisecond
+
+	skipReasonPolicy = "policy"
 )
 
 // Webhook implements a mutating webhook for automatic proxy injection.
@@ -81,28 +96,161 @@ type Webhook struct {
 
 	configFile string
 	valuesFile string
-
-	watcher *fsnotify.Watcher
-
-	mon      *monitor
-	env      *model.Environment
-	revision string
+	// templateOverrideDir holds files replacing configFile and valuesFile, if set.
+	templateOverrideDir string
+
+	watchdog *watchdog
+
+	mon        *monitor
+	statsd     *statsdExporter
+	queue      *fairQueue
+	env        *model.Environment
+	revision   string
+	kubeClient kubernetes.Interface
+	owners     *ownerResolver
+	limits     *limitRangeCache
+	// namespaces caches the namespaces, nil when not enabled.
+	namespaces *namespaceCache
+	injected   *injectedPodCounter
+	statuses   *statusStore
+	canary     *canary
+	decisions  *decisionLog
+	latencies  *latencyWindow
+	audit      *auditLog
+	// kubernetes are the capabilities of the cluster version, nil if unknown.
+	// Discovered again periodically, guarded by mu.
+	kubernetes *KubernetesCapabilities
+	// kubernetesDiscovered is closed once the first discovery is done, nil
+	// without a client.
+	kubernetesDiscovered chan struct{}
+
+	// insecurePort serves the handlers without TLS on localhost when positive.
+	insecurePort int
+	// udsPath serves the handlers without TLS on a unix socket when set.
+	udsPath string
+
+	discoveryGate DiscoveryGateOptions
+
+	// servingCertificate returns the certificate the webhook is served with, if known.
+	servingCertificate func() (*tls.Certificate, error)
+
+	// notifier posts the lifecycle events, nil when not configured.
+	notifier *notifier
+
+	// fieldManager the injected fields are attributed to, none when empty.
+	fieldManager string
+
+	// fanIn serves the injection of other clusters, nil when not configured.
+	fanIn *fanIn
+
+	// valuesKeys decrypts encrypted values files, nil when not configured.
+	valuesKeys ValuesKeyProvider
+
+	// configMaps applies the updates of the injection ConfigMap, nil when not watched.
+	configMaps *configMapWatcher
+	// meshConfigMap watches the ConfigMap of the mesh config, nil when not watched.
+	meshConfigMap *configMapWatcher
+
+	// drift verifies the running injected pods, nil when not configured.
+	drift *driftVerifier
+
+	// namespaceFilter restricts the namespaces injected.
+	namespaceFilter NamespaceFilterOptions
+
+	// costs measures the sampled admissions, nil when not configured.
+	costs *costSampler
+
+	// events holds the handlers of the admission decisions.
+	events injectionEvents
+
+	// startup gates the admissions until the configuration is validated, nil when not configured.
+	startup *startupGate
+
+	// reloads requests Run to load the configuration files again.
+	reloads chan struct{}
+
+	// configInfo records the versions of the active configuration.
+	configInfo configInfoRecorder
+
+	// recorder records the events of the injector, nil without a Kubernetes client.
+	recorder *eventRecorder
+	// failureEvents records the injection failures as events.
+	failureEvents bool
+
+	// injectHandler serves /inject on every listener, authorizing the callers.
+	injectHandler http.HandlerFunc
+
+	// inflight is the number of admission requests being served.
+	inflight            atomic.Int64
+	shutdownGracePeriod time.Duration
 }
 
 //nolint directives: interfacer
-func loadConfig(injectFile, valuesFile string) (*Config, string, error) {
+func loadConfig(injectFile, valuesFile string, keys ValuesKeyProvider) (*Config, string, error) {
 	data, err := ioutil.ReadFile(injectFile)
 	if err != nil {
 		return nil, "", err
 	}
+	valuesConfig, err := ioutil.ReadFile(valuesFile)
+	if err != nil {
+		return nil, "", err
+	}
+	return parseConfig(data, valuesConfig, valuesFile, keys)
+}
+
+// parseConfig parses and validates the injection config and values, named
+// by valuesSource in errors.
+func parseConfig(data, valuesConfig []byte, valuesSource string, keys ValuesKeyProvider) (*Config, string, error) {
 	var c Config
 	if err := yaml.Unmarshal(data, &c); err != nil {
 		log.Warnf("Failed to parse injectFile %s", string(data))
 		return nil, "", err
 	}
+	if err := validateTrustedProxies(&c); err != nil {
+		return nil, "", err
+	}
+	if err := validateCompatibilityProfile(c.CompatibilityProfile); err != nil {
+		return nil, "", err
+	}
+	if err := validateProxyPriority(c.ProxyPriority); err != nil {
+		return nil, "", err
+	}
+	if err := validateVPAPolicy(c.VerticalPodAutoscaler); err != nil {
+		return nil, "", err
+	}
+	if err := validateFreezeWindows(c.FreezeWindows); err != nil {
+		return nil, "", err
+	}
+	if err := validateStatusPortRange(c.StatusPortRange); err != nil {
+		return nil, "", err
+	}
+	if err := validatePortCollisionPolicy(c.PortCollisionPolicy); err != nil {
+		return nil, "", err
+	}
+	if err := validateEgressGateways(c.EgressGateways); err != nil {
+		return nil, "", err
+	}
+	if err := validateStatsInclusion(c.StatsInclusion); err != nil {
+		return nil, "", err
+	}
+	if err := validateHostNamespacePolicy(c.HostNamespacePolicy); err != nil {
+		return nil, "", err
+	}
+	if err := validateMetadataPropagation(c.MetadataPropagation); err != nil {
+		return nil, "", err
+	}
+	if err := validateAppProxy(c.AppProxy); err != nil {
+		return nil, "", err
+	}
+	if err := validatePodValuesAllowlist(c.PodValuesAllowlist); err != nil {
+		return nil, "", err
+	}
 
-	valuesConfig, err := ioutil.ReadFile(valuesFile)
+	valuesConfig, err := decryptValues(valuesConfig, keys)
 	if err != nil {
+		return nil, "", fmt.Errorf("%s: %v", valuesSource, err)
+	}
+	if err := checkProxyVersionSkew(c.ProxyVersionSkew, buildversion.Info.Version, string(valuesConfig)); err != nil {
 		return nil, "", err
 	}
 
@@ -123,14 +271,32 @@ type WebhookParameters struct {
 
 	ValuesFile string
 
+	// TemplateOverrideDir, if set, is a directory whose config and values
+	// files take precedence over ConfigFile and ValuesFile. This is an
+	// emergency escape hatch for when the injection ConfigMap cannot be fixed.
+	TemplateOverrideDir string
+
 	// Port is the webhook port, e.g. typically 443 for https.
 	// This is mainly used for tests. Webhook runs on the port started by Istiod.
 	Port int
 
+	// InsecurePort, if positive, additionally serves the injection handlers
+	// without TLS on localhost. This is meant for local testing only.
+	InsecurePort int
+
+	// UDSPath, if set, additionally serves the injection handlers without TLS
+	// on a unix socket at this path, for integration tests and local tooling
+	// driving the real handlers without certificates.
+	UDSPath string
+
 	// MonitoringPort is the webhook port, e.g. typically 15014.
 	// Set to -1 to disable monitoring
 	MonitoringPort int
 
+	// EnableProfiling serves the net/http/pprof handlers on /debug/pprof/ of
+	// the monitoring port, when monitoring with Prometheus.
+	EnableProfiling bool
+
 	// HealthCheckInterval configures how frequently the health check
 	// file is updated. Value of zero disables the health check
 	// update.
@@ -147,6 +313,112 @@ type WebhookParameters struct {
 
 	// The istio.io/rev this injector is responsible for
 	Revision string
+
+	// Watchdog configures the resource leak watchdog.
+	Watchdog WatchdogOptions
+
+	// MetricsBackend selects how injector metrics are exported, either
+	// PrometheusMetricsBackend (the default) or StatsdMetricsBackend.
+	MetricsBackend string
+
+	// StatsdAddress is the host:port of the statsd server used by StatsdMetricsBackend.
+	StatsdAddress string
+
+	// AdmissionWorkers is the number of workers handling admission requests,
+	// served fairly across namespaces. Zero handles each request on its own
+	// goroutine, as received.
+	AdmissionWorkers int
+
+	// LoadShedding bounds the admission requests waiting for one of the
+	// AdmissionWorkers, rejecting the ones beyond it with a fast error.
+	LoadShedding LoadSheddingOptions
+
+	// KubeClient is used to look up namespaces of injected pods. Optional;
+	// namespace level settings are ignored when not set.
+	KubeClient kubernetes.Interface
+
+	// Informers are the shared informers of KubeClient, started by the caller,
+	// e.g. the ones of istiod. Optional; what watches the pods of the cluster
+	// is disabled when not set.
+	Informers informers.SharedInformerFactory
+
+	// ClientAuth restricts the clients allowed to request injection.
+	ClientAuth ClientAuthOptions
+
+	// TokenAuth requires audience bound tokens of the clients requesting
+	// injection. Requires KubeClient.
+	TokenAuth TokenAuthOptions
+
+	// Canary validates reloaded configurations against recently injected pods.
+	Canary CanaryOptions
+
+	// DiscoveryGate gates readiness on the reachability of the control plane.
+	DiscoveryGate DiscoveryGateOptions
+
+	// ServingCertificate returns the certificate the webhook is served with.
+	// Optional; the readiness endpoint checks it is valid when set.
+	ServingCertificate func() (*tls.Certificate, error)
+
+	// ShutdownGracePeriod bounds how long Run waits, once stopped, for the
+	// in-flight admission requests to be answered. Zero stops immediately.
+	ShutdownGracePeriod time.Duration
+
+	// Notifications posts the lifecycle events of the injector to a URL.
+	Notifications NotificationOptions
+
+	// AuditLogFile, if set, is appended a JSON line per admission decision.
+	AuditLogFile string
+
+	// NamespaceCache keeps the eligibility of the namespaces in a cache backed
+	// by the shared namespace informer, so admission requests do not get the
+	// namespace of the pod from the API server. Requires KubeClient and
+	// Informers.
+	NamespaceCache bool
+
+	// FieldManager, if set, is the manager the injected fields are attributed
+	// to in the managedFields of the pod, e.g. FieldManager, so they do not
+	// conflict with the server-side applies of the pod manifest.
+	FieldManager string
+
+	// FanInConfigFile, if set, lists the clusters whose API servers call the
+	// injector through a URL clientConfig, each injected with its own
+	// configuration. Their callers are identified by TokenAuth or ClientAuth,
+	// which must allow them.
+	FanInConfigFile string
+
+	// NamespaceFilter restricts the namespaces injected, in addition to the
+	// namespaceSelector of the webhook configuration.
+	NamespaceFilter NamespaceFilterOptions
+
+	// ValuesKeys, if set, decrypts the values files encrypted with
+	// EncryptValues. The decrypted values are not served by the debug handlers.
+	ValuesKeys ValuesKeyProvider
+
+	// ConfigMap watches the injection ConfigMap with the Kubernetes API, in
+	// addition to the files. Requires KubeClient.
+	ConfigMap ConfigMapWatchOptions
+
+	// MeshConfigMap watches the ConfigMap the mesh config file is mounted
+	// from, so the mesh config is identified by its resource version in
+	// ConfigVersions. Requires KubeClient.
+	MeshConfigMap ConfigMapWatchOptions
+
+	// DriftVerifier verifies in the background that the running injected
+	// pods match the active configuration. Requires Informers.
+	DriftVerifier DriftVerifierOptions
+
+	// CostSampleRate is the fraction of the admissions labeled with their
+	// namespace and template version in the CPU profiles. Zero labels none.
+	CostSampleRate float64
+
+	// StartupGate answers the admissions received before the configuration
+	// and the serving certificate are validated without injecting.
+	StartupGate StartupGateOptions
+
+	// FailureEvents records the failures to render the template or create the
+	// patch of a pod as events on its namespace and ReplicaSet. Requires
+	// KubeClient.
+	FailureEvents bool
 }
 
 // NewWebhook creates a new instance of a mutating webhook for automatic sidecar injection.
@@ -154,63 +426,373 @@ func NewWebhook(p WebhookParameters) (*Webhook, error) {
 	if p.Mux == nil {
 		return nil, errors.New("expected mux to be passed, but was not passed")
 	}
-	sidecarConfig, valuesConfig, err := loadConfig(p.ConfigFile, p.ValuesFile)
-	if err != nil {
+	if err := validateNotificationOptions(p.Notifications); err != nil {
 		return nil, err
 	}
-	watcher, err := fsnotify.NewWatcher()
+	if err := validateLoadSheddingOptions(p.LoadShedding, p.AdmissionWorkers); err != nil {
+		return nil, err
+	}
+	if err := validateNamespaceFilterOptions(p.NamespaceFilter); err != nil {
+		return nil, err
+	}
+	configFile, valuesFile := templateOverrideFiles(p.TemplateOverrideDir, p.ConfigFile, p.ValuesFile)
+	sidecarConfig, valuesConfig, err := loadConfig(configFile, valuesFile, p.ValuesKeys)
 	if err != nil {
 		return nil, err
 	}
-	// watch the parent directory of the target files so we can catch
-	// symlink updates of k8s ConfigMaps volumes.
-	for _, file := range []string{p.ConfigFile} {
-		watchDir, _ := filepath.Split(file)
-		if err := watcher.Watch(watchDir); err != nil {
-			return nil, fmt.Errorf("could not watch %v: %v", file, err)
-		}
+	audit, err := openAuditLog(p.AuditLogFile)
+	if err != nil {
+		return nil, err
 	}
-
 	wh := &Webhook{
 		Config:                 sidecarConfig,
 		sidecarTemplateVersion: sidecarTemplateVersionHash(sidecarConfig.Template),
 		meshConfig:             p.Env.Mesh(),
 		configFile:             p.ConfigFile,
 		valuesFile:             p.ValuesFile,
+		templateOverrideDir:    p.TemplateOverrideDir,
 		valuesConfig:           valuesConfig,
-		watcher:                watcher,
 		healthCheckInterval:    p.HealthCheckInterval,
 		healthCheckFile:        p.HealthCheckFile,
 		env:                    p.Env,
 		revision:               p.Revision,
+		kubeClient:             p.KubeClient,
+		insecurePort:           p.InsecurePort,
+		udsPath:                p.UDSPath,
+		canary:                 newCanary(p.Canary),
+		discoveryGate:          p.DiscoveryGate,
+		decisions:              &decisionLog{},
+		latencies:              &latencyWindow{},
+		audit:                  audit,
+		shutdownGracePeriod:    p.ShutdownGracePeriod,
+		servingCertificate:     p.ServingCertificate,
+		notifier:               newNotifier(p.Notifications, notificationSource(p.Revision)),
+		fieldManager:           p.FieldManager,
+		namespaceFilter:        p.NamespaceFilter,
+		valuesKeys:             p.ValuesKeys,
+		costs:                  newCostSampler(p.CostSampleRate),
+		startup:                newStartupGate(p.StartupGate),
+		reloads:                make(chan struct{}, 1),
+	}
+	wh.watchdog = newWatchdog(p.Watchdog, func() int { return inotifyWatches("/proc/self") })
+	if p.KubeClient != nil {
+		// the version is discovered while the servers start, the first
+		// admissions wait for it
+		wh.kubernetesDiscovered = make(chan struct{})
+		go wh.discoverKubernetesCapabilities(p.KubeClient)
+		wh.owners = newOwnerResolver(p.KubeClient)
+		wh.limits = newLimitRangeCache(p.KubeClient)
+		if p.NamespaceCache && p.Informers != nil {
+			wh.namespaces = newNamespaceCache(p.KubeClient, p.Informers.Core().V1().Namespaces(), wh.revision)
+		} else if p.NamespaceCache {
+			log.Warnf("Not caching the namespaces without the shared informers")
+		}
+		var pods coreinformers.PodInformer
+		if p.Informers != nil {
+			pods = p.Informers.Core().V1().Pods()
+		}
+		wh.statuses = newStatusStore(p.KubeClient, pods)
+		if p.ConfigMap.enabled() {
+			wh.configMaps = newConfigMapWatcher(p.KubeClient, p.ConfigMap, "injection", wh.applyConfigMap)
+		}
+		if p.MeshConfigMap.enabled() {
+			wh.meshConfigMap = newConfigMapWatcher(p.KubeClient, p.MeshConfigMap, "mesh", func(*corev1.ConfigMap) {
+				wh.recordConfigInfo()
+			})
+		}
+		if p.Informers != nil {
+			wh.injected = newInjectedPodCounter(p.Informers.Core().V1().Pods())
+			wh.drift = newDriftVerifier(wh, p.DriftVerifier, p.Informers.Core().V1().Pods())
+		} else if p.DriftVerifier.Interval > 0 {
+			log.Warnf("Not verifying the drift of the injected pods without the shared informers")
+		}
+		wh.recorder = newEventRecorder(p.KubeClient)
+		wh.failureEvents = p.FailureEvents
+	} else if p.ConfigMap.enabled() {
+		log.Warnf("Not watching the injection ConfigMap %s/%s without a Kubernetes client, relying on the mounted files",
+			p.ConfigMap.Namespace, p.ConfigMap.Name)
+	}
+	if p.AdmissionWorkers > 0 {
+		wh.queue = newFairQueue(p.LoadShedding)
+		wh.queue.start(p.AdmissionWorkers)
 	}
-	p.Mux.HandleFunc("/inject", wh.serveInject)
-	p.Mux.HandleFunc("/inject/", wh.serveInject)
+	if err := p.ClientAuth.Validate(); err != nil {
+		return nil, err
+	}
+	tokens, err := newTokenAuthenticator(p.TokenAuth, p.KubeClient)
+	if err != nil {
+		return nil, err
+	}
+	if wh.fanIn, err = newFanIn(p, wh.queue); err != nil {
+		return nil, err
+	}
+	serve := wh.serveInject
+	if wh.fanIn != nil {
+		serve = wh.serveFanIn
+	}
+	// the insecure, unix socket and health listeners serve the same handler, so
+	// the callers are authorized whichever listener they reach
+	wh.injectHandler = p.ClientAuth.authorizeClient(tokens.authenticateClient(serve))
+	p.Mux.HandleFunc("/inject", wh.injectHandler)
+	p.Mux.HandleFunc("/inject/", wh.injectHandler)
+	p.Mux.HandleFunc(annotationCatalogPath, wh.serveAnnotationCatalog)
+	p.Mux.HandleFunc(templateVariablesPath, serveTemplateVariables)
+	p.Mux.HandleFunc(readyzPath, wh.serveReadyz)
 
 	p.Env.Watcher.AddMeshHandler(func() {
-		wh.mu.Lock()
-		wh.meshConfig = p.Env.Mesh()
-		wh.mu.Unlock()
+		wh.updateMeshConfig(p.Env.Mesh())
 	})
+	wh.recordConfigInfo()
 
-	if p.MonitoringPort >= 0 {
-		mon, err := startMonitor(p.Mux, p.MonitoringPort)
+	switch p.MetricsBackend {
+	case "", PrometheusMetricsBackend:
+		if p.MonitoringPort >= 0 {
+			mon, err := startMonitor(p.Mux, p.MonitoringPort, p.EnableProfiling)
+			if err != nil {
+				return nil, fmt.Errorf("could not start monitoring server %v", err)
+			}
+			wh.mon = mon
+		}
+	case StatsdMetricsBackend:
+		exporter, err := newStatsdExporter(p.StatsdAddress, "istio")
 		if err != nil {
-			return nil, fmt.Errorf("could not start monitoring server %v", err)
+			return nil, err
 		}
-		wh.mon = mon
+		wh.statsd = exporter
+	default:
+		return nil, fmt.Errorf("unknown metrics backend %q", p.MetricsBackend)
 	}
 
 	return wh, nil
 }
 
+// watchConfig starts watching the injection config for changes. This is done
+// from Run rather than NewWebhook to keep file system watchers off the startup path.
+func (wh *Webhook) watchConfig() (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// watch the parent directory of the target files so we can catch
+	// symlink updates of k8s ConfigMaps volumes.
+	watched := map[string]bool{}
+	for _, file := range []string{wh.configFile, wh.valuesFile} {
+		watchDir, _ := filepath.Split(file)
+		if file == "" || watched[watchDir] {
+			continue
+		}
+		watched[watchDir] = true
+		if err := watcher.Watch(watchDir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("could not watch %v: %v", file, err)
+		}
+	}
+	if wh.templateOverrideDir != "" {
+		if err := watcher.Watch(wh.templateOverrideDir); err != nil {
+			log.Warnf("Could not watch template override directory %s, overrides apply on the next reload: %v",
+				wh.templateOverrideDir, err)
+		}
+	}
+	return watcher, nil
+}
+
+// reloadConfig loads the injection configuration from its files and, once
+// the canary accepts it, swaps it in for the requests admitted after it.
+func (wh *Webhook) reloadConfig() {
+	configFile, valuesFile := templateOverrideFiles(wh.templateOverrideDir, wh.configFile, wh.valuesFile)
+	if configFile == wh.configFile && valuesFile == wh.valuesFile && wh.configMaps.synced() {
+		// the mounted files lag behind the watched ConfigMap
+		if cm := wh.configMaps.latest(); cm != nil {
+			wh.applyConfigMap(cm)
+		}
+		return
+	}
+	sidecarConfig, valuesConfig, err := loadConfig(configFile, valuesFile, wh.valuesKeys)
+	if err != nil {
+		configReloads.With(resultTag.Value(reloadFailure)).Increment()
+		log.Errorf("update error: %v", err)
+		return
+	}
+	wh.activateConfig(sidecarConfig, valuesConfig)
+}
+
+// Reload loads the injection configuration files again, as if they had
+// changed, once Run is started. Freeze windows still apply, including to
+// the template overrides.
+func (wh *Webhook) Reload() {
+	select {
+	case wh.reloads <- struct{}{}:
+	default:
+		// a reload is already pending
+	}
+}
+
+// activateConfig swaps in a loaded configuration once the canary accepts it.
+func (wh *Webhook) activateConfig(sidecarConfig *Config, valuesConfig string) {
+	version := sidecarTemplateVersionHash(sidecarConfig.Template)
+	wh.mu.RLock()
+	meshConfig := wh.meshConfig
+	previous := wh.sidecarTemplateVersion
+	wh.mu.RUnlock()
+	if err := wh.canary.check(sidecarConfig, valuesConfig, version, meshConfig); err != nil {
+		configCanaryHolds.Increment()
+		log.Errorf("Not activating the new injection configuration: %v", err)
+		return
+	}
+	wh.mu.Lock()
+	wh.Config = sidecarConfig
+	wh.valuesConfig = valuesConfig
+	wh.sidecarTemplateVersion = version
+	wh.mu.Unlock()
+	wh.recordConfigInfo()
+	configReloads.With(resultTag.Value(reloadSuccess)).Increment()
+	wh.notifier.notify(EventConfigReloaded, map[string]string{"template": version})
+	if version != previous {
+		log.Infof("Reloaded the injection configuration, template version %s (was %s)", version, previous)
+		wh.notifier.notify(EventTemplateChanged, map[string]string{"template": version, "previous": previous})
+	} else {
+		log.Infof("Reloaded the injection configuration, template version %s unchanged", version)
+	}
+}
+
+// ConfigVersions are the versions of the configuration the webhook injects with.
+type ConfigVersions struct {
+	Template   string `json:"template"`
+	Values     string `json:"values"`
+	MeshConfig string `json:"meshConfig"`
+}
+
+// ConfigVersions returns the versions of the active injection configuration:
+// the hashes of the template and values, and the resource version of the mesh
+// config.
+func (wh *Webhook) ConfigVersions() ConfigVersions {
+	wh.mu.RLock()
+	defer wh.mu.RUnlock()
+	return wh.configVersions()
+}
+
+// configVersions returns the versions of the active injection configuration.
+// The caller holds wh.mu.
+func (wh *Webhook) configVersions() ConfigVersions {
+	return ConfigVersions{
+		Template:   wh.sidecarTemplateVersion,
+		Values:     sidecarTemplateVersionHash(wh.valuesConfig),
+		MeshConfig: meshConfigVersion(wh.meshConfigMap.latest(), wh.meshConfig),
+	}
+}
+
+// updateMeshConfig swaps in a new mesh config once the canary accepts it with
+// the current injection configuration. A mesh config breaking injection is
+// not activated, the webhook keeps injecting with the last good one.
+func (wh *Webhook) updateMeshConfig(mc *meshconfig.MeshConfig) {
+	wh.mu.RLock()
+	config, valuesConfig, version := wh.Config, wh.valuesConfig, wh.sidecarTemplateVersion
+	wh.mu.RUnlock()
+	if err := wh.canary.check(config, valuesConfig, version, mc); err != nil {
+		configCanaryHolds.Increment()
+		log.Errorf("Not activating the new mesh config for injection: %v", err)
+		return
+	}
+	wh.mu.Lock()
+	wh.meshConfig = mc
+	wh.mu.Unlock()
+	wh.recordConfigInfo()
+	log.Infof("Injecting with the updated mesh config")
+}
+
+// serveInsecure serves the injection handlers without TLS on the address.
+func (wh *Webhook) serveInsecure(addr string) (*http.Server, net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to listen on %s: %v", addr, err)
+	}
+	return wh.serveInsecureListener(listener), listener, nil
+}
+
+// serveInsecureListener serves the injection handlers without TLS on the listener.
+func (wh *Webhook) serveInsecureListener(listener net.Listener) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/inject", wh.injectHandler)
+	mux.HandleFunc("/inject/", wh.injectHandler)
+	mux.HandleFunc(annotationCatalogPath, wh.serveAnnotationCatalog)
+	mux.HandleFunc(templateVariablesPath, serveTemplateVariables)
+	mux.HandleFunc(readyzPath, wh.serveReadyz)
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Insecure injection server failed: %v", err)
+		}
+	}()
+	return server
+}
+
 // Run implements the webhook server
 func (wh *Webhook) Run(stop <-chan struct{}) {
-	defer wh.watcher.Close()
+	var eventC <-chan *fsnotify.FileEvent
+	var errorC <-chan error
+	if watcher, err := wh.watchConfig(); err != nil {
+		log.Errorf("Injection config changes will not be picked up: %v", err)
+	} else {
+		defer watcher.Close()
+		eventC = watcher.Event
+		errorC = watcher.Error
+	}
 
+	var insecureServer *http.Server
+	if wh.insecurePort > 0 {
+		if server, listener, err := wh.serveInsecure(fmt.Sprintf("127.0.0.1:%d", wh.insecurePort)); err != nil {
+			log.Errorf("Could not serve injection without TLS: %v", err)
+		} else {
+			log.Warnf("Serving injection without TLS on %s, for local testing only", listener.Addr())
+			insecureServer = server
+			defer server.Close()
+		}
+	}
+	var udsServer *http.Server
+	if wh.udsPath != "" {
+		if server, err := wh.serveUDS(wh.udsPath); err != nil {
+			log.Errorf("Could not serve injection on the unix socket: %v", err)
+		} else {
+			log.Warnf("Serving injection without TLS on the unix socket %s", wh.udsPath)
+			udsServer = server
+			defer server.Close()
+		}
+	}
+	if wh.notifier != nil {
+		go wh.notifier.run(stop)
+	}
+	defer wh.audit.close()
+	defer wh.recorder.close()
+	defer configInfos.remove(&wh.configInfo)
 	if wh.mon != nil {
 		defer wh.mon.monitoringServer.Close()
 	}
+	if wh.statsd != nil {
+		// the exporter is registered for the lifetime of the webhook only
+		view.RegisterExporter(wh.statsd)
+		defer wh.statsd.close()
+	}
+	if wh.queue != nil {
+		defer wh.queue.close()
+	}
+	if wh.namespaces != nil {
+		go wh.namespaces.run(stop)
+	}
+	if wh.meshConfigMap != nil {
+		go wh.meshConfigMap.run(stop)
+	}
+	if wh.configMaps != nil {
+		go wh.configMaps.run(stop)
+	}
+	if wh.drift != nil {
+		go wh.drift.run(stop)
+	}
+	if wh.statuses != nil {
+		go wh.statuses.run(stop)
+	}
+	if wh.kubeClient != nil {
+		go wh.rediscoverKubernetesCapabilities(stop)
+	}
 
 	var healthC <-chan time.Time
 	if wh.healthCheckInterval != 0 && wh.healthCheckFile != "" {
@@ -219,34 +801,55 @@ func (wh *Webhook) Run(stop <-chan struct{}) {
 		defer t.Stop()
 	}
 	var timerC <-chan time.Time
+	// overrideChanged is set when the pending reload follows a change of the
+	// override directory, which freeze windows do not hold: the overrides are
+	// the emergency fix of the injection config, needed during a freeze too.
+	var overrideChanged bool
+
+	go wh.watchdog.run(stop)
+	if wh.fanIn != nil {
+		go wh.fanIn.run(stop)
+	}
 
 	for {
 		select {
 		case <-timerC:
 			timerC = nil
-			sidecarConfig, valuesConfig, err := loadConfig(wh.configFile, wh.valuesFile)
-			if err != nil {
-				log.Errorf("update error: %v", err)
+			wh.mu.RLock()
+			until := frozenUntil(wh.Config.FreezeWindows, time.Now())
+			wh.mu.RUnlock()
+			override := overrideChanged && hasTemplateOverride(wh.templateOverrideDir, wh.configFile, wh.valuesFile)
+			if !until.IsZero() && !override {
+				configFreezeHolds.Increment()
+				log.Warnf("Not reloading the injection configuration until the end of the freeze window at %v", until)
+				// reload once the window is over, in case the configuration changed
+				timerC = time.After(time.Until(until))
 				break
 			}
-
-			version := sidecarTemplateVersionHash(sidecarConfig.Template)
-			if err != nil {
-				log.Errorf("reload cert error: %v", err)
-				break
+			if !until.IsZero() {
+				log.Warnf("Reloading the template override during the freeze window ending at %v", until)
 			}
-			wh.mu.Lock()
-			wh.Config = sidecarConfig
-			wh.valuesConfig = valuesConfig
-			wh.sidecarTemplateVersion = version
-			wh.mu.Unlock()
-		case event := <-wh.watcher.Event:
+			overrideChanged = false
+			wh.reloadConfig()
+		case <-wh.reloads:
+			if timerC == nil {
+				timerC = time.After(0)
+			}
+		case event := <-eventC:
 			log.Debugf("Injector watch update: %+v", event)
 			// use a timer to debounce configuration updates
-			if (event.IsModify() || event.IsCreate()) && timerC == nil {
+			// deletions are followed too, so removing an override restores the ConfigMap files
+			if !event.IsModify() && !event.IsCreate() && !event.IsDelete() {
+				break
+			}
+			if inTemplateOverrideDir(wh.templateOverrideDir, event.Name) {
+				// restart the timer, it may be held until the end of a freeze window
+				overrideChanged = true
+				timerC = time.After(watchDebounceDelay)
+			} else if timerC == nil {
 				timerC = time.After(watchDebounceDelay)
 			}
-		case err := <-wh.watcher.Error:
+		case err := <-errorC:
 			log.Errorf("Watcher error: %v", err)
 		case <-healthC:
 			content := []byte(`ok`)
@@ -254,7 +857,41 @@ func (wh *Webhook) Run(stop <-chan struct{}) {
 				log.Errorf("Health check update of %q failed: %v", wh.healthCheckFile, err)
 			}
 		case <-stop:
+			wh.drain(insecureServer, udsServer)
+			return
+		}
+	}
+}
+
+// drain stops the insecure servers from accepting connections and waits, up
+// to the shutdown grace period, for the in-flight admission requests to be
+// answered, so they are not failed by the shutdown of the queue. The HTTPS
+// server serving the webhook is drained by its owner.
+func (wh *Webhook) drain(insecureServers ...*http.Server) {
+	if wh.shutdownGracePeriod <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), wh.shutdownGracePeriod)
+	defer cancel()
+	for _, server := range insecureServers {
+		if server == nil {
+			continue
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			log.Warnf("Insecure injection server shutdown: %v", err)
+		}
+	}
+	if n := wh.inflight.Load(); n > 0 {
+		log.Infof("Draining %d in-flight admission requests", n)
+	}
+	t := time.NewTicker(drainPollInterval)
+	defer t.Stop()
+	for wh.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			log.Warnf("Shutting down with %d admission requests in flight after %v", wh.inflight.Load(), wh.shutdownGracePeriod)
 			return
+		case <-t.C:
 		}
 	}
 }
@@ -341,11 +978,15 @@ func addContainer(sic *SidecarInjectionSpec, target, added []corev1.Container, b
 			// so that envoy could fetch/pass k8s sa jwt and pass to sds server, which will be used to request workload identity for the pod.
 			add.VolumeMounts = append(add.VolumeMounts, saJwtSecretMount)
 		}
-		value = add
+		var container interface{} = add
+		if sic.NativeSidecar && add.Name == ProxyContainerName {
+			container = nativeSidecarContainer{Container: add, RestartPolicy: nativeSidecarRestartPolicy}
+		}
+		value = container
 		path := basePath
 		if first {
 			first = false
-			value = []corev1.Container{add}
+			value = []interface{}{container}
 		} else if shouldBeInjectedInFront(add, sic) {
 			path += "/0"
 		} else {
@@ -521,8 +1162,17 @@ func createPatch(pod *corev1.Pod, prevStatus *SidecarInjectionStatus, revision s
 	}
 
 	if rewrite {
-		patch = append(patch, createProbeRewritePatch(pod.Annotations, &pod.Spec, sic, mesh.GetDefaultConfig().GetStatusPort())...)
+		probeAnnotations := pod.Annotations
+		if port, f := annotations[annotation.SidecarStatusPort.Name]; f {
+			probeAnnotations = map[string]string{annotation.SidecarStatusPort.Name: port}
+			for k, v := range pod.Annotations {
+				probeAnnotations[k] = v
+			}
+		}
+		patch = append(patch, createProbeRewritePatch(probeAnnotations, &pod.Spec, sic, mesh.GetDefaultConfig().GetStatusPort())...)
 	}
+	// patched before the injected containers are removed or added, shifting the application containers
+	patch = append(patch, addAppEnv(pod.Spec.Containers, sic.AppEnv, prevStatus.Containers, "/spec/containers")...)
 
 	// Remove any containers previously injected by kube-inject using
 	// container and volume name as unique key for removal.
@@ -550,8 +1200,9 @@ func createPatch(pod *corev1.Pod, prevStatus *SidecarInjectionStatus, revision s
 		annotations["prometheus.io/scrape"] = "true"
 	}
 
-	patch = append(patch, addContainer(sic, pod.Spec.InitContainers, sic.InitContainers, "/spec/initContainers")...)
-	patch = append(patch, addContainer(sic, pod.Spec.Containers, sic.Containers, "/spec/containers")...)
+	initContainers, containers := sic.podContainers()
+	patch = append(patch, addContainer(sic, pod.Spec.InitContainers, initContainers, "/spec/initContainers")...)
+	patch = append(patch, addContainer(sic, pod.Spec.Containers, containers, "/spec/containers")...)
 	patch = append(patch, addVolume(pod.Spec.Volumes, sic.Volumes, "/spec/volumes")...)
 	patch = append(patch, addImagePullSecrets(pod.Spec.ImagePullSecrets, sic.ImagePullSecrets, "/spec/imagePullSecrets")...)
 
@@ -596,11 +1247,11 @@ func topologyValues(sic *SidecarInjectionSpec) string {
 
 func enablePrometheusMerge(mesh *meshconfig.MeshConfig, anno map[string]string) bool {
 	// If annotation is present, we look there first
-	if val, f := anno[annotation.PrometheusMergeMetrics.Name]; f {
-		bval, err := strconv.ParseBool(val)
+	if _, f := anno[annotation.PrometheusMergeMetrics.Name]; f {
+		bval, err := annotations.GetBool(anno, annotation.PrometheusMergeMetrics.Name, true)
 		if err != nil {
 			// This shouldn't happen since we validate earlier in the code
-			log.Warnf("invalid annotation %v=%v", annotation.PrometheusMergeMetrics.Name, bval)
+			log.Warnf("%v", err)
 		} else {
 			return bval
 		}
@@ -689,20 +1340,65 @@ func injectionStatus(pod *corev1.Pod) *SidecarInjectionStatus {
 }
 
 func toAdmissionResponse(err error) *kube.AdmissionResponse {
-	return &kube.AdmissionResponse{Result: &metav1.Status{Message: err.Error()}}
+	return webhooks.ToAdmissionResponse(err)
 }
 
 type InjectionParameters struct {
-	pod                 *corev1.Pod
-	deployMeta          *metav1.ObjectMeta
-	typeMeta            *metav1.TypeMeta
-	template            string
-	version             string
-	meshConfig          *meshconfig.MeshConfig
-	valuesConfig        string
-	revision            string
-	proxyEnvs           map[string]string
-	injectedAnnotations map[string]string
+	pod                  *corev1.Pod
+	deployMeta           *metav1.ObjectMeta
+	typeMeta             *metav1.TypeMeta
+	template             string
+	version              string
+	meshConfig           *meshconfig.MeshConfig
+	valuesConfig         string
+	revision             string
+	proxyEnvs            map[string]string
+	injectedAnnotations  map[string]string
+	declareStatusPort    bool
+	statusPortRange      *PortRange
+	portCollisionPolicy  PortCollisionPolicy
+	compatibilityProfile string
+	workloadIdentity     bool
+	limitRanges          []corev1.LimitRangeItem
+	proxyPriority        *ProxyPriorityPolicy
+	vpaPolicy            *VPAPolicy
+	egressGateways       map[string]string
+	statsInclusion       *StatsInclusionConfig
+	hostNamespacePolicy  HostNamespacePolicy
+	metadataPropagation  *MetadataPropagationConfig
+	appProxy             *AppProxyConfig
+	namespaceAppProxy    string
+	kubernetes           *KubernetesCapabilities
+	nativeSidecar        bool
+	statusStore          *statusStore
+	namespaceValues      string
+	workloadValues       string
+	podValuesAllowlist   []string
+	fieldManager         string
+}
+
+// withConfig returns the parameters with the settings of the injection configuration applied.
+func (p InjectionParameters) withConfig(c *Config, valuesConfig, version string, mc *meshconfig.MeshConfig) InjectionParameters {
+	p.template = c.Template
+	p.version = version
+	p.meshConfig = applyTrustedProxies(mc, trustedProxiesForNamespace(c, p.pod.Namespace))
+	p.valuesConfig = valuesConfig
+	p.injectedAnnotations = c.InjectedAnnotations
+	p.declareStatusPort = c.DeclareStatusPort
+	p.statusPortRange = c.StatusPortRange
+	p.portCollisionPolicy = c.PortCollisionPolicy
+	p.nativeSidecar = c.NativeSidecar
+	p.compatibilityProfile = c.CompatibilityProfile
+	p.workloadIdentity = c.WorkloadIdentity
+	p.proxyPriority = c.ProxyPriority
+	p.vpaPolicy = c.VerticalPodAutoscaler
+	p.egressGateways = c.EgressGateways
+	p.statsInclusion = c.StatsInclusion
+	p.hostNamespacePolicy = c.HostNamespacePolicy
+	p.metadataPropagation = c.MetadataPropagation
+	p.appProxy = c.AppProxy
+	p.podValuesAllowlist = c.PodValuesAllowlist
+	return p
 }
 
 func getDeployMetaFromPod(pod *corev1.Pod) (*metav1.ObjectMeta, *metav1.TypeMeta) {
@@ -749,7 +1445,9 @@ func getDeployMetaFromPod(pod *corev1.Pod) (*metav1.ObjectMeta, *metav1.TypeMeta
 	return deployMeta, typeMetadata
 }
 
-func injectPod(req InjectionParameters) ([]byte, error) {
+// injectPod returns the patch injecting the pod of req, with the Kubernetes
+// lookups of the injection bounded by ctx.
+func injectPod(ctx context.Context, req InjectionParameters) ([]byte, error) {
 	pod := req.pod
 
 	// due to bug https://github.com/kubernetes/kubernetes/issues/57923,
@@ -764,72 +1462,259 @@ func injectPod(req InjectionParameters) ([]byte, error) {
 		pod.Spec.SecurityContext.FSGroup = &grp
 	}
 
+	statusPort, err := allocateStatusPort(pod, req.meshConfig.GetDefaultConfig().GetStatusPort(), req.statusPortRange)
+	if err != nil {
+		return nil, err
+	}
+	if statusPort != 0 {
+		// render with the new status port, while patching the original pod
+		log.Infof("Moving the status port of %s/%s to %d, the port is declared by the application",
+			pod.Namespace, potentialPodName(&pod.ObjectMeta), statusPort)
+		req.pod = withStatusPort(pod, statusPort)
+	}
+	var hostPortsExcluded bool
+	if podHostNamespacePolicy(req.hostNamespacePolicy, &pod.Spec) == HostNamespaceAdjust {
+		// render with the host ports excluded from capture, while patching the original pod
+		if adjusted := withHostPortsExcluded(req.pod); adjusted != nil {
+			req.pod = adjusted
+			hostPortsExcluded = true
+		}
+	}
+
 	spec, iStatus, err := InjectionData(req, req.typeMeta, req.deployMeta)
 	if err != nil {
 		return nil, err
 	}
 
 	annotations := map[string]string{annotation.SidecarStatus.Name: iStatus}
+	if statusPort != 0 {
+		annotations[annotation.SidecarStatusPort.Name] = req.pod.Annotations[annotation.SidecarStatusPort.Name]
+	}
+	if hostPortsExcluded {
+		annotations[annotation.SidecarTrafficExcludeInboundPorts.Name] = req.pod.Annotations[annotation.SidecarTrafficExcludeInboundPorts.Name]
+	}
 
+	for k, v := range spec.Annotations {
+		annotations[k] = v
+	}
 	// Add all additional injected annotations
 	for k, v := range req.injectedAnnotations {
 		annotations[k] = v
 	}
+	vpa, err := vpaAnnotations(req.vpaPolicy)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range vpa {
+		annotations[k] = v
+	}
 
-	patchBytes, err := createPatch(pod, injectionStatus(pod), req.revision, annotations, spec, req.deployMeta.Name, req.meshConfig)
+	annotations[annotation.SidecarStatus.Name] = req.statusStore.fit(ctx, pod, annotations)
+	prevStatus, err := req.statusStore.resolve(ctx, pod)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Debugf("AdmissionResponse: patch=%v\n", string(patchBytes))
+	patchBytes, err := createPatch(pod, prevStatus, req.revision, annotations, spec, req.deployMeta.Name, req.meshConfig)
+	if err != nil {
+		return nil, err
+	}
+	if req.fieldManager != "" {
+		if patchBytes, err = attributeManagedFields(pod, patchBytes, req.fieldManager, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to attribute the injected fields to %s: %v", req.fieldManager, err)
+		}
+	}
+
+	if log.DebugEnabled() {
+		log.Debugf("AdmissionResponse: patch=%v\n", redactedPatch(patchBytes))
+	}
 	return patchBytes, nil
 }
 
-func (wh *Webhook) inject(ar *kube.AdmissionReview, path string) *kube.AdmissionResponse {
+func (wh *Webhook) inject(ctx context.Context, ar *kube.AdmissionReview, path string) *kube.AdmissionResponse {
 	req := ar.Request
+	ctx = withDryRun(ctx, req.DryRun != nil && *req.DryRun)
 	var pod corev1.Pod
 	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
-		handleError(fmt.Sprintf("Could not unmarshal raw object: %v %s", err,
-			string(req.Object.Raw)))
+		handleError(fmt.Sprintf("Could not unmarshal raw object of %d bytes: %v", len(req.Object.Raw), err))
+		wh.decisions.record(Decision{Namespace: req.Namespace, Outcome: DecisionFailed, Reason: "could not unmarshal the pod"})
+		wh.audit.record(AuditRecord{Namespace: req.Namespace, Decision: DecisionFailed, Reason: "could not unmarshal the pod"})
 		return toAdmissionResponse(err)
 	}
 
 	// Deal with potential empty fields, e.g., when the pod is created by a deployment
-	podName := potentialPodName(&pod.ObjectMeta)
 	if pod.ObjectMeta.Namespace == "" {
 		pod.ObjectMeta.Namespace = req.Namespace
 	}
+	wh.awaitKubernetesCapabilities(ctx)
+	// a reload may swap the configuration concurrently, inject with one snapshot of it
+	wh.mu.RLock()
+	config, valuesConfig, version, mc := wh.Config, wh.valuesConfig, wh.sidecarTemplateVersion, wh.meshConfig
+	capabilities := wh.kubernetes
+	wh.mu.RUnlock()
+	deploy, typeMeta := wh.getDeployMeta(ctx, &pod)
+	podName := workloadLogName(&pod, deploy, typeMeta)
 	log.Infof("Sidecar injection request for %v/%v", req.Namespace, podName)
-	log.Debugf("Object: %v", string(req.Object.Raw))
-	log.Debugf("OldObject: %v", string(req.OldObject.Raw))
+	decide := func(outcome, reason string, patch []byte) {
+		wh.decisions.record(Decision{Namespace: pod.Namespace, Workload: podName, Owner: deploy.Name,
+			Template: version, Outcome: outcome, Reason: reason})
+		wh.audit.record(AuditRecord{Namespace: pod.Namespace, GenerateName: pod.GenerateName, OwnerKind: typeMeta.Kind,
+			Decision: outcome, Reason: reason, Template: version, PatchSize: len(patch)})
+		wh.notifier.admission(outcome, reason)
+		wh.events.dispatch(ctx, outcome, func() InjectionEvent {
+			return InjectionEvent{Pod: *pod.ObjectMeta.DeepCopy(), OwnerKind: typeMeta.Kind, OwnerName: deploy.Name,
+				Decision: outcome, Reason: reason, Template: version, Patch: summarizePatch(patch)}
+		})
+	}
+	if log.DebugEnabled() {
+		log.Debugf("Object: %v", redactedPodJSON(req.Object.Raw))
+		log.Debugf("OldObject: %v", redactedPodJSON(req.OldObject.Raw))
+	}
+
+	if reason := wh.namespaceFilter.skipReason(pod.Namespace); reason != "" {
+		log.Infof("Skipping %s/%s, namespace excluded from injection (%s)", pod.ObjectMeta.Namespace, podName, reason)
+		totalSkippedInjections.With(reasonTag.Value(reason)).Increment()
+		decide(DecisionSkipped, reason, nil)
+		return &kube.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	ns := wh.getNamespace(ctx, pod.Namespace)
+	nsAnnotations := ns.nsAnnotations()
+	if reason := ns.revisionSkipReason(wh.revision, pod.Labels); reason != "" {
+		log.Infof("Skipping %s/%s, not of revision %q", pod.ObjectMeta.Namespace, podName, wh.revision)
+		totalSkippedInjections.With(reasonTag.Value(reason)).Increment()
+		decide(DecisionSkipped, reason, nil)
+		return &kube.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	if ns.ambientEnabled(pod.Labels) {
+		log.Infof("Skipping %s/%s due to ambient mode", pod.ObjectMeta.Namespace, podName)
+		totalSkippedInjections.With(reasonTag.Value(skipReasonAmbient)).Increment()
+		patchBytes, err := createAmbientPatch(ctx, &pod, wh.statuses)
+		if err != nil {
+			handleError(fmt.Sprintf("Pod ambient patch failed: %v", err))
+			decide(DecisionFailed, err.Error(), nil)
+			return toAdmissionResponse(err)
+		}
+		decide(DecisionSkipped, skipReasonAmbient, patchBytes)
+		return &kube.AdmissionResponse{
+			Allowed: true,
+			Patch:   patchBytes,
+			PatchType: func() *string {
+				pt := "JSONPatch"
+				return &pt
+			}(),
+		}
+	}
 
-	if !injectRequired(ignoredNamespaces, wh.Config, &pod.Spec, &pod.ObjectMeta) {
+	if !injectRequired(ignoredNamespaces, config, &pod.Spec, &pod.ObjectMeta) {
 		log.Infof("Skipping %s/%s due to policy check", pod.ObjectMeta.Namespace, podName)
-		totalSkippedInjections.Increment()
+		totalSkippedInjections.With(reasonTag.Value(skipReasonPolicy)).Increment()
+		decide(DecisionSkipped, skipReasonPolicy, nil)
 		return &kube.AdmissionResponse{
 			Allowed: true,
 		}
 	}
 
-	deploy, typeMeta := getDeployMetaFromPod(&pod)
+	// injected is set once the pod is injected, for the accounting of the quota
+	injected := false
+	if quota := namespaceQuota(nsAnnotations, config.NamespaceInjectionQuota); quota > 0 && wh.injected != nil {
+		admitted, release, err := wh.injected.admit(ctx, pod.Namespace, quota)
+		if admitted {
+			// only the injected pods are counted
+			defer func() {
+				if !injected {
+					release()
+				}
+			}()
+		}
+		if err != nil {
+			log.Warnf("Failed to count injected pods of namespace %s, not enforcing its quota: %v", pod.Namespace, err)
+		} else if !admitted {
+			log.Warnf("Skipping %s/%s, namespace has reached its quota of %d injected pods", pod.ObjectMeta.Namespace, podName, quota)
+			totalSkippedInjections.With(reasonTag.Value(skipReasonQuota)).Increment()
+			recordQuotaEvent(ctx, wh.recorder, pod.Namespace, quota)
+			patchBytes, err := createQuotaPatch(&pod)
+			if err != nil {
+				handleError(fmt.Sprintf("Pod quota patch failed: %v", err))
+				decide(DecisionFailed, err.Error(), nil)
+				return toAdmissionResponse(err)
+			}
+			decide(DecisionSkipped, skipReasonQuota, patchBytes)
+			return &kube.AdmissionResponse{
+				Allowed: true,
+				Patch:   patchBytes,
+				PatchType: func() *string {
+					pt := "JSONPatch"
+					return &pt
+				}(),
+			}
+		}
+	}
+
+	if usage := hostNamespaceUsage(&pod.Spec); len(usage) > 0 {
+		switch podHostNamespacePolicy(config.HostNamespacePolicy, &pod.Spec) {
+		case HostNamespaceReject:
+			err := fmt.Errorf("sidecar injection rejected, the pod uses %s", strings.Join(usage, ", "))
+			handleError(fmt.Sprintf("Pod injection failed: %v", err))
+			decide(DecisionFailed, err.Error(), nil)
+			return toAdmissionResponse(err)
+		case HostNamespaceSkip:
+			log.Infof("Skipping %s/%s, the pod uses %s", pod.ObjectMeta.Namespace, podName, strings.Join(usage, ", "))
+			totalSkippedInjections.With(reasonTag.Value(skipReasonHostNamespaces)).Increment()
+			recordHostNamespaceEvent(ctx, wh.recorder, deploy, typeMeta, usage)
+			patchBytes, err := createHostNamespacePatch(&pod)
+			if err != nil {
+				handleError(fmt.Sprintf("Pod host namespaces patch failed: %v", err))
+				decide(DecisionFailed, err.Error(), nil)
+				return toAdmissionResponse(err)
+			}
+			decide(DecisionSkipped, skipReasonHostNamespaces, patchBytes)
+			return &kube.AdmissionResponse{
+				Allowed: true,
+				Patch:   patchBytes,
+				PatchType: func() *string {
+					pt := "JSONPatch"
+					return &pt
+				}(),
+			}
+		}
+	}
+
 	params := InjectionParameters{
-		pod:                 &pod,
-		deployMeta:          deploy,
-		typeMeta:            typeMeta,
-		template:            wh.Config.Template,
-		version:             wh.sidecarTemplateVersion,
-		meshConfig:          wh.meshConfig,
-		valuesConfig:        wh.valuesConfig,
-		revision:            wh.revision,
-		injectedAnnotations: wh.Config.InjectedAnnotations,
-		proxyEnvs:           parseInjectEnvs(path),
-	}
-
-	patchBytes, err := injectPod(params)
+		pod:               &pod,
+		deployMeta:        deploy,
+		typeMeta:          typeMeta,
+		revision:          wh.revision,
+		proxyEnvs:         parseInjectEnvs(path),
+		namespaceValues:   nsAnnotations[ValuesAnnotation],
+		workloadValues:    wh.getWorkloadValues(ctx, &pod, deploy, typeMeta),
+		namespaceAppProxy: nsAnnotations[AppProxyAnnotation],
+		statusStore:       wh.statuses,
+		kubernetes:        capabilities,
+		fieldManager:      wh.fieldManager,
+	}.withConfig(config, valuesConfig, version, mc)
+	if config.LimitRangeAware && wh.limits != nil && lookupAllowed(ctx, "limitrange") {
+		params.limitRanges = wh.limits.get(ctx, pod.Namespace)
+	}
+	sample := params
+	if wh.canary != nil {
+		sample.pod = pod.DeepCopy()
+	}
+
+	patchBytes, err := injectPod(ctx, params)
 	if err != nil {
 		handleError(fmt.Sprintf("Pod injection failed: %v", err))
+		decide(DecisionFailed, err.Error(), nil)
+		if wh.failureEvents {
+			recordFailureEvent(ctx, wh.recorder, &pod, err)
+		}
 		return toAdmissionResponse(err)
 	}
+	wh.canary.record(sample)
 
 	reviewResponse := kube.AdmissionResponse{
 		Allowed: true,
@@ -839,74 +1724,77 @@ func (wh *Webhook) inject(ar *kube.AdmissionReview, path string) *kube.Admission
 			return &pt
 		}(),
 	}
+	injected = true
 	totalSuccessfulInjections.Increment()
+	decide(DecisionInjected, "", patchBytes)
 	return &reviewResponse
 }
 
-func (wh *Webhook) serveInject(w http.ResponseWriter, r *http.Request) {
-	totalInjections.Increment()
-	var body []byte
-	if r.Body != nil {
-		if data, err := ioutil.ReadAll(r.Body); err == nil {
-			body = data
+// getNamespace returns the eligibility of the namespace, or nil if it cannot
+// be found.
+func (wh *Webhook) getNamespace(ctx context.Context, namespace string) *namespaceEligibility {
+	if wh.kubeClient == nil || namespace == "" {
+		return nil
+	}
+	if wh.namespaces != nil {
+		if e, f := wh.namespaces.get(namespace); f {
+			return e
 		}
 	}
-	if len(body) == 0 {
-		handleError("no body found")
-		http.Error(w, "no body found", http.StatusBadRequest)
-		return
+	if !lookupAllowed(ctx, "namespace") {
+		return nil
 	}
-
-	// verify the content type is accurate
-	contentType := r.Header.Get("Content-Type")
-	if contentType != "application/json" {
-		handleError(fmt.Sprintf("contentType=%s, expect application/json", contentType))
-		http.Error(w, "invalid Content-Type, want `application/json`", http.StatusUnsupportedMediaType)
-		return
+	ns, err := wh.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("Failed to get namespace %s: %v", namespace, err)
+		return nil
 	}
+	return newNamespaceEligibility(ns, wh.revision)
+}
 
+// serveInject serves the injection request, on the worker pool if configured.
+func (wh *Webhook) serveInject(w http.ResponseWriter, r *http.Request) {
+	wh.inflight.Inc()
+	defer wh.inflight.Dec()
+	totalInjections.Increment()
+	start := time.Now()
+	defer func() {
+		elapsed := time.Since(start)
+		wh.latencies.record(elapsed)
+		admissionDuration.Record(elapsed.Seconds())
+	}()
 	path := ""
 	if r.URL != nil {
 		path = r.URL.Path
 	}
-
-	var reviewResponse *kube.AdmissionResponse
-	var obj runtime.Object
-	var ar *kube.AdmissionReview
-	if out, _, err := deserializer.Decode(body, nil, obj); err != nil {
-		handleError(fmt.Sprintf("Could not decode body: %v", err))
-		reviewResponse = toAdmissionResponse(err)
-	} else {
-		log.Debugf("AdmissionRequest for path=%s\n", path)
-		ar, err = kube.AdmissionReviewKubeToAdapter(out)
-		if err != nil {
-			handleError(fmt.Sprintf("Could not decode object: %v", err))
-		}
-		reviewResponse = wh.inject(ar, path)
-	}
-
-	response := kube.AdmissionReview{}
-	response.Response = reviewResponse
-	var responseKube runtime.Object
-	var apiVersion string
-	if ar != nil {
-		apiVersion = ar.APIVersion
-		response.TypeMeta = ar.TypeMeta
-		if response.Response != nil {
-			if ar.Request != nil {
-				response.Response.UID = ar.Request.UID
+	ctx, cancel := admissionContext(r)
+	defer cancel()
+	serve := func() {
+		webhooks.ServeAdmission(w, r, func(ar *kube.AdmissionReview) *kube.AdmissionResponse {
+			log.Debugf("AdmissionRequest for path=%s\n", path)
+			if resp := wh.startup.admission(wh, ar); resp != nil {
+				return resp
 			}
-		}
+			return wh.injectWithCost(ctx, ar, path)
+		}, webhooks.ServeOptions{
+			OnError: func(_ int, err error) {
+				handleError(err.Error())
+			},
+		})
 	}
-	responseKube = kube.AdmissionReviewAdapterToKube(&response, apiVersion)
-	resp, err := json.Marshal(responseKube)
-	if err != nil {
-		log.Errorf("Could not encode response: %v", err)
-		http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+	if wh.queue == nil {
+		serve()
+		return
+	}
+	var body []byte
+	if r.Body != nil {
+		if data, err := ioutil.ReadAll(r.Body); err == nil {
+			body = data
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
 	}
-	if _, err := w.Write(resp); err != nil {
-		log.Errorf("Could not write response: %v", err)
-		http.Error(w, fmt.Sprintf("could not write response: %v", err), http.StatusInternalServerError)
+	if reason := wh.queue.do(ctx, admissionNamespace(body), serve); reason != "" {
+		shedAdmission(w, r, reason)
 	}
 }
 
@@ -16,6 +16,7 @@ package inject
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -26,6 +27,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/ghodss/yaml"
@@ -607,7 +609,7 @@ func TestWebhookInject(t *testing.T) {
 			if err != nil {
 				t.Fatalf(err.Error())
 			}
-			got := wh.inject(&kube.AdmissionReview{
+			got := wh.inject(context.TODO(), &kube.AdmissionReview{
 				Request: &kube.AdmissionRequest{
 					Object: runtime.RawExtension{
 						Raw: podJSON,
@@ -1002,6 +1004,36 @@ func createWebhook(t testing.TB, cfg *Config) (*Webhook, func()) {
 	return wh, cleanup
 }
 
+func TestReloadConfig(t *testing.T) {
+	wh, cleanup := createWebhook(t, minimalSidecarTemplate)
+	defer cleanup()
+	initial := wh.sidecarTemplateVersion
+
+	updated := *minimalSidecarTemplate
+	updated.Template += "- name: istio-certs\n"
+	configBytes, err := yaml.Marshal(&updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(wh.configFile, configBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wh.reloadConfig()
+	if wh.sidecarTemplateVersion == initial || wh.Config.Template != updated.Template {
+		t.Fatalf("new template not swapped in: version %s", wh.sidecarTemplateVersion)
+	}
+
+	// an invalid configuration keeps the current one
+	version := wh.sidecarTemplateVersion
+	if err := ioutil.WriteFile(wh.configFile, []byte("portCollisionPolicy: unknown"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wh.reloadConfig()
+	if wh.sidecarTemplateVersion != version || wh.Config.Template != updated.Template {
+		t.Fatalf("invalid configuration swapped in: version %s", wh.sidecarTemplateVersion)
+	}
+}
+
 func TestRunAndServe(t *testing.T) {
 	// TODO: adjust the test to match prod defaults instead of fake defaults.
 	wh, cleanup := createWebhook(t, minimalSidecarTemplate)
@@ -1241,6 +1273,10 @@ func testSideCarInjectorMetrics(t *testing.T, wh *Webhook) {
 	if !strings.Contains(output, "sidecar_injection_failure_total") {
 		t.Fatalf("incorrect value for metric sidecar_injection_failure_total")
 	}
+
+	if !strings.Contains(output, "sidecar_injection_admission_duration_seconds") {
+		t.Fatalf("metric sidecar_injection_admission_duration_seconds not found")
+	}
 }
 
 func BenchmarkInjectServe(b *testing.B) {
@@ -1367,6 +1403,35 @@ func TestParseInjectEnvs(t *testing.T) {
 	}
 }
 
+func TestServeInsecure(t *testing.T) {
+	wh := &Webhook{}
+	wh.injectHandler = wh.serveInject
+	server, listener, err := wh.serveInsecure("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	base := "http://" + listener.Addr().String()
+
+	res, err := http.Post(base+"/inject", "application/json", bytes.NewReader(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("got status %v for empty injection request, want %v", res.StatusCode, http.StatusBadRequest)
+	}
+
+	res, err = http.Get(base + annotationCatalogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got status %v for annotation catalog, want %v", res.StatusCode, http.StatusOK)
+	}
+}
+
 // defaultInstallPackageDir returns a path to a snapshot of the helm charts used for testing.
 func defaultInstallPackageDir() string {
 	wd, err := os.Getwd()
@@ -1375,3 +1440,31 @@ func defaultInstallPackageDir() string {
 	}
 	return filepath.Join(wd, "../../../operator/cmd/mesh/testdata/manifest-generate/data-snapshot")
 }
+
+func TestDrain(t *testing.T) {
+	wh := &Webhook{shutdownGracePeriod: time.Second}
+	wh.inflight.Inc()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		wh.inflight.Dec()
+	}()
+	start := time.Now()
+	wh.drain(nil)
+	if wh.inflight.Load() != 0 {
+		t.Fatalf("drain returned after %v with requests in flight", time.Since(start))
+	}
+
+	// bounded by the grace period
+	wh = &Webhook{shutdownGracePeriod: 100 * time.Millisecond}
+	wh.inflight.Inc()
+	done := make(chan struct{})
+	go func() {
+		wh.drain(nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("drain not bounded by the grace period")
+	}
+}
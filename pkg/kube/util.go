@@ -16,8 +16,11 @@ package kube
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 
+	"golang.org/x/net/http/httpproxy"
 	kubeApiCore "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -124,6 +127,27 @@ func SetRestDefaults(config *rest.Config) *rest.Config {
 	return config
 }
 
+// SetProxy routes the requests of the config through the proxy, except for
+// the hosts matched by noProxy, a comma separated list in the NO_PROXY format.
+// Without a proxy, client-go uses HTTPS_PROXY and NO_PROXY of the environment.
+func SetProxy(config *rest.Config, proxy, noProxy string) error {
+	if proxy == "" {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy %q: %v", proxy, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid proxy %q: scheme must be http or https", proxy)
+	}
+	proxyFunc := (&httpproxy.Config{HTTPProxy: proxy, HTTPSProxy: proxy, NoProxy: noProxy}).ProxyFunc()
+	config.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	return nil
+}
+
 // CheckPodReady returns nil if the given pod and all of its containers are ready.
 func CheckPodReady(pod *kubeApiCore.Pod) error {
 	switch pod.Status.Phase {
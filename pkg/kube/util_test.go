@@ -17,9 +17,12 @@ package kube
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"k8s.io/client-go/rest"
 )
 
 func TestBuildClientConfig(t *testing.T) {
@@ -142,3 +145,54 @@ users:
 	}
 	return filePath, nil
 }
+
+func TestSetProxy(t *testing.T) {
+	tests := []struct {
+		name      string
+		proxy     string
+		noProxy   string
+		host      string
+		wantProxy string
+		wantErr   bool
+	}{
+		{name: "no proxy", host: "https://10.0.0.1"},
+		{name: "proxy", proxy: "http://proxy.corp:3128", host: "https://api.cluster.example.com", wantProxy: "http://proxy.corp:3128"},
+		{name: "no proxy domain", proxy: "http://proxy.corp:3128", noProxy: ".cluster.local,10.0.0.0/8",
+			host: "https://kubernetes.default.svc.cluster.local"},
+		{name: "no proxy cidr", proxy: "http://proxy.corp:3128", noProxy: ".cluster.local,10.0.0.0/8", host: "https://10.0.0.1"},
+		{name: "invalid scheme", proxy: "ftp://proxy.corp", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &rest.Config{}
+			err := SetProxy(config, tt.proxy, tt.noProxy)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if config.Proxy == nil {
+				if tt.proxy != "" {
+					t.Fatalf("proxy not set")
+				}
+				return
+			}
+			req, err := http.NewRequest("GET", tt.host, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := config.Proxy(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotProxy := ""
+			if got != nil {
+				gotProxy = got.String()
+			}
+			if gotProxy != tt.wantProxy {
+				t.Fatalf("got proxy %q, want %q", gotProxy, tt.wantProxy)
+			}
+		})
+	}
+}
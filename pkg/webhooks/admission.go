@@ -0,0 +1,154 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	kubeApiAdmissionv1 "k8s.io/api/admission/v1"
+	kubeApiAdmissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/istio/pkg/kube"
+)
+
+var (
+	runtimeScheme = runtime.NewScheme()
+	codecs        = serializer.NewCodecFactory(runtimeScheme)
+	deserializer  = codecs.UniversalDeserializer()
+)
+
+func init() {
+	_ = kubeApiAdmissionv1.AddToScheme(runtimeScheme)
+	_ = kubeApiAdmissionv1beta1.AddToScheme(runtimeScheme)
+}
+
+// AdmitFunc handles a decoded admission review.
+type AdmitFunc func(review *kube.AdmissionReview) *kube.AdmissionResponse
+
+// ServeOptions configures ServeAdmission.
+type ServeOptions struct {
+	// OnError, if set, is called for every request which could not be
+	// admitted. The status is the HTTP status of the reply; it is
+	// http.StatusOK when the error is returned in the admission response.
+	OnError func(status int, err error)
+}
+
+// ToAdmissionResponse returns an admission response denying the request with the error.
+func ToAdmissionResponse(err error) *kube.AdmissionResponse {
+	return &kube.AdmissionResponse{Result: &metav1.Status{Message: err.Error()}}
+}
+
+// AdmissionReviewVersions are the versions of the AdmissionReview API served,
+// in order of preference.
+var AdmissionReviewVersions = []string{kubeApiAdmissionv1.SchemeGroupVersion.Version, kubeApiAdmissionv1beta1.SchemeGroupVersion.Version}
+
+// negotiateReview returns the API version the response to the review in body
+// is written in, and the UID of its request, even if the review cannot be
+// decoded: the API server rejects admission.k8s.io/v1 responses in another
+// version or without the UID of the request. Unknown versions are answered
+// in admission.k8s.io/v1beta1.
+func negotiateReview(body []byte) (string, types.UID) {
+	var review struct {
+		APIVersion string `json:"apiVersion"`
+		Request    *struct {
+			UID types.UID `json:"uid"`
+		} `json:"request"`
+	}
+	_ = json.Unmarshal(body, &review)
+	var uid types.UID
+	if review.Request != nil {
+		uid = review.Request.UID
+	}
+	switch review.APIVersion {
+	case kubeApiAdmissionv1.SchemeGroupVersion.String(), kubeApiAdmissionv1beta1.SchemeGroupVersion.String():
+		return review.APIVersion, uid
+	default:
+		return kubeApiAdmissionv1beta1.SchemeGroupVersion.String(), uid
+	}
+}
+
+// ServeAdmission decodes the admission review of the request, hands it to
+// admit and writes the response, in the API version of the review.
+func ServeAdmission(w http.ResponseWriter, r *http.Request, admit AdmitFunc, o ServeOptions) {
+	onError := func(status int, err error) {
+		if o.OnError != nil {
+			o.OnError(status, err)
+		}
+	}
+
+	var body []byte
+	if r.Body != nil {
+		if data, err := ioutil.ReadAll(r.Body); err == nil {
+			body = data
+		}
+	}
+	if len(body) == 0 {
+		onError(http.StatusBadRequest, fmt.Errorf("no body found"))
+		http.Error(w, "no body found", http.StatusBadRequest)
+		return
+	}
+
+	// verify the content type is accurate
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/json" {
+		onError(http.StatusUnsupportedMediaType, fmt.Errorf("contentType=%s, expect application/json", contentType))
+		http.Error(w, "invalid Content-Type, want `application/json`", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var reviewResponse *kube.AdmissionResponse
+	var obj runtime.Object
+	var ar *kube.AdmissionReview
+	if out, _, err := deserializer.Decode(body, nil, obj); err != nil {
+		err = fmt.Errorf("could not decode body: %v", err)
+		onError(http.StatusOK, err)
+		reviewResponse = ToAdmissionResponse(err)
+	} else if ar, err = kube.AdmissionReviewKubeToAdapter(out); err != nil {
+		err = fmt.Errorf("could not decode object: %v", err)
+		onError(http.StatusOK, err)
+		reviewResponse = ToAdmissionResponse(err)
+	} else {
+		reviewResponse = admit(ar)
+	}
+
+	apiVersion, uid := negotiateReview(body)
+	response := kube.AdmissionReview{}
+	response.Response = reviewResponse
+	response.TypeMeta = metav1.TypeMeta{APIVersion: apiVersion, Kind: "AdmissionReview"}
+	if ar != nil && ar.Request != nil {
+		uid = ar.Request.UID
+	}
+	if response.Response != nil {
+		response.Response.UID = uid
+	}
+	responseKube := kube.AdmissionReviewAdapterToKube(&response, apiVersion)
+	resp, err := json.Marshal(responseKube)
+	if err != nil {
+		onError(http.StatusInternalServerError, err)
+		http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(resp); err != nil {
+		onError(http.StatusInternalServerError, err)
+		http.Error(w, fmt.Sprintf("could not write response: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubeApiAdmissionv1 "k8s.io/api/admission/v1"
+	kubeApiAdmissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"istio.io/istio/pkg/kube"
+)
+
+func TestServeAdmission(t *testing.T) {
+	review, err := json.Marshal(kubeApiAdmissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  &kubeApiAdmissionv1.AdmissionRequest{UID: types.UID("uid")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewV1beta1, err := json.Marshal(kubeApiAdmissionv1beta1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1beta1", Kind: "AdmissionReview"},
+		Request:  &kubeApiAdmissionv1beta1.AdmissionRequest{UID: types.UID("uid-v1beta1")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name           string
+		body           []byte
+		contentType    string
+		wantStatusCode int
+		wantAllowed    bool
+		wantUID        types.UID
+		wantErrStatus  int
+		wantAPIVersion string
+	}{
+		{
+			name:           "admitted",
+			body:           review,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusOK,
+			wantAllowed:    true,
+			wantUID:        "uid",
+			wantAPIVersion: "admission.k8s.io/v1",
+		},
+		{
+			name:           "admitted v1beta1",
+			body:           reviewV1beta1,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusOK,
+			wantAllowed:    true,
+			wantUID:        "uid-v1beta1",
+			wantAPIVersion: "admission.k8s.io/v1beta1",
+		},
+		{
+			name:           "undecodable v1 request",
+			body:           []byte(`{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"uid","operation":5}}`),
+			contentType:    "application/json",
+			wantStatusCode: http.StatusOK,
+			wantUID:        "uid",
+			wantErrStatus:  http.StatusOK,
+			wantAPIVersion: "admission.k8s.io/v1",
+		},
+		{
+			name:           "no body",
+			contentType:    "application/json",
+			wantStatusCode: http.StatusBadRequest,
+			wantErrStatus:  http.StatusBadRequest,
+		},
+		{
+			name:           "wrong content type",
+			body:           review,
+			contentType:    "application/yaml",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+			wantErrStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "undecodable body",
+			body:           []byte("{"),
+			contentType:    "application/json",
+			wantStatusCode: http.StatusOK,
+			wantErrStatus:  http.StatusOK,
+			wantAPIVersion: "admission.k8s.io/v1beta1",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://webhook", bytes.NewReader(c.body))
+			req.Header.Add("Content-Type", c.contentType)
+			w := httptest.NewRecorder()
+
+			errStatus := 0
+			ServeAdmission(w, req, func(*kube.AdmissionReview) *kube.AdmissionResponse {
+				return &kube.AdmissionResponse{Allowed: true}
+			}, ServeOptions{OnError: func(status int, _ error) { errStatus = status }})
+
+			res := w.Result()
+			if res.StatusCode != c.wantStatusCode {
+				t.Fatalf("got status code %v, want %v", res.StatusCode, c.wantStatusCode)
+			}
+			if errStatus != c.wantErrStatus {
+				t.Fatalf("got reported error status %v, want %v", errStatus, c.wantErrStatus)
+			}
+			if res.StatusCode != http.StatusOK {
+				return
+			}
+			var got kubeApiAdmissionv1.AdmissionReview
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatalf("could not decode response body: %v", err)
+			}
+			if got.APIVersion != c.wantAPIVersion || got.Kind != "AdmissionReview" {
+				t.Fatalf("got response %v, want API version %v", got.TypeMeta, c.wantAPIVersion)
+			}
+			if got.Response.Allowed != c.wantAllowed || got.Response.UID != c.wantUID {
+				t.Fatalf("got response %+v", got.Response)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/pkg/log"
+)
+
+// CABundleOptions configures a CABundleController.
+type CABundleOptions struct {
+	// WebhookConfigName is the name of the MutatingWebhookConfiguration to keep in sync.
+	WebhookConfigName string
+	// WebhookName is the name of the webhook entry in WebhookConfigName.
+	WebhookName string
+
+	// Namespace and ConfigMapName locate the ConfigMap holding the mesh root
+	// certificate, e.g. istio-ca-root-cert. If SecretName is set, the root is
+	// read from the ca.crt or root-cert.pem of that Secret instead, e.g. the
+	// Secret of the serving certificate.
+	Namespace     string
+	ConfigMapName string
+	SecretName    string
+
+	// ServingCertChain returns the PEM encoded certificate chain currently
+	// served by the webhook.
+	ServingCertChain func() ([]byte, error)
+
+	// Reload triggers a reconcile, e.g. once the serving certificate is
+	// reissued, so a rotation in progress is completed.
+	Reload <-chan struct{}
+
+	// Policies are patched together with the caBundle.
+	Policies WebhookPolicies
+}
+
+// CABundleController keeps the caBundle of the injection webhook in sync
+// with the mesh root certificate.
+//
+// A root rotation is applied in two phases, so admission keeps working while
+// the serving certificate is being reissued: the new root is first added to
+// the bundle next to the old one, and the old root is only removed once the
+// serving chain verifies against the new root.
+type CABundleController struct {
+	o      CABundleOptions
+	client kubernetes.Interface
+
+	// trusted are the PEM encoded roots in the caBundle, in the order they
+	// were added. Only accessed from Run.
+	trusted [][]byte
+	notify  chan struct{}
+}
+
+// caBundleRetryDelay is how long a failed reconcile waits to be retried.
+const caBundleRetryDelay = 5 * time.Second
+
+func NewCABundleController(o CABundleOptions, client kubernetes.Interface) *CABundleController {
+	return &CABundleController{
+		o:      o,
+		client: client,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Run reconciles the caBundle on the changes of the mesh root and on
+// Reload, until the stop channel is closed.
+func (c *CABundleController) Run(stop <-chan struct{}) {
+	watchlist, obj := c.rootListWatch()
+	_, informer := cache.NewInformer(watchlist, obj, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { c.queue() },
+		UpdateFunc: func(interface{}, interface{}) { c.queue() },
+	})
+	go informer.Run(stop)
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case <-c.notify:
+		case <-c.o.Reload:
+		case <-retry:
+		}
+		retry = nil
+		if err := c.reconcile(); err != nil {
+			log.Errorf("Failed to reconcile caBundle of webhook %s: %v", c.o.WebhookName, err)
+			retry = time.After(caBundleRetryDelay)
+		}
+	}
+}
+
+// rootListWatch watches the ConfigMap or Secret holding the mesh root.
+func (c *CABundleController) rootListWatch() (*cache.ListWatch, runtime.Object) {
+	if c.o.SecretName != "" {
+		secrets := c.client.CoreV1().Secrets(c.o.Namespace)
+		selector := fields.OneTermEqualSelector("metadata.name", c.o.SecretName).String()
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = selector
+				return secrets.List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = selector
+				return secrets.Watch(context.TODO(), options)
+			},
+		}, &corev1.Secret{}
+	}
+	configMaps := c.client.CoreV1().ConfigMaps(c.o.Namespace)
+	selector := fields.OneTermEqualSelector("metadata.name", c.o.ConfigMapName).String()
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return configMaps.List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return configMaps.Watch(context.TODO(), options)
+		},
+	}, &corev1.ConfigMap{}
+}
+
+func (c *CABundleController) queue() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *CABundleController) reconcile() error {
+	bundle, err := c.meshRoots()
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	roots := splitPEM(bundle)
+	if len(roots) == 0 {
+		return nil
+	}
+
+	if c.trusted == nil {
+		if c.trusted, err = c.currentBundle(); err != nil {
+			return err
+		}
+	}
+
+	next := nextTrustedRoots(c.trusted, roots, c.servingChainVerifies)
+	if err := patchMutatingWebhookConfig(c.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
+		c.o.WebhookConfigName, c.o.WebhookName, bytes.Join(next, nil), c.o.Policies); err != nil {
+		return err
+	}
+	if len(next) != len(c.trusted) {
+		log.Infof("Updated caBundle of webhook %s to %d root(s)", c.o.WebhookName, len(next))
+	}
+	c.trusted = next
+	return nil
+}
+
+// meshRoots returns the PEM encoded mesh roots of the ConfigMap or Secret.
+func (c *CABundleController) meshRoots() ([]byte, error) {
+	if c.o.SecretName != "" {
+		secret, err := c.client.CoreV1().Secrets(c.o.Namespace).Get(context.TODO(), c.o.SecretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if roots := secret.Data[corev1.ServiceAccountRootCAKey]; len(roots) > 0 {
+			return roots, nil
+		}
+		return secret.Data[constants.RootCertFilename], nil
+	}
+	cm, err := c.client.CoreV1().ConfigMaps(c.o.Namespace).Get(context.TODO(), c.o.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(cm.Data[constants.CACertNamespaceConfigMapDataName]), nil
+}
+
+// nextTrustedRoots returns the roots the caBundle should hold once roots are
+// the mesh roots. Roots not yet trusted are added to the trusted roots; the
+// other trusted roots are dropped once verifies reports the serving chain is
+// signed by the mesh roots.
+func nextTrustedRoots(trusted, roots [][]byte, verifies func(roots []byte) bool) [][]byte {
+	var added [][]byte
+	for _, r := range roots {
+		if !containsCert(trusted, r) {
+			added = append(added, r)
+		}
+	}
+	if len(added) > 0 {
+		return append(append([][]byte{}, trusted...), added...)
+	}
+	if len(trusted) > len(roots) && verifies(bytes.Join(roots, nil)) {
+		return roots
+	}
+	return trusted
+}
+
+func containsCert(certs [][]byte, cert []byte) bool {
+	for _, c := range certs {
+		if bytes.Equal(c, cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentBundle returns the roots in the caBundle of the webhook.
+func (c *CABundleController) currentBundle() ([][]byte, error) {
+	config, err := c.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+		Get(context.TODO(), c.o.WebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range config.Webhooks {
+		if IsWebhookEntry(w.Name, c.o.WebhookName) {
+			return splitPEM(w.ClientConfig.CABundle), nil
+		}
+	}
+	return nil, apierrors.NewInternalError(fmt.Errorf(
+		"webhook entry %q not found in config %q", c.o.WebhookName, c.o.WebhookConfigName))
+}
+
+func (c *CABundleController) servingChainVerifies(roots []byte) bool {
+	if c.o.ServingCertChain == nil {
+		return false
+	}
+	chain, err := c.o.ServingCertChain()
+	if err != nil {
+		log.Warnf("Failed to read serving certificate chain: %v", err)
+		return false
+	}
+	return verifyChain(chain, roots)
+}
+
+// verifyChain returns true if the leaf of the PEM encoded chain is signed by one of roots.
+func verifyChain(chain, roots []byte) bool {
+	var certs []*x509.Certificate
+	for _, b := range splitPEM(chain) {
+		block, _ := pem.Decode(b)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return false
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return false
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(roots) {
+		return false
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// splitPEM splits a PEM bundle into its certificates, each PEM encoded.
+func splitPEM(bundle []byte) [][]byte {
+	var out [][]byte
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return out
+		}
+		if block.Type == "CERTIFICATE" {
+			out = append(out, pem.EncodeToMemory(block))
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/pkg/config/constants"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func TestNextTrustedRoots(t *testing.T) {
+	oldRoot, newRoot := []byte("old"), []byte("new")
+	cases := []struct {
+		name     string
+		trusted  [][]byte
+		roots    [][]byte
+		verifies bool
+		want     [][]byte
+	}{
+		{"empty bundle", nil, [][]byte{newRoot}, false, [][]byte{newRoot}},
+		{"unchanged", [][]byte{oldRoot}, [][]byte{oldRoot}, true, [][]byte{oldRoot}},
+		{"rotation adds root", [][]byte{oldRoot}, [][]byte{newRoot}, true, [][]byte{oldRoot, newRoot}},
+		{"old root kept until reissued", [][]byte{oldRoot, newRoot}, [][]byte{newRoot}, false, [][]byte{oldRoot, newRoot}},
+		{"old root dropped once reissued", [][]byte{oldRoot, newRoot}, [][]byte{newRoot}, true, [][]byte{newRoot}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := nextTrustedRoots(c.trusted, c.roots, func([]byte) bool { return c.verifies })
+			if !bytes.Equal(bytes.Join(got, []byte(",")), bytes.Join(c.want, []byte(","))) {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCABundleControllerRotation(t *testing.T) {
+	oldRoot := newTestCert(t, "old", nil)
+	newRoot := newTestCert(t, "new", nil)
+	serving := newTestCert(t, "istiod", oldRoot)
+
+	rootCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-ca-root-cert", Namespace: "istio-system"},
+		Data:       map[string]string{constants.CACertNamespaceConfigMapDataName: string(oldRoot.pem)},
+	}
+	client := fake.NewSimpleClientset(rootCM, &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	c := NewCABundleController(CABundleOptions{
+		WebhookConfigName: "config1",
+		WebhookName:       "webhook1",
+		Namespace:         "istio-system",
+		ConfigMapName:     "istio-ca-root-cert",
+		ServingCertChain:  func() ([]byte, error) { return serving.pem, nil },
+	}, client)
+
+	caBundle := func() []byte {
+		t.Helper()
+		config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+			Get(context.TODO(), "config1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config.Webhooks[0].ClientConfig.CABundle
+	}
+	reconcile := func(want ...*testCert) {
+		t.Helper()
+		if err := c.reconcile(); err != nil {
+			t.Fatal(err)
+		}
+		var wantBundle []byte
+		for _, w := range want {
+			wantBundle = append(wantBundle, w.pem...)
+		}
+		if got := caBundle(); !bytes.Equal(got, wantBundle) {
+			t.Fatalf("got caBundle\n%s\nwant\n%s", got, wantBundle)
+		}
+	}
+
+	reconcile(oldRoot)
+
+	// Phase one: the new root is trusted next to the old one.
+	rootCM.Data[constants.CACertNamespaceConfigMapDataName] = string(newRoot.pem)
+	if _, err := client.CoreV1().ConfigMaps("istio-system").Update(context.TODO(), rootCM, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	reconcile(oldRoot, newRoot)
+	reconcile(oldRoot, newRoot)
+
+	// Phase two: once the serving certificate is reissued, the old root is dropped.
+	serving = newTestCert(t, "istiod", newRoot)
+	reconcile(newRoot)
+}
+
+func TestCABundleControllerSecret(t *testing.T) {
+	root := newTestCert(t, "root", nil)
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "istiod-tls", Namespace: "istio-system"},
+		Data:       map[string][]byte{corev1.ServiceAccountRootCAKey: root.pem},
+	}, &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	c := NewCABundleController(CABundleOptions{
+		WebhookConfigName: "config1",
+		WebhookName:       "webhook1",
+		Namespace:         "istio-system",
+		SecretName:        "istiod-tls",
+	}, client)
+	if err := c.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+		Get(context.TODO(), "config1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Webhooks[0].ClientConfig.CABundle; !bytes.Equal(got, root.pem) {
+		t.Fatalf("got caBundle\n%s\nwant\n%s", got, root.pem)
+	}
+}
+
+func TestCABundleControllerRun(t *testing.T) {
+	oldRoot := newTestCert(t, "old", nil)
+	newRoot := newTestCert(t, "new", nil)
+	serving := newTestCert(t, "istiod", oldRoot)
+	var mu sync.Mutex
+
+	rootCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-ca-root-cert", Namespace: "istio-system"},
+		Data:       map[string]string{constants.CACertNamespaceConfigMapDataName: string(oldRoot.pem)},
+	}
+	client := fake.NewSimpleClientset(rootCM, &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	reload := make(chan struct{}, 1)
+	c := NewCABundleController(CABundleOptions{
+		WebhookConfigName: "config1",
+		WebhookName:       "webhook1",
+		Namespace:         "istio-system",
+		ConfigMapName:     "istio-ca-root-cert",
+		ServingCertChain: func() ([]byte, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return serving.pem, nil
+		},
+		Reload: reload,
+	}, client)
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+
+	waitForBundle := func(want ...*testCert) {
+		t.Helper()
+		var wantBundle []byte
+		for _, w := range want {
+			wantBundle = append(wantBundle, w.pem...)
+		}
+		var got []byte
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+				Get(context.TODO(), "config1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got = config.Webhooks[0].ClientConfig.CABundle; bytes.Equal(got, wantBundle) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("got caBundle\n%s\nwant\n%s", got, wantBundle)
+	}
+	waitForBundle(oldRoot)
+
+	// the update of the ConfigMap is watched
+	rootCM.Data[constants.CACertNamespaceConfigMapDataName] = string(newRoot.pem)
+	if _, err := client.CoreV1().ConfigMaps("istio-system").Update(context.TODO(), rootCM, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForBundle(oldRoot, newRoot)
+
+	// the reissue of the serving certificate is notified
+	mu.Lock()
+	serving = newTestCert(t, "istiod", newRoot)
+	mu.Unlock()
+	reload <- struct{}{}
+	waitForBundle(newRoot)
+}
+
+func TestVerifyChain(t *testing.T) {
+	root := newTestCert(t, "root", nil)
+	other := newTestCert(t, "other", nil)
+	leaf := newTestCert(t, "leaf", root)
+
+	if !verifyChain(leaf.pem, root.pem) {
+		t.Fatalf("expected chain to verify against its root")
+	}
+	if verifyChain(leaf.pem, other.pem) {
+		t.Fatalf("expected chain not to verify against another root")
+	}
+	if !verifyChain(leaf.pem, append(append([]byte{}, other.pem...), root.pem...)) {
+		t.Fatalf("expected chain to verify against a bundle holding its root")
+	}
+	if verifyChain(nil, root.pem) {
+		t.Fatalf("expected empty chain not to verify")
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/api/label"
+	"istio.io/pkg/log"
+)
+
+// Policies of the Janitor for the webhook configs of removed revisions.
+const (
+	// JanitorPolicyReport logs the orphaned webhook configs.
+	JanitorPolicyReport = "report"
+	// JanitorPolicyRemove deletes the orphaned webhook configs.
+	JanitorPolicyRemove = "remove"
+)
+
+// injectorWebhookSelector selects the injection webhook configs of all revisions.
+var injectorWebhookSelector = "app=sidecar-injector," + label.IstioRev
+
+// JanitorOptions configures a Janitor.
+type JanitorOptions struct {
+	// Policy is JanitorPolicyReport or JanitorPolicyRemove.
+	Policy string
+
+	// Revision is the revision of this injector, whose webhook configs are
+	// never orphaned.
+	Revision string
+
+	// GracePeriod is how long a webhook config has to stay orphaned before
+	// it is reported or removed, so injectors being rolled out are not
+	// mistaken for removed ones. Defaults to 10 minutes.
+	GracePeriod time.Duration
+
+	// Interval is how often the webhook configs are checked. Defaults to a minute.
+	Interval time.Duration
+}
+
+// Validate checks the policy of the Janitor.
+func (o JanitorOptions) Validate() error {
+	switch o.Policy {
+	case JanitorPolicyReport, JanitorPolicyRemove:
+	default:
+		return fmt.Errorf("invalid webhook janitor policy %q: must be %s or %s", o.Policy, JanitorPolicyReport, JanitorPolicyRemove)
+	}
+	if o.GracePeriod < 0 {
+		return fmt.Errorf("invalid webhook janitor grace period %v", o.GracePeriod)
+	}
+	return nil
+}
+
+// Janitor finds the injection webhook configs of revisions without a running
+// injector, e.g. left behind by a failed canary cleanup, which would block the
+// creation of pods selected by them with a Fail failure policy.
+//
+// A webhook config is orphaned once none of the services of its webhooks has
+// a ready endpoint. Webhook configs with owner references are left to the
+// garbage collector, and webhooks reached through an URL are not checked.
+type Janitor struct {
+	o      JanitorOptions
+	client kubernetes.Interface
+	now    func() time.Time
+
+	// orphans are the webhook configs found orphaned, by name. Only accessed from Run.
+	orphans map[string]*orphanedWebhookConfig
+}
+
+type orphanedWebhookConfig struct {
+	since    time.Time
+	reported bool
+}
+
+func NewJanitor(o JanitorOptions, client kubernetes.Interface) *Janitor {
+	if o.GracePeriod == 0 {
+		o.GracePeriod = 10 * time.Minute
+	}
+	if o.Interval == 0 {
+		o.Interval = time.Minute
+	}
+	if o.Revision == "" {
+		o.Revision = "default"
+	}
+	return &Janitor{
+		o:       o,
+		client:  client,
+		now:     time.Now,
+		orphans: map[string]*orphanedWebhookConfig{},
+	}
+}
+
+// Run checks the webhook configs until the stop channel is closed.
+func (j *Janitor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(j.o.Interval)
+	defer ticker.Stop()
+	for {
+		if err := j.prune(); err != nil {
+			log.Errorf("Failed to check the injection webhook configs of removed revisions: %v", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Janitor) prune() error {
+	client := j.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	configs, err := client.List(context.TODO(), metav1.ListOptions{LabelSelector: injectorWebhookSelector})
+	if err != nil {
+		return err
+	}
+	now := j.now()
+	orphans := map[string]*orphanedWebhookConfig{}
+	for i := range configs.Items {
+		config := &configs.Items[i]
+		revision := config.Labels[label.IstioRev]
+		if revision == j.o.Revision || len(config.OwnerReferences) > 0 {
+			continue
+		}
+		orphaned, err := j.orphaned(config)
+		if err != nil {
+			log.Warnf("Unable to check whether webhook config %s of revision %s is orphaned: %v", config.Name, revision, err)
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+		o := j.orphans[config.Name]
+		if o == nil {
+			log.Infof("Webhook config %s of revision %s has no running injector", config.Name, revision)
+			o = &orphanedWebhookConfig{since: now}
+		}
+		orphans[config.Name] = o
+		if now.Sub(o.since) < j.o.GracePeriod || o.reported {
+			continue
+		}
+
+		if j.o.Policy == JanitorPolicyReport {
+			log.Warnf("Webhook config %s of revision %s has had no running injector for %v, it may block pod creation. "+
+				"Delete it if the revision was removed", config.Name, revision, now.Sub(o.since).Round(time.Second))
+			o.reported = true
+			continue
+		}
+		// only delete the webhook config checked, not one recreated or updated since
+		err = client.Delete(context.TODO(), config.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &config.UID, ResourceVersion: &config.ResourceVersion},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			log.Errorf("Failed to delete orphaned webhook config %s of revision %s: %v", config.Name, revision, err)
+			continue
+		}
+		log.Infof("Deleted webhook config %s of revision %s, without a running injector for %v",
+			config.Name, revision, now.Sub(o.since).Round(time.Second))
+		delete(orphans, config.Name)
+	}
+	j.orphans = orphans
+	return nil
+}
+
+// orphaned returns true if none of the services of the webhooks of the config has a ready endpoint.
+func (j *Janitor) orphaned(config *v1beta1.MutatingWebhookConfiguration) (bool, error) {
+	checked := false
+	for _, w := range config.Webhooks {
+		s := w.ClientConfig.Service
+		if s == nil {
+			return false, nil
+		}
+		checked = true
+		endpoints, err := j.client.CoreV1().Endpoints(s.Namespace).Get(context.TODO(), s.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				return false, nil
+			}
+		}
+	}
+	return checked, nil
+}
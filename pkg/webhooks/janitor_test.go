@@ -0,0 +1,130 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func injectorWebhookConfig(name, revision string, owned bool) *admissionregistrationv1beta1.MutatingWebhookConfiguration {
+	c := &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"app": "sidecar-injector", "istio.io/rev": revision},
+		},
+		Webhooks: []admissionregistrationv1beta1.MutatingWebhook{{
+			Name: "sidecar-injector.istio.io",
+			ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+				Service: &admissionregistrationv1beta1.ServiceReference{Namespace: "istio-system", Name: "istiod-" + revision},
+			},
+		}},
+	}
+	if owned {
+		c.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "1"}}
+	}
+	return c
+}
+
+func injectorEndpoints(revision string, ready bool) *corev1.Endpoints {
+	e := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "istiod-" + revision}}
+	if ready {
+		e.Subsets = []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}}
+	} else {
+		e.Subsets = []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}}
+	}
+	return e
+}
+
+func TestJanitor(t *testing.T) {
+	for _, policy := range []string{JanitorPolicyReport, JanitorPolicyRemove} {
+		t.Run(policy, func(t *testing.T) {
+			objects := []runtime.Object{
+				injectorWebhookConfig("istio-sidecar-injector", "default", false),
+				injectorWebhookConfig("istio-sidecar-injector-stable", "stable", false),
+				injectorEndpoints("stable", true),
+				injectorWebhookConfig("istio-sidecar-injector-canary", "canary", false),
+				injectorEndpoints("canary", false),
+				injectorWebhookConfig("istio-sidecar-injector-removed", "removed", false),
+				injectorWebhookConfig("istio-sidecar-injector-owned", "owned", true),
+			}
+			client := fake.NewSimpleClientset(objects...)
+			j := NewJanitor(JanitorOptions{Policy: policy, GracePeriod: 10 * time.Minute}, client)
+			now := time.Now()
+			j.now = func() time.Time { return now }
+
+			if err := j.prune(); err != nil {
+				t.Fatal(err)
+			}
+			wantOrphans := []string{"istio-sidecar-injector-canary", "istio-sidecar-injector-removed"}
+			var orphans []string
+			for name := range j.orphans {
+				orphans = append(orphans, name)
+			}
+			sort.Strings(orphans)
+			if len(orphans) != len(wantOrphans) || orphans[0] != wantOrphans[0] || orphans[1] != wantOrphans[1] {
+				t.Fatalf("got orphans %v, want %v", orphans, wantOrphans)
+			}
+
+			// the canary injector becomes ready within the grace period
+			if _, err := client.CoreV1().Endpoints("istio-system").Update(context.TODO(),
+				injectorEndpoints("canary", true), metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			now = now.Add(11 * time.Minute)
+			if err := j.prune(); err != nil {
+				t.Fatal(err)
+			}
+
+			configs, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			remaining := map[string]bool{}
+			for _, c := range configs.Items {
+				remaining[c.Name] = true
+			}
+			for _, name := range []string{"istio-sidecar-injector", "istio-sidecar-injector-stable",
+				"istio-sidecar-injector-canary", "istio-sidecar-injector-owned"} {
+				if !remaining[name] {
+					t.Errorf("webhook config %s should not be removed", name)
+				}
+			}
+			if removed := !remaining["istio-sidecar-injector-removed"]; removed != (policy == JanitorPolicyRemove) {
+				t.Errorf("got orphaned webhook config removed %v with policy %s", removed, policy)
+			}
+			if policy == JanitorPolicyReport && !j.orphans["istio-sidecar-injector-removed"].reported {
+				t.Errorf("expected the orphaned webhook config to be reported")
+			}
+		})
+	}
+}
+
+func TestJanitorOptionsValidate(t *testing.T) {
+	if err := (JanitorOptions{Policy: JanitorPolicyRemove}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (JanitorOptions{Policy: "delete"}).Validate(); err == nil {
+		t.Fatalf("expected error for an unknown policy")
+	}
+}
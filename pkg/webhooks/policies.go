@@ -0,0 +1,119 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebhookPolicies are the policies and the desired state patched onto the
+// webhook entry together with its caBundle, so they are restored when a stale
+// apply of the webhook config clobbers them. Fields left nil keep the value
+// of the webhook config.
+type WebhookPolicies struct {
+	FailurePolicy      *v1beta1.FailurePolicyType
+	ReinvocationPolicy *v1beta1.ReinvocationPolicyType
+	NamespaceSelector  *metav1.LabelSelector
+	ObjectSelector     *metav1.LabelSelector
+	Rules              []v1beta1.RuleWithOperations
+}
+
+// ParseWebhookPolicies returns the policies named, Fail or Ignore and Never
+// or IfNeeded. Empty names leave the policy unset.
+func ParseWebhookPolicies(failurePolicy, reinvocationPolicy string) (WebhookPolicies, error) {
+	var p WebhookPolicies
+	switch f := v1beta1.FailurePolicyType(failurePolicy); f {
+	case "":
+	case v1beta1.Fail, v1beta1.Ignore:
+		p.FailurePolicy = &f
+	default:
+		return WebhookPolicies{}, fmt.Errorf("invalid failure policy %q: must be %s or %s", failurePolicy, v1beta1.Fail, v1beta1.Ignore)
+	}
+	switch r := v1beta1.ReinvocationPolicyType(reinvocationPolicy); r {
+	case "":
+	case v1beta1.NeverReinvocationPolicy, v1beta1.IfNeededReinvocationPolicy:
+		p.ReinvocationPolicy = &r
+	default:
+		return WebhookPolicies{}, fmt.Errorf("invalid reinvocation policy %q: must be %s or %s",
+			reinvocationPolicy, v1beta1.NeverReinvocationPolicy, v1beta1.IfNeededReinvocationPolicy)
+	}
+	return p, nil
+}
+
+// LoadWebhookEntrySpec reads the desired state of the webhook entry from the
+// file, a MutatingWebhook whose namespaceSelector, objectSelector, rules,
+// failurePolicy and reinvocationPolicy are added to the policies. The
+// policies already set take precedence over the ones of the file.
+func LoadWebhookEntrySpec(file string, p WebhookPolicies) (WebhookPolicies, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return WebhookPolicies{}, fmt.Errorf("missing webhook entry spec %v: %v", file, err)
+	}
+	var spec v1beta1.MutatingWebhook
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return WebhookPolicies{}, fmt.Errorf("invalid webhook entry spec %v: %v", file, err)
+	}
+	if p.FailurePolicy == nil {
+		p.FailurePolicy = spec.FailurePolicy
+	}
+	if p.ReinvocationPolicy == nil {
+		p.ReinvocationPolicy = spec.ReinvocationPolicy
+	}
+	p.NamespaceSelector = spec.NamespaceSelector
+	p.ObjectSelector = spec.ObjectSelector
+	for i := range spec.Rules {
+		if spec.Rules[i].Scope == nil {
+			// defaulted by the API server, which would otherwise be patched on every reconcile
+			s := v1beta1.AllScopes
+			spec.Rules[i].Scope = &s
+		}
+	}
+	p.Rules = spec.Rules
+	return p, nil
+}
+
+// IsSet returns true if any policy is set.
+func (p WebhookPolicies) IsSet() bool {
+	return p.FailurePolicy != nil || p.ReinvocationPolicy != nil ||
+		p.NamespaceSelector != nil || p.ObjectSelector != nil || len(p.Rules) > 0
+}
+
+func (p WebhookPolicies) apply(w *v1beta1.MutatingWebhook) {
+	if p.FailurePolicy != nil {
+		f := *p.FailurePolicy
+		w.FailurePolicy = &f
+	}
+	if p.ReinvocationPolicy != nil {
+		r := *p.ReinvocationPolicy
+		w.ReinvocationPolicy = &r
+	}
+	if p.NamespaceSelector != nil {
+		w.NamespaceSelector = p.NamespaceSelector.DeepCopy()
+	}
+	if p.ObjectSelector != nil {
+		w.ObjectSelector = p.ObjectSelector.DeepCopy()
+	}
+	if len(p.Rules) > 0 {
+		w.Rules = make([]v1beta1.RuleWithOperations, len(p.Rules))
+		for i := range p.Rules {
+			p.Rules[i].DeepCopyInto(&w.Rules[i])
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestParseWebhookPolicies(t *testing.T) {
+	cases := []struct {
+		name             string
+		failure          string
+		reinvocation     string
+		wantFailure      string
+		wantReinvocation string
+		wantErr          bool
+	}{
+		{"unset", "", "", "", "", false},
+		{"fail", "Fail", "", "Fail", "", false},
+		{"ignore and reinvoke", "Ignore", "IfNeeded", "Ignore", "IfNeeded", false},
+		{"invalid failure policy", "fail", "", "", "", true},
+		{"invalid reinvocation policy", "", "Always", "", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := ParseWebhookPolicies(c.failure, c.reinvocation)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			gotFailure, gotReinvocation := "", ""
+			if p.FailurePolicy != nil {
+				gotFailure = string(*p.FailurePolicy)
+			}
+			if p.ReinvocationPolicy != nil {
+				gotReinvocation = string(*p.ReinvocationPolicy)
+			}
+			if gotFailure != c.wantFailure || gotReinvocation != c.wantReinvocation {
+				t.Fatalf("got policies %q, %q, want %q, %q", gotFailure, gotReinvocation, c.wantFailure, c.wantReinvocation)
+			}
+			if p.IsSet() != (c.wantFailure != "" || c.wantReinvocation != "") {
+				t.Fatalf("unexpected IsSet %v", p.IsSet())
+			}
+		})
+	}
+}
+
+func TestCertPatchReconcilerPolicies(t *testing.T) {
+	ignore := admissionregistrationv1beta1.Ignore
+	client := fake.NewSimpleClientset(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks: []admissionregistrationv1beta1.MutatingWebhook{
+			{Name: "webhook1", FailurePolicy: &ignore},
+			{Name: "other", FailurePolicy: &ignore},
+		},
+	})
+	policies, err := ParseWebhookPolicies("Fail", "IfNeeded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &certPatchReconciler{client: client, webhookConfigName: "config1", webhookName: "webhook1",
+		caBundle: []byte("fake CA"), policies: policies}
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err != nil {
+		t.Fatal(err)
+	}
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), "config1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := config.Webhooks[0]
+	if *w.FailurePolicy != admissionregistrationv1beta1.Fail || w.ReinvocationPolicy == nil ||
+		*w.ReinvocationPolicy != admissionregistrationv1beta1.IfNeededReinvocationPolicy {
+		t.Fatalf("policies not patched: %v, %v", w.FailurePolicy, w.ReinvocationPolicy)
+	}
+	if *config.Webhooks[1].FailurePolicy != admissionregistrationv1beta1.Ignore {
+		t.Fatalf("other webhook entries should be left unchanged")
+	}
+}
+
+func TestCertPatchReconcilerEntrySpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "entry_spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	spec := filepath.Join(dir, "webhook")
+	if err := ioutil.WriteFile(spec, []byte(`
+name: webhook1
+failurePolicy: Ignore
+namespaceSelector:
+  matchLabels:
+    istio-injection: enabled
+rules:
+- operations: [CREATE]
+  apiGroups: [""]
+  apiVersions: [v1]
+  resources: [pods]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	flags, err := ParseWebhookPolicies("Fail", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies, err := LoadWebhookEntrySpec(spec, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *policies.FailurePolicy != admissionregistrationv1beta1.Fail {
+		t.Fatalf("the failure policy of the flags should take precedence, got %v", *policies.FailurePolicy)
+	}
+
+	// a stale apply dropped the selector and the rules
+	client := fake.NewSimpleClientset(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	r := &certPatchReconciler{client: client, webhookConfigName: "config1", webhookName: "webhook1",
+		caBundle: []byte("fake CA"), policies: policies}
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err != nil {
+		t.Fatal(err)
+	}
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), "config1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := config.Webhooks[0]
+	if w.NamespaceSelector == nil || w.NamespaceSelector.MatchLabels["istio-injection"] != "enabled" {
+		t.Fatalf("namespaceSelector not restored: %v", w.NamespaceSelector)
+	}
+	if len(w.Rules) != 1 || w.Rules[0].Resources[0] != "pods" || w.Rules[0].Scope == nil {
+		t.Fatalf("rules not restored: %v", w.Rules)
+	}
+	if w.ObjectSelector != nil {
+		t.Fatalf("objectSelector not in the spec should be left unchanged, got %v", w.ObjectSelector)
+	}
+}
+
+func TestLoadWebhookEntrySpecErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "entry_spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if _, err := LoadWebhookEntrySpec(filepath.Join(dir, "missing"), WebhookPolicies{}); err == nil {
+		t.Fatal("expected an error for a missing spec")
+	}
+	invalid := filepath.Join(dir, "webhook")
+	if err := ioutil.WriteFile(invalid, []byte("rules: invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWebhookEntrySpec(invalid, WebhookPolicies{}); err == nil {
+		t.Fatal("expected an error for an invalid spec")
+	}
+}
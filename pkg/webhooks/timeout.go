@@ -0,0 +1,175 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/pkg/log"
+)
+
+const (
+	// defaultWebhookTimeoutSeconds is the timeout Kubernetes applies to
+	// v1beta1 webhooks without timeoutSeconds.
+	defaultWebhookTimeoutSeconds = 30
+
+	// timeoutLowerIntervals is the number of consecutive intervals the
+	// desired timeout must stay below the current one before it is lowered.
+	timeoutLowerIntervals = 3
+)
+
+// TimeoutTunerOptions configures a TimeoutTuner.
+type TimeoutTunerOptions struct {
+	// WebhookConfigName is the name of the MutatingWebhookConfiguration to tune.
+	WebhookConfigName string
+	// WebhookName is the name of the webhook entry in WebhookConfigName.
+	WebhookName string
+
+	// MinTimeout and MaxTimeout bound the timeout, in whole seconds between
+	// 1 and 30 as allowed by Kubernetes.
+	MinTimeout time.Duration
+	MaxTimeout time.Duration
+
+	// Headroom is added to the observed latency.
+	Headroom time.Duration
+
+	// Interval is how often the timeout is adjusted.
+	Interval time.Duration
+
+	// MinSamples is the number of admission requests required to adjust the timeout.
+	MinSamples int
+
+	// Latency returns the 99th percentile admission latency and the number
+	// of requests it was computed from.
+	Latency func() (time.Duration, int)
+}
+
+// Validate checks the bounds of the timeout.
+func (o TimeoutTunerOptions) Validate() error {
+	if o.MinTimeout < time.Second || o.MaxTimeout > 30*time.Second || o.MinTimeout > o.MaxTimeout {
+		return fmt.Errorf("invalid webhook timeout bounds [%v, %v]: must be within [1s, 30s]", o.MinTimeout, o.MaxTimeout)
+	}
+	if o.Headroom < 0 {
+		return fmt.Errorf("invalid webhook timeout headroom %v", o.Headroom)
+	}
+	return nil
+}
+
+// TimeoutTuner adjusts the timeoutSeconds of the injection webhook to the
+// observed admission latency plus headroom, within bounds. The timeout is
+// raised as soon as the latency requires it but only lowered once the latency
+// has stayed low for a few intervals, so a noisy latency does not flap it.
+type TimeoutTuner struct {
+	o      TimeoutTunerOptions
+	client kubernetes.Interface
+
+	// lower counts the consecutive intervals the desired timeout was below
+	// the current one. Only accessed from Run.
+	lower int
+}
+
+func NewTimeoutTuner(o TimeoutTunerOptions, client kubernetes.Interface) *TimeoutTuner {
+	if o.Interval == 0 {
+		o.Interval = time.Minute
+	}
+	return &TimeoutTuner{o: o, client: client}
+}
+
+// Run adjusts the timeout until the stop channel is closed.
+func (t *TimeoutTuner) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(t.o.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if err := t.tune(); err != nil {
+			log.Errorf("Failed to tune timeout of webhook %s: %v", t.o.WebhookName, err)
+		}
+	}
+}
+
+// desiredTimeoutSeconds returns the timeout covering the latency plus
+// headroom, rounded up to the second and clamped to the bounds.
+func desiredTimeoutSeconds(latency, headroom, minTimeout, maxTimeout time.Duration) int32 {
+	seconds := int32(math.Ceil((latency + headroom).Seconds()))
+	if lower := int32(minTimeout / time.Second); seconds < lower {
+		seconds = lower
+	}
+	if upper := int32(maxTimeout / time.Second); seconds > upper {
+		seconds = upper
+	}
+	return seconds
+}
+
+func (t *TimeoutTuner) tune() error {
+	p99, samples := t.o.Latency()
+	if samples < t.o.MinSamples {
+		return nil
+	}
+	current, err := t.currentTimeoutSeconds()
+	if err != nil {
+		return err
+	}
+	desired := desiredTimeoutSeconds(p99, t.o.Headroom, t.o.MinTimeout, t.o.MaxTimeout)
+	switch {
+	case desired == current:
+		t.lower = 0
+		return nil
+	case desired < current:
+		t.lower++
+		if t.lower < timeoutLowerIntervals {
+			return nil
+		}
+	}
+	t.lower = 0
+
+	if err := patchMutatingWebhook(t.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
+		t.o.WebhookConfigName, t.o.WebhookName, func(w *v1beta1.MutatingWebhook) {
+			w.TimeoutSeconds = &desired
+		}); err != nil {
+		return err
+	}
+	log.Infof("Changed timeout of webhook %s from %ds to %ds for a p99 admission latency of %v over %d requests",
+		t.o.WebhookName, current, desired, p99, samples)
+	return nil
+}
+
+// currentTimeoutSeconds returns the timeoutSeconds of the webhook.
+func (t *TimeoutTuner) currentTimeoutSeconds() (int32, error) {
+	config, err := t.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+		Get(context.TODO(), t.o.WebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	for _, w := range config.Webhooks {
+		if IsWebhookEntry(w.Name, t.o.WebhookName) {
+			if w.TimeoutSeconds == nil {
+				return defaultWebhookTimeoutSeconds, nil
+			}
+			return *w.TimeoutSeconds, nil
+		}
+	}
+	return 0, fmt.Errorf("webhook entry %q not found in config %q", t.o.WebhookName, t.o.WebhookConfigName)
+}
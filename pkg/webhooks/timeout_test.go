@@ -0,0 +1,121 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDesiredTimeoutSeconds(t *testing.T) {
+	cases := []struct {
+		name    string
+		latency time.Duration
+		want    int32
+	}{
+		{"below min", 100 * time.Millisecond, 2},
+		{"rounded up", 2500 * time.Millisecond, 4},
+		{"above max", 20 * time.Second, 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := desiredTimeoutSeconds(c.latency, time.Second, 2*time.Second, 10*time.Second); got != c.want {
+				t.Fatalf("got %ds, want %ds", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutTunerOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		min, max time.Duration
+		valid    bool
+	}{
+		{"valid", time.Second, 30 * time.Second, true},
+		{"min below 1s", 0, 10 * time.Second, false},
+		{"max above 30s", time.Second, time.Minute, false},
+		{"min above max", 10 * time.Second, 5 * time.Second, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := TimeoutTunerOptions{MinTimeout: c.min, MaxTimeout: c.max}.Validate()
+			if (err == nil) != c.valid {
+				t.Fatalf("got error %v, want valid %v", err, c.valid)
+			}
+		})
+	}
+}
+
+func TestTimeoutTuner(t *testing.T) {
+	ten := int32(10)
+	client := fake.NewSimpleClientset(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook", TimeoutSeconds: &ten}},
+	})
+	var (
+		p99     time.Duration
+		samples int
+	)
+	tuner := NewTimeoutTuner(TimeoutTunerOptions{
+		WebhookConfigName: "config",
+		WebhookName:       "webhook",
+		MinTimeout:        2 * time.Second,
+		MaxTimeout:        20 * time.Second,
+		Headroom:          time.Second,
+		MinSamples:        10,
+		Latency:           func() (time.Duration, int) { return p99, samples },
+	}, client)
+	timeout := func() int32 {
+		t.Helper()
+		config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().
+			Get(context.TODO(), "config", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *config.Webhooks[0].TimeoutSeconds
+	}
+	tune := func(latency time.Duration, n int, want int32) {
+		t.Helper()
+		p99, samples = latency, n
+		if err := tuner.tune(); err != nil {
+			t.Fatal(err)
+		}
+		if got := timeout(); got != want {
+			t.Fatalf("got timeout %ds for latency %v, want %ds", got, latency, want)
+		}
+	}
+
+	// too few samples
+	tune(15*time.Second, 5, 10)
+	// raised immediately
+	tune(15*time.Second, 100, 16)
+	// lowered after staying low
+	tune(time.Second, 100, 16)
+	tune(time.Second, 100, 16)
+	tune(time.Second, 100, 2)
+	// a spike resets the lowering
+	tune(500*time.Millisecond, 100, 2)
+	tune(5*time.Second, 100, 6)
+	tune(time.Second, 100, 6)
+	tune(5*time.Second, 100, 6)
+	tune(time.Second, 100, 6)
+	tune(time.Second, 100, 6)
+}
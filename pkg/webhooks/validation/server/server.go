@@ -19,31 +19,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	multierror "github.com/hashicorp/go-multierror"
-	kubeApiAdmissionv1 "k8s.io/api/admission/v1"
-	kubeApiAdmissionv1beta1 "k8s.io/api/admission/v1beta1"
-	kubeApiApps "k8s.io/api/apps/v1beta1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
 
 	"istio.io/istio/pilot/pkg/config/kube/crd"
 	"istio.io/istio/pkg/config/schema/collection"
 	"istio.io/istio/pkg/config/schema/resource"
 	"istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/webhooks"
 	"istio.io/pkg/log"
 )
 
 var scope = log.RegisterScope("validationServer", "validation webhook server", 0)
 
 var (
-	runtimeScheme = runtime.NewScheme()
-	codecs        = serializer.NewCodecFactory(runtimeScheme)
-	deserializer  = codecs.UniversalDeserializer()
-
 	// Expect AdmissionRequest to only include these top-level field names
 	validFields = map[string]bool{
 		"apiVersion": true,
@@ -54,12 +44,6 @@ var (
 	}
 )
 
-func init() {
-	_ = kubeApiApps.AddToScheme(runtimeScheme)
-	_ = kubeApiAdmissionv1.AddToScheme(runtimeScheme)
-	_ = kubeApiAdmissionv1beta1.AddToScheme(runtimeScheme)
-}
-
 // Options contains the configuration for the Istio Pilot validation
 // admission controller.
 type Options struct {
@@ -139,70 +123,21 @@ func (wh *Webhook) Run(stopCh <-chan struct{}) {
 }
 
 func toAdmissionResponse(err error) *kube.AdmissionResponse {
-	return &kube.AdmissionResponse{Result: &metav1.Status{Message: err.Error()}}
+	return webhooks.ToAdmissionResponse(err)
 }
 
 type admitFunc func(*kube.AdmissionRequest) *kube.AdmissionResponse
 
 func serve(w http.ResponseWriter, r *http.Request, admit admitFunc) {
-	var body []byte
-	if r.Body != nil {
-		if data, err := ioutil.ReadAll(r.Body); err == nil {
-			body = data
-		}
-	}
-	if len(body) == 0 {
-		reportValidationHTTPError(http.StatusBadRequest)
-		http.Error(w, "no body found", http.StatusBadRequest)
-		return
-	}
-
-	// verify the content type is accurate
-	contentType := r.Header.Get("Content-Type")
-	if contentType != "application/json" {
-		reportValidationHTTPError(http.StatusUnsupportedMediaType)
-		http.Error(w, "invalid Content-Type, want `application/json`", http.StatusUnsupportedMediaType)
-		return
-	}
-
-	var reviewResponse *kube.AdmissionResponse
-	var obj runtime.Object
-	var ar *kube.AdmissionReview
-	if out, _, err := deserializer.Decode(body, nil, obj); err != nil {
-		reviewResponse = toAdmissionResponse(fmt.Errorf("could not decode body: %v", err))
-	} else {
-		ar, err = kube.AdmissionReviewKubeToAdapter(out)
-		if err != nil {
-			reviewResponse = toAdmissionResponse(fmt.Errorf("could not decode object: %v", err))
-		} else {
-			reviewResponse = admit(ar.Request)
-		}
-	}
-
-	response := kube.AdmissionReview{}
-	response.Response = reviewResponse
-	var responseKube runtime.Object
-	var apiVersion string
-	if ar != nil {
-		apiVersion = ar.APIVersion
-		response.TypeMeta = ar.TypeMeta
-		if response.Response != nil {
-			if ar.Request != nil {
-				response.Response.UID = ar.Request.UID
+	webhooks.ServeAdmission(w, r, func(ar *kube.AdmissionReview) *kube.AdmissionResponse {
+		return admit(ar.Request)
+	}, webhooks.ServeOptions{
+		OnError: func(status int, _ error) {
+			if status != http.StatusOK {
+				reportValidationHTTPError(status)
 			}
-		}
-	}
-	responseKube = kube.AdmissionReviewAdapterToKube(&response, apiVersion)
-	resp, err := json.Marshal(responseKube)
-	if err != nil {
-		reportValidationHTTPError(http.StatusInternalServerError)
-		http.Error(w, fmt.Sprintf("could encode response: %v", err), http.StatusInternalServerError)
-		return
-	}
-	if _, err := w.Write(resp); err != nil {
-		reportValidationHTTPError(http.StatusInternalServerError)
-		http.Error(w, fmt.Sprintf("could write response: %v", err), http.StatusInternalServerError)
-	}
+		},
+	})
 }
 
 func (wh *Webhook) serveAdmitPilot(w http.ResponseWriter, r *http.Request) {
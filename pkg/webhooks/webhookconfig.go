@@ -0,0 +1,359 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/admissionregistration/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"istio.io/api/label"
+	"istio.io/istio/pkg/kube"
+	"istio.io/pkg/log"
+)
+
+// loadWebhookConfigTemplate returns the webhook config of the template, named
+// configName and with the CA bundle and the policies set on all its webhooks.
+// With a revision, each webhook is split by revisionWebhooks.
+func loadWebhookConfigTemplate(data []byte, configName string, caBundle []byte,
+	revision string, policies WebhookPolicies) (*v1beta1.MutatingWebhookConfiguration, error) {
+	var config v1beta1.MutatingWebhookConfiguration
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid webhook config template: %v", err)
+	}
+	if len(config.Webhooks) == 0 {
+		return nil, fmt.Errorf("webhook config template has no webhooks")
+	}
+	managed := &v1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        configName,
+			Labels:      config.Labels,
+			Annotations: config.Annotations,
+		},
+		Webhooks: config.Webhooks,
+	}
+	for i := range managed.Webhooks {
+		managed.Webhooks[i].ClientConfig.CABundle = caBundle
+		policies.apply(&managed.Webhooks[i])
+		if len(managed.Webhooks[i].AdmissionReviewVersions) == 0 {
+			// the API server would only send v1beta1 reviews
+			managed.Webhooks[i].AdmissionReviewVersions = append([]string{}, AdmissionReviewVersions...)
+		}
+		defaultWebhook(&managed.Webhooks[i])
+	}
+	if revision != "" {
+		managed.Webhooks = revisionWebhooks(managed.Webhooks, revision)
+	}
+	return managed, nil
+}
+
+const (
+	// revisionNamespaceWebhookPrefix and revisionObjectWebhookPrefix prefix the
+	// names of the webhooks revisionWebhooks splits a webhook in.
+	revisionNamespaceWebhookPrefix = "rev.namespace."
+	revisionObjectWebhookPrefix    = "rev.object."
+)
+
+// IsWebhookEntry returns whether the webhook named name is the entry named
+// webhookName, or one of the entries it is split in for a revision.
+func IsWebhookEntry(name, webhookName string) bool {
+	return name == webhookName || name == revisionNamespaceWebhookPrefix+webhookName ||
+		name == revisionObjectWebhookPrefix+webhookName
+}
+
+// revisionWebhooks splits each webhook in two, so the pods are only sent to
+// the injector of the revision they or their namespace are labeled with: one
+// matching the namespaces labeled with the revision, the other matching the
+// pods labeled with it in the namespaces without a revision. The selectors are
+// added to the ones of the webhook.
+func revisionWebhooks(webhooks []v1beta1.MutatingWebhook, revision string) []v1beta1.MutatingWebhook {
+	withExpression := func(s *metav1.LabelSelector, r metav1.LabelSelectorRequirement) *metav1.LabelSelector {
+		out := s.DeepCopy()
+		if out == nil {
+			out = &metav1.LabelSelector{}
+		}
+		out.MatchExpressions = append(out.MatchExpressions, r)
+		return out
+	}
+	matchRevision := metav1.LabelSelectorRequirement{
+		Key: label.IstioRev, Operator: metav1.LabelSelectorOpIn, Values: []string{revision},
+	}
+	noRevision := metav1.LabelSelectorRequirement{Key: label.IstioRev, Operator: metav1.LabelSelectorOpDoesNotExist}
+	var out []v1beta1.MutatingWebhook
+	for _, w := range webhooks {
+		namespaced := *w.DeepCopy()
+		namespaced.Name = revisionNamespaceWebhookPrefix + w.Name
+		namespaced.NamespaceSelector = withExpression(w.NamespaceSelector, matchRevision)
+
+		object := *w.DeepCopy()
+		object.Name = revisionObjectWebhookPrefix + w.Name
+		object.NamespaceSelector = withExpression(w.NamespaceSelector, noRevision)
+		object.ObjectSelector = withExpression(w.ObjectSelector, matchRevision)
+
+		out = append(out, namespaced, object)
+	}
+	return out
+}
+
+// InjectionWebhookConfigOptions are the settings of the injection webhook
+// config generated by GenerateInjectionWebhookConfig.
+type InjectionWebhookConfigOptions struct {
+	// Name of the MutatingWebhookConfiguration.
+	Name string
+
+	// Template is the webhook config template ManageWebhookConfig creates the
+	// webhook config from, e.g. the webhook key of the injector ConfigMap.
+	Template []byte
+
+	// CABundle verifies the serving certificate of the injector.
+	CABundle []byte
+
+	// Policies override the webhooks of the template, e.g. with the ones
+	// patched by CertPatcher so both come from the same source.
+	Policies WebhookPolicies
+
+	// Revision, if set, splits the webhooks by revisionWebhooks so only the
+	// pods of the revision are selected.
+	Revision string
+}
+
+// GenerateInjectionWebhookConfig returns the injection webhook config of the
+// options, the one ManageWebhookConfig would create from the same template.
+func GenerateInjectionWebhookConfig(o InjectionWebhookConfigOptions) (*v1beta1.MutatingWebhookConfiguration, error) {
+	if o.Name == "" {
+		return nil, fmt.Errorf("the webhook config name is required")
+	}
+	if len(o.CABundle) == 0 {
+		return nil, fmt.Errorf("the CA bundle is required")
+	}
+	config, err := loadWebhookConfigTemplate(o.Template, o.Name, o.CABundle, o.Revision, o.Policies)
+	if err != nil {
+		return nil, err
+	}
+	config.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1beta1.SchemeGroupVersion.String(),
+		Kind:       "MutatingWebhookConfiguration",
+	}
+	return config, nil
+}
+
+// defaultWebhook sets the fields of the webhook omitted by the template to
+// the defaults of the API server, so the webhook can be compared to the one
+// read back from the cluster.
+func defaultWebhook(w *v1beta1.MutatingWebhook) {
+	if w.FailurePolicy == nil {
+		p := v1beta1.Ignore
+		w.FailurePolicy = &p
+	}
+	if w.MatchPolicy == nil {
+		p := v1beta1.Exact
+		w.MatchPolicy = &p
+	}
+	if w.NamespaceSelector == nil {
+		w.NamespaceSelector = &metav1.LabelSelector{}
+	}
+	if w.ObjectSelector == nil {
+		w.ObjectSelector = &metav1.LabelSelector{}
+	}
+	if w.SideEffects == nil {
+		s := v1beta1.SideEffectClassUnknown
+		w.SideEffects = &s
+	}
+	if w.TimeoutSeconds == nil {
+		t := int32(30)
+		w.TimeoutSeconds = &t
+	}
+	if len(w.AdmissionReviewVersions) == 0 {
+		w.AdmissionReviewVersions = []string{"v1beta1"}
+	}
+	if w.ReinvocationPolicy == nil {
+		p := v1beta1.NeverReinvocationPolicy
+		w.ReinvocationPolicy = &p
+	}
+	if s := w.ClientConfig.Service; s != nil && s.Port == nil {
+		port := int32(443)
+		s.Port = &port
+	}
+	for i := range w.Rules {
+		if w.Rules[i].Scope == nil {
+			s := v1beta1.AllScopes
+			w.Rules[i].Scope = &s
+		}
+	}
+}
+
+// webhookConfigReconciler creates the webhook config from its template, and
+// restores it when it is edited or deleted.
+type webhookConfigReconciler struct {
+	client  kubernetes.Interface
+	desired *v1beta1.MutatingWebhookConfiguration
+	// templateFile and caBundlePath, if set, are read again on each reconcile,
+	// keeping the previous webhook config when they cannot be loaded.
+	templateFile string
+	caBundlePath string
+	revision     string
+}
+
+// loadWebhookConfig reads the template and the CA bundle files and returns the
+// webhook config they make.
+func loadWebhookConfig(configName, templateFile, caBundlePath, revision string) (*v1beta1.MutatingWebhookConfiguration, error) {
+	caBundle, err := ioutil.ReadFile(caBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("missing CA path %v: %v", caBundlePath, err)
+	}
+	data, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return nil, fmt.Errorf("missing webhook config template %v: %v", templateFile, err)
+	}
+	return loadWebhookConfigTemplate(data, configName, caBundle, revision, WebhookPolicies{})
+}
+
+func (r *webhookConfigReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if request.Name != r.desired.Name {
+		return reconcile.Result{}, nil
+	}
+	if r.templateFile != "" && r.caBundlePath != "" {
+		if desired, err := loadWebhookConfig(r.desired.Name, r.templateFile, r.caBundlePath, r.revision); err != nil {
+			log.Warnf("Restoring the previous webhook config %s: %v", r.desired.Name, err)
+		} else {
+			r.desired = desired
+		}
+	}
+	client := r.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	current, err := client.Get(context.TODO(), r.desired.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := client.Create(context.TODO(), r.desired.DeepCopy(), metav1.CreateOptions{}); err != nil {
+			log.Errorf("Create webhook config %s failed: %v", r.desired.Name, err)
+			return reconcile.Result{}, err
+		}
+		log.Infof("Created webhook config %s", r.desired.Name)
+		return reconcile.Result{}, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	updated := current.DeepCopy()
+	updated.Webhooks = r.desired.DeepCopy().Webhooks
+	for k, v := range r.desired.Labels {
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		updated.Labels[k] = v
+	}
+	for k, v := range r.desired.Annotations {
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[k] = v
+	}
+	if equal, err := webhookConfigsEqual(current, updated); err != nil || equal {
+		return reconcile.Result{}, err
+	}
+	if _, err := client.Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+		log.Errorf("Update webhook config %s failed: %v", r.desired.Name, err)
+		return reconcile.Result{}, err
+	}
+	log.Infof("Restored webhook config %s to its template", r.desired.Name)
+	return reconcile.Result{}, nil
+}
+
+// webhookConfigsEqual compares the managed fields of the webhook configs.
+func webhookConfigsEqual(a, b *v1beta1.MutatingWebhookConfiguration) (bool, error) {
+	managed := func(c *v1beta1.MutatingWebhookConfiguration) ([]byte, error) {
+		return json.Marshal([]interface{}{c.Labels, c.Annotations, c.Webhooks})
+	}
+	am, err := managed(a)
+	if err != nil {
+		return false, err
+	}
+	bm, err := managed(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(am, bm), nil
+}
+
+// managedWebhookConfigPredicates pass the events of the named webhook config,
+// including its deletion so it is recreated.
+func managedWebhookConfigPredicates(webhookConfigName string) predicate.Funcs {
+	p := webhookConfigPredicates(webhookConfigName)
+	p.DeleteFunc = func(e event.DeleteEvent) bool {
+		return e.Meta != nil && e.Meta.GetName() == webhookConfigName
+	}
+	return p
+}
+
+// ManageWebhookConfig creates the injection webhook config from the template
+// file, with the CA bundle, and keeps it matching the template. Unlike
+// CertPatcher, the install does not need to create the webhook config. With a
+// revision, only the pods of the revision are selected. The template and the
+// CA bundle are read again on each reconcile, including on each receive from
+// reload, which may be nil.
+func ManageWebhookConfig(webhookConfigName, templateFile, caBundlePath, revision string, client kube.Client,
+	reload <-chan struct{}, stopCh <-chan struct{}) error {
+	desired, err := loadWebhookConfig(webhookConfigName, templateFile, caBundlePath, revision)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := manager.New(client.RESTConfig(), manager.Options{
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller manager: %v", err)
+	}
+	r := &webhookConfigReconciler{client: client.Kube(), desired: desired, templateFile: templateFile,
+		caBundlePath: caBundlePath, revision: revision}
+	c, err := crcontroller.New("webhook-config", mgr, crcontroller.Options{Reconciler: r})
+	if err == nil {
+		err = c.Watch(&source.Kind{Type: &v1beta1.MutatingWebhookConfiguration{}}, &handler.EnqueueRequestForObject{},
+			managedWebhookConfigPredicates(webhookConfigName))
+	}
+	// the watch only sees existing configs, reconcile once so a missing one is created
+	initial := make(chan event.GenericEvent, 1)
+	initial <- event.GenericEvent{Meta: desired, Object: desired}
+	if err == nil {
+		err = c.Watch(&source.Channel{Source: initial}, &handler.EnqueueRequestForObject{})
+	}
+	if err == nil && reload != nil {
+		err = c.Watch(&source.Channel{Source: reloadEvents(webhookConfigName, reload, stopCh)}, &handler.EnqueueRequestForObject{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	go func() {
+		if err := mgr.Start(stopCh); err != nil {
+			log.Errorf("Webhook config controller exited: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,307 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const webhookConfigTemplate = `
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: ignored
+  labels:
+    app: sidecar-injector
+webhooks:
+- name: sidecar-injector.istio.io
+  clientConfig:
+    service:
+      name: istiod
+      namespace: istio-system
+      path: /inject
+  failurePolicy: Fail
+  namespaceSelector:
+    matchLabels:
+      istio-injection: enabled
+  rules:
+  - operations: ["CREATE"]
+    apiGroups: [""]
+    apiVersions: ["v1"]
+    resources: ["pods"]
+`
+
+func TestLoadWebhookConfigTemplate(t *testing.T) {
+	config, err := loadWebhookConfigTemplate([]byte(webhookConfigTemplate), "istio-sidecar-injector", []byte("fake CA"), "", WebhookPolicies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := config.Webhooks[0]
+	if config.Name != "istio-sidecar-injector" || config.Labels["app"] != "sidecar-injector" {
+		t.Fatalf("unexpected metadata %v", config.ObjectMeta)
+	}
+	if !bytes.Equal(w.ClientConfig.CABundle, []byte("fake CA")) || *w.FailurePolicy != admissionregistrationv1beta1.Fail {
+		t.Fatalf("template not applied: %+v", w)
+	}
+	if *w.TimeoutSeconds != 30 || *w.ClientConfig.Service.Port != 443 || *w.Rules[0].Scope != admissionregistrationv1beta1.AllScopes {
+		t.Fatalf("defaults not applied: %+v", w)
+	}
+	if !reflect.DeepEqual(w.AdmissionReviewVersions, []string{"v1", "v1beta1"}) {
+		t.Fatalf("got admission review versions %v, want the versions served", w.AdmissionReviewVersions)
+	}
+
+	for _, data := range []string{"webhooks: {}", "metadata: {}"} {
+		if _, err := loadWebhookConfigTemplate([]byte(data), "config", nil, "", WebhookPolicies{}); err == nil {
+			t.Fatalf("expected error for template %q", data)
+		}
+	}
+}
+
+func TestWebhookConfigReconciler(t *testing.T) {
+	desired, err := loadWebhookConfigTemplate([]byte(webhookConfigTemplate), "config1", []byte("fake CA"), "", WebhookPolicies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewSimpleClientset()
+	r := &webhookConfigReconciler{client: client, desired: desired}
+	configs := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	reconcileConfig := func() *admissionregistrationv1beta1.MutatingWebhookConfiguration {
+		t.Helper()
+		if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := configs.Get(context.TODO(), "config1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// created when missing
+	if got := reconcileConfig(); len(got.Webhooks) != 1 || got.Webhooks[0].Name != "sidecar-injector.istio.io" {
+		t.Fatalf("webhook config not created: %+v", got)
+	}
+
+	// left alone when matching the template
+	client.ClearActions()
+	reconcileConfig()
+	for _, a := range client.Actions() {
+		if a.GetVerb() != "get" {
+			t.Fatalf("unexpected %s of a matching webhook config", a.GetVerb())
+		}
+	}
+
+	// restored when edited, keeping the labels of others
+	edited, _ := configs.Get(context.TODO(), "config1", metav1.GetOptions{})
+	ignore := admissionregistrationv1beta1.Ignore
+	edited.Webhooks[0].FailurePolicy = &ignore
+	edited.Webhooks[0].ClientConfig.CABundle = nil
+	edited.Labels["owner"] = "someone"
+	if _, err := configs.Update(context.TODO(), edited, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	got := reconcileConfig()
+	if *got.Webhooks[0].FailurePolicy != admissionregistrationv1beta1.Fail || !bytes.Equal(got.Webhooks[0].ClientConfig.CABundle, []byte("fake CA")) {
+		t.Fatalf("webhook config not restored: %+v", got.Webhooks[0])
+	}
+	if got.Labels["owner"] != "someone" || got.Labels["app"] != "sidecar-injector" {
+		t.Fatalf("unexpected labels %v", got.Labels)
+	}
+
+	// recreated when deleted
+	if err := configs.Delete(context.TODO(), "config1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	reconcileConfig()
+}
+
+func TestWebhookConfigReconcilerReloads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	templateFile, caBundlePath := filepath.Join(dir, "webhook"), filepath.Join(dir, "ca.crt")
+	write := func(file, data string) {
+		t.Helper()
+		if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(templateFile, webhookConfigTemplate)
+	write(caBundlePath, "old CA")
+	desired, err := loadWebhookConfig("config1", templateFile, caBundlePath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewSimpleClientset()
+	r := &webhookConfigReconciler{client: client, desired: desired, templateFile: templateFile, caBundlePath: caBundlePath}
+	reconcileConfig := func() *admissionregistrationv1beta1.MutatingWebhookConfiguration {
+		t.Helper()
+		if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), "config1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	reconcileConfig()
+
+	write(caBundlePath, "new CA")
+	if got := reconcileConfig(); !bytes.Equal(got.Webhooks[0].ClientConfig.CABundle, []byte("new CA")) {
+		t.Fatalf("got caBundle %q, want the rotated one", got.Webhooks[0].ClientConfig.CABundle)
+	}
+
+	// an invalid template keeps the previous webhook config
+	write(templateFile, "webhooks: {}")
+	if got := reconcileConfig(); len(got.Webhooks) != 1 || got.Webhooks[0].Name != "sidecar-injector.istio.io" {
+		t.Fatalf("webhook config not kept: %+v", got.Webhooks)
+	}
+}
+
+func TestManagedWebhookConfigPredicates(t *testing.T) {
+	p := managedWebhookConfigPredicates("config1")
+	if !p.Delete(event.DeleteEvent{Meta: &metav1.ObjectMeta{Name: "config1"}}) {
+		t.Fatalf("deletion of the managed webhook config filtered")
+	}
+	if p.Delete(event.DeleteEvent{Meta: &metav1.ObjectMeta{Name: "config2"}}) {
+		t.Fatalf("deletion of another webhook config passed")
+	}
+}
+
+func TestGenerateInjectionWebhookConfig(t *testing.T) {
+	ignore := admissionregistrationv1beta1.Ignore
+	options := InjectionWebhookConfigOptions{
+		Name:     "istio-sidecar-injector",
+		Template: []byte(webhookConfigTemplate),
+		CABundle: []byte("fake CA"),
+		Policies: WebhookPolicies{FailurePolicy: &ignore},
+	}
+	config, err := GenerateInjectionWebhookConfig(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Name != "istio-sidecar-injector" || config.Kind != "MutatingWebhookConfiguration" || len(config.Webhooks) != 1 {
+		t.Fatalf("unexpected webhook config %+v", config)
+	}
+	w := config.Webhooks[0]
+	service := w.ClientConfig.Service
+	if service.Name != "istiod" || service.Namespace != "istio-system" || *service.Path != "/inject" {
+		t.Fatalf("service reference of the template not kept: %+v", service)
+	}
+	if !bytes.Equal(w.ClientConfig.CABundle, []byte("fake CA")) {
+		t.Fatalf("got CA bundle %q", w.ClientConfig.CABundle)
+	}
+	if *w.FailurePolicy != ignore {
+		t.Fatalf("policies of the options not set: %+v", w)
+	}
+	if !reflect.DeepEqual(w.NamespaceSelector.MatchLabels, map[string]string{"istio-injection": "enabled"}) {
+		t.Fatalf("selector of the template not kept: %+v", w.NamespaceSelector)
+	}
+
+	// the same config as the one managed from the template
+	managed, err := loadWebhookConfigTemplate([]byte(webhookConfigTemplate), options.Name, options.CABundle, "",
+		WebhookPolicies{FailurePolicy: &ignore})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if equal, err := webhookConfigsEqual(config, managed); err != nil || !equal {
+		t.Fatalf("generated webhook config differs from the managed one: %v", err)
+	}
+
+	revision := options
+	revision.Revision = "canary"
+	if config, err = GenerateInjectionWebhookConfig(revision); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Webhooks) != 2 || config.Webhooks[0].Name != "rev.namespace.sidecar-injector.istio.io" ||
+		config.Webhooks[1].Name != "rev.object.sidecar-injector.istio.io" {
+		t.Fatalf("webhook not split for the revision: %+v", config.Webhooks)
+	}
+
+	for name, mutate := range map[string]func(*InjectionWebhookConfigOptions){
+		"name":      func(o *InjectionWebhookConfigOptions) { o.Name = "" },
+		"template":  func(o *InjectionWebhookConfigOptions) { o.Template = nil },
+		"CA bundle": func(o *InjectionWebhookConfigOptions) { o.CABundle = nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := options
+			mutate(&invalid)
+			if _, err := GenerateInjectionWebhookConfig(invalid); err == nil {
+				t.Fatalf("expected the webhook config without %s to be refused", name)
+			}
+		})
+	}
+}
+
+func TestRevisionWebhooks(t *testing.T) {
+	config, err := loadWebhookConfigTemplate([]byte(webhookConfigTemplate), "istio-sidecar-injector-canary", []byte("fake CA"), "canary", WebhookPolicies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Webhooks) != 2 {
+		t.Fatalf("got %d webhooks, want one for the namespaces and one for the pods of the revision", len(config.Webhooks))
+	}
+	matchRevision := metav1.LabelSelectorRequirement{Key: "istio.io/rev", Operator: metav1.LabelSelectorOpIn, Values: []string{"canary"}}
+	noRevision := metav1.LabelSelectorRequirement{Key: "istio.io/rev", Operator: metav1.LabelSelectorOpDoesNotExist}
+	has := func(s *metav1.LabelSelector, r metav1.LabelSelectorRequirement) bool {
+		for _, e := range s.MatchExpressions {
+			if reflect.DeepEqual(e, r) {
+				return true
+			}
+		}
+		return false
+	}
+	namespaced, object := config.Webhooks[0], config.Webhooks[1]
+	if namespaced.Name != "rev.namespace.sidecar-injector.istio.io" || !has(namespaced.NamespaceSelector, matchRevision) {
+		t.Fatalf("unexpected namespace webhook %s: %+v", namespaced.Name, namespaced.NamespaceSelector)
+	}
+	if object.Name != "rev.object.sidecar-injector.istio.io" || !has(object.NamespaceSelector, noRevision) ||
+		!has(object.ObjectSelector, matchRevision) {
+		t.Fatalf("unexpected object webhook %s: %+v %+v", object.Name, object.NamespaceSelector, object.ObjectSelector)
+	}
+	if !bytes.Equal(object.ClientConfig.CABundle, []byte("fake CA")) {
+		t.Fatalf("CA bundle not set on the revision webhooks")
+	}
+}
+
+func TestIsWebhookEntry(t *testing.T) {
+	for name, want := range map[string]bool{
+		"sidecar-injector.istio.io":               true,
+		"rev.namespace.sidecar-injector.istio.io": true,
+		"rev.object.sidecar-injector.istio.io":    true,
+		"other.istio.io":                          false,
+		"rev.object.other.istio.io":               false,
+	} {
+		if got := IsWebhookEntry(name, "sidecar-injector.istio.io"); got != want {
+			t.Errorf("IsWebhookEntry(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -15,31 +15,44 @@
 package webhooks
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"time"
+	"sync"
 
 	"k8s.io/api/admissionregistration/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes"
 	admissionregistrationv1beta1client "k8s.io/client-go/kubernetes/typed/admissionregistration/v1beta1"
-	"k8s.io/client-go/tools/cache"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/webhooks/validation/controller"
 	"istio.io/pkg/log"
 )
 
-// patchMutatingWebhookConfig patches a CA bundle into the specified webhook config.
+// patchMutatingWebhookConfig patches a CA bundle and the policies into the specified webhook config.
 func patchMutatingWebhookConfig(client admissionregistrationv1beta1client.MutatingWebhookConfigurationInterface,
-	webhookConfigName, webhookName string, caBundle []byte) error {
+	webhookConfigName, webhookName string, caBundle []byte, policies WebhookPolicies) error {
+	return patchMutatingWebhook(client, webhookConfigName, webhookName, func(w *v1beta1.MutatingWebhook) {
+		w.ClientConfig.CABundle = caBundle
+		policies.apply(w)
+	})
+}
+
+// patchMutatingWebhook applies update to the specified webhook of the config.
+func patchMutatingWebhook(client admissionregistrationv1beta1client.MutatingWebhookConfigurationInterface,
+	webhookConfigName, webhookName string, update func(w *v1beta1.MutatingWebhook)) error {
 	config, err := client.Get(context.TODO(), webhookConfigName, metav1.GetOptions{})
 	if err != nil {
 		return err
@@ -50,10 +63,10 @@ func patchMutatingWebhookConfig(client admissionregistrationv1beta1client.Mutati
 	}
 	found := false
 	for i, w := range config.Webhooks {
-		if w.Name == webhookName {
-			config.Webhooks[i].ClientConfig.CABundle = caBundle
+		// the entries split for a revision are all patched
+		if IsWebhookEntry(w.Name, webhookName) {
+			update(&config.Webhooks[i])
 			found = true
-			break
 		}
 	}
 	if !found {
@@ -75,94 +88,208 @@ func patchMutatingWebhookConfig(client admissionregistrationv1beta1client.Mutati
 	return err
 }
 
-const delayedRetryTime = time.Second
+// certPatchReconciler patches the CA bundle into the injection webhook
+// whenever its config changes. Failed patches are retried with backoff by the
+// controller.
+type certPatchReconciler struct {
+	client            kubernetes.Interface
+	webhookConfigName string
+	webhookName       string
+	// caBundlePath, if set, is read again on each reconcile, keeping the
+	// previous CA bundle when it cannot be read.
+	caBundlePath string
+	policies     WebhookPolicies
+	// active, if set, returns whether the config is patched, the requests are
+	// dropped otherwise.
+	active func() bool
 
-// Moved out of injector main. Changes:
-// - pass the existing k8s client
-// - use the K8S root instead of citadel root CA
-// - removed the watcher - the k8s CA is already mounted at startup, no more delay waiting for it
-func PatchCertLoop(injectionWebhookConfigName, webhookName, caBundlePath string, client kubernetes.Interface, stopCh <-chan struct{}) {
-	// K8S own CA
-	caCertPem, err := ioutil.ReadFile(caBundlePath)
+	mu       sync.Mutex
+	caBundle []byte
+}
+
+func (r *certPatchReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if request.Name != r.webhookConfigName || (r.active != nil && !r.active()) {
+		return reconcile.Result{}, nil
+	}
+	r.mu.Lock()
+	if r.caBundlePath != "" {
+		if caBundle, err := ioutil.ReadFile(r.caBundlePath); err != nil {
+			log.Warnf("Patching the previous webhook caBundle, failed to read %v: %v", r.caBundlePath, err)
+		} else {
+			r.caBundle = caBundle
+		}
+	}
+	caBundle := r.caBundle
+	r.mu.Unlock()
+	err := patchMutatingWebhookConfig(r.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
+		r.webhookConfigName, r.webhookName, caBundle, r.policies)
+	if apierrors.IsNotFound(err) {
+		// patched when created
+		return reconcile.Result{}, nil
+	}
 	if err != nil {
-		log.Errorf("Skipping webhook patch, missing CA path %v", caBundlePath)
-		return
-	}
-
-	var retry bool
-	if err = patchMutatingWebhookConfig(client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
-		injectionWebhookConfigName, webhookName, caCertPem); err != nil {
-		log.Warna("Error patching Webhook ", err)
-		retry = true
-	}
-
-	shouldPatch := make(chan struct{})
-
-	watchlist := cache.NewListWatchFromClient(
-		client.AdmissionregistrationV1beta1().RESTClient(),
-		"mutatingwebhookconfigurations",
-		"",
-		fields.ParseSelectorOrDie(fmt.Sprintf("metadata.name=%s", injectionWebhookConfigName)))
-
-	_, controller := cache.NewInformer(
-		watchlist,
-		&v1beta1.MutatingWebhookConfiguration{},
-		0,
-		cache.ResourceEventHandlerFuncs{
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				oldConfig := oldObj.(*v1beta1.MutatingWebhookConfiguration)
-				newConfig := newObj.(*v1beta1.MutatingWebhookConfiguration)
-
-				if oldConfig.ResourceVersion != newConfig.ResourceVersion {
-					for i, w := range newConfig.Webhooks {
-						if w.Name == webhookName && !bytes.Equal(newConfig.Webhooks[i].ClientConfig.CABundle, caCertPem) {
-							log.Infof("Detected a change in CABundle, patching MutatingWebhookConfiguration again")
-							shouldPatch <- struct{}{}
-							break
-						}
-					}
-				}
-			},
+		log.Errorf("Patch webhook failed: %v", err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// webhookConfigPredicates only pass events of the named webhook config.
+func webhookConfigPredicates(webhookConfigName string) predicate.Funcs {
+	named := func(meta metav1.Object) bool {
+		return meta != nil && meta.GetName() == webhookConfigName
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return named(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return named(e.MetaNew) && e.MetaOld.GetResourceVersion() != e.MetaNew.GetResourceVersion()
 		},
-	)
-	go controller.Run(stopCh)
+		DeleteFunc: func(_ event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return named(e.Meta)
+		},
+	}
+}
 
+// reloadEvents returns the events reconciling the webhook config each time
+// reload receives, until stopCh is closed.
+func reloadEvents(webhookConfigName string, reload, stopCh <-chan struct{}) <-chan event.GenericEvent {
+	reloads := make(chan event.GenericEvent)
 	go func() {
-		var delayedRetryC <-chan time.Time
-		if retry {
-			delayedRetryC = time.After(delayedRetryTime)
-		}
-
+		config := &v1beta1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhookConfigName}}
 		for {
 			select {
-			case <-delayedRetryC:
-				if retry := doPatch(client, injectionWebhookConfigName, webhookName, caCertPem); retry {
-					delayedRetryC = time.After(delayedRetryTime)
-				} else {
-					log.Infof("Retried patch succeeded")
-					delayedRetryC = nil
-				}
-			case <-shouldPatch:
-				if retry := doPatch(client, injectionWebhookConfigName, webhookName, caCertPem); retry {
-					if delayedRetryC == nil {
-						delayedRetryC = time.After(delayedRetryTime)
-					}
-				} else {
-					delayedRetryC = nil
+			case <-reload:
+				select {
+				case reloads <- event.GenericEvent{Meta: config, Object: config}:
+				case <-stopCh:
+					return
 				}
+			case <-stopCh:
+				return
 			}
 		}
 	}()
+	return reloads
 }
 
-func doPatch(cs kubernetes.Interface, webhookConfigName, webhookName string, caCertPem []byte) (retry bool) {
-	client := cs.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
-	if err := patchMutatingWebhookConfig(client, webhookConfigName, webhookName, caCertPem); err != nil {
-		log.Errorf("Patch webhook failed: %v", err)
+// NewManager returns the controller-runtime manager of the cluster of client.
+// The webhook controllers of the cluster are registered with it, so they share
+// its cache, and it is started once by the caller.
+func NewManager(client kube.Client) (manager.Manager, error) {
+	return manager.New(client.RESTConfig(), manager.Options{
+		MetricsBindAddress: "0",
+	})
+}
+
+// CertPatcher patches the CA bundle, and the failure and reinvocation policies
+// if set, into the injection webhook config while it is started. It runs as a
+// controller of a shared manager, which retries failed patches with backoff.
+//
+// Moved out of injector main. Changes:
+// - pass the existing k8s client
+// - use the K8S root instead of citadel root CA
+// - removed the watcher - the k8s CA is already mounted at startup, no more delay waiting for it
+// - patches again, reading the CA bundle again, on each receive from reload, which may be nil
+type CertPatcher struct {
+	reconciler *certPatchReconciler
+	events     chan event.GenericEvent
+
+	mu sync.Mutex
+	// stop is the stop channel of the last Start, nil before it.
+	stop <-chan struct{}
+}
+
+// NewCertPatcher registers the CertPatcher of the webhook config with mgr.
+func NewCertPatcher(mgr manager.Manager, injectionWebhookConfigName, webhookName, caBundlePath string,
+	policies WebhookPolicies, client kubernetes.Interface, reload <-chan struct{}) (*CertPatcher, error) {
+	p := &CertPatcher{events: make(chan event.GenericEvent, 1)}
+	p.reconciler = &certPatchReconciler{
+		client:            client,
+		webhookConfigName: injectionWebhookConfigName,
+		webhookName:       webhookName,
+		caBundlePath:      caBundlePath,
+		policies:          policies,
+		active:            p.active,
+	}
+	c, err := crcontroller.New("webhook-cert-patch-"+injectionWebhookConfigName, mgr, crcontroller.Options{Reconciler: p.reconciler})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the webhook patch controller: %v", err)
+	}
+	if err := c.Watch(&source.Kind{Type: &v1beta1.MutatingWebhookConfiguration{}}, &handler.EnqueueRequestForObject{},
+		webhookConfigPredicates(injectionWebhookConfigName)); err != nil {
+		return nil, fmt.Errorf("failed to watch the webhook config: %v", err)
+	}
+	if err := c.Watch(&source.Channel{Source: p.events}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, fmt.Errorf("failed to watch the webhook patch starts: %v", err)
+	}
+	if reload != nil {
+		// forwarded for the lifetime of the manager
+		if err := mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+			for {
+				select {
+				case <-reload:
+					p.reconcile()
+				case <-stop:
+					return nil
+				}
+			}
+		})); err != nil {
+			return nil, fmt.Errorf("failed to watch the webhook patch reloads: %v", err)
+		}
+	}
+	return p, nil
+}
+
+// Start patches the webhook config, and patches it again on changes, until
+// stop is closed. Leader election is up to the caller - different istiod
+// revisions patch their own cert. It returns an error, without patching, if
+// the CA bundle cannot be read.
+func (p *CertPatcher) Start(stop <-chan struct{}) error {
+	r := p.reconciler
+	caBundle, err := ioutil.ReadFile(r.caBundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to read the webhook CA bundle %v: %v", r.caBundlePath, err)
+	}
+	r.mu.Lock()
+	r.caBundle = caBundle
+	r.mu.Unlock()
+
+	p.mu.Lock()
+	p.stop = stop
+	p.mu.Unlock()
+	// the cache may be synced already, reconcile now rather than on the next change
+	p.reconcile()
+	return nil
+}
+
+// reconcile queues a reconcile of the webhook config.
+func (p *CertPatcher) reconcile() {
+	config := &v1beta1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: p.reconciler.webhookConfigName}}
+	select {
+	case p.events <- event.GenericEvent{Meta: config, Object: config}:
+	default:
+		// a reconcile is already pending
+	}
+}
+
+// active returns whether the last Start is not stopped.
+func (p *CertPatcher) active() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop == nil {
+		return false
+	}
+	select {
+	case <-p.stop:
+		return false
+	default:
 		return true
 	}
-	log.Infof("Patched webhook %s", webhookName)
-	return false
 }
 
 func CreateValidationWebhookController(client kube.Client,
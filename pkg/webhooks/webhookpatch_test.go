@@ -16,14 +16,21 @@ package webhooks
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestMutatingWebhookPatch(t *testing.T) {
@@ -81,12 +88,32 @@ func TestMutatingWebhookPatch(t *testing.T) {
 			[]byte("fake CA"),
 			"",
 		},
+		{
+			"RevisionEntriesPatched",
+			admissionregistrationv1beta1.MutatingWebhookConfigurationList{
+				Items: []admissionregistrationv1beta1.MutatingWebhookConfiguration{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "config1",
+						},
+						Webhooks: []admissionregistrationv1beta1.MutatingWebhook{
+							{Name: "rev.namespace.webhook1"},
+							{Name: "rev.object.webhook1"},
+						},
+					},
+				},
+			},
+			"config1",
+			"webhook1",
+			[]byte("fake CA"),
+			"",
+		},
 	}
 	for _, tc := range ts {
 		t.Run(tc.name, func(t *testing.T) {
 			client := fake.NewSimpleClientset(tc.configs.DeepCopyObject())
 			err := patchMutatingWebhookConfig(client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
-				tc.configName, tc.webhookName, tc.pemData)
+				tc.configName, tc.webhookName, tc.pemData, WebhookPolicies{})
 			if (err != nil) != (tc.err != "") {
 				t.Fatalf("Wrong error: got %v want %v", err, tc.err)
 			}
@@ -101,10 +128,174 @@ func TestMutatingWebhookPatch(t *testing.T) {
 				if err != nil {
 					t.Fatalf("Fail to parse the patch: %s", err.Error())
 				}
-				if !bytes.Equal(config.Webhooks[0].ClientConfig.CABundle, tc.pemData) {
-					t.Fatalf("Incorrect CA bundle: expect %s got %s", tc.pemData, config.Webhooks[0].ClientConfig.CABundle)
+				want := len(tc.configs.Items[0].Webhooks)
+				if len(config.Webhooks) != want {
+					t.Fatalf("Patched %d webhooks, want %d", len(config.Webhooks), want)
+				}
+				for _, w := range config.Webhooks {
+					if !bytes.Equal(w.ClientConfig.CABundle, tc.pemData) {
+						t.Fatalf("Incorrect CA bundle of %s: expect %s got %s", w.Name, tc.pemData, w.ClientConfig.CABundle)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCertPatchReconciler(t *testing.T) {
+	client := fake.NewSimpleClientset(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	r := &certPatchReconciler{client: client, webhookConfigName: "config1", webhookName: "webhook1", caBundle: []byte("fake CA")}
+
+	for _, name := range []string{"other", "config1"} {
+		if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("reconcile of %s failed: %v", name, err)
+		}
+	}
+	config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), "config1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(config.Webhooks[0].ClientConfig.CABundle, []byte("fake CA")) {
+		t.Fatalf("caBundle not patched: %q", config.Webhooks[0].ClientConfig.CABundle)
+	}
+
+	// a missing entry is retried
+	r.webhookName = "webhook2"
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err == nil {
+		t.Fatalf("expected error for a missing webhook entry")
+	}
+	// a missing config is patched once created
+	r.webhookConfigName = "config2"
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config2"}}); err != nil {
+		t.Fatalf("unexpected error for a missing config: %v", err)
+	}
+}
+
+func TestCertPatchReconcilerReadsCABundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca_bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caBundlePath := filepath.Join(dir, "root-cert.pem")
+
+	client := fake.NewSimpleClientset(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	r := &certPatchReconciler{client: client, webhookConfigName: "config1", webhookName: "webhook1",
+		caBundle: []byte("old CA"), caBundlePath: caBundlePath}
+	for _, c := range []struct {
+		name string
+		file string
+		want string
+	}{
+		{"unreadable keeps the previous CA", "", "old CA"},
+		{"rotated", "new CA", "new CA"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if c.file != "" {
+				if err := ioutil.WriteFile(caBundlePath, []byte(c.file), 0644); err != nil {
+					t.Fatal(err)
 				}
 			}
+			if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err != nil {
+				t.Fatal(err)
+			}
+			config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), "config1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(config.Webhooks[0].ClientConfig.CABundle); got != c.want {
+				t.Fatalf("got caBundle %q, want %q", got, c.want)
+			}
 		})
 	}
 }
+
+func TestWebhookConfigPredicates(t *testing.T) {
+	p := webhookConfigPredicates("config1")
+	config := func(name, version string) *admissionregistrationv1beta1.MutatingWebhookConfiguration {
+		return &admissionregistrationv1beta1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: version}}
+	}
+	if !p.Create(event.CreateEvent{Meta: config("config1", "1")}) || p.Create(event.CreateEvent{Meta: config("other", "1")}) {
+		t.Fatalf("create events not filtered by name")
+	}
+	if !p.Update(event.UpdateEvent{MetaOld: config("config1", "1"), MetaNew: config("config1", "2")}) {
+		t.Fatalf("update of the config filtered")
+	}
+	if p.Update(event.UpdateEvent{MetaOld: config("config1", "1"), MetaNew: config("config1", "1")}) {
+		t.Fatalf("resync of the config not filtered")
+	}
+	if p.Delete(event.DeleteEvent{Meta: config("config1", "1")}) {
+		t.Fatalf("delete of the config not filtered")
+	}
+}
+
+func TestCertPatcherStart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca_bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caBundlePath := filepath.Join(dir, "root-cert.pem")
+
+	client := fake.NewSimpleClientset(&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "config1"},
+		Webhooks:   []admissionregistrationv1beta1.MutatingWebhook{{Name: "webhook1"}},
+	})
+	p := &CertPatcher{events: make(chan event.GenericEvent, 1)}
+	p.reconciler = &certPatchReconciler{client: client, webhookConfigName: "config1", webhookName: "webhook1",
+		caBundlePath: caBundlePath, active: p.active}
+	caBundle := func() string {
+		config, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(context.TODO(), "config1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(config.Webhooks[0].ClientConfig.CABundle)
+	}
+	reconcileConfig := func() {
+		if _, err := p.reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "config1"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the CA bundle is missing, the caller retries
+	stop := make(chan struct{})
+	if err := p.Start(stop); err == nil {
+		t.Fatal("expected an error without a CA bundle")
+	}
+	reconcileConfig()
+	if got := caBundle(); got != "" {
+		t.Fatalf("patched before being started: %q", got)
+	}
+
+	if err := ioutil.WriteFile(caBundlePath, []byte("fake CA"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Start(stop); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-p.events:
+	default:
+		t.Fatal("no reconcile queued on start")
+	}
+	reconcileConfig()
+	if got := caBundle(); got != "fake CA" {
+		t.Fatalf("got caBundle %q once started", got)
+	}
+
+	// a lost leadership stops the patches
+	close(stop)
+	if err := ioutil.WriteFile(caBundlePath, []byte("new CA"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reconcileConfig()
+	if got := caBundle(); got != "fake CA" {
+		t.Fatalf("patched once stopped: %q", got)
+	}
+}
@@ -73,6 +73,14 @@ func getRandomCsrName(secretName, namespace string) string {
 // 5. Clean up the artifacts (e.g., delete CSR)
 func GenKeyCertK8sCA(certClient certclient.CertificateSigningRequestInterface, dnsName,
 	secretName, secretNamespace, caFilePath string) ([]byte, []byte, []byte, error) {
+	return GenKeyCertK8sCAWithApproval(certClient, dnsName, secretName, secretNamespace, caFilePath, true, certWatchTimeout)
+}
+
+// GenKeyCertK8sCAWithApproval is GenKeyCertK8sCA, approving the CSR only if
+// selfApprove is set. Otherwise it waits up to approvalTimeout for the CSR to
+// be approved and signed, e.g. by an operator or an approval controller.
+func GenKeyCertK8sCAWithApproval(certClient certclient.CertificateSigningRequestInterface, dnsName,
+	secretName, secretNamespace, caFilePath string, selfApprove bool, approvalTimeout time.Duration) ([]byte, []byte, []byte, error) {
 	// 1. Generate a CSR
 	options := util.CertOptions{
 		Host:       dnsName,
@@ -98,27 +106,31 @@ func GenKeyCertK8sCA(certClient certclient.CertificateSigningRequestInterface, d
 	}
 
 	// 3. Approve a CSR
-	log.Debugf("approve CSR (%v) ...", csrName)
-	csrMsg := fmt.Sprintf("CSR (%s) for the certificate (%s) is approved", csrName, dnsName)
-	r.Status.Conditions = append(r.Status.Conditions, cert.CertificateSigningRequestCondition{
-		Type:    cert.CertificateApproved,
-		Reason:  csrMsg,
-		Message: csrMsg,
-	})
-	reqApproval, err := certClient.UpdateApproval(context.TODO(), r, metav1.UpdateOptions{})
-	if err != nil {
-		log.Errorf("failed to approve CSR (%v): %v", csrName, err)
-		errCsr := cleanUpCertGen(certClient, csrName)
-		if errCsr != nil {
-			log.Errorf("failed to clean up CSR (%v): %v", csrName, err)
+	if selfApprove {
+		log.Debugf("approve CSR (%v) ...", csrName)
+		csrMsg := fmt.Sprintf("CSR (%s) for the certificate (%s) is approved", csrName, dnsName)
+		r.Status.Conditions = append(r.Status.Conditions, cert.CertificateSigningRequestCondition{
+			Type:    cert.CertificateApproved,
+			Reason:  csrMsg,
+			Message: csrMsg,
+		})
+		reqApproval, err := certClient.UpdateApproval(context.TODO(), r, metav1.UpdateOptions{})
+		if err != nil {
+			log.Errorf("failed to approve CSR (%v): %v", csrName, err)
+			errCsr := cleanUpCertGen(certClient, csrName)
+			if errCsr != nil {
+				log.Errorf("failed to clean up CSR (%v): %v", csrName, err)
+			}
+			return nil, nil, nil, err
 		}
-		return nil, nil, nil, err
+		log.Debugf("CSR (%v) is approved: %v", csrName, reqApproval)
+	} else {
+		log.Infof("waiting up to %v for CSR (%v) for the certificate (%v) to be approved", approvalTimeout, csrName, dnsName)
 	}
-	log.Debugf("CSR (%v) is approved: %v", csrName, reqApproval)
 
 	// 4. Read the signed certificate
 	certChain, caCert, err := readSignedCertificate(certClient,
-		csrName, certReadInterval, certWatchTimeout, maxNumCertRead, caFilePath)
+		csrName, certReadInterval, approvalTimeout, maxNumCertRead, caFilePath)
 	if err != nil {
 		log.Errorf("failed to read signed cert. (%v): %v", csrName, err)
 		errCsr := cleanUpCertGen(certClient, csrName)